@@ -39,6 +39,8 @@ type Config struct {
 	ShutdownTimeout     time.Duration
 	MaxRequestBodySize  int64
 	JwtSecret           string
+	GuestTokenSecret    string
+	GuestTokenTTL       time.Duration
 }
 
 func loadConfig() *Config {
@@ -49,6 +51,8 @@ func loadConfig() *Config {
 		CheckoutServiceAddr: getEnv("CHECKOUT_SERVICE_ADDR", "localhost:50056"),
 		OrdersServiceAddr:   getEnv("ORDERS_SERVICE_ADDR", "localhost:50055"),
 		JwtSecret:           getEnv("JWT_SECRET", "Yn8x85spEjIXQnGlmaWAqbX9I6RS3ts2TBXUXQoyi2g="), // same in tokengen
+		GuestTokenSecret:    getEnv("GUEST_TOKEN_SECRET", "dG9rZW5zLWZvci1ndWVzdC1jYXJ0cy1vbmx5LWNoYW5nZS1tZQ=="),
+		GuestTokenTTL:       30 * 24 * time.Hour, // matches the cart-service GUEST_CART_TTL default
 		RequestTimeout:      30 * time.Second,
 		ShutdownTimeout:     10 * time.Second,
 		MaxRequestBodySize:  1 << 20, // 1MB
@@ -87,6 +91,7 @@ func main() {
 	defer cartServiceConn.Close()
 	cartClient := cartpb.NewCartServiceClient(cartServiceConn)
 	cartHandler := h.NewCartHandler(cartClient, cfg.RequestTimeout)
+	guestTokenHandler := h.NewGuestTokenHandler([]byte(cfg.GuestTokenSecret), cfg.GuestTokenTTL)
 
 	productCb := circuitbreaker.New(circuitbreaker.DefaultSettings("product-service", log))
 	productServiceConn, err := grpc.NewClient(
@@ -136,35 +141,93 @@ func main() {
 	r.Use(l.RequestIDMiddleware)  // 2. your custom request ID propagation
 	r.Use(middleware.Recoverer)   // 3. catch panics
 	r.Use(l.MyRequestLogger(log)) // 4. log with request ID + correct status
-	r.Use(middleware.Timeout(cfg.RequestTimeout))
 	r.Use(middleware.Compress(5))
 	//r.Use(l.MockAuthMiddleware)
-	r.Use(l.JWTAuthMiddleware([]byte(cfg.JwtSecret)))
 	r.Use(limiter.Middleware)
 
-	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
+	// cart routes also take anonymous shoppers with a guest cart token, everything else needs a JWT
+	jwtAuth := l.JWTAuthMiddleware([]byte(cfg.JwtSecret))
+	cartAuth := l.GuestOrJWTAuthMiddleware([]byte(cfg.JwtSecret), []byte(cfg.GuestTokenSecret))
+
+	r.With(jwtAuth).Get("/health", func(w http.ResponseWriter, r *http.Request) {
 		respondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
 	})
 
 	r.Route("/api/v1", func(r chi.Router) {
-		r.Route("/cart", func(r chi.Router) {
-			r.Get("/", cartHandler.GetCart)
-			r.Post("/items", cartHandler.AddItem)
-			r.Put("/items/{product_id}", cartHandler.UpdateQuantity)
-			r.Delete("/items/{product_id}", cartHandler.RemoveItem)
-			r.Delete("/", cartHandler.ClearCart)
-		})
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Timeout(cfg.RequestTimeout))
+
+			r.Route("/cart", func(r chi.Router) {
+				r.Post("/guest-token", guestTokenHandler.IssueGuestToken)
+
+				r.Group(func(r chi.Router) {
+					r.Use(cartAuth)
 
-		r.Route("/products", func(r chi.Router) {
-			r.Get("/", productHandler.Get)
+					r.Get("/", cartHandler.GetCart)
+					r.Get("/priced", cartHandler.GetPricedCart)
+					r.Post("/items", cartHandler.AddItem)
+					r.Put("/items/{product_id}", cartHandler.UpdateQuantity)
+					r.Delete("/items/{product_id}", cartHandler.RemoveItem)
+					r.Put("/", cartHandler.ReplaceCart)
+					r.Delete("/", cartHandler.ClearCart)
+					r.Post("/promo", cartHandler.ApplyPromoCode)
+					r.Delete("/promo", cartHandler.RemovePromoCode)
+					r.Post("/merge", cartHandler.MergeCarts) // needs the JWT and the guest token
+				})
+
+				// saved items and wishlists belong to signed-in users
+				r.Group(func(r chi.Router) {
+					r.Use(jwtAuth)
+
+					r.Get("/saved", cartHandler.GetSavedItems)
+					r.Post("/saved", cartHandler.SaveForLater)
+					r.Post("/saved/move", cartHandler.MoveToCart)
+					r.Delete("/saved/items/{product_id}", cartHandler.RemoveSavedItem)
+					r.Delete("/saved/lists/{list}", cartHandler.DeleteSavedList)
+				})
+			})
 		})
 
-		r.Post("/checkout", checkoutHandler.InitiateCheckout)
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Timeout(cfg.RequestTimeout))
+			r.Use(jwtAuth)
+
+			r.Route("/products", func(r chi.Router) {
+				r.Get("/", productHandler.Get)
+				r.Get("/search", productHandler.Search)
+				r.Get("/{product_id}", productHandler.GetProduct)
+			})
 
-		r.Route("/orders", func(r chi.Router) {
-			r.Get("/", ordersHandler.ListOrders)
-			r.Get("/{order_id}", ordersHandler.GetOrder)
+			r.Get("/categories", productHandler.ListCategories)
+
+			r.Route("/checkout", func(r chi.Router) {
+				r.Post("/", checkoutHandler.InitiateCheckout)
+				r.Get("/", checkoutHandler.ListCheckouts)
+				r.Get("/{checkout_id}", checkoutHandler.GetCheckout)
+			})
+
+			r.Route("/orders", func(r chi.Router) {
+				r.Get("/", ordersHandler.ListOrders)
+				r.Get("/{order_id}", ordersHandler.GetOrder)
+			})
+
+			r.Route("/admin", func(r chi.Router) {
+				r.Use(l.RequireRole(l.RoleAdmin))
+
+				r.Route("/products", func(r chi.Router) {
+					r.Post("/", productHandler.CreateProduct)
+					r.Put("/{product_id}", productHandler.UpdateProduct)
+					r.Delete("/{product_id}", productHandler.ArchiveProduct)
+					r.Post("/{product_id}/scheduled-prices", productHandler.SchedulePriceChange)
+					r.Get("/{product_id}/price", productHandler.GetPriceAt)
+				})
+
+				r.Post("/promotions", productHandler.CreatePromotion)
+			})
 		})
+
+		// event stream stays open until the checkout finishes, so it is outside the request timeout
+		r.With(jwtAuth).Get("/checkout/{checkout_id}/events", checkoutHandler.WatchCheckout)
 	})
 
 	srv := &http.Server{
@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	m "github.com/fjod/go_cart/api-gateway/internal/middleware"
@@ -30,9 +31,15 @@ func NewCartHandler(cartClient pb.CartServiceClient, timeout time.Duration) *Car
 
 type AddItemRequestDTO struct {
 	ProductID int64 `json:"product_id"`
+	VariantID int64 `json:"variant_id,omitempty"` // required when the product is sold per variant
 	Quantity  int32 `json:"quantity"`
 }
 
+// ReplaceCartRequestDTO lists every line the cart should hold, no items empties the cart
+type ReplaceCartRequestDTO struct {
+	Items []AddItemRequestDTO `json:"items"`
+}
+
 type UpdateQuantityRequestDTO struct {
 	Quantity int32 `json:"quantity"`
 }
@@ -47,8 +54,8 @@ func (h *CartHandler) AddItem(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
 	defer cancel()
 
-	userID := getUserIDFromContext(r.Context())
-	if userID == 0 {
+	userID, guestID := getCartOwnerFromContext(r.Context())
+	if userID == 0 && guestID == "" {
 		respondError(w, http.StatusUnauthorized, "unauthorized", "missing user authentication")
 		return
 	}
@@ -65,25 +72,38 @@ func (h *CartHandler) AddItem(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusBadRequest, "invalid_product_id", "product_id must be positive")
 		return
 	}
+	if req.VariantID < 0 {
+		respondError(w, http.StatusBadRequest, "invalid_variant_id", "variant_id must not be negative")
+		return
+	}
 	if req.Quantity <= 0 || req.Quantity > 99 {
 		respondError(w, http.StatusBadRequest, "invalid_quantity", "quantity must be between 1 and 99")
 		return
 	}
 
+	version, ok := ifMatchVersion(w, r)
+	if !ok {
+		return
+	}
+
 	// Propagate metadata
 	ctx = metadata.AppendToOutgoingContext(ctx, "user-id", fmt.Sprint(userID), "request-id", getRequestID(r.Context()))
 
 	// Call gRPC service
 	resp, err := h.cartClient.AddItem(ctx, &pb.AddCartItemRequest{
-		UserId:    userID,
-		ProductId: req.ProductID,
-		Quantity:  req.Quantity,
+		UserId:          userID,
+		GuestId:         guestID,
+		ProductId:       req.ProductID,
+		VariantId:       req.VariantID,
+		Quantity:        req.Quantity,
+		ExpectedVersion: version,
 	})
 	if err != nil {
 		handleGRPCError(w, err)
 		return
 	}
 
+	setCartETag(w, resp.Cart)
 	respondJSON(w, http.StatusCreated, resp.Cart)
 }
 
@@ -91,8 +111,8 @@ func (h *CartHandler) GetCart(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
 	defer cancel()
 
-	userID := getUserIDFromContext(r.Context())
-	if userID == 0 {
+	userID, guestID := getCartOwnerFromContext(r.Context())
+	if userID == 0 && guestID == "" {
 		respondError(w, http.StatusUnauthorized, "unauthorized", "missing user authentication")
 		return
 	}
@@ -101,13 +121,15 @@ func (h *CartHandler) GetCart(w http.ResponseWriter, r *http.Request) {
 
 	// Call gRPC service
 	resp, err := h.cartClient.GetCart(ctx, &pb.GetCartRequest{
-		UserId: userID,
+		UserId:  userID,
+		GuestId: guestID,
 	})
 	if err != nil {
 		handleGRPCError(w, err)
 		return
 	}
 
+	setCartETag(w, resp.Cart)
 	respondJSON(w, http.StatusOK, resp.Cart)
 }
 
@@ -118,6 +140,20 @@ func getUserIDFromContext(ctx context.Context) int64 {
 	return 0
 }
 
+// getCartOwnerFromContext returns who a cart request acts for: the signed-in user, or else the guest
+// named by the guest cart token. A guest id is only returned when there is no user.
+func getCartOwnerFromContext(ctx context.Context) (int64, string) {
+	if userID := getUserIDFromContext(ctx); userID != 0 {
+		return userID, ""
+	}
+	return 0, getGuestIDFromContext(ctx)
+}
+
+func getGuestIDFromContext(ctx context.Context) string {
+	guestID, _ := ctx.Value(m.GuestIDKey).(string)
+	return guestID
+}
+
 func getRequestID(ctx context.Context) string {
 	if requestID, ok := ctx.Value("request_id").(string); ok {
 		return requestID
@@ -165,6 +201,9 @@ func handleGRPCError(w http.ResponseWriter, err error) {
 	case "Unauthenticated":
 		httpStatus = http.StatusUnauthorized
 		code = "unauthenticated"
+	case "FailedPrecondition":
+		httpStatus = http.StatusConflict
+		code = "failed_precondition"
 	case "PermissionDenied":
 		httpStatus = http.StatusForbidden
 		code = "permission_denied"
@@ -177,6 +216,10 @@ func handleGRPCError(w http.ResponseWriter, err error) {
 	case "DeadlineExceeded":
 		httpStatus = http.StatusGatewayTimeout
 		code = "timeout"
+	case "Aborted":
+		// the cart is no longer at the version named by If-Match
+		httpStatus = http.StatusPreconditionFailed
+		code = "precondition_failed"
 	default:
 		httpStatus = http.StatusInternalServerError
 		code = "internal_error"
@@ -189,8 +232,8 @@ func (h *CartHandler) UpdateQuantity(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
 	defer cancel()
 
-	userID := getUserIDFromContext(r.Context())
-	if userID == 0 {
+	userID, guestID := getCartOwnerFromContext(r.Context())
+	if userID == 0 && guestID == "" {
 		respondError(w, http.StatusUnauthorized, "unauthorized", "missing user authentication")
 		return
 	}
@@ -202,6 +245,10 @@ func (h *CartHandler) UpdateQuantity(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusBadRequest, "invalid_product_id", "product_id must be a positive integer")
 		return
 	}
+	variantID, ok := variantIDQuery(w, r)
+	if !ok {
+		return
+	}
 
 	// Parse request body
 	var req UpdateQuantityRequestDTO
@@ -216,20 +263,29 @@ func (h *CartHandler) UpdateQuantity(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	version, ok := ifMatchVersion(w, r)
+	if !ok {
+		return
+	}
+
 	// Propagate metadata
 	ctx = metadata.AppendToOutgoingContext(ctx, "user-id", fmt.Sprint(userID), "request-id", getRequestID(r.Context()))
 
 	// Call gRPC service
 	resp, err := h.cartClient.UpdateQuantity(ctx, &pb.UpdateQuantityRequest{
-		UserId:    userID,
-		ProductId: productID,
-		Quantity:  req.Quantity,
+		UserId:          userID,
+		GuestId:         guestID,
+		ProductId:       productID,
+		VariantId:       variantID,
+		Quantity:        req.Quantity,
+		ExpectedVersion: version,
 	})
 	if err != nil {
 		handleGRPCError(w, err)
 		return
 	}
 
+	setCartETag(w, resp.Cart)
 	respondJSON(w, http.StatusOK, resp.Cart)
 }
 
@@ -237,8 +293,8 @@ func (h *CartHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
 	defer cancel()
 
-	userID := getUserIDFromContext(r.Context())
-	if userID == 0 {
+	userID, guestID := getCartOwnerFromContext(r.Context())
+	if userID == 0 && guestID == "" {
 		respondError(w, http.StatusUnauthorized, "unauthorized", "missing user authentication")
 		return
 	}
@@ -250,44 +306,206 @@ func (h *CartHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusBadRequest, "invalid_product_id", "product_id must be a positive integer")
 		return
 	}
+	variantID, ok := variantIDQuery(w, r)
+	if !ok {
+		return
+	}
+
+	version, ok := ifMatchVersion(w, r)
+	if !ok {
+		return
+	}
 
 	// Propagate metadata
 	ctx = metadata.AppendToOutgoingContext(ctx, "user-id", fmt.Sprint(userID), "request-id", getRequestID(r.Context()))
 
 	// Call gRPC service
 	resp, err := h.cartClient.RemoveItem(ctx, &pb.RemoveItemRequest{
-		UserId:    userID,
-		ProductId: productID,
+		UserId:          userID,
+		GuestId:         guestID,
+		ProductId:       productID,
+		VariantId:       variantID,
+		ExpectedVersion: version,
 	})
 	if err != nil {
 		handleGRPCError(w, err)
 		return
 	}
 
+	setCartETag(w, resp.Cart)
 	respondJSON(w, http.StatusOK, resp.Cart)
 }
 
+// setCartETag sends the cart version as a strong ETag, a client passes it back in If-Match
+// to change the cart only if nobody else changed it in between
+func setCartETag(w http.ResponseWriter, cart *pb.Cart) {
+	w.Header().Set("ETag", strconv.Quote(strconv.FormatInt(cart.GetVersion(), 10)))
+}
+
+// ifMatchVersion reads the cart version a change is conditional on from If-Match.
+// No header or "*" makes the change unconditional.
+func ifMatchVersion(w http.ResponseWriter, r *http.Request) (*int64, bool) {
+	v := strings.TrimSpace(r.Header.Get("If-Match"))
+	if v == "" || v == "*" {
+		return nil, true
+	}
+	tag, err := strconv.Unquote(v)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_if_match", "If-Match must be an ETag returned for the cart")
+		return nil, false
+	}
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil || version < 0 {
+		respondError(w, http.StatusBadRequest, "invalid_if_match", "If-Match must be an ETag returned for the cart")
+		return nil, false
+	}
+	return &version, true
+}
+
+// variantIDQuery reads the optional ?variant_id= that picks one variant line of a product sold per variant
+func variantIDQuery(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	v := r.URL.Query().Get("variant_id")
+	if v == "" {
+		return 0, true
+	}
+	variantID, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || variantID <= 0 {
+		respondError(w, http.StatusBadRequest, "invalid_variant_id", "variant_id must be a positive integer")
+		return 0, false
+	}
+	return variantID, true
+}
+
 func (h *CartHandler) ClearCart(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
 	defer cancel()
 
+	userID, guestID := getCartOwnerFromContext(r.Context())
+	if userID == 0 && guestID == "" {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "missing user authentication")
+		return
+	}
+
+	version, ok := ifMatchVersion(w, r)
+	if !ok {
+		return
+	}
+
+	// Propagate metadata
+	ctx = metadata.AppendToOutgoingContext(ctx, "user-id", fmt.Sprint(userID), "request-id", getRequestID(r.Context()))
+
+	// Call gRPC service
+	resp, err := h.cartClient.ClearCart(ctx, &pb.ClearCartRequest{
+		UserId:          userID,
+		GuestId:         guestID,
+		ExpectedVersion: version,
+	})
+	if err != nil {
+		handleGRPCError(w, err)
+		return
+	}
+
+	setCartETag(w, resp.Cart)
+	respondJSON(w, http.StatusOK, resp.Cart)
+}
+
+// ReplaceCart swaps all the lines of the cart for the listed items, the cart is left as it is
+// when any of them can't be added
+func (h *CartHandler) ReplaceCart(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	userID, guestID := getCartOwnerFromContext(r.Context())
+	if userID == 0 && guestID == "" {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "missing user authentication")
+		return
+	}
+
+	// Parse request body
+	var req ReplaceCartRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_request", "invalid JSON body")
+		return
+	}
+
+	// Validate request
+	items := make([]*pb.CartItemInput, len(req.Items))
+	for i, item := range req.Items {
+		if item.ProductID <= 0 {
+			respondError(w, http.StatusBadRequest, "invalid_product_id", "product_id must be positive")
+			return
+		}
+		if item.VariantID < 0 {
+			respondError(w, http.StatusBadRequest, "invalid_variant_id", "variant_id must not be negative")
+			return
+		}
+		if item.Quantity <= 0 || item.Quantity > 99 {
+			respondError(w, http.StatusBadRequest, "invalid_quantity", "quantity must be between 1 and 99")
+			return
+		}
+		items[i] = &pb.CartItemInput{ProductId: item.ProductID, VariantId: item.VariantID, Quantity: item.Quantity}
+	}
+
+	version, ok := ifMatchVersion(w, r)
+	if !ok {
+		return
+	}
+
+	// Propagate metadata
+	ctx = metadata.AppendToOutgoingContext(ctx, "user-id", fmt.Sprint(userID), "request-id", getRequestID(r.Context()))
+
+	// Call gRPC service
+	resp, err := h.cartClient.ReplaceCart(ctx, &pb.ReplaceCartRequest{
+		UserId:          userID,
+		GuestId:         guestID,
+		Items:           items,
+		ExpectedVersion: version,
+	})
+	if err != nil {
+		handleGRPCError(w, err)
+		return
+	}
+
+	setCartETag(w, resp.Cart)
+	respondJSON(w, http.StatusOK, resp.Cart)
+}
+
+// MergeCarts moves the guest cart into the cart of the user who just signed in.
+// The request carries the user's JWT and the guest cart token the shopper had before signing in.
+func (h *CartHandler) MergeCarts(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
 	userID := getUserIDFromContext(r.Context())
 	if userID == 0 {
 		respondError(w, http.StatusUnauthorized, "unauthorized", "missing user authentication")
 		return
 	}
+	guestID := getGuestIDFromContext(r.Context())
+	if guestID == "" {
+		respondError(w, http.StatusBadRequest, "missing_guest_token", "guest token is required to merge carts")
+		return
+	}
+
+	version, ok := ifMatchVersion(w, r)
+	if !ok {
+		return
+	}
 
 	// Propagate metadata
 	ctx = metadata.AppendToOutgoingContext(ctx, "user-id", fmt.Sprint(userID), "request-id", getRequestID(r.Context()))
 
 	// Call gRPC service
-	resp, err := h.cartClient.ClearCart(ctx, &pb.ClearCartRequest{
-		UserId: userID,
+	resp, err := h.cartClient.MergeCarts(ctx, &pb.MergeCartsRequest{
+		GuestId:         guestID,
+		UserId:          userID,
+		ExpectedVersion: version,
 	})
 	if err != nil {
 		handleGRPCError(w, err)
 		return
 	}
 
+	setCartETag(w, resp.Cart)
 	respondJSON(w, http.StatusOK, resp.Cart)
 }
@@ -67,6 +67,87 @@ func (c ClientMock) ClearCart(ctx context.Context, in *pb.ClearCartRequest, opts
 	}, nil
 }
 
+func (c ClientMock) MergeCarts(ctx context.Context, in *pb.MergeCartsRequest, opts ...grpc.CallOption) (*pb.CartResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &pb.CartResponse{
+		Cart: c.cart,
+	}, nil
+}
+
+func (c ClientMock) GetPricedCart(ctx context.Context, in *pb.GetCartRequest, opts ...grpc.CallOption) (*pb.PricedCartResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &pb.PricedCartResponse{
+		Cart: c.cart,
+	}, nil
+}
+
+func (c ClientMock) ApplyPromoCode(ctx context.Context, in *pb.ApplyPromoCodeRequest, opts ...grpc.CallOption) (*pb.PricedCartResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &pb.PricedCartResponse{Cart: c.cart}, nil
+}
+
+func (c ClientMock) RemovePromoCode(ctx context.Context, in *pb.RemovePromoCodeRequest, opts ...grpc.CallOption) (*pb.PricedCartResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &pb.PricedCartResponse{Cart: c.cart}, nil
+}
+
+func (c ClientMock) AddItems(ctx context.Context, in *pb.AddItemsRequest, opts ...grpc.CallOption) (*pb.CartResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &pb.CartResponse{Cart: c.cart}, nil
+}
+
+func (c ClientMock) ReplaceCart(ctx context.Context, in *pb.ReplaceCartRequest, opts ...grpc.CallOption) (*pb.CartResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &pb.CartResponse{Cart: c.cart}, nil
+}
+
+func (c ClientMock) GetSavedItems(ctx context.Context, in *pb.GetSavedItemsRequest, opts ...grpc.CallOption) (*pb.SavedItemsResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &pb.SavedItemsResponse{Cart: c.cart}, nil
+}
+
+func (c ClientMock) SaveForLater(ctx context.Context, in *pb.SaveForLaterRequest, opts ...grpc.CallOption) (*pb.SavedItemsResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &pb.SavedItemsResponse{Cart: c.cart}, nil
+}
+
+func (c ClientMock) MoveToCart(ctx context.Context, in *pb.MoveToCartRequest, opts ...grpc.CallOption) (*pb.SavedItemsResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &pb.SavedItemsResponse{Cart: c.cart}, nil
+}
+
+func (c ClientMock) RemoveSavedItem(ctx context.Context, in *pb.RemoveSavedItemRequest, opts ...grpc.CallOption) (*pb.SavedItemsResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &pb.SavedItemsResponse{Cart: c.cart}, nil
+}
+
+func (c ClientMock) DeleteSavedList(ctx context.Context, in *pb.DeleteSavedListRequest, opts ...grpc.CallOption) (*pb.SavedItemsResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &pb.SavedItemsResponse{Cart: c.cart}, nil
+}
+
 func TestGetCart_Success(t *testing.T) {
 	clientMock := ClientMock{
 		cart: &pb.Cart{
@@ -635,3 +716,436 @@ func TestClearCart_GRPCError(t *testing.T) {
 		t.Errorf("Expected error code 'internal_error', got '%s'", response.Code)
 	}
 }
+
+// recordingCartClient captures the requests that target one cart line
+type recordingCartClient struct {
+	ClientMock
+	added    *pb.AddCartItemRequest
+	removed  *pb.RemoveItemRequest
+	merged   *pb.MergeCartsRequest
+	replaced *pb.ReplaceCartRequest
+}
+
+func (c *recordingCartClient) ReplaceCart(ctx context.Context, in *pb.ReplaceCartRequest, opts ...grpc.CallOption) (*pb.CartResponse, error) {
+	c.replaced = in
+	return c.ClientMock.ReplaceCart(ctx, in, opts...)
+}
+
+func (c *recordingCartClient) MergeCarts(ctx context.Context, in *pb.MergeCartsRequest, opts ...grpc.CallOption) (*pb.CartResponse, error) {
+	c.merged = in
+	return c.ClientMock.MergeCarts(ctx, in, opts...)
+}
+
+func (c *recordingCartClient) AddItem(ctx context.Context, in *pb.AddCartItemRequest, opts ...grpc.CallOption) (*pb.CartResponse, error) {
+	c.added = in
+	return c.ClientMock.AddItem(ctx, in, opts...)
+}
+
+func (c *recordingCartClient) RemoveItem(ctx context.Context, in *pb.RemoveItemRequest, opts ...grpc.CallOption) (*pb.CartResponse, error) {
+	c.removed = in
+	return c.ClientMock.RemoveItem(ctx, in, opts...)
+}
+
+func TestAddItem_PassesVariant(t *testing.T) {
+	client := &recordingCartClient{ClientMock: ClientMock{cart: &pb.Cart{UserId: 1}}}
+	handler := NewCartHandler(client, 5*time.Second)
+	body, _ := json.Marshal(AddItemRequestDTO{ProductID: 1, VariantID: 2, Quantity: 1})
+	request := httptest.NewRequest("POST", "/items", bytes.NewReader(body))
+	request = request.WithContext(context.WithValue(request.Context(), middleware.UserIDKey, int64(1)))
+	recorder := httptest.NewRecorder()
+
+	handler.AddItem(recorder, request)
+
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d", http.StatusCreated, recorder.Code)
+	}
+	if client.added == nil || client.added.VariantId != 2 {
+		t.Errorf("Expected variant_id 2 to reach the cart service, got %+v", client.added)
+	}
+}
+
+func TestRemoveItem_VariantQuery(t *testing.T) {
+	tests := []struct {
+		target      string
+		wantStatus  int
+		wantVariant int64
+	}{
+		{"/items/1", http.StatusOK, 0},
+		{"/items/1?variant_id=2", http.StatusOK, 2},
+		{"/items/1?variant_id=0", http.StatusBadRequest, 0},
+		{"/items/1?variant_id=abc", http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		client := &recordingCartClient{ClientMock: ClientMock{cart: &pb.Cart{UserId: 1}}}
+		handler := NewCartHandler(client, 5*time.Second)
+		request := httptest.NewRequest("DELETE", tt.target, nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("product_id", "1")
+		ctx := context.WithValue(request.Context(), middleware.UserIDKey, int64(1))
+		request = request.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+		recorder := httptest.NewRecorder()
+
+		handler.RemoveItem(recorder, request)
+
+		if recorder.Code != tt.wantStatus {
+			t.Errorf("%s: expected status code %d, got %d", tt.target, tt.wantStatus, recorder.Code)
+			continue
+		}
+		if tt.wantStatus == http.StatusOK && client.removed.VariantId != tt.wantVariant {
+			t.Errorf("%s: expected variant_id %d, got %d", tt.target, tt.wantVariant, client.removed.VariantId)
+		}
+	}
+}
+
+func TestAddItem_Guest(t *testing.T) {
+	tests := []struct {
+		name      string
+		userID    int64
+		wantUser  int64
+		wantGuest string
+	}{
+		{"guest only", 0, 0, "3f2a9c"},
+		{"signed-in user wins over guest token", 1, 1, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &recordingCartClient{ClientMock: ClientMock{cart: &pb.Cart{GuestId: "3f2a9c"}}}
+			handler := NewCartHandler(client, 5*time.Second)
+			body, _ := json.Marshal(AddItemRequestDTO{ProductID: 1, Quantity: 1})
+			request := httptest.NewRequest("POST", "/items", bytes.NewReader(body))
+			ctx := context.WithValue(request.Context(), middleware.GuestIDKey, "3f2a9c")
+			if tt.userID != 0 {
+				ctx = context.WithValue(ctx, middleware.UserIDKey, tt.userID)
+			}
+			request = request.WithContext(ctx)
+			recorder := httptest.NewRecorder()
+
+			handler.AddItem(recorder, request)
+
+			if recorder.Code != http.StatusCreated {
+				t.Fatalf("Expected status code %d, got %d", http.StatusCreated, recorder.Code)
+			}
+			if client.added.UserId != tt.wantUser || client.added.GuestId != tt.wantGuest {
+				t.Errorf("Expected user %d guest %q, got %+v", tt.wantUser, tt.wantGuest, client.added)
+			}
+		})
+	}
+}
+
+func TestMergeCarts(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     int64
+		guestID    string
+		wantStatus int
+	}{
+		{"user and guest", 1, "3f2a9c", http.StatusOK},
+		{"guest only", 0, "3f2a9c", http.StatusUnauthorized},
+		{"user only", 1, "", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &recordingCartClient{ClientMock: ClientMock{cart: &pb.Cart{UserId: 1}}}
+			handler := NewCartHandler(client, 5*time.Second)
+			request := httptest.NewRequest("POST", "/merge", nil)
+			ctx := request.Context()
+			if tt.userID != 0 {
+				ctx = context.WithValue(ctx, middleware.UserIDKey, tt.userID)
+			}
+			if tt.guestID != "" {
+				ctx = context.WithValue(ctx, middleware.GuestIDKey, tt.guestID)
+			}
+			request = request.WithContext(ctx)
+			recorder := httptest.NewRecorder()
+
+			handler.MergeCarts(recorder, request)
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("Expected status code %d, got %d", tt.wantStatus, recorder.Code)
+			}
+			if tt.wantStatus == http.StatusOK && (client.merged.UserId != 1 || client.merged.GuestId != "3f2a9c") {
+				t.Errorf("Expected merge of guest 3f2a9c into user 1, got %+v", client.merged)
+			}
+		})
+	}
+}
+
+func TestGetCart_ETag(t *testing.T) {
+	handler := NewCartHandler(ClientMock{cart: &pb.Cart{UserId: 1, Version: 7}}, 5*time.Second)
+	request := httptest.NewRequest("GET", "/", nil)
+	request = request.WithContext(context.WithValue(request.Context(), middleware.UserIDKey, int64(1)))
+	recorder := httptest.NewRecorder()
+
+	handler.GetCart(recorder, request)
+
+	if got := recorder.Header().Get("ETag"); got != `"7"` {
+		t.Errorf("Expected ETag %q, got %q", `"7"`, got)
+	}
+}
+
+func TestAddItem_IfMatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		ifMatch     string
+		wantStatus  int
+		wantVersion *int64
+	}{
+		{"no header", "", http.StatusCreated, nil},
+		{"any version", "*", http.StatusCreated, nil},
+		{"version", `"3"`, http.StatusCreated, int64Ptr(3)},
+		{"unquoted", "3", http.StatusBadRequest, nil},
+		{"not a version", `"abc"`, http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &recordingCartClient{ClientMock: ClientMock{cart: &pb.Cart{UserId: 1, Version: 4}}}
+			handler := NewCartHandler(client, 5*time.Second)
+			body, _ := json.Marshal(AddItemRequestDTO{ProductID: 1, Quantity: 1})
+			request := httptest.NewRequest("POST", "/items", bytes.NewReader(body))
+			if tt.ifMatch != "" {
+				request.Header.Set("If-Match", tt.ifMatch)
+			}
+			request = request.WithContext(context.WithValue(request.Context(), middleware.UserIDKey, int64(1)))
+			recorder := httptest.NewRecorder()
+
+			handler.AddItem(recorder, request)
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("Expected status code %d, got %d", tt.wantStatus, recorder.Code)
+			}
+			if tt.wantStatus != http.StatusCreated {
+				return
+			}
+			if got := client.added.ExpectedVersion; (got == nil) != (tt.wantVersion == nil) || (got != nil && *got != *tt.wantVersion) {
+				t.Errorf("Expected version %v, got %v", tt.wantVersion, got)
+			}
+			if got := recorder.Header().Get("ETag"); got != `"4"` {
+				t.Errorf("Expected ETag of the changed cart, got %q", got)
+			}
+		})
+	}
+}
+
+func TestClearCart_StaleIfMatch(t *testing.T) {
+	clientMock := ClientMock{err: status.Error(codes.Aborted, "cart was changed by another request")}
+	handler := NewCartHandler(clientMock, 5*time.Second)
+	request := httptest.NewRequest("DELETE", "/", nil)
+	request.Header.Set("If-Match", `"2"`)
+	request = request.WithContext(context.WithValue(request.Context(), middleware.UserIDKey, int64(1)))
+	recorder := httptest.NewRecorder()
+
+	handler.ClearCart(recorder, request)
+
+	if recorder.Code != http.StatusPreconditionFailed {
+		t.Fatalf("Expected status code %d, got %d", http.StatusPreconditionFailed, recorder.Code)
+	}
+	var response ErrorResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Code != "precondition_failed" {
+		t.Errorf("Expected error code precondition_failed, got %q", response.Code)
+	}
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func TestReplaceCart(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantItems  int
+	}{
+		{"items", `{"items":[{"product_id":1,"quantity":2},{"product_id":2,"variant_id":7,"quantity":1}]}`, http.StatusOK, 2},
+		{"empty cart", `{"items":[]}`, http.StatusOK, 0},
+		{"bad quantity", `{"items":[{"product_id":1,"quantity":2},{"product_id":2,"quantity":100}]}`, http.StatusBadRequest, 0},
+		{"bad product", `{"items":[{"product_id":0,"quantity":1}]}`, http.StatusBadRequest, 0},
+		{"invalid JSON", `{"items":`, http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &recordingCartClient{ClientMock: ClientMock{cart: &pb.Cart{UserId: 1, Version: 3}}}
+			handler := NewCartHandler(client, 5*time.Second)
+			request := httptest.NewRequest("PUT", "/", bytes.NewReader([]byte(tt.body)))
+			request = request.WithContext(context.WithValue(request.Context(), middleware.UserIDKey, int64(1)))
+			recorder := httptest.NewRecorder()
+
+			handler.ReplaceCart(recorder, request)
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("Expected status code %d, got %d", tt.wantStatus, recorder.Code)
+			}
+			if tt.wantStatus != http.StatusOK {
+				if client.replaced != nil {
+					t.Errorf("Expected no call to the cart service, got %+v", client.replaced)
+				}
+				return
+			}
+			if len(client.replaced.Items) != tt.wantItems {
+				t.Errorf("Expected %d items, got %d", tt.wantItems, len(client.replaced.Items))
+			}
+			if got := recorder.Header().Get("ETag"); got != `"3"` {
+				t.Errorf("Expected ETag %q, got %q", `"3"`, got)
+			}
+		})
+	}
+}
+
+func TestIssueGuestToken(t *testing.T) {
+	handler := NewGuestTokenHandler([]byte("test-guest-secret"), time.Hour)
+	recorder := httptest.NewRecorder()
+
+	handler.IssueGuestToken(recorder, httptest.NewRequest("POST", "/guest-token", nil))
+
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d", http.StatusCreated, recorder.Code)
+	}
+	var response GuestTokenResponseDTO
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	guestID, err := middleware.ParseGuestToken([]byte("test-guest-secret"), response.GuestToken)
+	if err != nil || guestID != response.GuestID {
+		t.Errorf("Expected token for guest %q, got %q (err %v)", response.GuestID, guestID, err)
+	}
+}
+
+// pricedCartClient returns a fixed priced cart
+type pricedCartClient struct {
+	ClientMock
+	priced *pb.PricedCartResponse
+}
+
+func (c *pricedCartClient) GetPricedCart(ctx context.Context, in *pb.GetCartRequest, opts ...grpc.CallOption) (*pb.PricedCartResponse, error) {
+	return c.priced, nil
+}
+
+func TestGetPricedCart(t *testing.T) {
+	client := &pricedCartClient{priced: &pb.PricedCartResponse{
+		Cart: &pb.Cart{UserId: 1},
+		Items: []*pb.PricedCartItem{
+			{ProductId: 1, Quantity: 2, ProductName: "Mouse", UnitPriceMinor: 2550, SubtotalMinor: 5100, Currency: "USD",
+				StockStatus: pb.StockStatus_STOCK_STATUS_LOW_STOCK, AvailableQuantity: 3},
+			{ProductId: 2, Quantity: 1, Status: pb.PricedItemStatus_PRICED_ITEM_STATUS_PRODUCT_NOT_FOUND},
+		},
+		TotalMinor: 5100,
+		Currency:   "USD",
+		ItemCount:  2,
+	}}
+	handler := NewCartHandler(client, 5*time.Second)
+	request := httptest.NewRequest("GET", "/priced", nil)
+	request = request.WithContext(context.WithValue(request.Context(), middleware.UserIDKey, int64(1)))
+	recorder := httptest.NewRecorder()
+
+	handler.GetPricedCart(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, recorder.Code)
+	}
+	var response PricedCartResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.TotalMinor != 5100 || response.ItemCount != 2 || len(response.Items) != 2 {
+		t.Errorf("Unexpected priced cart: %+v", response)
+	}
+	if response.Items[0].Status != "available" || response.Items[1].Status != "product_not_found" {
+		t.Errorf("Unexpected line statuses: %q, %q", response.Items[0].Status, response.Items[1].Status)
+	}
+	if response.Items[0].StockStatus != "low_stock" || response.Items[0].AvailableQuantity != 3 {
+		t.Errorf("Unexpected stock: %q, %d", response.Items[0].StockStatus, response.Items[0].AvailableQuantity)
+	}
+	if response.Items[1].StockStatus != "unknown" {
+		t.Errorf("Expected unknown stock, got %q", response.Items[1].StockStatus)
+	}
+}
+
+// promoCartClient records the promo code sent to cart-service
+type promoCartClient struct {
+	ClientMock
+	applyReq *pb.ApplyPromoCodeRequest
+}
+
+func (c *promoCartClient) ApplyPromoCode(ctx context.Context, in *pb.ApplyPromoCodeRequest, opts ...grpc.CallOption) (*pb.PricedCartResponse, error) {
+	c.applyReq = in
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &pb.PricedCartResponse{
+		Cart:          &pb.Cart{UserId: 1, PromoCode: "TEN"},
+		Items:         []*pb.PricedCartItem{{ProductId: 1, Quantity: 2, SubtotalMinor: 5100, DiscountMinor: 510}},
+		SubtotalMinor: 5100,
+		DiscountMinor: 510,
+		TotalMinor:    4590,
+		Currency:      "USD",
+		PromoCode:     "TEN",
+	}, nil
+}
+
+func TestApplyPromoCode(t *testing.T) {
+	client := &promoCartClient{}
+	handler := NewCartHandler(client, 5*time.Second)
+	request := httptest.NewRequest("POST", "/promo", bytes.NewBufferString(`{"code":"ten"}`))
+	request = request.WithContext(context.WithValue(request.Context(), middleware.UserIDKey, int64(1)))
+	recorder := httptest.NewRecorder()
+
+	handler.ApplyPromoCode(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, recorder.Code)
+	}
+	if client.applyReq.Code != "ten" || client.applyReq.UserId != 1 {
+		t.Errorf("Unexpected request sent to cart-service: %+v", client.applyReq)
+	}
+	var response PricedCartResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.PromoCode != "TEN" || response.SubtotalMinor != 5100 || response.DiscountMinor != 510 || response.TotalMinor != 4590 {
+		t.Errorf("Unexpected priced cart: %+v", response)
+	}
+	if response.Items[0].DiscountMinor != 510 {
+		t.Errorf("Expected line discount 510, got %d", response.Items[0].DiscountMinor)
+	}
+}
+
+func TestApplyPromoCode_Rejected(t *testing.T) {
+	client := &promoCartClient{ClientMock: ClientMock{
+		err: status.Error(codes.FailedPrecondition, "promo code cannot be applied: it has expired"),
+	}}
+	handler := NewCartHandler(client, 5*time.Second)
+	request := httptest.NewRequest("POST", "/promo", bytes.NewBufferString(`{"code":"OLD"}`))
+	request = request.WithContext(context.WithValue(request.Context(), middleware.UserIDKey, int64(1)))
+	recorder := httptest.NewRecorder()
+
+	handler.ApplyPromoCode(recorder, request)
+
+	if recorder.Code != http.StatusConflict {
+		t.Errorf("Expected status code %d, got %d", http.StatusConflict, recorder.Code)
+	}
+}
+
+func TestApplyPromoCode_MissingCode(t *testing.T) {
+	client := &promoCartClient{}
+	handler := NewCartHandler(client, 5*time.Second)
+	request := httptest.NewRequest("POST", "/promo", bytes.NewBufferString(`{}`))
+	request = request.WithContext(context.WithValue(request.Context(), middleware.UserIDKey, int64(1)))
+	recorder := httptest.NewRecorder()
+
+	handler.ApplyPromoCode(recorder, request)
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, recorder.Code)
+	}
+	if client.applyReq != nil {
+		t.Error("Expected no call to cart-service")
+	}
+}
@@ -0,0 +1,46 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	pb "github.com/fjod/go_cart/product-service/pkg/proto"
+)
+
+type CategoryResponse struct {
+	ID       int64  `json:"id"`
+	ParentID int64  `json:"parent_id,omitempty"` // absent for a top level category
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+}
+
+type CategoriesResponse struct {
+	Categories []CategoryResponse `json:"categories"` // every parent before its children
+}
+
+// GET /api/v1/categories
+func (h *ProductHandler) ListCategories(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	res, err := h.productClient.ListCategories(ctx, &pb.ListCategoriesRequest{})
+	if err != nil {
+		handleGRPCError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, &CategoriesResponse{Categories: convertProtoCategories(res.Categories)})
+}
+
+func convertProtoCategories(categories []*pb.Category) []CategoryResponse {
+	result := make([]CategoryResponse, len(categories))
+	for i, c := range categories {
+		result[i] = CategoryResponse{
+			ID:       c.Id,
+			ParentID: c.ParentId,
+			Name:     c.Name,
+			Slug:     c.Slug,
+		}
+	}
+	return result
+}
@@ -0,0 +1,54 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	pb "github.com/fjod/go_cart/product-service/pkg/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestListCategories_Success(t *testing.T) {
+	clientMock := ProductClientMock{
+		categories: []*pb.Category{
+			{Id: 1, Name: "Electronics", Slug: "electronics"},
+			{Id: 6, ParentId: 1, Name: "Audio", Slug: "audio"},
+		},
+	}
+	handler := NewProductHandler(clientMock, 5*time.Second)
+	recorder := httptest.NewRecorder()
+
+	handler.ListCategories(recorder, httptest.NewRequest("GET", "/api/v1/categories", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, recorder.Code)
+	}
+	var response CategoriesResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Categories) != 2 {
+		t.Fatalf("Expected 2 categories, got %d", len(response.Categories))
+	}
+	if response.Categories[0].ParentID != 0 || response.Categories[1].ParentID != 1 {
+		t.Errorf("Unexpected parents: %+v", response.Categories)
+	}
+	if response.Categories[1].Slug != "audio" {
+		t.Errorf("Expected slug 'audio', got '%s'", response.Categories[1].Slug)
+	}
+}
+
+func TestListCategories_GRPCError(t *testing.T) {
+	handler := NewProductHandler(ProductClientMock{err: status.Error(codes.Unavailable, "product service down")}, 5*time.Second)
+	recorder := httptest.NewRecorder()
+
+	handler.ListCategories(recorder, httptest.NewRequest("GET", "/api/v1/categories", nil))
+
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status code %d, got %d", http.StatusServiceUnavailable, recorder.Code)
+	}
+}
@@ -3,12 +3,17 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 	"time"
 
 	pb "github.com/fjod/go_cart/checkout-service/pkg/proto"
+	"github.com/go-chi/chi/v5"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 type CheckoutHandler struct {
@@ -25,6 +30,7 @@ func NewCheckoutHandler(client pb.CheckoutServiceClient, timeout time.Duration)
 
 type InitiateCheckoutRequestDTO struct {
 	IdempotencyKey string `json:"idempotency_key"`
+	Async          bool   `json:"async"` // respond right away, follow progress via /checkout/{checkout_id}/events
 }
 
 type CheckoutResponseDTO struct {
@@ -32,6 +38,44 @@ type CheckoutResponseDTO struct {
 	Status     string `json:"status"`
 }
 
+type CheckoutItemDTO struct {
+	ProductID      int64   `json:"product_id"`
+	VariantID      int64   `json:"variant_id,omitempty"`
+	SKU            string  `json:"sku,omitempty"`
+	ProductName    string  `json:"product_name"`
+	Quantity       int32   `json:"quantity"`
+	UnitPrice      float64 `json:"unit_price"` // deprecated, use unit_price_minor
+	Subtotal       float64 `json:"subtotal"`   // deprecated, use subtotal_minor
+	UnitPriceMinor int64   `json:"unit_price_minor"`
+	SubtotalMinor  int64   `json:"subtotal_minor"`
+}
+
+type CheckoutDetailsDTO struct {
+	CheckoutID             string            `json:"checkout_id"`
+	Status                 string            `json:"status"`
+	Items                  []CheckoutItemDTO `json:"items"`
+	TotalAmount            string            `json:"total_amount"`
+	TotalAmountMinor       int64             `json:"total_amount_minor"`
+	Currency               string            `json:"currency"`
+	InventoryReservationID string            `json:"inventory_reservation_id,omitempty"`
+	PaymentID              string            `json:"payment_id,omitempty"`
+	FailureReason          string            `json:"failure_reason,omitempty"`
+	CreatedAt              string            `json:"created_at"`
+	UpdatedAt              string            `json:"updated_at"`
+}
+
+type CheckoutEventDTO struct {
+	CheckoutID    string `json:"checkout_id"`
+	Status        string `json:"status"`
+	FailureReason string `json:"failure_reason,omitempty"`
+	OccurredAt    string `json:"occurred_at"`
+}
+
+type CheckoutListDTO struct {
+	Checkouts     []CheckoutDetailsDTO `json:"checkouts"`
+	NextPageToken string               `json:"next_page_token,omitempty"`
+}
+
 // POST /api/v1/checkout
 func (h *CheckoutHandler) InitiateCheckout(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
@@ -62,18 +106,221 @@ func (h *CheckoutHandler) InitiateCheckout(w http.ResponseWriter, r *http.Reques
 	resp, err := h.checkoutClient.InitiateCheckout(ctx, &pb.InitiateCheckoutRequest{
 		UserId:         userID,
 		IdempotencyKey: req.IdempotencyKey,
+		Async:          req.Async,
 	})
 	if err != nil {
 		handleGRPCError(w, err)
 		return
 	}
 
-	respondJSON(w, http.StatusCreated, CheckoutResponseDTO{
+	code := http.StatusCreated
+	if req.Async {
+		// the saga is still running, point the client at the checkout it can poll or watch
+		w.Header().Set("Location", "/api/v1/checkout/"+resp.CheckoutId)
+		code = http.StatusAccepted
+	}
+	respondJSON(w, code, CheckoutResponseDTO{
 		CheckoutID: resp.CheckoutId,
 		Status:     mapProtoStatusToString(resp.Status),
 	})
 }
 
+// GET /api/v1/checkout/{checkout_id}
+func (h *CheckoutHandler) GetCheckout(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "missing user authentication")
+		return
+	}
+
+	checkoutID := chi.URLParam(r, "checkout_id")
+	if checkoutID == "" {
+		respondError(w, http.StatusBadRequest, "missing_checkout_id", "checkout_id is required")
+		return
+	}
+
+	ctx = metadata.AppendToOutgoingContext(ctx,
+		"user-id", fmt.Sprint(userID),
+		"request-id", getRequestID(r.Context()))
+
+	resp, err := h.checkoutClient.GetCheckout(ctx, &pb.GetCheckoutRequest{
+		CheckoutId: checkoutID,
+		UserId:     userID,
+	})
+	if err != nil {
+		handleGRPCError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, convertProtoCheckout(resp.Checkout))
+}
+
+// GET /api/v1/checkout?page_size=&page_token=
+func (h *CheckoutHandler) ListCheckouts(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "missing user authentication")
+		return
+	}
+
+	var pageSize int64
+	if raw := r.URL.Query().Get("page_size"); raw != "" {
+		var err error
+		pageSize, err = strconv.ParseInt(raw, 10, 32)
+		if err != nil || pageSize < 0 {
+			respondError(w, http.StatusBadRequest, "invalid_page_size", "page_size must be a non-negative integer")
+			return
+		}
+	}
+
+	ctx = metadata.AppendToOutgoingContext(ctx,
+		"user-id", fmt.Sprint(userID),
+		"request-id", getRequestID(r.Context()))
+
+	resp, err := h.checkoutClient.ListCheckouts(ctx, &pb.ListCheckoutsRequest{
+		UserId:    userID,
+		PageSize:  int32(pageSize),
+		PageToken: r.URL.Query().Get("page_token"),
+	})
+	if err != nil {
+		handleGRPCError(w, err)
+		return
+	}
+
+	dtos := make([]CheckoutDetailsDTO, 0, len(resp.Checkouts))
+	for _, c := range resp.Checkouts {
+		dtos = append(dtos, convertProtoCheckout(c))
+	}
+
+	respondJSON(w, http.StatusOK, CheckoutListDTO{
+		Checkouts:     dtos,
+		NextPageToken: resp.NextPageToken,
+	})
+}
+
+// GET /api/v1/checkout/{checkout_id}/events
+// Server-sent events with the current status and every transition until COMPLETED or FAILED.
+func (h *CheckoutHandler) WatchCheckout(w http.ResponseWriter, r *http.Request) {
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "missing user authentication")
+		return
+	}
+
+	checkoutID := chi.URLParam(r, "checkout_id")
+	if checkoutID == "" {
+		respondError(w, http.StatusBadRequest, "missing_checkout_id", "checkout_id is required")
+		return
+	}
+
+	// no h.timeout here: the stream lives as long as the checkout or the client connection
+	ctx := metadata.AppendToOutgoingContext(r.Context(),
+		"user-id", fmt.Sprint(userID),
+		"request-id", getRequestID(r.Context()))
+
+	stream, err := h.checkoutClient.WatchCheckout(ctx, &pb.WatchCheckoutRequest{
+		CheckoutId: checkoutID,
+		UserId:     userID,
+	})
+	if err != nil {
+		handleGRPCError(w, err)
+		return
+	}
+
+	// wait for the first event before committing to 200, so NOT_FOUND and friends still map to HTTP errors
+	event, err := stream.Recv()
+	if err != nil {
+		handleGRPCError(w, err)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	// the server WriteTimeout would cut the stream in the middle of a checkout
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	for {
+		if err := writeSSE(w, "status", convertProtoCheckoutEvent(event)); err != nil {
+			return
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+
+		event, err = stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			if r.Context().Err() == nil {
+				// headers are already sent, report the failure as an event
+				_ = writeSSE(w, "error", ErrorResponse{Error: status.Convert(err).Message(), Code: "stream_failed"})
+				_ = rc.Flush()
+			}
+			return
+		}
+	}
+}
+
+func writeSSE(w http.ResponseWriter, event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+	return err
+}
+
+func convertProtoCheckoutEvent(e *pb.CheckoutEvent) CheckoutEventDTO {
+	return CheckoutEventDTO{
+		CheckoutID:    e.CheckoutId,
+		Status:        mapProtoStatusToString(e.Status),
+		FailureReason: e.FailureReason,
+		OccurredAt:    e.OccurredAt,
+	}
+}
+
+func convertProtoCheckout(c *pb.Checkout) CheckoutDetailsDTO {
+	items := make([]CheckoutItemDTO, 0, len(c.Items))
+	for _, item := range c.Items {
+		items = append(items, CheckoutItemDTO{
+			ProductID:      item.ProductId,
+			VariantID:      item.VariantId,
+			SKU:            item.Sku,
+			ProductName:    item.ProductName,
+			Quantity:       item.Quantity,
+			UnitPrice:      item.UnitPrice,
+			Subtotal:       item.Subtotal,
+			UnitPriceMinor: item.UnitPriceMinor,
+			SubtotalMinor:  item.SubtotalMinor,
+		})
+	}
+
+	return CheckoutDetailsDTO{
+		CheckoutID:             c.CheckoutId,
+		Status:                 mapProtoStatusToString(c.Status),
+		Items:                  items,
+		TotalAmount:            c.TotalAmount,
+		TotalAmountMinor:       c.TotalAmountMinor,
+		Currency:               c.Currency,
+		InventoryReservationID: c.InventoryReservationId,
+		PaymentID:              c.PaymentId,
+		FailureReason:          c.FailureReason,
+		CreatedAt:              c.CreatedAt,
+		UpdatedAt:              c.UpdatedAt,
+	}
+}
+
 func mapProtoStatusToString(status pb.CheckoutStatus) string {
 	switch status {
 	case pb.CheckoutStatus_CHECKOUT_STATUS_INITIATED:
@@ -84,6 +331,8 @@ func mapProtoStatusToString(status pb.CheckoutStatus) string {
 		return "PAYMENT_PENDING"
 	case pb.CheckoutStatus_CHECKOUT_STATUS_PAYMENT_COMPLETED:
 		return "PAYMENT_COMPLETED"
+	case pb.CheckoutStatus_CHECKOUT_STATUS_INVENTORY_CONFIRMED:
+		return "INVENTORY_CONFIRMED"
 	case pb.CheckoutStatus_CHECKOUT_STATUS_COMPLETED:
 		return "COMPLETED"
 	case pb.CheckoutStatus_CHECKOUT_STATUS_FAILED:
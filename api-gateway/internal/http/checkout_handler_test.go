@@ -0,0 +1,347 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/fjod/go_cart/checkout-service/pkg/proto"
+	"github.com/go-chi/chi/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// --- Mock ---
+
+type CheckoutClientMock struct {
+	checkout      *pb.Checkout
+	checkouts     []*pb.Checkout
+	nextPageToken string
+	err           error
+	lastList      *pb.ListCheckoutsRequest
+	events        []*pb.CheckoutEvent // sent by WatchCheckout, then streamErr (io.EOF when nil)
+	streamErr     error
+}
+
+func (m *CheckoutClientMock) InitiateCheckout(ctx context.Context, in *pb.InitiateCheckoutRequest, opts ...grpc.CallOption) (*pb.InitiateCheckoutResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if in.Async {
+		return &pb.InitiateCheckoutResponse{CheckoutId: "checkout-uuid-1", Status: pb.CheckoutStatus_CHECKOUT_STATUS_INITIATED}, nil
+	}
+	return &pb.InitiateCheckoutResponse{CheckoutId: "checkout-uuid-1", Status: pb.CheckoutStatus_CHECKOUT_STATUS_COMPLETED}, nil
+}
+
+func (m *CheckoutClientMock) WatchCheckout(ctx context.Context, in *pb.WatchCheckoutRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[pb.CheckoutEvent], error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &checkoutEventStreamMock{events: m.events, err: m.streamErr}, nil
+}
+
+func (m *CheckoutClientMock) GetCheckoutHistory(ctx context.Context, in *pb.GetCheckoutHistoryRequest, opts ...grpc.CallOption) (*pb.GetCheckoutHistoryResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &pb.GetCheckoutHistoryResponse{CheckoutId: in.CheckoutId}, nil
+}
+
+type checkoutEventStreamMock struct {
+	grpc.ClientStream
+	events []*pb.CheckoutEvent
+	err    error
+}
+
+func (s *checkoutEventStreamMock) Recv() (*pb.CheckoutEvent, error) {
+	if len(s.events) == 0 {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	}
+	event := s.events[0]
+	s.events = s.events[1:]
+	return event, nil
+}
+
+func (m *CheckoutClientMock) GetCheckout(ctx context.Context, in *pb.GetCheckoutRequest, opts ...grpc.CallOption) (*pb.GetCheckoutResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &pb.GetCheckoutResponse{Checkout: m.checkout}, nil
+}
+
+func (m *CheckoutClientMock) ListCheckouts(ctx context.Context, in *pb.ListCheckoutsRequest, opts ...grpc.CallOption) (*pb.ListCheckoutsResponse, error) {
+	m.lastList = in
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &pb.ListCheckoutsResponse{Checkouts: m.checkouts, NextPageToken: m.nextPageToken}, nil
+}
+
+// --- helper ---
+
+func withCheckoutID(r *http.Request, id string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("checkout_id", id)
+	return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+}
+
+// --- GetCheckout tests ---
+
+func TestGetCheckout_Success(t *testing.T) {
+	mock := &CheckoutClientMock{
+		checkout: &pb.Checkout{
+			CheckoutId:       "checkout-uuid-1",
+			Status:           pb.CheckoutStatus_CHECKOUT_STATUS_FAILED,
+			TotalAmount:      "59.98",
+			TotalAmountMinor: 5998,
+			Currency:         "USD",
+			FailureReason:    "payment failed: NO_FUNDS",
+			Items: []*pb.CheckoutItem{
+				{ProductId: 2, ProductName: "Mouse", Quantity: 2, UnitPrice: 29.99, Subtotal: 59.98, UnitPriceMinor: 2999, SubtotalMinor: 5998},
+			},
+		},
+	}
+
+	handler := NewCheckoutHandler(mock, 5*time.Second)
+	recorder := httptest.NewRecorder()
+	request := withUser(httptest.NewRequest("GET", "/api/v1/checkout/checkout-uuid-1", nil))
+	request = withCheckoutID(request, "checkout-uuid-1")
+
+	handler.GetCheckout(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, recorder.Code)
+	}
+
+	var response CheckoutDetailsDTO
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.Status != "FAILED" {
+		t.Errorf("expected status FAILED, got '%s'", response.Status)
+	}
+	if response.FailureReason != "payment failed: NO_FUNDS" {
+		t.Errorf("unexpected failure_reason '%s'", response.FailureReason)
+	}
+	if len(response.Items) != 1 || response.Items[0].ProductName != "Mouse" {
+		t.Errorf("expected one Mouse item, got %+v", response.Items)
+	}
+	if response.TotalAmountMinor != 5998 || response.Items[0].SubtotalMinor != 5998 {
+		t.Errorf("expected minor amounts 5998, got %d and %d", response.TotalAmountMinor, response.Items[0].SubtotalMinor)
+	}
+}
+
+func TestGetCheckout_NotFound(t *testing.T) {
+	mock := &CheckoutClientMock{err: status.Error(codes.NotFound, "checkout not found")}
+
+	handler := NewCheckoutHandler(mock, 5*time.Second)
+	recorder := httptest.NewRecorder()
+	request := withUser(httptest.NewRequest("GET", "/api/v1/checkout/unknown", nil))
+	request = withCheckoutID(request, "unknown")
+
+	handler.GetCheckout(recorder, request)
+
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("expected %d, got %d", http.StatusNotFound, recorder.Code)
+	}
+}
+
+func TestGetCheckout_Unauthorized(t *testing.T) {
+	handler := NewCheckoutHandler(&CheckoutClientMock{}, 5*time.Second)
+	recorder := httptest.NewRecorder()
+	request := withCheckoutID(httptest.NewRequest("GET", "/api/v1/checkout/checkout-uuid-1", nil), "checkout-uuid-1")
+
+	handler.GetCheckout(recorder, request)
+
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("expected %d, got %d", http.StatusUnauthorized, recorder.Code)
+	}
+}
+
+// --- ListCheckouts tests ---
+
+func TestListCheckouts_Success(t *testing.T) {
+	mock := &CheckoutClientMock{
+		checkouts: []*pb.Checkout{
+			{CheckoutId: "checkout-uuid-1", Status: pb.CheckoutStatus_CHECKOUT_STATUS_COMPLETED},
+		},
+		nextPageToken: "next",
+	}
+
+	handler := NewCheckoutHandler(mock, 5*time.Second)
+	recorder := httptest.NewRecorder()
+	request := withUser(httptest.NewRequest("GET", "/api/v1/checkout?page_size=1&page_token=abc", nil))
+
+	handler.ListCheckouts(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, recorder.Code)
+	}
+	if mock.lastList.PageSize != 1 || mock.lastList.PageToken != "abc" || mock.lastList.UserId != 1 {
+		t.Errorf("unexpected request forwarded: %+v", mock.lastList)
+	}
+
+	var response CheckoutListDTO
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(response.Checkouts) != 1 || response.Checkouts[0].Status != "COMPLETED" {
+		t.Errorf("unexpected checkouts %+v", response.Checkouts)
+	}
+	if response.NextPageToken != "next" {
+		t.Errorf("expected next_page_token 'next', got '%s'", response.NextPageToken)
+	}
+}
+
+func TestListCheckouts_EmptyList(t *testing.T) {
+	handler := NewCheckoutHandler(&CheckoutClientMock{}, 5*time.Second)
+	recorder := httptest.NewRecorder()
+	request := withUser(httptest.NewRequest("GET", "/api/v1/checkout", nil))
+
+	handler.ListCheckouts(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, recorder.Code)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(recorder.Body).Decode(&raw); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if string(raw["checkouts"]) != "[]" {
+		t.Errorf("expected empty JSON array, got %s", raw["checkouts"])
+	}
+}
+
+func TestListCheckouts_InvalidPageSize(t *testing.T) {
+	handler := NewCheckoutHandler(&CheckoutClientMock{}, 5*time.Second)
+	recorder := httptest.NewRecorder()
+	request := withUser(httptest.NewRequest("GET", "/api/v1/checkout?page_size=abc", nil))
+
+	handler.ListCheckouts(recorder, request)
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("expected %d, got %d", http.StatusBadRequest, recorder.Code)
+	}
+}
+
+// --- InitiateCheckout tests ---
+
+func TestInitiateCheckout_Async(t *testing.T) {
+	handler := NewCheckoutHandler(&CheckoutClientMock{}, 5*time.Second)
+	recorder := httptest.NewRecorder()
+	body := strings.NewReader(`{"idempotency_key":"key-1","async":true}`)
+	request := withUser(httptest.NewRequest("POST", "/api/v1/checkout", body))
+
+	handler.InitiateCheckout(recorder, request)
+
+	if recorder.Code != http.StatusAccepted {
+		t.Fatalf("expected %d, got %d", http.StatusAccepted, recorder.Code)
+	}
+	if location := recorder.Header().Get("Location"); location != "/api/v1/checkout/checkout-uuid-1" {
+		t.Errorf("unexpected Location '%s'", location)
+	}
+
+	var response CheckoutResponseDTO
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.Status != "INITIATED" {
+		t.Errorf("expected status INITIATED, got '%s'", response.Status)
+	}
+}
+
+func TestInitiateCheckout_QueueFull(t *testing.T) {
+	mock := &CheckoutClientMock{err: status.Error(codes.ResourceExhausted, "too many checkouts in progress")}
+	handler := NewCheckoutHandler(mock, 5*time.Second)
+	recorder := httptest.NewRecorder()
+	body := strings.NewReader(`{"idempotency_key":"key-1","async":true}`)
+	request := withUser(httptest.NewRequest("POST", "/api/v1/checkout", body))
+
+	handler.InitiateCheckout(recorder, request)
+
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Errorf("expected %d, got %d", http.StatusTooManyRequests, recorder.Code)
+	}
+}
+
+// --- WatchCheckout tests ---
+
+func TestWatchCheckout_StreamsEvents(t *testing.T) {
+	mock := &CheckoutClientMock{
+		events: []*pb.CheckoutEvent{
+			{CheckoutId: "checkout-uuid-1", Status: pb.CheckoutStatus_CHECKOUT_STATUS_INITIATED},
+			{CheckoutId: "checkout-uuid-1", Status: pb.CheckoutStatus_CHECKOUT_STATUS_COMPLETED},
+		},
+	}
+
+	handler := NewCheckoutHandler(mock, 5*time.Second)
+	recorder := httptest.NewRecorder()
+	request := withUser(httptest.NewRequest("GET", "/api/v1/checkout/checkout-uuid-1/events", nil))
+	request = withCheckoutID(request, "checkout-uuid-1")
+
+	handler.WatchCheckout(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, recorder.Code)
+	}
+	if ct := recorder.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("expected text/event-stream, got '%s'", ct)
+	}
+
+	body := recorder.Body.String()
+	if strings.Count(body, "event: status\n") != 2 {
+		t.Errorf("expected two status events, got %q", body)
+	}
+	if !strings.Contains(body, `"status":"INITIATED"`) || !strings.Contains(body, `"status":"COMPLETED"`) {
+		t.Errorf("unexpected events %q", body)
+	}
+	if !recorder.Flushed {
+		t.Error("expected events to be flushed")
+	}
+}
+
+func TestWatchCheckout_NotFound(t *testing.T) {
+	mock := &CheckoutClientMock{streamErr: status.Error(codes.NotFound, "checkout not found")}
+
+	handler := NewCheckoutHandler(mock, 5*time.Second)
+	recorder := httptest.NewRecorder()
+	request := withUser(httptest.NewRequest("GET", "/api/v1/checkout/unknown/events", nil))
+	request = withCheckoutID(request, "unknown")
+
+	handler.WatchCheckout(recorder, request)
+
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("expected %d, got %d", http.StatusNotFound, recorder.Code)
+	}
+}
+
+func TestWatchCheckout_StreamBroken(t *testing.T) {
+	mock := &CheckoutClientMock{
+		events:    []*pb.CheckoutEvent{{CheckoutId: "checkout-uuid-1", Status: pb.CheckoutStatus_CHECKOUT_STATUS_PAYMENT_PENDING}},
+		streamErr: status.Error(codes.Unavailable, "connection lost"),
+	}
+
+	handler := NewCheckoutHandler(mock, 5*time.Second)
+	recorder := httptest.NewRecorder()
+	request := withUser(httptest.NewRequest("GET", "/api/v1/checkout/checkout-uuid-1/events", nil))
+	request = withCheckoutID(request, "checkout-uuid-1")
+
+	handler.WatchCheckout(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, recorder.Code)
+	}
+	if !strings.Contains(recorder.Body.String(), "event: error\n") {
+		t.Errorf("expected an error event, got %q", recorder.Body.String())
+	}
+}
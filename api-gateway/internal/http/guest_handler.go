@@ -0,0 +1,41 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	m "github.com/fjod/go_cart/api-gateway/internal/middleware"
+)
+
+// GuestTokenHandler hands out the guest cart tokens anonymous shoppers keep their cart under
+type GuestTokenHandler struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewGuestTokenHandler(secret []byte, ttl time.Duration) *GuestTokenHandler {
+	return &GuestTokenHandler{
+		secret: secret,
+		ttl:    ttl,
+	}
+}
+
+type GuestTokenResponseDTO struct {
+	GuestToken string `json:"guest_token"` // send back in the X-Guest-Token header
+	GuestID    string `json:"guest_id"`
+	ExpiresAt  string `json:"expires_at"`
+}
+
+func (h *GuestTokenHandler) IssueGuestToken(w http.ResponseWriter, r *http.Request) {
+	token, guestID, expiresAt, err := m.IssueGuestToken(h.secret, h.ttl)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "internal_error", "failed to issue guest token")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, GuestTokenResponseDTO{
+		GuestToken: token,
+		GuestID:    guestID,
+		ExpiresAt:  expiresAt.UTC().Format(time.RFC3339),
+	})
+}
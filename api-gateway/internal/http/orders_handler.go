@@ -25,19 +25,27 @@ func NewOrdersHandler(client pb.OrdersServiceClient, timeout time.Duration) *Ord
 
 type OrderItemDTO struct {
 	ProductID   int64   `json:"product_id"`
+	VariantID   int64   `json:"variant_id,omitempty"`
+	SKU         string  `json:"sku,omitempty"`
 	ProductName string  `json:"product_name"`
 	Quantity    int32   `json:"quantity"`
-	Price       float64 `json:"price"`
+	Price       float64 `json:"price"`       // deprecated, use price_minor
+	PriceMinor  int64   `json:"price_minor"` // minor units of the order currency, e.g. cents
+	// DiscountMinor is the share of the promo code discount
+	DiscountMinor int64 `json:"discount_minor,omitempty"`
 }
 
 type OrderResponseDTO struct {
-	ID          string         `json:"id"`
-	CheckoutID  string         `json:"checkout_id"`
-	TotalAmount float64        `json:"total_amount"`
-	Currency    string         `json:"currency"`
-	Status      string         `json:"status"`
-	Items       []OrderItemDTO `json:"items"`
-	CreatedAt   string         `json:"created_at"`
+	ID               string         `json:"id"`
+	CheckoutID       string         `json:"checkout_id"`
+	TotalAmount      float64        `json:"total_amount"`       // deprecated, use total_amount_minor
+	TotalAmountMinor int64          `json:"total_amount_minor"` // what was paid, after the discount
+	Currency         string         `json:"currency"`
+	PromoCode        string         `json:"promo_code,omitempty"`
+	DiscountMinor    int64          `json:"discount_amount_minor,omitempty"`
+	Status           string         `json:"status"`
+	Items            []OrderItemDTO `json:"items"`
+	CreatedAt        string         `json:"created_at"`
 }
 
 // GET /api/v1/orders
@@ -79,23 +87,30 @@ func convertProtoOrder(o *pb.Order) OrderResponseDTO {
 		dtoItems = make([]OrderItemDTO, 0, len(o.Items))
 		for _, item := range o.Items {
 			orderItem := OrderItemDTO{
-				ProductID:   item.ProductId,
-				ProductName: item.ProductName,
-				Quantity:    item.Quantity,
-				Price:       item.Price,
+				ProductID:     item.ProductId,
+				VariantID:     item.VariantId,
+				SKU:           item.Sku,
+				ProductName:   item.ProductName,
+				Quantity:      item.Quantity,
+				Price:         item.Price,
+				PriceMinor:    item.PriceMinor,
+				DiscountMinor: item.DiscountMinor,
 			}
 			dtoItems = append(dtoItems, orderItem)
 		}
 	}
 
 	dto := OrderResponseDTO{
-		ID:          o.Id,
-		CheckoutID:  o.CheckoutId,
-		TotalAmount: o.TotalAmount,
-		Currency:    o.Currency,
-		Status:      o.Status,
-		Items:       dtoItems,
-		CreatedAt:   o.CreatedAt,
+		ID:               o.Id,
+		CheckoutID:       o.CheckoutId,
+		TotalAmount:      o.TotalAmount,
+		TotalAmountMinor: o.TotalAmountMinor,
+		Currency:         o.Currency,
+		PromoCode:        o.PromoCode,
+		DiscountMinor:    o.DiscountAmountMinor,
+		Status:           o.Status,
+		Items:            dtoItems,
+		CreatedAt:        o.CreatedAt,
 	}
 	return dto
 }
@@ -306,14 +306,15 @@ func TestGetOrder_GRPCErrors(t *testing.T) {
 
 func TestConvertProtoOrder_AllFields(t *testing.T) {
 	order := &pb.Order{
-		Id:          "abc-123",
-		CheckoutId:  "chk-456",
-		UserId:      "1",
-		TotalAmount: 399.98,
-		Currency:    "USD",
-		Status:      "CONFIRMED",
+		Id:               "abc-123",
+		CheckoutId:       "chk-456",
+		UserId:           "1",
+		TotalAmount:      399.98,
+		TotalAmountMinor: 39998,
+		Currency:         "USD",
+		Status:           "CONFIRMED",
 		Items: []*pb.OrderItem{
-			{ProductId: 4, ProductName: "Monitor", Quantity: 2, Price: 199.99},
+			{ProductId: 4, ProductName: "Monitor", Quantity: 2, Price: 199.99, PriceMinor: 19999},
 		},
 		CreatedAt: "2026-02-12T10:00:00Z",
 	}
@@ -329,6 +330,9 @@ func TestConvertProtoOrder_AllFields(t *testing.T) {
 	if dto.TotalAmount != 399.98 {
 		t.Errorf("TotalAmount: expected 399.98, got %f", dto.TotalAmount)
 	}
+	if dto.TotalAmountMinor != 39998 {
+		t.Errorf("TotalAmountMinor: expected 39998, got %d", dto.TotalAmountMinor)
+	}
 	if dto.Currency != "USD" {
 		t.Errorf("Currency: expected 'USD', got '%s'", dto.Currency)
 	}
@@ -0,0 +1,214 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	pb "github.com/fjod/go_cart/cart-service/pkg/proto"
+	"google.golang.org/grpc/metadata"
+)
+
+type PricedCartItemResponse struct {
+	ProductID      int64  `json:"product_id"`
+	VariantID      int64  `json:"variant_id,omitempty"`
+	SKU            string `json:"sku,omitempty"`
+	Quantity       int32  `json:"quantity"`
+	AddedAt        string `json:"added_at"`
+	ProductName    string `json:"product_name,omitempty"`
+	VariantName    string `json:"variant_name,omitempty"`
+	ImageURL       string `json:"image_url,omitempty"`
+	UnitPriceMinor int64  `json:"unit_price_minor"` // minor units of currency, e.g. cents
+	SubtotalMinor  int64  `json:"subtotal_minor"`
+	DiscountMinor  int64  `json:"discount_minor,omitempty"` // share of the promo code discount
+	Currency       string `json:"currency,omitempty"`
+	Status         string `json:"status"` // available, product_not_found or unavailable
+	// StockStatus is in_stock, low_stock or insufficient, or unknown when inventory-service could not be asked
+	StockStatus       string `json:"stock_status"`
+	AvailableQuantity int32  `json:"available_quantity"`
+}
+
+// PricedCartResponse is the cart with current prices, lines that are not available are left out of the totals.
+// total_minor is subtotal_minor less the promo code discount.
+type PricedCartResponse struct {
+	ID            string                   `json:"id,omitempty"`
+	UserID        int64                    `json:"user_id,omitempty"`
+	GuestID       string                   `json:"guest_id,omitempty"`
+	Items         []PricedCartItemResponse `json:"items"`
+	SubtotalMinor int64                    `json:"subtotal_minor"`
+	DiscountMinor int64                    `json:"discount_minor"`
+	TotalMinor    int64                    `json:"total_minor"`
+	Currency      string                   `json:"currency"`
+	ItemCount     int32                    `json:"item_count"`
+	PromoCode     string                   `json:"promo_code,omitempty"`
+	PromoError    string                   `json:"promo_error,omitempty"` // why the stored code gives no discount
+	UpdatedAt     string                   `json:"updated_at,omitempty"`
+	Version       int64                    `json:"version"`
+}
+
+type PromoCodeRequestDTO struct {
+	Code string `json:"code"`
+}
+
+func (h *CartHandler) GetPricedCart(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	userID, guestID := getCartOwnerFromContext(r.Context())
+	if userID == 0 && guestID == "" {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "missing user authentication")
+		return
+	}
+	// Propagate metadata
+	ctx = metadata.AppendToOutgoingContext(ctx, "user-id", fmt.Sprint(userID), "request-id", getRequestID(r.Context()))
+
+	// Call gRPC service
+	resp, err := h.cartClient.GetPricedCart(ctx, &pb.GetCartRequest{
+		UserId:  userID,
+		GuestId: guestID,
+	})
+	if err != nil {
+		handleGRPCError(w, err)
+		return
+	}
+
+	setCartETag(w, resp.Cart)
+	respondJSON(w, http.StatusOK, toPricedCartResponse(resp))
+}
+
+// POST /api/v1/cart/promo
+func (h *CartHandler) ApplyPromoCode(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	userID, guestID := getCartOwnerFromContext(r.Context())
+	if userID == 0 && guestID == "" {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "missing user authentication")
+		return
+	}
+
+	var req PromoCodeRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_request", "invalid JSON body")
+		return
+	}
+	if req.Code == "" {
+		respondError(w, http.StatusBadRequest, "invalid_request", "code is required")
+		return
+	}
+
+	version, ok := ifMatchVersion(w, r)
+	if !ok {
+		return
+	}
+
+	// Propagate metadata
+	ctx = metadata.AppendToOutgoingContext(ctx, "user-id", fmt.Sprint(userID), "request-id", getRequestID(r.Context()))
+
+	resp, err := h.cartClient.ApplyPromoCode(ctx, &pb.ApplyPromoCodeRequest{
+		UserId:          userID,
+		GuestId:         guestID,
+		Code:            req.Code,
+		ExpectedVersion: version,
+	})
+	if err != nil {
+		handleGRPCError(w, err)
+		return
+	}
+
+	setCartETag(w, resp.Cart)
+	respondJSON(w, http.StatusOK, toPricedCartResponse(resp))
+}
+
+// DELETE /api/v1/cart/promo
+func (h *CartHandler) RemovePromoCode(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	userID, guestID := getCartOwnerFromContext(r.Context())
+	if userID == 0 && guestID == "" {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "missing user authentication")
+		return
+	}
+	version, ok := ifMatchVersion(w, r)
+	if !ok {
+		return
+	}
+
+	// Propagate metadata
+	ctx = metadata.AppendToOutgoingContext(ctx, "user-id", fmt.Sprint(userID), "request-id", getRequestID(r.Context()))
+
+	resp, err := h.cartClient.RemovePromoCode(ctx, &pb.RemovePromoCodeRequest{
+		UserId:          userID,
+		GuestId:         guestID,
+		ExpectedVersion: version,
+	})
+	if err != nil {
+		handleGRPCError(w, err)
+		return
+	}
+
+	setCartETag(w, resp.Cart)
+	respondJSON(w, http.StatusOK, toPricedCartResponse(resp))
+}
+
+func toPricedCartResponse(resp *pb.PricedCartResponse) *PricedCartResponse {
+	out := &PricedCartResponse{
+		Items:         make([]PricedCartItemResponse, len(resp.Items)),
+		SubtotalMinor: resp.SubtotalMinor,
+		DiscountMinor: resp.DiscountMinor,
+		TotalMinor:    resp.TotalMinor,
+		Currency:      resp.Currency,
+		ItemCount:     resp.ItemCount,
+		PromoCode:     resp.PromoCode,
+		PromoError:    resp.PromoError,
+	}
+	if c := resp.Cart; c != nil {
+		out.ID, out.UserID, out.GuestID, out.UpdatedAt, out.Version = c.Id, c.UserId, c.GuestId, c.UpdatedAt, c.Version
+	}
+	for i, item := range resp.Items {
+		out.Items[i] = PricedCartItemResponse{
+			ProductID:         item.ProductId,
+			VariantID:         item.VariantId,
+			SKU:               item.Sku,
+			Quantity:          item.Quantity,
+			AddedAt:           item.AddedAt,
+			ProductName:       item.ProductName,
+			VariantName:       item.VariantName,
+			ImageURL:          item.ImageUrl,
+			UnitPriceMinor:    item.UnitPriceMinor,
+			SubtotalMinor:     item.SubtotalMinor,
+			DiscountMinor:     item.DiscountMinor,
+			Currency:          item.Currency,
+			Status:            pricedItemStatus(item.Status),
+			StockStatus:       stockStatus(item.StockStatus),
+			AvailableQuantity: item.AvailableQuantity,
+		}
+	}
+	return out
+}
+
+func pricedItemStatus(s pb.PricedItemStatus) string {
+	switch s {
+	case pb.PricedItemStatus_PRICED_ITEM_STATUS_PRODUCT_NOT_FOUND:
+		return "product_not_found"
+	case pb.PricedItemStatus_PRICED_ITEM_STATUS_UNAVAILABLE:
+		return "unavailable"
+	default:
+		return "available"
+	}
+}
+
+func stockStatus(s pb.StockStatus) string {
+	switch s {
+	case pb.StockStatus_STOCK_STATUS_IN_STOCK:
+		return "in_stock"
+	case pb.StockStatus_STOCK_STATUS_LOW_STOCK:
+		return "low_stock"
+	case pb.StockStatus_STOCK_STATUS_INSUFFICIENT:
+		return "insufficient"
+	default:
+		return "unknown"
+	}
+}
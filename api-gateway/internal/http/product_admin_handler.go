@@ -0,0 +1,107 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	pb "github.com/fjod/go_cart/product-service/pkg/proto"
+	"github.com/go-chi/chi/v5"
+)
+
+// ProductRequestDTO is the body of the admin create and update routes, update replaces every field
+type ProductRequestDTO struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	PriceMinor  int64  `json:"price_minor"`
+	Currency    string `json:"currency"`
+	ImageURL    string `json:"image_url"`
+}
+
+// POST /api/v1/admin/products
+func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	var req ProductRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_request", "invalid JSON body")
+		return
+	}
+
+	resp, err := h.productClient.CreateProduct(ctx, &pb.CreateProductRequest{
+		Name:        req.Name,
+		Description: req.Description,
+		PriceMinor:  req.PriceMinor,
+		Currency:    req.Currency,
+		ImageUrl:    req.ImageURL,
+	})
+	if err != nil {
+		handleGRPCError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, convertProtoProduct(resp.Product))
+}
+
+// PUT /api/v1/admin/products/{product_id}
+func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	productID, ok := productIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req ProductRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_request", "invalid JSON body")
+		return
+	}
+
+	resp, err := h.productClient.UpdateProduct(ctx, &pb.UpdateProductRequest{
+		Id:          productID,
+		Name:        req.Name,
+		Description: req.Description,
+		PriceMinor:  req.PriceMinor,
+		Currency:    req.Currency,
+		ImageUrl:    req.ImageURL,
+	})
+	if err != nil {
+		handleGRPCError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, convertProtoProduct(resp.Product))
+}
+
+// DELETE /api/v1/admin/products/{product_id}
+// Archives the product: it leaves the catalog, carts and orders holding it keep working.
+func (h *ProductHandler) ArchiveProduct(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	productID, ok := productIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	resp, err := h.productClient.ArchiveProduct(ctx, &pb.ArchiveProductRequest{Id: productID})
+	if err != nil {
+		handleGRPCError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, convertProtoProduct(resp.Product))
+}
+
+func productIDParam(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	productID, err := strconv.ParseInt(chi.URLParam(r, "product_id"), 10, 64)
+	if err != nil || productID <= 0 {
+		respondError(w, http.StatusBadRequest, "invalid_product_id", "product_id must be a positive integer")
+		return 0, false
+	}
+	return productID, true
+}
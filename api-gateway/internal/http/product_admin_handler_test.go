@@ -0,0 +1,146 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/fjod/go_cart/product-service/pkg/proto"
+	"github.com/go-chi/chi/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ProductAdminClientMock captures the admin requests sent to product-service
+type ProductAdminClientMock struct {
+	pb.ProductServiceClient
+	product *pb.Product
+	err     error
+
+	createReq  *pb.CreateProductRequest
+	updateReq  *pb.UpdateProductRequest
+	archiveReq *pb.ArchiveProductRequest
+}
+
+func (m *ProductAdminClientMock) CreateProduct(_ context.Context, req *pb.CreateProductRequest, _ ...grpc.CallOption) (*pb.CreateProductResponse, error) {
+	m.createReq = req
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &pb.CreateProductResponse{Product: m.product}, nil
+}
+
+func (m *ProductAdminClientMock) UpdateProduct(_ context.Context, req *pb.UpdateProductRequest, _ ...grpc.CallOption) (*pb.UpdateProductResponse, error) {
+	m.updateReq = req
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &pb.UpdateProductResponse{Product: m.product}, nil
+}
+
+func (m *ProductAdminClientMock) ArchiveProduct(_ context.Context, req *pb.ArchiveProductRequest, _ ...grpc.CallOption) (*pb.ArchiveProductResponse, error) {
+	m.archiveReq = req
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &pb.ArchiveProductResponse{Product: m.product}, nil
+}
+
+func withProductID(r *http.Request, id string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("product_id", id)
+	return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+}
+
+func TestCreateProduct_Created(t *testing.T) {
+	mock := &ProductAdminClientMock{product: &pb.Product{Id: 6, Name: "Desk Lamp", PriceMinor: 2599, Currency: "USD"}}
+	handler := NewProductHandler(mock, 5*time.Second)
+	recorder := httptest.NewRecorder()
+	body := `{"name":"Desk Lamp","price_minor":2599,"currency":"USD"}`
+
+	handler.CreateProduct(recorder, httptest.NewRequest("POST", "/api/v1/admin/products", strings.NewReader(body)))
+
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("expected %d, got %d", http.StatusCreated, recorder.Code)
+	}
+	if mock.createReq.Name != "Desk Lamp" || mock.createReq.PriceMinor != 2599 {
+		t.Errorf("unexpected request sent to product-service: %+v", mock.createReq)
+	}
+	var response ProductResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.ID != 6 {
+		t.Errorf("expected id 6, got %d", response.ID)
+	}
+}
+
+func TestCreateProduct_InvalidJSON(t *testing.T) {
+	mock := &ProductAdminClientMock{}
+	handler := NewProductHandler(mock, 5*time.Second)
+	recorder := httptest.NewRecorder()
+
+	handler.CreateProduct(recorder, httptest.NewRequest("POST", "/api/v1/admin/products", strings.NewReader("{")))
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("expected %d, got %d", http.StatusBadRequest, recorder.Code)
+	}
+	if mock.createReq != nil {
+		t.Error("product-service should not be called")
+	}
+}
+
+func TestUpdateProduct_ArchivedConflict(t *testing.T) {
+	mock := &ProductAdminClientMock{err: status.Error(codes.FailedPrecondition, "product is archived")}
+	handler := NewProductHandler(mock, 5*time.Second)
+	recorder := httptest.NewRecorder()
+	request := withProductID(httptest.NewRequest("PUT", "/api/v1/admin/products/3", strings.NewReader(`{"name":"Lamp","price_minor":100}`)), "3")
+
+	handler.UpdateProduct(recorder, request)
+
+	if recorder.Code != http.StatusConflict {
+		t.Errorf("expected %d, got %d", http.StatusConflict, recorder.Code)
+	}
+	if mock.updateReq.Id != 3 {
+		t.Errorf("expected id 3, got %d", mock.updateReq.Id)
+	}
+}
+
+func TestArchiveProduct_InvalidID(t *testing.T) {
+	mock := &ProductAdminClientMock{}
+	handler := NewProductHandler(mock, 5*time.Second)
+	recorder := httptest.NewRecorder()
+
+	handler.ArchiveProduct(recorder, withProductID(httptest.NewRequest("DELETE", "/api/v1/admin/products/abc", nil), "abc"))
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("expected %d, got %d", http.StatusBadRequest, recorder.Code)
+	}
+	if mock.archiveReq != nil {
+		t.Error("product-service should not be called")
+	}
+}
+
+func TestArchiveProduct_Success(t *testing.T) {
+	mock := &ProductAdminClientMock{product: &pb.Product{Id: 3, Name: "Lamp", ArchivedAt: "2026-10-16T10:00:00Z"}}
+	handler := NewProductHandler(mock, 5*time.Second)
+	recorder := httptest.NewRecorder()
+
+	handler.ArchiveProduct(recorder, withProductID(httptest.NewRequest("DELETE", "/api/v1/admin/products/3", nil), "3"))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, recorder.Code)
+	}
+	var response ProductResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.ArchivedAt == "" {
+		t.Error("expected archived_at in the response")
+	}
+}
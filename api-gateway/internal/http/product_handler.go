@@ -2,7 +2,12 @@ package http
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	pb "github.com/fjod/go_cart/product-service/pkg/proto"
@@ -22,34 +27,243 @@ func NewProductHandler(productClient pb.ProductServiceClient, timeout time.Durat
 
 type ProductResponse struct {
 	ID          int64   `json:"id"`
+	SKU         string  `json:"sku,omitempty"`
 	Name        string  `json:"name"`
 	Description string  `json:"description"`
-	Price       float64 `json:"price"`
+	Price       float64 `json:"price"`       // deprecated, use price_minor
+	PriceMinor  int64   `json:"price_minor"` // minor units of currency, e.g. cents
+	Currency    string  `json:"currency"`
 	ImageURL    string  `json:"image_url"`
+	ArchivedAt  string  `json:"archived_at,omitempty"`
+	// Variants are the SKUs of a product sold per variant, cart items of such a product need a variant_id
+	Variants []VariantResponse `json:"variants,omitempty"`
+}
+
+type VariantResponse struct {
+	ID         int64             `json:"id"`
+	SKU        string            `json:"sku"`
+	Name       string            `json:"name"`
+	PriceMinor int64             `json:"price_minor"`
+	Currency   string            `json:"currency"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	ArchivedAt string            `json:"archived_at,omitempty"`
+}
+
+// ProductDetailResponse is a single product with the category paths it is listed under
+type ProductDetailResponse struct {
+	ProductResponse
+	Breadcrumbs [][]CategoryResponse `json:"breadcrumbs"` // root first, one path per category
 }
 
 type ProductsResponse struct {
-	Products []ProductResponse `json:"products"`
+	Products      []ProductResponse `json:"products"`
+	NextPageToken string            `json:"next_page_token,omitempty"`
+}
+
+type ProductSearchResultResponse struct {
+	ProductResponse
+	NameHighlighted string  `json:"name_highlighted"` // matched terms wrapped in <mark></mark>
+	Snippet         string  `json:"snippet"`
+	Score           float64 `json:"score"`
+}
+
+type ProductSearchResponse struct {
+	Results       []ProductSearchResultResponse `json:"results"`
+	NextPageToken string                        `json:"next_page_token,omitempty"`
+}
+
+var productSorts = map[string]pb.ProductSort{
+	"":           pb.ProductSort_PRODUCT_SORT_UNSPECIFIED,
+	"price":      pb.ProductSort_PRODUCT_SORT_PRICE,
+	"name":       pb.ProductSort_PRODUCT_SORT_NAME,
+	"created_at": pb.ProductSort_PRODUCT_SORT_CREATED_AT,
 }
 
+// GET /api/v1/products?page_size=&page_token=&sort=price|name|created_at&order=asc|desc&min_price_minor=&max_price_minor=
+// &category_id=&include_descendants=true|false
 func (h *ProductHandler) Get(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
 	defer cancel()
-	res, err := h.productClient.GetProducts(ctx, &pb.GetProductsRequest{})
+
+	req, err := parseGetProductsQuery(r.URL.Query())
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_query", err.Error())
+		return
+	}
+
+	res, err := h.productClient.GetProducts(ctx, req)
 	if err != nil {
 		handleGRPCError(w, err)
 		return
 	}
 	products := make([]ProductResponse, len(res.Products))
 	for i, p := range res.Products {
-		products[i] = ProductResponse{
-			ID:          p.Id,
-			Name:        p.Name,
-			Description: p.Description,
-			Price:       p.Price,
-			ImageURL:    p.ImageUrl,
+		products[i] = convertProtoProduct(p)
+	}
+
+	respondJSON(w, http.StatusOK, &ProductsResponse{Products: products, NextPageToken: res.NextPageToken})
+}
+
+func parseGetProductsQuery(query url.Values) (*pb.GetProductsRequest, error) {
+	req := &pb.GetProductsRequest{PageToken: query.Get("page_token")}
+
+	size, err := parsePageSize(query)
+	if err != nil {
+		return nil, err
+	}
+	req.PageSize = size
+
+	sort, ok := productSorts[query.Get("sort")]
+	if !ok {
+		return nil, errors.New("sort must be one of price, name, created_at")
+	}
+	req.SortBy = sort
+
+	switch query.Get("order") {
+	case "", "asc":
+	case "desc":
+		req.Descending = true
+	default:
+		return nil, errors.New("order must be asc or desc")
+	}
+
+	for name, dst := range map[string]**int64{"min_price_minor": &req.MinPriceMinor, "max_price_minor": &req.MaxPriceMinor} {
+		v := query.Get(name)
+		if v == "" {
+			continue
+		}
+		price, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || price < 0 {
+			return nil, fmt.Errorf("%s must be a non-negative integer", name)
+		}
+		*dst = &price
+	}
+
+	if v := query.Get("category_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, errors.New("category_id must be a positive integer")
+		}
+		req.CategoryId = id
+	}
+	if v := query.Get("include_descendants"); v != "" {
+		include, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, errors.New("include_descendants must be true or false")
+		}
+		req.IncludeDescendants = include
+	}
+	return req, nil
+}
+
+// GET /api/v1/products/{product_id}
+func (h *ProductHandler) GetProduct(w http.ResponseWriter, r *http.Request) {
+	productID, ok := productIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	res, err := h.productClient.GetProduct(ctx, &pb.GetProductRequest{Id: productID})
+	if err != nil {
+		handleGRPCError(w, err)
+		return
+	}
+
+	breadcrumbs := make([][]CategoryResponse, len(res.Breadcrumbs))
+	for i, b := range res.Breadcrumbs {
+		breadcrumbs[i] = convertProtoCategories(b.Categories)
+	}
+	respondJSON(w, http.StatusOK, &ProductDetailResponse{
+		ProductResponse: convertProtoProduct(res.Product),
+		Breadcrumbs:     breadcrumbs,
+	})
+}
+
+// GET /api/v1/products/search?q=&page_size=&page_token=
+func (h *ProductHandler) Search(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	query := r.URL.Query()
+	q := strings.TrimSpace(query.Get("q"))
+	if q == "" {
+		respondError(w, http.StatusBadRequest, "invalid_query", "q is required")
+		return
+	}
+	size, err := parsePageSize(query)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_query", err.Error())
+		return
+	}
+
+	res, err := h.productClient.SearchProducts(ctx, &pb.SearchProductsRequest{
+		Query:     q,
+		PageSize:  size,
+		PageToken: query.Get("page_token"),
+	})
+	if err != nil {
+		handleGRPCError(w, err)
+		return
+	}
+	results := make([]ProductSearchResultResponse, len(res.Results))
+	for i, hit := range res.Results {
+		results[i] = ProductSearchResultResponse{
+			ProductResponse: convertProtoProduct(hit.Product),
+			NameHighlighted: hit.NameHighlighted,
+			Snippet:         hit.Snippet,
+			Score:           hit.Score,
 		}
 	}
 
-	respondJSON(w, http.StatusOK, &ProductsResponse{Products: products})
+	respondJSON(w, http.StatusOK, &ProductSearchResponse{Results: results, NextPageToken: res.NextPageToken})
+}
+
+// parsePageSize reads the optional page_size parameter, 0 leaves the choice to the product service
+func parsePageSize(query url.Values) (int32, error) {
+	v := query.Get("page_size")
+	if v == "" {
+		return 0, nil
+	}
+	size, err := strconv.ParseInt(v, 10, 32)
+	if err != nil || size <= 0 {
+		return 0, errors.New("page_size must be a positive integer")
+	}
+	return int32(size), nil
+}
+
+func convertProtoProduct(p *pb.Product) ProductResponse {
+	return ProductResponse{
+		ID:          p.Id,
+		SKU:         p.Sku,
+		Name:        p.Name,
+		Description: p.Description,
+		Price:       p.Price,
+		PriceMinor:  p.PriceMinor,
+		Currency:    p.Currency,
+		ImageURL:    p.ImageUrl,
+		ArchivedAt:  p.ArchivedAt,
+		Variants:    convertProtoVariants(p.Variants),
+	}
+}
+
+func convertProtoVariants(variants []*pb.ProductVariant) []VariantResponse {
+	if len(variants) == 0 {
+		return nil
+	}
+	result := make([]VariantResponse, len(variants))
+	for i, v := range variants {
+		result[i] = VariantResponse{
+			ID:         v.Id,
+			SKU:        v.Sku,
+			Name:       v.Name,
+			PriceMinor: v.PriceMinor,
+			Currency:   v.Currency,
+			Attributes: v.Attributes,
+			ArchivedAt: v.ArchivedAt,
+		}
+	}
+	return result
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
@@ -15,8 +16,13 @@ import (
 )
 
 type ProductClientMock struct {
-	products []*pb.Product
-	err      error
+	pb.ProductServiceClient // RPCs without a gateway route are left unimplemented
+	products                []*pb.Product
+	nextPageToken           string
+	searchResults           []*pb.ProductSearchResult
+	breadcrumbs             []*pb.Breadcrumb
+	categories              []*pb.Category
+	err                     error
 }
 
 func (m ProductClientMock) GetProducts(context.Context, *pb.GetProductsRequest, ...grpc.CallOption) (*pb.GetProductsResponse, error) {
@@ -24,7 +30,8 @@ func (m ProductClientMock) GetProducts(context.Context, *pb.GetProductsRequest,
 		return nil, m.err
 	}
 	return &pb.GetProductsResponse{
-		Products: m.products,
+		Products:      m.products,
+		NextPageToken: m.nextPageToken,
 	}, nil
 }
 
@@ -35,12 +42,45 @@ func (m ProductClientMock) GetProduct(context.Context, *pb.GetProductRequest, ..
 	// Return first product if available
 	if len(m.products) > 0 {
 		return &pb.GetProductResponse{
-			Product: m.products[0],
+			Product:     m.products[0],
+			Breadcrumbs: m.breadcrumbs,
 		}, nil
 	}
 	return nil, status.Error(codes.NotFound, "product not found")
 }
 
+func (m ProductClientMock) GetProductsByIds(_ context.Context, req *pb.GetProductsByIdsRequest, _ ...grpc.CallOption) (*pb.GetProductsByIdsResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var found []*pb.Product
+	for _, p := range m.products {
+		for _, id := range req.Ids {
+			if p.Id == id {
+				found = append(found, p)
+			}
+		}
+	}
+	return &pb.GetProductsByIdsResponse{Products: found}, nil
+}
+
+func (m ProductClientMock) SearchProducts(context.Context, *pb.SearchProductsRequest, ...grpc.CallOption) (*pb.SearchProductsResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &pb.SearchProductsResponse{
+		Results:       m.searchResults,
+		NextPageToken: m.nextPageToken,
+	}, nil
+}
+
+func (m ProductClientMock) ListCategories(context.Context, *pb.ListCategoriesRequest, ...grpc.CallOption) (*pb.ListCategoriesResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &pb.ListCategoriesResponse{Categories: m.categories}, nil
+}
+
 func TestGetProducts_Success(t *testing.T) {
 	clientMock := ProductClientMock{
 		products: []*pb.Product{
@@ -49,6 +89,8 @@ func TestGetProducts_Success(t *testing.T) {
 				Name:        "Laptop",
 				Description: "A powerful laptop",
 				Price:       1299.99,
+				PriceMinor:  129999,
+				Currency:    "USD",
 				ImageUrl:    "https://example.com/laptop.jpg",
 			},
 			{
@@ -91,6 +133,9 @@ func TestGetProducts_Success(t *testing.T) {
 	if response.Products[0].Price != 1299.99 {
 		t.Errorf("Expected product price 1299.99, got %f", response.Products[0].Price)
 	}
+	if response.Products[0].PriceMinor != 129999 || response.Products[0].Currency != "USD" {
+		t.Errorf("Expected price_minor 129999 USD, got %d %s", response.Products[0].PriceMinor, response.Products[0].Currency)
+	}
 
 	// Verify second product
 	if response.Products[1].ID != 2 {
@@ -211,3 +256,213 @@ func TestGetProducts_AllFields(t *testing.T) {
 		t.Errorf("Expected image URL 'https://example.com/test.jpg', got '%s'", product.ImageURL)
 	}
 }
+
+func TestGetProducts_ReturnsNextPageToken(t *testing.T) {
+	clientMock := ProductClientMock{
+		products:      []*pb.Product{{Id: 1, Name: "Laptop"}},
+		nextPageToken: "token-2",
+	}
+	handler := NewProductHandler(clientMock, 5*time.Second)
+	recorder := httptest.NewRecorder()
+
+	handler.Get(recorder, httptest.NewRequest("GET", "/api/v1/products?page_size=1", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, recorder.Code)
+	}
+	var response ProductsResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.NextPageToken != "token-2" {
+		t.Errorf("Expected next_page_token 'token-2', got '%s'", response.NextPageToken)
+	}
+}
+
+func TestGetProducts_InvalidQuery(t *testing.T) {
+	handler := NewProductHandler(ProductClientMock{}, 5*time.Second)
+	recorder := httptest.NewRecorder()
+
+	handler.Get(recorder, httptest.NewRequest("GET", "/api/v1/products?sort=rating", nil))
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, recorder.Code)
+	}
+}
+
+func TestParseGetProductsQuery(t *testing.T) {
+	query, _ := url.ParseQuery("page_size=10&page_token=abc&sort=price&order=desc&min_price_minor=100&max_price_minor=5000")
+
+	req, err := parseGetProductsQuery(query)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if req.PageSize != 10 || req.PageToken != "abc" || req.SortBy != pb.ProductSort_PRODUCT_SORT_PRICE || !req.Descending {
+		t.Errorf("Unexpected paging or sort: %+v", req)
+	}
+	if req.MinPriceMinor == nil || *req.MinPriceMinor != 100 || req.MaxPriceMinor == nil || *req.MaxPriceMinor != 5000 {
+		t.Errorf("Unexpected price filter: %v %v", req.MinPriceMinor, req.MaxPriceMinor)
+	}
+
+	query, _ = url.ParseQuery("category_id=2&include_descendants=true")
+	req, err = parseGetProductsQuery(query)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if req.CategoryId != 2 || !req.IncludeDescendants {
+		t.Errorf("Unexpected category filter: %+v", req)
+	}
+
+	for _, bad := range []string{"page_size=0", "page_size=x", "order=up", "min_price_minor=-1", "max_price_minor=1.5",
+		"category_id=0", "category_id=x", "include_descendants=maybe"} {
+		query, _ := url.ParseQuery(bad)
+		if _, err := parseGetProductsQuery(query); err == nil {
+			t.Errorf("Expected an error for %q", bad)
+		}
+	}
+}
+
+func TestSearchProducts_Success(t *testing.T) {
+	clientMock := ProductClientMock{
+		searchResults: []*pb.ProductSearchResult{{
+			Product:         &pb.Product{Id: 2, Name: "Mouse", PriceMinor: 2999, Currency: "USD"},
+			NameHighlighted: "<mark>Mouse</mark>",
+			Snippet:         "Ergonomic wireless <mark>mouse</mark>",
+			Score:           3.5,
+		}},
+		nextPageToken: "token-2",
+	}
+	handler := NewProductHandler(clientMock, 5*time.Second)
+	recorder := httptest.NewRecorder()
+
+	handler.Search(recorder, httptest.NewRequest("GET", "/api/v1/products/search?q=mouse&page_size=1", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, recorder.Code)
+	}
+	var response ProductSearchResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(response.Results))
+	}
+	got := response.Results[0]
+	if got.ID != 2 || got.PriceMinor != 2999 || got.NameHighlighted != "<mark>Mouse</mark>" || got.Score != 3.5 {
+		t.Errorf("Unexpected result: %+v", got)
+	}
+	if got.Snippet != "Ergonomic wireless <mark>mouse</mark>" {
+		t.Errorf("Unexpected snippet '%s'", got.Snippet)
+	}
+	if response.NextPageToken != "token-2" {
+		t.Errorf("Expected next_page_token 'token-2', got '%s'", response.NextPageToken)
+	}
+}
+
+func TestSearchProducts_InvalidQuery(t *testing.T) {
+	handler := NewProductHandler(ProductClientMock{}, 5*time.Second)
+
+	for _, target := range []string{"/api/v1/products/search", "/api/v1/products/search?q=%20", "/api/v1/products/search?q=lamp&page_size=0"} {
+		recorder := httptest.NewRecorder()
+		handler.Search(recorder, httptest.NewRequest("GET", target, nil))
+
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status code %d, got %d", target, http.StatusBadRequest, recorder.Code)
+		}
+	}
+}
+
+func TestSearchProducts_GRPCError(t *testing.T) {
+	handler := NewProductHandler(ProductClientMock{err: status.Error(codes.InvalidArgument, "invalid page token")}, 5*time.Second)
+	recorder := httptest.NewRecorder()
+
+	handler.Search(recorder, httptest.NewRequest("GET", "/api/v1/products/search?q=lamp&page_token=bad", nil))
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, recorder.Code)
+	}
+}
+
+func TestGetProduct_WithBreadcrumbs(t *testing.T) {
+	clientMock := ProductClientMock{
+		products: []*pb.Product{{Id: 5, Name: "Headphones", PriceMinor: 24999, Currency: "USD"}},
+		breadcrumbs: []*pb.Breadcrumb{{Categories: []*pb.Category{
+			{Id: 1, Name: "Electronics", Slug: "electronics"},
+			{Id: 6, ParentId: 1, Name: "Audio", Slug: "audio"},
+		}}},
+	}
+	handler := NewProductHandler(clientMock, 5*time.Second)
+	recorder := httptest.NewRecorder()
+
+	handler.GetProduct(recorder, withProductID(httptest.NewRequest("GET", "/api/v1/products/5", nil), "5"))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, recorder.Code)
+	}
+	var response ProductDetailResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.ID != 5 || response.Name != "Headphones" {
+		t.Errorf("Unexpected product: %+v", response.ProductResponse)
+	}
+	if len(response.Breadcrumbs) != 1 || len(response.Breadcrumbs[0]) != 2 {
+		t.Fatalf("Expected one breadcrumb of two categories, got %+v", response.Breadcrumbs)
+	}
+	if response.Breadcrumbs[0][0].Slug != "electronics" || response.Breadcrumbs[0][1].ParentID != 1 {
+		t.Errorf("Unexpected breadcrumb: %+v", response.Breadcrumbs[0])
+	}
+}
+
+func TestGetProduct_NotFound(t *testing.T) {
+	handler := NewProductHandler(ProductClientMock{}, 5*time.Second)
+	recorder := httptest.NewRecorder()
+
+	handler.GetProduct(recorder, withProductID(httptest.NewRequest("GET", "/api/v1/products/42", nil), "42"))
+
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d", http.StatusNotFound, recorder.Code)
+	}
+}
+
+func TestGetProduct_InvalidID(t *testing.T) {
+	handler := NewProductHandler(ProductClientMock{}, 5*time.Second)
+	recorder := httptest.NewRecorder()
+
+	handler.GetProduct(recorder, withProductID(httptest.NewRequest("GET", "/api/v1/products/abc", nil), "abc"))
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, recorder.Code)
+	}
+}
+
+func TestGetProducts_IncludesVariants(t *testing.T) {
+	clientMock := ProductClientMock{
+		products: []*pb.Product{{
+			Id:         1,
+			Name:       "Laptop",
+			PriceMinor: 129999,
+			Currency:   "USD",
+			Variants: []*pb.ProductVariant{
+				{Id: 2, Sku: "LAPTOP-32GB-1TB", Name: "32GB RAM, 1TB SSD", PriceMinor: 159999, Currency: "USD", Attributes: map[string]string{"memory": "32GB"}},
+			},
+		}},
+	}
+	handler := NewProductHandler(clientMock, 5*time.Second)
+	recorder := httptest.NewRecorder()
+
+	handler.Get(recorder, httptest.NewRequest("GET", "/api/v1/products", nil))
+
+	var response ProductsResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Products) != 1 || len(response.Products[0].Variants) != 1 {
+		t.Fatalf("Expected one product with one variant, got %+v", response.Products)
+	}
+	v := response.Products[0].Variants[0]
+	if v.SKU != "LAPTOP-32GB-1TB" || v.PriceMinor != 159999 || v.Attributes["memory"] != "32GB" {
+		t.Errorf("Unexpected variant: %+v", v)
+	}
+}
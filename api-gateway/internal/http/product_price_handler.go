@@ -0,0 +1,100 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	pb "github.com/fjod/go_cart/product-service/pkg/proto"
+)
+
+// ScheduledPriceRequestDTO is the body of the scheduled price route, times are RFC3339
+type ScheduledPriceRequestDTO struct {
+	PriceMinor int64  `json:"price_minor"`
+	StartsAt   string `json:"starts_at"`
+	EndsAt     string `json:"ends_at,omitempty"` // empty keeps the new price
+}
+
+type ScheduledPriceResponse struct {
+	ID         int64  `json:"id"`
+	ProductID  int64  `json:"product_id"`
+	PriceMinor int64  `json:"price_minor"`
+	Currency   string `json:"currency"`
+	StartsAt   string `json:"starts_at"`
+	EndsAt     string `json:"ends_at,omitempty"`
+}
+
+type PriceAtResponse struct {
+	ProductID  int64  `json:"product_id"`
+	PriceMinor int64  `json:"price_minor"`
+	Currency   string `json:"currency"`
+	ValidFrom  string `json:"valid_from"`
+	ValidTo    string `json:"valid_to,omitempty"` // empty while this is the current price
+}
+
+// POST /api/v1/admin/products/{product_id}/scheduled-prices
+func (h *ProductHandler) SchedulePriceChange(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	productID, ok := productIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req ScheduledPriceRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_request", "invalid JSON body")
+		return
+	}
+
+	resp, err := h.productClient.SchedulePriceChange(ctx, &pb.SchedulePriceChangeRequest{
+		ProductId:  productID,
+		PriceMinor: req.PriceMinor,
+		StartsAt:   req.StartsAt,
+		EndsAt:     req.EndsAt,
+	})
+	if err != nil {
+		handleGRPCError(w, err)
+		return
+	}
+
+	sp := resp.ScheduledPrice
+	respondJSON(w, http.StatusCreated, ScheduledPriceResponse{
+		ID:         sp.Id,
+		ProductID:  sp.ProductId,
+		PriceMinor: sp.PriceMinor,
+		Currency:   sp.Currency,
+		StartsAt:   sp.StartsAt,
+		EndsAt:     sp.EndsAt,
+	})
+}
+
+// GET /api/v1/admin/products/{product_id}/price?at=2026-01-15T12:00:00Z
+// The price the product sold at at that time, now when at is omitted.
+func (h *ProductHandler) GetPriceAt(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	productID, ok := productIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	resp, err := h.productClient.GetPriceAt(ctx, &pb.GetPriceAtRequest{
+		ProductId: productID,
+		At:        r.URL.Query().Get("at"),
+	})
+	if err != nil {
+		handleGRPCError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, PriceAtResponse{
+		ProductID:  resp.ProductId,
+		PriceMinor: resp.PriceMinor,
+		Currency:   resp.Currency,
+		ValidFrom:  resp.ValidFrom,
+		ValidTo:    resp.ValidTo,
+	})
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/fjod/go_cart/product-service/pkg/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ProductPriceClientMock captures the price requests sent to product-service
+type ProductPriceClientMock struct {
+	pb.ProductServiceClient
+	err error
+
+	scheduleReq *pb.SchedulePriceChangeRequest
+	priceAtReq  *pb.GetPriceAtRequest
+}
+
+func (m *ProductPriceClientMock) SchedulePriceChange(_ context.Context, req *pb.SchedulePriceChangeRequest, _ ...grpc.CallOption) (*pb.SchedulePriceChangeResponse, error) {
+	m.scheduleReq = req
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &pb.SchedulePriceChangeResponse{ScheduledPrice: &pb.ScheduledPrice{
+		Id: 1, ProductId: req.ProductId, PriceMinor: req.PriceMinor, Currency: "USD", StartsAt: req.StartsAt, EndsAt: req.EndsAt,
+	}}, nil
+}
+
+func (m *ProductPriceClientMock) GetPriceAt(_ context.Context, req *pb.GetPriceAtRequest, _ ...grpc.CallOption) (*pb.GetPriceAtResponse, error) {
+	m.priceAtReq = req
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &pb.GetPriceAtResponse{
+		ProductId: req.ProductId, PriceMinor: 1999, Currency: "USD", ValidFrom: "2026-01-01T00:00:00Z",
+	}, nil
+}
+
+func TestSchedulePriceChange_Created(t *testing.T) {
+	mock := &ProductPriceClientMock{}
+	handler := NewProductHandler(mock, 5*time.Second)
+	recorder := httptest.NewRecorder()
+	body := `{"price_minor":999,"starts_at":"2026-11-27T00:00:00Z","ends_at":"2026-11-30T00:00:00Z"}`
+	request := withProductID(httptest.NewRequest("POST", "/api/v1/admin/products/3/scheduled-prices", strings.NewReader(body)), "3")
+
+	handler.SchedulePriceChange(recorder, request)
+
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("expected %d, got %d", http.StatusCreated, recorder.Code)
+	}
+	if mock.scheduleReq.ProductId != 3 || mock.scheduleReq.PriceMinor != 999 || mock.scheduleReq.EndsAt != "2026-11-30T00:00:00Z" {
+		t.Errorf("unexpected request sent to product-service: %+v", mock.scheduleReq)
+	}
+	var response ScheduledPriceResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.ID != 1 || response.StartsAt != "2026-11-27T00:00:00Z" {
+		t.Errorf("unexpected response: %+v", response)
+	}
+}
+
+func TestSchedulePriceChange_InvalidTimes(t *testing.T) {
+	mock := &ProductPriceClientMock{err: status.Error(codes.InvalidArgument, "ends_at must be after starts_at")}
+	handler := NewProductHandler(mock, 5*time.Second)
+	recorder := httptest.NewRecorder()
+	body := `{"price_minor":999,"starts_at":"2026-11-27T00:00:00Z","ends_at":"2026-11-20T00:00:00Z"}`
+	request := withProductID(httptest.NewRequest("POST", "/api/v1/admin/products/3/scheduled-prices", strings.NewReader(body)), "3")
+
+	handler.SchedulePriceChange(recorder, request)
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("expected %d, got %d", http.StatusBadRequest, recorder.Code)
+	}
+}
+
+func TestGetPriceAt_PassesTimestamp(t *testing.T) {
+	mock := &ProductPriceClientMock{}
+	handler := NewProductHandler(mock, 5*time.Second)
+	recorder := httptest.NewRecorder()
+	request := withProductID(httptest.NewRequest("GET", "/api/v1/admin/products/3/price?at=2026-01-15T12:00:00Z", nil), "3")
+
+	handler.GetPriceAt(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, recorder.Code)
+	}
+	if mock.priceAtReq.At != "2026-01-15T12:00:00Z" {
+		t.Errorf("expected at to be passed through, got %q", mock.priceAtReq.At)
+	}
+	var response PriceAtResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.PriceMinor != 1999 || response.ValidTo != "" {
+		t.Errorf("unexpected response: %+v", response)
+	}
+}
+
+func TestGetPriceAt_NotFound(t *testing.T) {
+	mock := &ProductPriceClientMock{err: status.Error(codes.NotFound, "product had no price at that time")}
+	handler := NewProductHandler(mock, 5*time.Second)
+	recorder := httptest.NewRecorder()
+
+	handler.GetPriceAt(recorder, withProductID(httptest.NewRequest("GET", "/api/v1/admin/products/3/price", nil), "3"))
+
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("expected %d, got %d", http.StatusNotFound, recorder.Code)
+	}
+}
@@ -0,0 +1,94 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	pb "github.com/fjod/go_cart/product-service/pkg/proto"
+)
+
+// PromotionRequestDTO is the body of the promotion route. Value is a percentage for kind percentage
+// and minor units of currency for kind fixed. Without product_ids and category_ids the code covers every product.
+type PromotionRequestDTO struct {
+	Code          string  `json:"code"`
+	Kind          string  `json:"kind"` // percentage or fixed
+	Value         int64   `json:"value"`
+	Currency      string  `json:"currency,omitempty"`
+	MinSpendMinor int64   `json:"min_spend_minor,omitempty"`
+	ProductIDs    []int64 `json:"product_ids,omitempty"`
+	CategoryIDs   []int64 `json:"category_ids,omitempty"`
+	UsageLimit    *int64  `json:"usage_limit,omitempty"` // unlimited when omitted
+	StartsAt      string  `json:"starts_at,omitempty"`
+	ExpiresAt     string  `json:"expires_at,omitempty"`
+}
+
+type PromotionResponse struct {
+	ID            int64   `json:"id"`
+	Code          string  `json:"code"`
+	Kind          string  `json:"kind"`
+	Value         int64   `json:"value"`
+	Currency      string  `json:"currency,omitempty"`
+	MinSpendMinor int64   `json:"min_spend_minor,omitempty"`
+	ProductIDs    []int64 `json:"product_ids,omitempty"`
+	CategoryIDs   []int64 `json:"category_ids,omitempty"`
+	UsageLimit    *int64  `json:"usage_limit,omitempty"`
+	TimesUsed     int64   `json:"times_used"`
+	StartsAt      string  `json:"starts_at,omitempty"`
+	ExpiresAt     string  `json:"expires_at,omitempty"`
+}
+
+// POST /api/v1/admin/promotions
+func (h *ProductHandler) CreatePromotion(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	var req PromotionRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_request", "invalid JSON body")
+		return
+	}
+	kind, ok := promotionKinds[req.Kind]
+	if !ok {
+		respondError(w, http.StatusBadRequest, "invalid_request", "kind must be percentage or fixed")
+		return
+	}
+
+	resp, err := h.productClient.CreatePromotion(ctx, &pb.CreatePromotionRequest{
+		Code:          req.Code,
+		Kind:          kind,
+		Value:         req.Value,
+		Currency:      req.Currency,
+		MinSpendMinor: req.MinSpendMinor,
+		ProductIds:    req.ProductIDs,
+		CategoryIds:   req.CategoryIDs,
+		UsageLimit:    req.UsageLimit,
+		StartsAt:      req.StartsAt,
+		ExpiresAt:     req.ExpiresAt,
+	})
+	if err != nil {
+		handleGRPCError(w, err)
+		return
+	}
+
+	p := resp.Promotion
+	respondJSON(w, http.StatusCreated, PromotionResponse{
+		ID:            p.Id,
+		Code:          p.Code,
+		Kind:          req.Kind,
+		Value:         p.Value,
+		Currency:      p.Currency,
+		MinSpendMinor: p.MinSpendMinor,
+		ProductIDs:    p.ProductIds,
+		CategoryIDs:   p.CategoryIds,
+		UsageLimit:    p.UsageLimit,
+		TimesUsed:     p.TimesUsed,
+		StartsAt:      p.StartsAt,
+		ExpiresAt:     p.ExpiresAt,
+	})
+}
+
+var promotionKinds = map[string]pb.PromotionKind{
+	"percentage": pb.PromotionKind_PROMOTION_KIND_PERCENTAGE,
+	"fixed":      pb.PromotionKind_PROMOTION_KIND_FIXED,
+}
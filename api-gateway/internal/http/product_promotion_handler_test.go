@@ -0,0 +1,87 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/fjod/go_cart/product-service/pkg/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ProductPromotionClientMock captures the promotion sent to product-service
+type ProductPromotionClientMock struct {
+	pb.ProductServiceClient
+	err error
+
+	createReq *pb.CreatePromotionRequest
+}
+
+func (m *ProductPromotionClientMock) CreatePromotion(_ context.Context, req *pb.CreatePromotionRequest, _ ...grpc.CallOption) (*pb.CreatePromotionResponse, error) {
+	m.createReq = req
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &pb.CreatePromotionResponse{Promotion: &pb.Promotion{
+		Id: 1, Code: "SPRING-10", Kind: req.Kind, Value: req.Value, CategoryIds: req.CategoryIds, UsageLimit: req.UsageLimit,
+	}}, nil
+}
+
+func TestCreatePromotion_Created(t *testing.T) {
+	mock := &ProductPromotionClientMock{}
+	handler := NewProductHandler(mock, 5*time.Second)
+	recorder := httptest.NewRecorder()
+	body := `{"code":"spring-10","kind":"percentage","value":10,"category_ids":[2],"usage_limit":100}`
+	request := httptest.NewRequest("POST", "/api/v1/admin/promotions", strings.NewReader(body))
+
+	handler.CreatePromotion(recorder, request)
+
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("expected %d, got %d", http.StatusCreated, recorder.Code)
+	}
+	if mock.createReq.Kind != pb.PromotionKind_PROMOTION_KIND_PERCENTAGE || mock.createReq.GetUsageLimit() != 100 {
+		t.Errorf("unexpected request sent to product-service: %+v", mock.createReq)
+	}
+	var response PromotionResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.Code != "SPRING-10" || response.Kind != "percentage" || *response.UsageLimit != 100 {
+		t.Errorf("unexpected response: %+v", response)
+	}
+}
+
+func TestCreatePromotion_UnknownKind(t *testing.T) {
+	mock := &ProductPromotionClientMock{}
+	handler := NewProductHandler(mock, 5*time.Second)
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest("POST", "/api/v1/admin/promotions", strings.NewReader(`{"code":"TEN","kind":"bogo","value":10}`))
+
+	handler.CreatePromotion(recorder, request)
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("expected %d, got %d", http.StatusBadRequest, recorder.Code)
+	}
+	if mock.createReq != nil {
+		t.Error("expected no call to product-service")
+	}
+}
+
+func TestCreatePromotion_DuplicateCode(t *testing.T) {
+	mock := &ProductPromotionClientMock{err: status.Error(codes.AlreadyExists, "promo code already exists")}
+	handler := NewProductHandler(mock, 5*time.Second)
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest("POST", "/api/v1/admin/promotions", strings.NewReader(`{"code":"TEN","kind":"fixed","value":500,"currency":"USD"}`))
+
+	handler.CreatePromotion(recorder, request)
+
+	if recorder.Code != http.StatusConflict {
+		t.Errorf("expected %d, got %d", http.StatusConflict, recorder.Code)
+	}
+}
@@ -0,0 +1,225 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	pb "github.com/fjod/go_cart/cart-service/pkg/proto"
+	"github.com/go-chi/chi/v5"
+	"google.golang.org/grpc/metadata"
+)
+
+// SavedItemRequestDTO names an item and the list it moves to or from.
+// An empty list is the saved-for-later list, any other name a wishlist.
+type SavedItemRequestDTO struct {
+	ProductID int64  `json:"product_id"`
+	VariantID int64  `json:"variant_id,omitempty"`
+	List      string `json:"list,omitempty"`
+}
+
+// Saved items and wishlists belong to signed-in users, the routes take a JWT but no guest token.
+// Every response holds the cart together with the lists, with the cart version as ETag.
+
+// GET /api/v1/cart/saved
+func (h *CartHandler) GetSavedItems(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "missing user authentication")
+		return
+	}
+	// Propagate metadata
+	ctx = metadata.AppendToOutgoingContext(ctx, "user-id", fmt.Sprint(userID), "request-id", getRequestID(r.Context()))
+
+	resp, err := h.cartClient.GetSavedItems(ctx, &pb.GetSavedItemsRequest{UserId: userID})
+	if err != nil {
+		handleGRPCError(w, err)
+		return
+	}
+
+	setCartETag(w, resp.Cart)
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// POST /api/v1/cart/saved moves a cart line to a list
+func (h *CartHandler) SaveForLater(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "missing user authentication")
+		return
+	}
+	req, ok := savedItemRequest(w, r)
+	if !ok {
+		return
+	}
+	version, ok := ifMatchVersion(w, r)
+	if !ok {
+		return
+	}
+
+	// Propagate metadata
+	ctx = metadata.AppendToOutgoingContext(ctx, "user-id", fmt.Sprint(userID), "request-id", getRequestID(r.Context()))
+
+	resp, err := h.cartClient.SaveForLater(ctx, &pb.SaveForLaterRequest{
+		UserId:          userID,
+		ProductId:       req.ProductID,
+		VariantId:       req.VariantID,
+		List:            req.List,
+		ExpectedVersion: version,
+	})
+	if err != nil {
+		handleGRPCError(w, err)
+		return
+	}
+
+	setCartETag(w, resp.Cart)
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// POST /api/v1/cart/saved/move moves an item of a list back into the cart
+func (h *CartHandler) MoveToCart(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "missing user authentication")
+		return
+	}
+	req, ok := savedItemRequest(w, r)
+	if !ok {
+		return
+	}
+	version, ok := ifMatchVersion(w, r)
+	if !ok {
+		return
+	}
+
+	// Propagate metadata
+	ctx = metadata.AppendToOutgoingContext(ctx, "user-id", fmt.Sprint(userID), "request-id", getRequestID(r.Context()))
+
+	resp, err := h.cartClient.MoveToCart(ctx, &pb.MoveToCartRequest{
+		UserId:          userID,
+		ProductId:       req.ProductID,
+		VariantId:       req.VariantID,
+		List:            req.List,
+		ExpectedVersion: version,
+	})
+	if err != nil {
+		handleGRPCError(w, err)
+		return
+	}
+
+	setCartETag(w, resp.Cart)
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// DELETE /api/v1/cart/saved/items/{product_id}?variant_id=&list=
+func (h *CartHandler) RemoveSavedItem(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "missing user authentication")
+		return
+	}
+
+	// Get product_id from URL path
+	productID, err := strconv.ParseInt(chi.URLParam(r, "product_id"), 10, 64)
+	if err != nil || productID <= 0 {
+		respondError(w, http.StatusBadRequest, "invalid_product_id", "product_id must be a positive integer")
+		return
+	}
+	variantID, ok := variantIDQuery(w, r)
+	if !ok {
+		return
+	}
+	version, ok := ifMatchVersion(w, r)
+	if !ok {
+		return
+	}
+
+	// Propagate metadata
+	ctx = metadata.AppendToOutgoingContext(ctx, "user-id", fmt.Sprint(userID), "request-id", getRequestID(r.Context()))
+
+	resp, err := h.cartClient.RemoveSavedItem(ctx, &pb.RemoveSavedItemRequest{
+		UserId:          userID,
+		ProductId:       productID,
+		VariantId:       variantID,
+		List:            r.URL.Query().Get("list"),
+		ExpectedVersion: version,
+	})
+	if err != nil {
+		handleGRPCError(w, err)
+		return
+	}
+
+	setCartETag(w, resp.Cart)
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// DELETE /api/v1/cart/saved/lists/{list} deletes a wishlist with its items
+func (h *CartHandler) DeleteSavedList(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	userID := getUserIDFromContext(r.Context())
+	if userID == 0 {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "missing user authentication")
+		return
+	}
+	// chi matches the escaped path when there is one, so a name with spaces arrives escaped
+	list, err := url.PathUnescape(chi.URLParam(r, "list"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_list", "list must be a list name")
+		return
+	}
+	version, ok := ifMatchVersion(w, r)
+	if !ok {
+		return
+	}
+
+	// Propagate metadata
+	ctx = metadata.AppendToOutgoingContext(ctx, "user-id", fmt.Sprint(userID), "request-id", getRequestID(r.Context()))
+
+	resp, err := h.cartClient.DeleteSavedList(ctx, &pb.DeleteSavedListRequest{
+		UserId:          userID,
+		List:            list,
+		ExpectedVersion: version,
+	})
+	if err != nil {
+		handleGRPCError(w, err)
+		return
+	}
+
+	setCartETag(w, resp.Cart)
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// savedItemRequest reads and checks the body of a move between the cart and a list
+func savedItemRequest(w http.ResponseWriter, r *http.Request) (SavedItemRequestDTO, bool) {
+	var req SavedItemRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_request", "invalid JSON body")
+		return req, false
+	}
+	if req.ProductID <= 0 {
+		respondError(w, http.StatusBadRequest, "invalid_product_id", "product_id must be positive")
+		return req, false
+	}
+	if req.VariantID < 0 {
+		respondError(w, http.StatusBadRequest, "invalid_variant_id", "variant_id must not be negative")
+		return req, false
+	}
+	return req, true
+}
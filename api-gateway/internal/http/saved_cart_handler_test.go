@@ -0,0 +1,121 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fjod/go_cart/api-gateway/internal/middleware"
+	pb "github.com/fjod/go_cart/cart-service/pkg/proto"
+	"github.com/go-chi/chi/v5"
+	"google.golang.org/grpc"
+)
+
+// savedCartClient captures the saved list requests
+type savedCartClient struct {
+	ClientMock
+	saved   *pb.SaveForLaterRequest
+	removed *pb.RemoveSavedItemRequest
+	deleted *pb.DeleteSavedListRequest
+}
+
+func (c *savedCartClient) SaveForLater(ctx context.Context, in *pb.SaveForLaterRequest, opts ...grpc.CallOption) (*pb.SavedItemsResponse, error) {
+	c.saved = in
+	return c.ClientMock.SaveForLater(ctx, in, opts...)
+}
+
+func (c *savedCartClient) RemoveSavedItem(ctx context.Context, in *pb.RemoveSavedItemRequest, opts ...grpc.CallOption) (*pb.SavedItemsResponse, error) {
+	c.removed = in
+	return c.ClientMock.RemoveSavedItem(ctx, in, opts...)
+}
+
+func (c *savedCartClient) DeleteSavedList(ctx context.Context, in *pb.DeleteSavedListRequest, opts ...grpc.CallOption) (*pb.SavedItemsResponse, error) {
+	c.deleted = in
+	return c.ClientMock.DeleteSavedList(ctx, in, opts...)
+}
+
+func TestSaveForLater(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		guestOnly  bool
+		wantStatus int
+	}{
+		{"wishlist", `{"product_id":1,"variant_id":7,"list":"birthday"}`, false, http.StatusOK},
+		{"guest", `{"product_id":1}`, true, http.StatusUnauthorized},
+		{"bad product", `{"product_id":0}`, false, http.StatusBadRequest},
+		{"invalid JSON", `{"product_id":`, false, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &savedCartClient{ClientMock: ClientMock{cart: &pb.Cart{UserId: 1, Version: 2}}}
+			handler := NewCartHandler(client, 5*time.Second)
+			request := httptest.NewRequest("POST", "/saved", bytes.NewReader([]byte(tt.body)))
+			ctx := context.WithValue(request.Context(), middleware.GuestIDKey, "3f2a9c")
+			if !tt.guestOnly {
+				ctx = context.WithValue(ctx, middleware.UserIDKey, int64(1))
+			}
+			request = request.WithContext(ctx)
+			recorder := httptest.NewRecorder()
+
+			handler.SaveForLater(recorder, request)
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("Expected status code %d, got %d", tt.wantStatus, recorder.Code)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if client.saved.UserId != 1 || client.saved.ProductId != 1 || client.saved.VariantId != 7 || client.saved.List != "birthday" {
+				t.Errorf("Expected product 1 variant 7 saved to birthday for user 1, got %+v", client.saved)
+			}
+			if got := recorder.Header().Get("ETag"); got != `"2"` {
+				t.Errorf("Expected ETag %q, got %q", `"2"`, got)
+			}
+		})
+	}
+}
+
+func TestRemoveSavedItem_Query(t *testing.T) {
+	client := &savedCartClient{ClientMock: ClientMock{cart: &pb.Cart{UserId: 1}}}
+	handler := NewCartHandler(client, 5*time.Second)
+	request := httptest.NewRequest("DELETE", "/saved/items/1?variant_id=7&list=birthday", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("product_id", "1")
+	ctx := context.WithValue(request.Context(), middleware.UserIDKey, int64(1))
+	request = request.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+	recorder := httptest.NewRecorder()
+
+	handler.RemoveSavedItem(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, recorder.Code)
+	}
+	if client.removed.ProductId != 1 || client.removed.VariantId != 7 || client.removed.List != "birthday" {
+		t.Errorf("Expected product 1 variant 7 removed from birthday, got %+v", client.removed)
+	}
+}
+
+func TestDeleteSavedList_EscapedName(t *testing.T) {
+	client := &savedCartClient{ClientMock: ClientMock{cart: &pb.Cart{UserId: 1}}}
+	handler := NewCartHandler(client, 5*time.Second)
+	request := httptest.NewRequest("DELETE", "/saved/lists/for%20mom", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("list", "for%20mom")
+	ctx := context.WithValue(request.Context(), middleware.UserIDKey, int64(1))
+	request = request.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+	recorder := httptest.NewRecorder()
+
+	handler.DeleteSavedList(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, recorder.Code)
+	}
+	if client.deleted.List != "for mom" {
+		t.Errorf("Expected list %q, got %q", "for mom", client.deleted.List)
+	}
+}
@@ -12,9 +12,14 @@ import (
 type contextKey string
 
 const UserIDKey contextKey = "user_id"
+const RoleKey contextKey = "role"
+
+// RoleAdmin is the role claim that unlocks the catalog admin routes
+const RoleAdmin = "admin"
 
 type Claims struct {
-	UserID int64 `json:"user_id"`
+	UserID int64  `json:"user_id"`
+	Role   string `json:"role,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -37,39 +42,62 @@ func MockAuthMiddleware(next http.Handler) http.Handler {
 func JWTAuthMiddleware(secret []byte) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			authHeader := r.Header.Get("Authorization")
-			if authHeader == "" {
-				http.Error(w, `{"error":"missing authorization header"}`, http.StatusUnauthorized)
+			claims, errBody := parseBearerToken(r.Header.Get("Authorization"), secret)
+			if errBody != "" {
+				http.Error(w, errBody, http.StatusUnauthorized)
 				return
 			}
 
-			// Expect "Bearer <token>"
-			parts := strings.SplitN(authHeader, " ", 2)
-			if len(parts) != 2 || parts[0] != "Bearer" {
-				http.Error(w, `{"error":"invalid authorization format"}`, http.StatusUnauthorized)
-				return
-			}
+			next.ServeHTTP(w, r.WithContext(withClaims(r.Context(), claims)))
+		})
+	}
+}
 
-			token, err := jwt.ParseWithClaims(parts[1], &Claims{}, func(t *jwt.Token) (interface{}, error) {
-				// Validate signing method to prevent algorithm switching attacks
-				if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-					return nil, jwt.ErrSignatureInvalid
-				}
-				return secret, nil
-			})
-			if err != nil {
-				http.Error(w, `{"error":"invalid token"}`, http.StatusUnauthorized)
-				return
-			}
+// parseBearerToken validates a "Bearer <token>" header, on failure it returns the JSON error body to send
+func parseBearerToken(authHeader string, secret []byte) (*Claims, string) {
+	if authHeader == "" {
+		return nil, `{"error":"missing authorization header"}`
+	}
 
-			claims, ok := token.Claims.(*Claims)
-			if !ok || !token.Valid {
-				http.Error(w, `{"error":"invalid claims"}`, http.StatusUnauthorized)
+	// Expect "Bearer <token>"
+	parts := strings.SplitN(authHeader, " ", 2)
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return nil, `{"error":"invalid authorization format"}`
+	}
+
+	token, err := jwt.ParseWithClaims(parts[1], &Claims{}, func(t *jwt.Token) (interface{}, error) {
+		// Validate signing method to prevent algorithm switching attacks
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return secret, nil
+	})
+	if err != nil {
+		return nil, `{"error":"invalid token"}`
+	}
+
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
+		return nil, `{"error":"invalid claims"}`
+	}
+	return claims, ""
+}
+
+func withClaims(ctx context.Context, claims *Claims) context.Context {
+	ctx = context.WithValue(ctx, UserIDKey, claims.UserID)
+	return context.WithValue(ctx, RoleKey, claims.Role)
+}
+
+// RequireRole lets through only requests whose JWT carries the given role claim,
+// it must run after JWTAuthMiddleware
+func RequireRole(role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if got, _ := r.Context().Value(RoleKey).(string); got != role {
+				http.Error(w, `{"error":"forbidden"}`, http.StatusForbidden)
 				return
 			}
-
-			ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
-			next.ServeHTTP(w, r.WithContext(ctx))
+			next.ServeHTTP(w, r)
 		})
 	}
 }
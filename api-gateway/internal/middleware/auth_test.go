@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testSecret = "test-secret"
+
+func signedToken(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	claims["exp"] = time.Now().Add(time.Hour).Unix()
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return signed
+}
+
+func TestRequireRole(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		want   int
+	}{
+		{"admin passes", jwt.MapClaims{"user_id": 1, "role": RoleAdmin}, http.StatusOK},
+		{"other role is forbidden", jwt.MapClaims{"user_id": 1, "role": "support"}, http.StatusForbidden},
+		{"no role claim is forbidden", jwt.MapClaims{"user_id": 1}, http.StatusForbidden},
+	}
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	handler := JWTAuthMiddleware([]byte(testSecret))(RequireRole(RoleAdmin)(ok))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest("POST", "/api/v1/admin/products", nil)
+			request.Header.Set("Authorization", "Bearer "+signedToken(t, tt.claims))
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, recorder.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const GuestIDKey contextKey = "guest_id"
+
+// GuestTokenHeader carries the guest cart token of an anonymous shopper
+const GuestTokenHeader = "X-Guest-Token"
+
+// guestAudience keeps guest cart tokens and user JWTs from being taken for one another
+const guestAudience = "guest-cart"
+
+var errInvalidGuestToken = errors.New("invalid guest token")
+
+// IssueGuestToken creates a new guest id and the signed token that names it, the token expires after ttl
+func IssueGuestToken(secret []byte, ttl time.Duration) (token, guestID string, expiresAt time.Time, err error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", time.Time{}, err
+	}
+	guestID = hex.EncodeToString(id)
+
+	now := time.Now()
+	expiresAt = now.Add(ttl)
+	claims := jwt.RegisteredClaims{
+		Subject:   guestID,
+		Audience:  jwt.ClaimStrings{guestAudience},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}
+	token, err = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	if err != nil {
+		return "", "", time.Time{}, err
+	}
+	return token, guestID, expiresAt, nil
+}
+
+// ParseGuestToken validates a guest cart token and returns the guest id it names
+func ParseGuestToken(secret []byte, token string) (string, error) {
+	claims := &jwt.RegisteredClaims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return secret, nil
+	}, jwt.WithAudience(guestAudience), jwt.WithExpirationRequired())
+	if err != nil || !parsed.Valid || claims.Subject == "" {
+		return "", errInvalidGuestToken
+	}
+	return claims.Subject, nil
+}
+
+// GuestOrJWTAuthMiddleware guards the cart routes, which anonymous shoppers use too.
+// A request with an Authorization header is authenticated as with JWTAuthMiddleware, a request without one
+// needs a guest cart token. A guest token sent along with a JWT is validated and kept, so the guest cart
+// can be merged into the user's cart.
+func GuestOrJWTAuthMiddleware(jwtSecret, guestSecret []byte) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			guestToken := r.Header.Get(GuestTokenHeader)
+			if guestToken != "" {
+				guestID, err := ParseGuestToken(guestSecret, guestToken)
+				if err != nil {
+					http.Error(w, `{"error":"invalid guest token"}`, http.StatusUnauthorized)
+					return
+				}
+				ctx = context.WithValue(ctx, GuestIDKey, guestID)
+			}
+
+			authHeader := r.Header.Get("Authorization")
+			if authHeader == "" {
+				if guestToken == "" {
+					http.Error(w, `{"error":"missing authorization header or guest token"}`, http.StatusUnauthorized)
+					return
+				}
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
+			claims, errBody := parseBearerToken(authHeader, jwtSecret)
+			if errBody != "" {
+				http.Error(w, errBody, http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(withClaims(ctx, claims)))
+		})
+	}
+}
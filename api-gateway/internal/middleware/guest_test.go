@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testGuestSecret = "test-guest-secret"
+
+func TestGuestToken_RoundTrip(t *testing.T) {
+	token, guestID, expiresAt, err := IssueGuestToken([]byte(testGuestSecret), time.Hour)
+	if err != nil {
+		t.Fatalf("failed to issue guest token: %v", err)
+	}
+	if len(guestID) != 32 {
+		t.Errorf("expected a 32 character guest id, got %q", guestID)
+	}
+	if time.Until(expiresAt) <= 0 {
+		t.Errorf("expected expiry in the future, got %v", expiresAt)
+	}
+
+	got, err := ParseGuestToken([]byte(testGuestSecret), token)
+	if err != nil || got != guestID {
+		t.Errorf("expected guest id %q, got %q (err %v)", guestID, got, err)
+	}
+
+	if _, err := ParseGuestToken([]byte("other-secret"), token); err == nil {
+		t.Error("expected a token signed with another secret to be rejected")
+	}
+
+	expired, _, _, _ := IssueGuestToken([]byte(testGuestSecret), -time.Minute)
+	if _, err := ParseGuestToken([]byte(testGuestSecret), expired); err == nil {
+		t.Error("expected an expired token to be rejected")
+	}
+}
+
+func TestParseGuestToken_RejectsUserJWT(t *testing.T) {
+	// a user JWT signed with the same secret lacks the guest audience
+	userToken := signedToken(t, jwt.MapClaims{"user_id": 1, "sub": "1"})
+	if _, err := ParseGuestToken([]byte(testSecret), userToken); err == nil {
+		t.Error("expected a user JWT to be rejected as guest token")
+	}
+}
+
+func TestGuestOrJWTAuthMiddleware(t *testing.T) {
+	guestToken, guestID, _, err := IssueGuestToken([]byte(testGuestSecret), time.Hour)
+	if err != nil {
+		t.Fatalf("failed to issue guest token: %v", err)
+	}
+	userToken := signedToken(t, jwt.MapClaims{"user_id": 7})
+
+	tests := []struct {
+		name       string
+		auth       string
+		guest      string
+		wantStatus int
+		wantOwner  string
+	}{
+		{"guest token only", "", guestToken, http.StatusOK, "user=0 guest=" + guestID},
+		{"jwt only", "Bearer " + userToken, "", http.StatusOK, "user=7 guest="},
+		{"jwt and guest token", "Bearer " + userToken, guestToken, http.StatusOK, "user=7 guest=" + guestID},
+		{"neither", "", "", http.StatusUnauthorized, ""},
+		{"invalid guest token", "", "not-a-token", http.StatusUnauthorized, ""},
+		{"invalid jwt", "Bearer not-a-token", guestToken, http.StatusUnauthorized, ""},
+	}
+
+	owner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, _ := r.Context().Value(UserIDKey).(int64)
+		guest, _ := r.Context().Value(GuestIDKey).(string)
+		fmt.Fprintf(w, "user=%d guest=%s", userID, guest)
+	})
+	handler := GuestOrJWTAuthMiddleware([]byte(testSecret), []byte(testGuestSecret))(owner)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest("GET", "/api/v1/cart", nil)
+			if tt.auth != "" {
+				request.Header.Set("Authorization", tt.auth)
+			}
+			if tt.guest != "" {
+				request.Header.Set(GuestTokenHeader, tt.guest)
+			}
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d", tt.wantStatus, recorder.Code)
+			}
+			if tt.wantOwner != "" && recorder.Body.String() != tt.wantOwner {
+				t.Errorf("expected %q, got %q", tt.wantOwner, recorder.Body.String())
+			}
+		})
+	}
+}
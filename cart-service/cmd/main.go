@@ -7,16 +7,19 @@ import (
 	"net"
 	"os"
 	"os/signal"
+	"strconv"
 	"sync"
 	"syscall"
 	"time"
 
+	"github.com/fjod/go_cart/cart-service/internal/abandoned"
 	c "github.com/fjod/go_cart/cart-service/internal/cache"
 	cartgrpc "github.com/fjod/go_cart/cart-service/internal/grpc"
 	poller2 "github.com/fjod/go_cart/cart-service/internal/poller"
 	"github.com/fjod/go_cart/cart-service/internal/repository"
 	s "github.com/fjod/go_cart/cart-service/internal/service"
 	pb "github.com/fjod/go_cart/cart-service/pkg/proto"
+	inventorypb "github.com/fjod/go_cart/inventory-service/pkg/proto"
 	"github.com/fjod/go_cart/pkg/logger"
 	"github.com/fjod/go_cart/pkg/tracing"
 	productpb "github.com/fjod/go_cart/product-service/pkg/proto"
@@ -33,8 +36,34 @@ func main() {
 	// Configuration
 	cartServicePort := getEnv("CART_SERVICE_PORT", "50052")
 	productServiceAddr := getEnv("PRODUCT_SERVICE_ADDR", "localhost:50051")
+	inventoryServiceAddr := getEnv("INVENTORY_SERVICE_ADDR", "localhost:50053")
 	mongoURI := getEnv("MONGO_URI", "mongodb://localhost:27017")
 	mongoDBName := getEnv("MONGO_DB_NAME", "cartdb")
+	guestCartTTL, err := time.ParseDuration(getEnv("GUEST_CART_TTL", repository.DefaultGuestCartTTL.String()))
+	if err != nil || guestCartTTL <= 0 {
+		log.Error("invalid GUEST_CART_TTL", "value", os.Getenv("GUEST_CART_TTL"))
+		os.Exit(1)
+	}
+	abandonedConfig := abandoned.DefaultConfig
+	for _, d := range []struct {
+		key   string
+		value *time.Duration
+	}{
+		{"ABANDONED_CART_AFTER", &abandonedConfig.IdleAfter},
+		{"ABANDONED_CART_MAX_IDLE", &abandonedConfig.MaxIdle},
+		{"ABANDONED_CART_SCAN_INTERVAL", &abandonedConfig.Interval},
+	} {
+		parsed, err := time.ParseDuration(getEnv(d.key, d.value.String()))
+		if err != nil || parsed <= 0 {
+			log.Error("invalid "+d.key, "value", os.Getenv(d.key))
+			os.Exit(1)
+		}
+		*d.value = parsed
+	}
+	if abandonedConfig.MaxIdle <= abandonedConfig.IdleAfter {
+		log.Error("ABANDONED_CART_MAX_IDLE must exceed ABANDONED_CART_AFTER")
+		os.Exit(1)
+	}
 
 	// Set up MongoDB connection
 	ctx := context.Background()
@@ -44,8 +73,13 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := repository.CreateIndexes(ctx, mongoDB); err != nil {
+		log.Error("failed to create MongoDB indexes", "error", err)
+		os.Exit(1)
+	}
+
 	// Create repository
-	repo := repository.NewMongoRepository(mongoDB)
+	repo := repository.NewMongoRepository(mongoDB, guestCartTTL)
 	log.Info("connected to MongoDB", "uri", mongoURI)
 
 	// Set up gRPC connection to Product Service
@@ -63,6 +97,31 @@ func main() {
 	productClient := productpb.NewProductServiceClient(productConn)
 	log.Info("connected to product service", "addr", productServiceAddr)
 
+	// Set up gRPC connection to Inventory Service, cart lines are annotated with their stock
+	inventoryCb := circuitbreaker.New(circuitbreaker.DefaultSettings("inventory-service", log))
+	inventoryConn, err := grpc.NewClient(
+		inventoryServiceAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithUnaryInterceptor(inventoryCb.UnaryClientInterceptor()))
+	if err != nil {
+		log.Error("failed to connect to inventory service", "addr", inventoryServiceAddr, "error", err)
+		os.Exit(1)
+	}
+	defer inventoryConn.Close()
+	inventoryClient := inventorypb.NewInventoryServiceClient(inventoryConn)
+	log.Info("connected to inventory service", "addr", inventoryServiceAddr)
+
+	lowStock, err := strconv.Atoi(getEnv("LOW_STOCK_THRESHOLD", strconv.Itoa(cartgrpc.DefaultLowStockThreshold)))
+	if err != nil || lowStock < 0 {
+		log.Error("invalid LOW_STOCK_THRESHOLD", "value", os.Getenv("LOW_STOCK_THRESHOLD"))
+		os.Exit(1)
+	}
+	stockCheck := cartgrpc.StockCheck{
+		LowStockThreshold: int32(lowStock),
+		RejectOverStock:   getEnv("REJECT_OVER_STOCK", "false") == "true",
+	}
+
 	redisClient := redis.NewClient(&redis.Options{
 		Addr:     getEnv("REDIS_ADDR", "localhost:6379"),
 		Password: getEnv("REDIS_PASSWORD", ""),
@@ -77,7 +136,7 @@ func main() {
 
 	cache := c.NewRedisCache(redisClient)
 	service := s.NewCartService(repo, cache, log)
-	cartServer := cartgrpc.NewCartServiceServer(service, productClient, log)
+	cartServer := cartgrpc.NewCartServiceServer(service, productClient, inventoryClient, stockCheck, log)
 
 	// Set up gRPC server for cart service
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", cartServicePort))
@@ -103,13 +162,18 @@ func main() {
 
 	kafkaPort := getEnv("KAFKA_ADDR", "localhost:9092")
 	poller := poller2.NewPoller(repo, cache, log, kafkaPort)
+	scanner := abandoned.NewScanner(repository.NewAbandonedCartRepository(mongoDB), abandonedConfig, log, kafkaPort)
 	wg := &sync.WaitGroup{}
-	wg.Add(1)
+	wg.Add(2)
 	pollerCtx, pollerCancel := context.WithCancel(ctx)
 	go func() {
 		poller.Run(pollerCtx)
 		wg.Done()
 	}()
+	go func() {
+		scanner.Run(pollerCtx)
+		wg.Done()
+	}()
 
 	chWait := make(chan struct{})
 	go func() {
@@ -140,11 +204,12 @@ func main() {
 	mongoDB.Client().Disconnect(timeoutCtx)
 
 	poller.Close()
+	scanner.Close()
 	select {
 	case <-chWait:
-		log.Info("poller stopped")
+		log.Info("poller and abandoned cart scanner stopped")
 	case <-timeoutCtx.Done():
-		log.Warn("poller did not stop within timeout")
+		log.Warn("poller and abandoned cart scanner did not stop within timeout")
 	}
 
 	log.Info("cart service stopped")
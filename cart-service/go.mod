@@ -4,6 +4,7 @@ go 1.25.0
 
 require (
 	github.com/alicebob/miniredis/v2 v2.35.0
+	github.com/fjod/go_cart/inventory-service v0.0.0-20260209110557-c7b933341acd
 	github.com/fjod/go_cart/product-service v0.0.0-20260104100930-8f0a50ffa8c5
 	github.com/redis/go-redis/v9 v9.17.2
 	github.com/segmentio/kafka-go v0.4.50
@@ -0,0 +1,132 @@
+package abandoned
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/fjod/go_cart/cart-service/internal/domain"
+	r "github.com/fjod/go_cart/cart-service/internal/repository"
+	pk "github.com/fjod/go_cart/pkg/tracing"
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+)
+
+// Topic carries CartAbandoned events keyed by user id
+const Topic = "cart-events"
+
+// Config holds the thresholds of the scanner
+type Config struct {
+	IdleAfter time.Duration // a cart nobody changed for this long is abandoned
+	MaxIdle   time.Duration // carts idle for longer were abandoned before anyone looked and are left alone
+	Interval  time.Duration // how often the carts are scanned
+	BatchSize int           // carts reported per scan at most, the rest follow on the next scans
+}
+
+// DefaultConfig reports carts after an hour without changes, up to a week back
+var DefaultConfig = Config{
+	IdleAfter: time.Hour,
+	MaxIdle:   7 * 24 * time.Hour,
+	Interval:  5 * time.Minute,
+	BatchSize: 100,
+}
+
+type messageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// Scanner looks for the carts of signed-in users that sit untouched and sends a CartAbandoned event for each.
+// A cart is marked before its event goes out, so it is reported once until it changes again; when the event
+// can't be sent the mark is taken back and the cart is tried again on the next scan.
+// A completed checkout empties the cart, which takes it out of the scan.
+type Scanner struct {
+	repo   r.AbandonedCartRepository
+	writer messageWriter
+	config Config
+	logger *slog.Logger
+}
+
+func NewScanner(repo r.AbandonedCartRepository, config Config, log *slog.Logger, brokers ...string) *Scanner {
+	w := &kafka.Writer{
+		Addr:                   kafka.TCP(brokers...),
+		Topic:                  Topic,
+		Balancer:               &kafka.Hash{}, // events of one user stay in order on one partition
+		AllowAutoTopicCreation: true,
+	}
+	return &Scanner{repo, w, config, log}
+}
+
+func (s *Scanner) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.scan(ctx, time.Now())
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Scanner) Close() error {
+	return s.writer.Close()
+}
+
+// scan reports the carts found abandoned at now. It stops at the first event it can't send
+// and leaves the remaining carts to the next scan.
+func (s *Scanner) scan(ctx context.Context, now time.Time) {
+	carts, err := s.repo.FindAbandoned(ctx, now.Add(-s.config.IdleAfter), now.Add(-s.config.MaxIdle), s.config.BatchSize)
+	if err != nil {
+		s.logger.Error("failed to find abandoned carts", "error", err)
+		return
+	}
+
+	for _, cart := range carts {
+		marked, err := s.repo.MarkAbandoned(ctx, cart, now)
+		if err != nil {
+			s.logger.Error("failed to mark abandoned cart", "user_id", cart.UserID, "error", err)
+			return
+		}
+		if !marked {
+			continue // changed, checked out or reported by another instance in the meantime
+		}
+
+		if err := s.publish(ctx, domain.NewCartAbandonedEvent(cart, now)); err != nil {
+			s.logger.Error("failed to publish cart abandoned event", "user_id", cart.UserID, "error", err)
+			if err := s.repo.UnmarkAbandoned(ctx, cart.UserID, now); err != nil {
+				s.logger.Error("failed to unmark abandoned cart", "user_id", cart.UserID, "error", err)
+			}
+			return
+		}
+		s.logger.Info("cart abandoned", "user_id", cart.UserID, "last_activity_at", cart.UpdatedAt)
+	}
+}
+
+func (s *Scanner) publish(ctx context.Context, event domain.CartAbandonedEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+
+	tr := otel.Tracer("kafka")
+	spanCtx, messageSpan := tr.Start(ctx, fmt.Sprintf("kafka - publish - %s", domain.CartAbandonedEventType))
+	defer messageSpan.End()
+
+	headers := []kafka.Header{
+		{Key: "event_type", Value: []byte(domain.CartAbandonedEventType)},
+	}
+	for k, v := range pk.Inject(spanCtx) {
+		headers = append(headers, kafka.Header{Key: k, Value: []byte(v)})
+	}
+
+	msg := kafka.Message{
+		Key:     []byte(event.UserID),
+		Value:   payload,
+		Headers: headers,
+	}
+	return s.writer.WriteMessages(spanCtx, msg)
+}
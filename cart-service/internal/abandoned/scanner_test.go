@@ -0,0 +1,137 @@
+package abandoned
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/fjod/go_cart/cart-service/internal/domain"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStore reports its carts until they are marked, like the carts collection does
+type fakeStore struct {
+	carts     []domain.Cart
+	emptied   map[string]bool // carts emptied by a checkout after they were found
+	marked    map[string]time.Time
+	idleSince time.Time
+	notBefore time.Time
+}
+
+func (f *fakeStore) FindAbandoned(_ context.Context, idleSince, notBefore time.Time, limit int) ([]domain.Cart, error) {
+	f.idleSince, f.notBefore = idleSince, notBefore
+	var found []domain.Cart
+	for _, cart := range f.carts {
+		if _, ok := f.marked[cart.UserID]; !ok && len(found) < limit {
+			found = append(found, cart)
+		}
+	}
+	return found, nil
+}
+
+func (f *fakeStore) MarkAbandoned(_ context.Context, cart domain.Cart, at time.Time) (bool, error) {
+	if _, ok := f.marked[cart.UserID]; ok || f.emptied[cart.UserID] {
+		return false, nil
+	}
+	f.marked[cart.UserID] = at
+	return true, nil
+}
+
+func (f *fakeStore) UnmarkAbandoned(_ context.Context, userID string, at time.Time) error {
+	if f.marked[userID].Equal(at) {
+		delete(f.marked, userID)
+	}
+	return nil
+}
+
+type fakeWriter struct {
+	messages []kafka.Message
+	failOn   int // 1-based index of the write that fails, 0 never fails
+}
+
+func (f *fakeWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	if f.failOn == len(f.messages)+1 {
+		return errors.New("broker unavailable")
+	}
+	f.messages = append(f.messages, msgs...)
+	return nil
+}
+
+func (f *fakeWriter) Close() error { return nil }
+
+func newTestScanner(store *fakeStore, writer *fakeWriter) *Scanner {
+	return &Scanner{repo: store, writer: writer, config: DefaultConfig, logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+}
+
+func idleCarts(now time.Time) []domain.Cart {
+	return []domain.Cart{
+		{UserID: "1", Items: []domain.CartItem{{ProductID: 7, VariantID: 2, SKU: "TSHIRT-M", Quantity: 2}}, PromoCode: "TEN", UpdatedAt: now.Add(-2 * time.Hour)},
+		{UserID: "2", Items: []domain.CartItem{{ProductID: 8, Quantity: 1}}, UpdatedAt: now.Add(-3 * time.Hour)},
+	}
+}
+
+func header(msg kafka.Message, key string) string {
+	for _, h := range msg.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func TestScan_PublishesOncePerCart(t *testing.T) {
+	now := time.Now()
+	store := &fakeStore{carts: idleCarts(now), marked: map[string]time.Time{}}
+	writer := &fakeWriter{}
+	scanner := newTestScanner(store, writer)
+
+	scanner.scan(context.Background(), now)
+	scanner.scan(context.Background(), now.Add(time.Minute))
+
+	require.Len(t, writer.messages, 2, "marked carts are not reported again")
+	assert.Equal(t, "1", string(writer.messages[0].Key))
+	assert.Equal(t, domain.CartAbandonedEventType, header(writer.messages[0], "event_type"))
+	assert.Equal(t, now.Add(time.Minute-DefaultConfig.IdleAfter), store.idleSince)
+	assert.Equal(t, now.Add(time.Minute-DefaultConfig.MaxIdle), store.notBefore)
+
+	var event domain.CartAbandonedEvent
+	require.NoError(t, json.Unmarshal(writer.messages[0].Value, &event))
+	assert.Equal(t, "1", event.UserID)
+	assert.Equal(t, []domain.CartAbandonedItem{{ProductID: 7, VariantID: 2, SKU: "TSHIRT-M", Quantity: 2}}, event.Items)
+	assert.Equal(t, "TEN", event.PromoCode)
+	assert.True(t, event.LastActivityAt.Equal(now.Add(-2*time.Hour)))
+	assert.True(t, event.AbandonedAt.Equal(now))
+}
+
+func TestScan_SkipsCartsCheckedOutMeanwhile(t *testing.T) {
+	now := time.Now()
+	store := &fakeStore{carts: idleCarts(now), marked: map[string]time.Time{}, emptied: map[string]bool{"1": true}}
+	writer := &fakeWriter{}
+
+	newTestScanner(store, writer).scan(context.Background(), now)
+
+	require.Len(t, writer.messages, 1)
+	assert.Equal(t, "2", string(writer.messages[0].Key))
+}
+
+func TestScan_UnmarksWhenPublishFails(t *testing.T) {
+	now := time.Now()
+	store := &fakeStore{carts: idleCarts(now), marked: map[string]time.Time{}}
+	writer := &fakeWriter{failOn: 1}
+	scanner := newTestScanner(store, writer)
+
+	scanner.scan(context.Background(), now)
+
+	assert.Empty(t, writer.messages)
+	assert.Empty(t, store.marked, "the failed cart is unmarked and the rest wait for the next scan")
+
+	writer.failOn = 0
+	scanner.scan(context.Background(), now.Add(time.Minute))
+	assert.Len(t, writer.messages, 2)
+}
@@ -1,17 +1,183 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
+
+// MaxItemQuantity is the most units of one product variant a cart line can hold
+const MaxItemQuantity = 99
+
+// guestKeyPrefix marks the carts of anonymous shoppers, user carts are keyed by the numeric user id
+const guestKeyPrefix = "guest:"
 
 type Cart struct {
 	ID        string     `bson:"_id,omitempty"`
-	UserID    string     `bson:"user_id"`
+	UserID    string     `bson:"user_id"` // cart key: the user id, or GuestCartKey for a guest cart
 	Items     []CartItem `bson:"items"`
 	CreatedAt time.Time  `bson:"created_at"`
 	UpdatedAt time.Time  `bson:"updated_at"`
+	ExpiresAt *time.Time `bson:"expires_at,omitempty"` // set on guest carts only, MongoDB deletes the cart then
+	PromoCode string     `bson:"promo_code,omitempty"` // applied promo code, re-validated whenever the cart is priced
+	// Lists are the items kept out of the cart: the saved-for-later list and the user's wishlists.
+	// They live in the cart document so moving an item between them and the cart is one write.
+	Lists []SavedList `bson:"lists,omitempty"`
+	// AbandonedAt is when a CartAbandoned event went out for the cart. A change after it makes the cart
+	// active again, so the next time it sits untouched it is reported again.
+	AbandonedAt *time.Time `bson:"abandoned_at,omitempty"`
+	// Version goes up with every change. Carts written before versions have none and are at version 0,
+	// so is a cart that doesn't exist yet.
+	Version int64 `bson:"version"`
+}
+
+// SavedList holds items the user keeps out of the cart. The saved-for-later list has no name,
+// wishlists are named.
+type SavedList struct {
+	Name  string     `bson:"name"`
+	Items []CartItem `bson:"items"`
 }
 
+// CartItem is one line of the cart. A product sold per variant takes one line per variant (SKU),
+// a product sold by its id alone has VariantID 0.
 type CartItem struct {
 	ProductID int64     `bson:"product_id"`
+	VariantID int64     `bson:"variant_id,omitempty"`
+	SKU       string    `bson:"sku,omitempty"`
 	Quantity  int       `bson:"quantity"`
 	AddedAt   time.Time `bson:"added_at"`
 }
+
+// Is reports whether the item is the given product variant
+func (i CartItem) Is(productID, variantID int64) bool {
+	return i.ProductID == productID && i.VariantID == variantID
+}
+
+// GuestCartKey is the cart key of an anonymous shopper's cart
+func GuestCartKey(guestID string) string {
+	return guestKeyPrefix + guestID
+}
+
+// IsGuestCartKey reports whether the cart key belongs to a guest cart
+func IsGuestCartKey(key string) bool {
+	return strings.HasPrefix(key, guestKeyPrefix)
+}
+
+// Merge adds the items of another cart to this one. A product variant both carts hold keeps one line
+// with the quantities added up, capped at MaxItemQuantity; the other lines are appended as they are.
+func (c *Cart) Merge(items []CartItem) {
+	for _, item := range items {
+		merged := false
+		for i := range c.Items {
+			if c.Items[i].Is(item.ProductID, item.VariantID) {
+				c.Items[i].Quantity = min(c.Items[i].Quantity+item.Quantity, MaxItemQuantity)
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			c.Items = append(c.Items, item)
+		}
+	}
+}
+
+// Put adds the items the way a single add does: a line of a product variant the cart already holds
+// takes the item's quantity and added time, the other items are appended.
+func (c *Cart) Put(items []CartItem) {
+	for _, item := range items {
+		put := false
+		for i := range c.Items {
+			if c.Items[i].Is(item.ProductID, item.VariantID) {
+				c.Items[i].Quantity = item.Quantity
+				c.Items[i].AddedAt = item.AddedAt
+				put = true
+				break
+			}
+		}
+		if !put {
+			c.Items = append(c.Items, item)
+		}
+	}
+}
+
+// List returns the saved list of that name, nil when the cart has none
+func (c *Cart) List(name string) *SavedList {
+	for i := range c.Lists {
+		if c.Lists[i].Name == name {
+			return &c.Lists[i]
+		}
+	}
+	return nil
+}
+
+// SaveForLater moves the cart line of a product variant to the named list, creating the list when needed.
+// A line of the same product variant already on the list takes the cart line's quantity.
+// It reports false when the cart doesn't hold the product variant.
+func (c *Cart) SaveForLater(productID, variantID int64, list string) bool {
+	item, ok := takeItem(&c.Items, productID, variantID)
+	if !ok {
+		return false
+	}
+	saved := c.List(list)
+	if saved == nil {
+		c.Lists = append(c.Lists, SavedList{Name: list})
+		saved = &c.Lists[len(c.Lists)-1]
+	}
+	putItem(&saved.Items, item)
+	return true
+}
+
+// MoveToCart moves a product variant from the named list back into the cart, the way a single add does.
+// It reports false when the list doesn't hold the product variant.
+func (c *Cart) MoveToCart(productID, variantID int64, list string) bool {
+	saved := c.List(list)
+	if saved == nil {
+		return false
+	}
+	item, ok := takeItem(&saved.Items, productID, variantID)
+	if !ok {
+		return false
+	}
+	c.Put([]CartItem{item})
+	return true
+}
+
+// RemoveSaved drops a product variant from the named list, it reports false when the list doesn't hold it
+func (c *Cart) RemoveSaved(productID, variantID int64, list string) bool {
+	saved := c.List(list)
+	if saved == nil {
+		return false
+	}
+	_, ok := takeItem(&saved.Items, productID, variantID)
+	return ok
+}
+
+// DeleteList drops the named list with its items, it reports false when there is no such list
+func (c *Cart) DeleteList(name string) bool {
+	for i := range c.Lists {
+		if c.Lists[i].Name == name {
+			c.Lists = append(c.Lists[:i], c.Lists[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func takeItem(items *[]CartItem, productID, variantID int64) (CartItem, bool) {
+	for i, item := range *items {
+		if item.Is(productID, variantID) {
+			*items = append((*items)[:i], (*items)[i+1:]...)
+			return item, true
+		}
+	}
+	return CartItem{}, false
+}
+
+func putItem(items *[]CartItem, item CartItem) {
+	for i := range *items {
+		if (*items)[i].Is(item.ProductID, item.VariantID) {
+			(*items)[i].Quantity = item.Quantity
+			return
+		}
+	}
+	*items = append(*items, item)
+}
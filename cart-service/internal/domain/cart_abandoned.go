@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+// CartAbandonedEventType is the event_type header of CartAbandoned events
+const CartAbandonedEventType = "CartAbandoned"
+
+// CartAbandonedEvent is the payload of the CartAbandoned event sent when a user's cart sits untouched
+type CartAbandonedEvent struct {
+	UserID         string              `json:"user_id"`
+	Items          []CartAbandonedItem `json:"items"`
+	PromoCode      string              `json:"promo_code,omitempty"`
+	LastActivityAt time.Time           `json:"last_activity_at"`
+	AbandonedAt    time.Time           `json:"abandoned_at"` // when the cart was found abandoned
+}
+
+type CartAbandonedItem struct {
+	ProductID int64  `json:"product_id"`
+	VariantID int64  `json:"variant_id,omitempty"`
+	SKU       string `json:"sku,omitempty"`
+	Quantity  int    `json:"quantity"`
+}
+
+// NewCartAbandonedEvent builds the event of a cart found abandoned at
+func NewCartAbandonedEvent(cart Cart, at time.Time) CartAbandonedEvent {
+	items := make([]CartAbandonedItem, len(cart.Items))
+	for i, item := range cart.Items {
+		items[i] = CartAbandonedItem{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			SKU:       item.SKU,
+			Quantity:  item.Quantity,
+		}
+	}
+	return CartAbandonedEvent{
+		UserID:         cart.UserID,
+		Items:          items,
+		PromoCode:      cart.PromoCode,
+		LastActivityAt: cart.UpdatedAt,
+		AbandonedAt:    at,
+	}
+}
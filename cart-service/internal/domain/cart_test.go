@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCartMerge(t *testing.T) {
+	cart := Cart{Items: []CartItem{
+		{ProductID: 1, Quantity: 2},
+		{ProductID: 2, VariantID: 7, SKU: "TSHIRT-M", Quantity: 90},
+	}}
+
+	cart.Merge([]CartItem{
+		{ProductID: 1, Quantity: 3},
+		{ProductID: 2, VariantID: 7, SKU: "TSHIRT-M", Quantity: 20},
+		{ProductID: 2, VariantID: 8, SKU: "TSHIRT-L", Quantity: 1},
+	})
+
+	assert.Equal(t, []CartItem{
+		{ProductID: 1, Quantity: 5},
+		{ProductID: 2, VariantID: 7, SKU: "TSHIRT-M", Quantity: MaxItemQuantity},
+		{ProductID: 2, VariantID: 8, SKU: "TSHIRT-L", Quantity: 1},
+	}, cart.Items)
+}
+
+func TestCartPut(t *testing.T) {
+	cart := Cart{Items: []CartItem{
+		{ProductID: 1, Quantity: 2},
+		{ProductID: 2, VariantID: 7, SKU: "TSHIRT-M", Quantity: 90},
+	}}
+
+	cart.Put([]CartItem{
+		{ProductID: 1, Quantity: 3},
+		{ProductID: 2, VariantID: 8, SKU: "TSHIRT-L", Quantity: 1},
+	})
+
+	assert.Equal(t, []CartItem{
+		{ProductID: 1, Quantity: 3},
+		{ProductID: 2, VariantID: 7, SKU: "TSHIRT-M", Quantity: 90},
+		{ProductID: 2, VariantID: 8, SKU: "TSHIRT-L", Quantity: 1},
+	}, cart.Items)
+}
+
+func TestGuestCartKey(t *testing.T) {
+	assert.True(t, IsGuestCartKey(GuestCartKey("3f2a")))
+	assert.False(t, IsGuestCartKey("42"))
+}
+
+func TestCartSaveForLaterAndMoveToCart(t *testing.T) {
+	cart := Cart{Items: []CartItem{
+		{ProductID: 1, Quantity: 2},
+		{ProductID: 2, VariantID: 7, SKU: "TSHIRT-M", Quantity: 1},
+	}}
+
+	assert.True(t, cart.SaveForLater(2, 7, ""))
+	assert.True(t, cart.SaveForLater(1, 0, "birthday"))
+	assert.False(t, cart.SaveForLater(1, 0, "birthday"), "the line has left the cart")
+
+	assert.Empty(t, cart.Items)
+	assert.Equal(t, []CartItem{{ProductID: 2, VariantID: 7, SKU: "TSHIRT-M", Quantity: 1}}, cart.List("").Items)
+	assert.Equal(t, []CartItem{{ProductID: 1, Quantity: 2}}, cart.List("birthday").Items)
+
+	assert.False(t, cart.MoveToCart(2, 7, "birthday"), "the item is on another list")
+	assert.True(t, cart.MoveToCart(2, 7, ""))
+	assert.Equal(t, []CartItem{{ProductID: 2, VariantID: 7, SKU: "TSHIRT-M", Quantity: 1}}, cart.Items)
+	assert.Empty(t, cart.List("").Items, "an emptied list stays")
+}
+
+func TestCartSaveForLater_KeepsOneLinePerVariant(t *testing.T) {
+	cart := Cart{
+		Items: []CartItem{{ProductID: 1, Quantity: 5}},
+		Lists: []SavedList{{Name: "", Items: []CartItem{{ProductID: 1, Quantity: 2}}}},
+	}
+
+	assert.True(t, cart.SaveForLater(1, 0, ""))
+
+	assert.Equal(t, []CartItem{{ProductID: 1, Quantity: 5}}, cart.List("").Items)
+}
+
+func TestCartRemoveSavedAndDeleteList(t *testing.T) {
+	cart := Cart{Lists: []SavedList{
+		{Name: "", Items: []CartItem{{ProductID: 1, Quantity: 1}}},
+		{Name: "birthday", Items: []CartItem{{ProductID: 2, Quantity: 1}}},
+	}}
+
+	assert.True(t, cart.RemoveSaved(1, 0, ""))
+	assert.False(t, cart.RemoveSaved(1, 0, ""))
+	assert.True(t, cart.DeleteList("birthday"))
+	assert.False(t, cart.DeleteList("birthday"))
+	assert.Nil(t, cart.List("birthday"))
+}
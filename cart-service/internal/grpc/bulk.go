@@ -0,0 +1,142 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/fjod/go_cart/cart-service/internal/domain"
+	"github.com/fjod/go_cart/cart-service/internal/repository"
+	pb "github.com/fjod/go_cart/cart-service/pkg/proto"
+	"github.com/fjod/go_cart/pkg/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxBulkItems bounds the lines of one AddItems or ReplaceCart request
+const maxBulkItems = 100
+
+// AddItems adds all the items in one write, or none of them when any line fails validation
+func (s *CartServiceServer) AddItems(
+	ctx context.Context,
+	req *pb.AddItemsRequest) (*pb.CartResponse, error) {
+
+	log := logger.WithContext(s.logger, ctx)
+	owner, err := resolveOwner(req.UserId, req.GuestId)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("add items", owner.logAttr(), slog.Int("items", len(req.Items)))
+
+	if len(req.Items) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "items must not be empty")
+	}
+	items, err := s.cartLines(ctx, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	cart, err := s.service.AddItems(ctx, owner.key(), items, req.ExpectedVersion)
+	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return nil, errStaleVersion
+		}
+		return nil, status.Errorf(codes.Internal, "failed to add items to cart: %v", err)
+	}
+
+	return &pb.CartResponse{
+		Cart: s.stockedCart(ctx, *cart, owner),
+	}, nil
+}
+
+// ReplaceCart swaps the lines of the cart for the items in one write, the cart is left as it is
+// when any line fails validation
+func (s *CartServiceServer) ReplaceCart(
+	ctx context.Context,
+	req *pb.ReplaceCartRequest) (*pb.CartResponse, error) {
+
+	log := logger.WithContext(s.logger, ctx)
+	owner, err := resolveOwner(req.UserId, req.GuestId)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("replace cart", owner.logAttr(), slog.Int("items", len(req.Items)))
+
+	items, err := s.cartLines(ctx, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	cart, err := s.service.ReplaceCart(ctx, owner.key(), items, req.ExpectedVersion)
+	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return nil, errStaleVersion
+		}
+		return nil, status.Errorf(codes.Internal, "failed to replace cart: %v", err)
+	}
+
+	return &pb.CartResponse{
+		Cart: s.stockedCart(ctx, *cart, owner),
+	}, nil
+}
+
+// cartLines validates the lines of a bulk request the way AddItem validates one, with a single
+// product-service call for all of them. The first line that fails fails the whole request.
+func (s *CartServiceServer) cartLines(ctx context.Context, lines []*pb.CartItemInput) ([]domain.CartItem, error) {
+	if len(lines) > maxBulkItems {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d items can be changed at once", maxBulkItems)
+	}
+
+	var ids []int64
+	seen := make(map[stockKey]bool, len(lines))
+	for _, line := range lines {
+		if line.ProductId <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "product_id must be greater than 0")
+		}
+		if line.VariantId < 0 {
+			return nil, status.Error(codes.InvalidArgument, "variant_id must not be negative")
+		}
+		if line.Quantity <= 0 || line.Quantity > domain.MaxItemQuantity {
+			return nil, status.Errorf(codes.InvalidArgument, "quantity must be between 1 and %d", domain.MaxItemQuantity)
+		}
+		key := stockKey{line.ProductId, line.VariantId}
+		if seen[key] {
+			return nil, status.Errorf(codes.InvalidArgument, "product %d variant %d is listed twice", line.ProductId, line.VariantId)
+		}
+		seen[key] = true
+		ids = append(ids, line.ProductId)
+	}
+
+	items := make([]domain.CartItem, len(lines))
+	if len(lines) == 0 {
+		return items, nil
+	}
+	products, err := s.lookupProducts(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for i, line := range lines {
+		product := products[line.ProductId]
+		if product.ArchivedAt != "" {
+			return nil, status.Errorf(codes.FailedPrecondition, "product %d is no longer available", line.ProductId)
+		}
+		sku, err := variantSKU(product, line.VariantId)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = domain.CartItem{
+			ProductID: line.ProductId,
+			VariantID: line.VariantId,
+			SKU:       sku,
+			Quantity:  int(line.Quantity),
+			AddedAt:   now,
+		}
+	}
+
+	if err := s.checkStockLines(ctx, items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,133 @@
+package grpc
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/fjod/go_cart/cart-service/internal/domain"
+	pb "github.com/fjod/go_cart/cart-service/pkg/proto"
+	inventorypb "github.com/fjod/go_cart/inventory-service/pkg/proto"
+	productpb "github.com/fjod/go_cart/product-service/pkg/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func tshirtClient() *mockProductServiceClient {
+	return &mockProductServiceClient{getProductResp: &productpb.GetProductResponse{
+		Product: &productpb.Product{Id: 1, Name: "T-shirt", Variants: []*productpb.ProductVariant{
+			{Id: 7, Sku: "TSHIRT-M"},
+			{Id: 8, Sku: "TSHIRT-L"},
+		}},
+	}}
+}
+
+func TestAddItems(t *testing.T) {
+	cart := &domain.Cart{UserID: "123", Items: []domain.CartItem{{ProductID: 1, VariantID: 7, SKU: "TSHIRT-M", Quantity: 1}}}
+	server := NewCartServiceServer(createCacheAndRepo(cart), tshirtClient(), nil, StockCheck{}, slog.Default())
+
+	resp, err := server.AddItems(context.Background(), &pb.AddItemsRequest{UserId: 123, Items: []*pb.CartItemInput{
+		{ProductId: 1, VariantId: 7, Quantity: 2},
+		{ProductId: 1, VariantId: 8, Quantity: 1},
+	}})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Cart.Cart, 2)
+	assert.Equal(t, int32(2), resp.Cart.Cart[0].Quantity, "a line the cart holds takes the new quantity")
+	assert.Equal(t, "TSHIRT-L", resp.Cart.Cart[1].Sku)
+	assert.Equal(t, int64(1), resp.Cart.Version)
+}
+
+func TestAddItems_AllOrNothing(t *testing.T) {
+	tests := []struct {
+		name     string
+		items    []*pb.CartItemInput
+		wantCode codes.Code
+	}{
+		{"no items", nil, codes.InvalidArgument},
+		{"unknown product", []*pb.CartItemInput{{ProductId: 1, VariantId: 7, Quantity: 1}, {ProductId: 2, Quantity: 1}}, codes.NotFound},
+		{"unknown variant", []*pb.CartItemInput{{ProductId: 1, VariantId: 7, Quantity: 1}, {ProductId: 1, VariantId: 9, Quantity: 1}}, codes.NotFound},
+		{"listed twice", []*pb.CartItemInput{{ProductId: 1, VariantId: 7, Quantity: 1}, {ProductId: 1, VariantId: 7, Quantity: 2}}, codes.InvalidArgument},
+		{"bad quantity", []*pb.CartItemInput{{ProductId: 1, VariantId: 7, Quantity: 1}, {ProductId: 1, VariantId: 8, Quantity: 100}}, codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cart := &domain.Cart{UserID: "123", Items: []domain.CartItem{{ProductID: 5, Quantity: 1}}}
+			server := NewCartServiceServer(createCacheAndRepo(cart), tshirtClient(), nil, StockCheck{}, slog.Default())
+
+			_, err := server.AddItems(context.Background(), &pb.AddItemsRequest{UserId: 123, Items: tt.items})
+
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Equal(t, []domain.CartItem{{ProductID: 5, Quantity: 1}}, cart.Items)
+			assert.Zero(t, cart.Version)
+		})
+	}
+}
+
+func TestAddItems_RejectOverStock(t *testing.T) {
+	cart := &domain.Cart{UserID: "123"}
+	inventory := &mockInventoryServiceClient{stocks: []*inventorypb.StockInfo{
+		{ProductId: 1, VariantId: 7, Available: 5},
+		{ProductId: 1, VariantId: 8, Available: 1},
+	}}
+	server := NewCartServiceServer(createCacheAndRepo(cart), tshirtClient(), inventory, StockCheck{RejectOverStock: true}, slog.Default())
+
+	_, err := server.AddItems(context.Background(), &pb.AddItemsRequest{UserId: 123, Items: []*pb.CartItemInput{
+		{ProductId: 1, VariantId: 7, Quantity: 2},
+		{ProductId: 1, VariantId: 8, Quantity: 2},
+	}})
+
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Empty(t, cart.Items)
+}
+
+func TestReplaceCart(t *testing.T) {
+	cart := &domain.Cart{
+		UserID:    "123",
+		Items:     []domain.CartItem{{ProductID: 5, Quantity: 1}},
+		PromoCode: "TEN",
+		Version:   4,
+	}
+	server := NewCartServiceServer(createCacheAndRepo(cart), tshirtClient(), nil, StockCheck{}, slog.Default())
+
+	resp, err := server.ReplaceCart(context.Background(), &pb.ReplaceCartRequest{
+		UserId:          123,
+		Items:           []*pb.CartItemInput{{ProductId: 1, VariantId: 8, Quantity: 3}},
+		ExpectedVersion: proto.Int64(4),
+	})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Cart.Cart, 1)
+	assert.Equal(t, int64(1), resp.Cart.Cart[0].ProductId)
+	assert.Equal(t, int32(3), resp.Cart.Cart[0].Quantity)
+	assert.Equal(t, "TEN", resp.Cart.PromoCode, "the promo code stays on the cart")
+	assert.Equal(t, int64(5), resp.Cart.Version)
+}
+
+func TestReplaceCart_Empty(t *testing.T) {
+	cart := &domain.Cart{UserID: "123", Items: []domain.CartItem{{ProductID: 5, Quantity: 1}}}
+	server := NewCartServiceServer(createCacheAndRepo(cart), tshirtClient(), nil, StockCheck{}, slog.Default())
+
+	resp, err := server.ReplaceCart(context.Background(), &pb.ReplaceCartRequest{UserId: 123})
+
+	require.NoError(t, err)
+	assert.Empty(t, resp.Cart.Cart)
+}
+
+func TestReplaceCart_StaleVersion(t *testing.T) {
+	cart := &domain.Cart{UserID: "123", Items: []domain.CartItem{{ProductID: 5, Quantity: 1}}, Version: 4}
+	server := NewCartServiceServer(createCacheAndRepo(cart), tshirtClient(), nil, StockCheck{}, slog.Default())
+
+	_, err := server.ReplaceCart(context.Background(), &pb.ReplaceCartRequest{
+		UserId:          123,
+		Items:           []*pb.CartItemInput{{ProductId: 1, VariantId: 8, Quantity: 3}},
+		ExpectedVersion: proto.Int64(3),
+	})
+
+	assert.Equal(t, codes.Aborted, status.Code(err))
+	assert.Equal(t, []domain.CartItem{{ProductID: 5, Quantity: 1}}, cart.Items)
+}
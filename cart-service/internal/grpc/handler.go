@@ -11,6 +11,7 @@ import (
 	"github.com/fjod/go_cart/cart-service/internal/repository"
 	s "github.com/fjod/go_cart/cart-service/internal/service"
 	pb "github.com/fjod/go_cart/cart-service/pkg/proto"
+	inventorypb "github.com/fjod/go_cart/inventory-service/pkg/proto"
 	"github.com/fjod/go_cart/pkg/logger"
 	productpb "github.com/fjod/go_cart/product-service/pkg/proto"
 	"google.golang.org/grpc/codes"
@@ -19,39 +20,113 @@ import (
 
 const timeFormat string = "2006-01-02T15:04:05Z07:00"
 
+// errStaleVersion answers a change whose expected_version is no longer the cart's version
+var errStaleVersion = status.Error(codes.Aborted, "cart was changed by another request, read it again and retry")
+
 type CartServiceServer struct {
 	pb.UnimplementedCartServiceServer
-	service       *s.CartService
-	productClient productpb.ProductServiceClient
-	logger        *slog.Logger
+	service         *s.CartService
+	productClient   productpb.ProductServiceClient
+	inventoryClient inventorypb.InventoryServiceClient // nil turns the stock annotations off
+	stockCheck      StockCheck
+	logger          *slog.Logger
 }
 
-func NewCartServiceServer(service *s.CartService, productClient productpb.ProductServiceClient, logger *slog.Logger) *CartServiceServer {
+func NewCartServiceServer(
+	service *s.CartService,
+	productClient productpb.ProductServiceClient,
+	inventoryClient inventorypb.InventoryServiceClient,
+	stockCheck StockCheck,
+	logger *slog.Logger) *CartServiceServer {
 	return &CartServiceServer{
-		service:       service,
-		productClient: productClient,
-		logger:        logger,
+		service:         service,
+		productClient:   productClient,
+		inventoryClient: inventoryClient,
+		stockCheck:      stockCheck,
+		logger:          logger,
 	}
 }
 
-func convertCart(c domain.Cart, userId int64) *pb.Cart {
+// cartOwner is whoever a cart request acts for: a signed-in user or an anonymous shopper's guest id
+type cartOwner struct {
+	userID  int64
+	guestID string
+}
+
+// maxGuestIDLength bounds the guest ids taken from the gateway's guest cart tokens
+const maxGuestIDLength = 64
+
+// resolveOwner checks that a request names exactly one of user_id and guest_id
+func resolveOwner(userID int64, guestID string) (cartOwner, error) {
+	if guestID == "" {
+		if userID <= 0 {
+			return cartOwner{}, status.Error(codes.InvalidArgument, "user_id must be greater than 0")
+		}
+		return cartOwner{userID: userID}, nil
+	}
+	if userID != 0 {
+		return cartOwner{}, status.Error(codes.InvalidArgument, "user_id and guest_id are mutually exclusive")
+	}
+	if err := validateGuestID(guestID); err != nil {
+		return cartOwner{}, err
+	}
+	return cartOwner{guestID: guestID}, nil
+}
+
+func validateGuestID(guestID string) error {
+	if guestID == "" || len(guestID) > maxGuestIDLength {
+		return status.Errorf(codes.InvalidArgument, "guest_id must be 1 to %d characters", maxGuestIDLength)
+	}
+	for _, c := range guestID {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return status.Error(codes.InvalidArgument, "guest_id may only hold letters, digits, '-' and '_'")
+		}
+	}
+	return nil
+}
+
+// key is the repository key of the owner's cart
+func (o cartOwner) key() string {
+	if o.guestID != "" {
+		return domain.GuestCartKey(o.guestID)
+	}
+	return fmt.Sprintf("%d", o.userID)
+}
+
+func (o cartOwner) logAttr() slog.Attr {
+	if o.guestID != "" {
+		return slog.String("guest_id", o.guestID)
+	}
+	return slog.Int64("user_id", o.userID)
+}
+
+func convertCart(c domain.Cart, owner cartOwner) *pb.Cart {
 	cart := &pb.Cart{
 		Id:        c.ID,
-		UserId:    userId, // Use the request user_id
-		Cart:      make([]*pb.CartItem, len(c.Items)),
+		UserId:    owner.userID, // Use the request user_id
+		GuestId:   owner.guestID,
+		Cart:      convertItems(c.Items),
 		CreatedAt: c.CreatedAt.Format(timeFormat),
 		UpdatedAt: c.UpdatedAt.Format(timeFormat),
+		PromoCode: c.PromoCode,
+		Version:   c.Version,
 	}
 
-	for i, item := range c.Items {
-		cart.Cart[i] = &pb.CartItem{
+	return cart
+}
+
+func convertItems(items []domain.CartItem) []*pb.CartItem {
+	out := make([]*pb.CartItem, len(items))
+	for i, item := range items {
+		out[i] = &pb.CartItem{
 			ProductId: item.ProductID,
+			VariantId: item.VariantID,
+			Sku:       item.SKU,
 			Quantity:  int32(item.Quantity),
 			AddedAt:   item.AddedAt.Format(timeFormat),
 		}
 	}
-
-	return cart
+	return out
 }
 
 func (s *CartServiceServer) GetCart(
@@ -59,20 +134,19 @@ func (s *CartServiceServer) GetCart(
 	req *pb.GetCartRequest) (*pb.CartResponse, error) {
 
 	log := logger.WithContext(s.logger, ctx)
-	log.Info("get cart", slog.Int64("user_id", req.UserId))
-
-	if req.UserId <= 0 {
-		log.Warn("invalid user_id: must be greater than 0", slog.Int64("user_id", req.UserId))
-		return nil, status.Error(codes.InvalidArgument, "user_id must be greater than 0")
+	owner, err := resolveOwner(req.UserId, req.GuestId)
+	if err != nil {
+		log.Warn("invalid cart owner", slog.Int64("user_id", req.UserId), slog.Any("error", err))
+		return nil, err
 	}
+	log.Info("get cart", owner.logAttr())
 
-	userID := fmt.Sprintf("%d", req.UserId)
-	cart, err := s.service.GetCart(ctx, userID)
+	cart, err := s.service.GetCart(ctx, owner.key())
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to get cart: %v", err)
 	}
 
-	protoCart := convertCart(*cart, req.UserId)
+	protoCart := s.stockedCart(ctx, *cart, owner)
 
 	return &pb.CartResponse{
 		Cart: protoCart,
@@ -90,42 +164,54 @@ func (s *CartServiceServer) AddItem(
 	if req.ProductId <= 0 {
 		return nil, status.Error(codes.InvalidArgument, "product_id must be greater than 0")
 	}
+	if req.VariantId < 0 {
+		return nil, status.Error(codes.InvalidArgument, "variant_id must not be negative")
+	}
 	if req.Quantity <= 0 {
 		return nil, status.Error(codes.InvalidArgument, "quantity must be greater than 0")
 	}
-	if req.UserId <= 0 {
-		return nil, status.Error(codes.InvalidArgument, "user_id must be greater than 0")
+	owner, err := resolveOwner(req.UserId, req.GuestId)
+	if err != nil {
+		return nil, err
 	}
 
 	// Call product-service to validate if product exists
-	_, err := s.productClient.GetProduct(ctx, &productpb.GetProductRequest{
-		Id: req.ProductId,
-	})
+	products, err := s.lookupProducts(ctx, req.ProductId)
 	if err != nil {
-		// Check if product not found
-		if st, ok := status.FromError(err); ok {
-			if st.Code() == codes.NotFound {
-				log.Error("product not found", slog.String("product_id", fmt.Sprintf("%d", req.ProductId)))
-				return nil, status.Error(codes.NotFound, "product not found")
-			}
-		}
-		log.Error("failed to validate product", slog.String("product_id", fmt.Sprintf("%d", req.ProductId)))
-		return nil, status.Errorf(codes.Internal, "failed to validate product: %v", err)
+		log.Error("failed to validate product", slog.String("product_id", fmt.Sprintf("%d", req.ProductId)), slog.Any("error", err))
+		return nil, err
+	}
+	// archived products stay in the carts that already hold them, but can't be added anew
+	product := products[req.ProductId]
+	if product.ArchivedAt != "" {
+		return nil, status.Error(codes.FailedPrecondition, "product is no longer available")
+	}
+	sku, err := variantSKU(product, req.VariantId)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkStock(ctx, req.ProductId, req.VariantId, req.Quantity); err != nil {
+		return nil, err
 	}
 
-	// Convert user_id to string for MongoDB
-	userID := fmt.Sprintf("%d", req.UserId)
+	// user id or guest cart key for MongoDB
+	userID := owner.key()
 
 	// Create cart item
 	cartItem := domain.CartItem{
 		ProductID: req.ProductId,
+		VariantID: req.VariantId,
+		SKU:       sku,
 		Quantity:  int(req.Quantity),
 		AddedAt:   time.Now(),
 	}
 
 	// Add item to cart via repository
-	err = s.service.AddItem(ctx, userID, cartItem)
+	err = s.service.AddItem(ctx, userID, cartItem, req.ExpectedVersion)
 	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return nil, errStaleVersion
+		}
 		return nil, status.Errorf(codes.Internal, "failed to add item to cart: %v", err)
 	}
 
@@ -135,7 +221,7 @@ func (s *CartServiceServer) AddItem(
 		return nil, status.Errorf(codes.Internal, "failed to get cart: %v", err)
 	}
 
-	protoCart := convertCart(*cart, req.UserId)
+	protoCart := s.stockedCart(ctx, *cart, owner)
 
 	return &pb.CartResponse{
 		Cart: protoCart,
@@ -150,8 +236,9 @@ func (s *CartServiceServer) UpdateQuantity(
 	log.Info("update quantity", slog.String("product_id", fmt.Sprintf("%d", req.ProductId)))
 
 	// Validate input
-	if req.UserId <= 0 {
-		return nil, status.Error(codes.InvalidArgument, "user_id must be greater than 0")
+	owner, err := resolveOwner(req.UserId, req.GuestId)
+	if err != nil {
+		return nil, err
 	}
 	if req.ProductId <= 0 {
 		return nil, status.Error(codes.InvalidArgument, "product_id must be greater than 0")
@@ -160,11 +247,18 @@ func (s *CartServiceServer) UpdateQuantity(
 		return nil, status.Error(codes.InvalidArgument, "quantity must be between 1 and 99")
 	}
 
-	userID := fmt.Sprintf("%d", req.UserId)
+	if err := s.checkStock(ctx, req.ProductId, req.VariantId, req.Quantity); err != nil {
+		return nil, err
+	}
+
+	userID := owner.key()
 
 	// Update item quantity in repository
-	err := s.service.UpdateQuantity(ctx, userID, req.ProductId, int(req.Quantity))
+	err = s.service.UpdateQuantity(ctx, userID, req.ProductId, req.VariantId, int(req.Quantity), req.ExpectedVersion)
 	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return nil, errStaleVersion
+		}
 		// Check if item was not found in cart
 		if errors.Is(err, repository.ErrItemNotFound) {
 			log.Warn("item not found in cart", slog.Int64("product_id", req.ProductId))
@@ -179,7 +273,7 @@ func (s *CartServiceServer) UpdateQuantity(
 		return nil, status.Errorf(codes.Internal, "failed to get cart: %v", err)
 	}
 
-	protoCart := convertCart(*cart, req.UserId)
+	protoCart := s.stockedCart(ctx, *cart, owner)
 
 	return &pb.CartResponse{
 		Cart: protoCart,
@@ -194,18 +288,22 @@ func (s *CartServiceServer) RemoveItem(
 	log.Info("remove item", slog.String("product_id", fmt.Sprintf("%d", req.ProductId)))
 
 	// Validate input
-	if req.UserId <= 0 {
-		return nil, status.Error(codes.InvalidArgument, "user_id must be greater than 0")
+	owner, err := resolveOwner(req.UserId, req.GuestId)
+	if err != nil {
+		return nil, err
 	}
 	if req.ProductId <= 0 {
 		return nil, status.Error(codes.InvalidArgument, "product_id must be greater than 0")
 	}
 
-	userID := fmt.Sprintf("%d", req.UserId)
+	userID := owner.key()
 
 	// Remove item from repository
-	err := s.service.RemoveItem(ctx, userID, req.ProductId)
+	err = s.service.RemoveItem(ctx, userID, req.ProductId, req.VariantId, req.ExpectedVersion)
 	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return nil, errStaleVersion
+		}
 		return nil, status.Errorf(codes.Internal, "failed to remove item: %v", err)
 	}
 
@@ -215,7 +313,7 @@ func (s *CartServiceServer) RemoveItem(
 		return nil, status.Errorf(codes.Internal, "failed to get cart: %v", err)
 	}
 
-	protoCart := convertCart(*cart, req.UserId)
+	protoCart := s.stockedCart(ctx, *cart, owner)
 
 	return &pb.CartResponse{
 		Cart: protoCart,
@@ -227,18 +325,19 @@ func (s *CartServiceServer) ClearCart(
 	req *pb.ClearCartRequest) (*pb.CartResponse, error) {
 
 	log := logger.WithContext(s.logger, ctx)
-	log.Info("clear cart", slog.String("user_id", fmt.Sprintf("%d", req.UserId)))
-
 	// Validate input
-	if req.UserId <= 0 {
-		return nil, status.Error(codes.InvalidArgument, "user_id must be greater than 0")
+	owner, err := resolveOwner(req.UserId, req.GuestId)
+	if err != nil {
+		return nil, err
 	}
+	log.Info("clear cart", owner.logAttr())
 
-	userID := fmt.Sprintf("%d", req.UserId)
-
-	// Delete cart from repository
-	err := s.service.ClearCart(ctx, userID)
+	// Empty the cart in the repository
+	err = s.service.ClearCart(ctx, owner.key(), req.ExpectedVersion)
 	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return nil, errStaleVersion
+		}
 		if errors.Is(err, repository.ErrCartNotFound) {
 			// Cart already cleared — treat as success (idempotent operation)
 		} else {
@@ -246,15 +345,96 @@ func (s *CartServiceServer) ClearCart(
 		}
 	}
 
-	// Return empty cart response
-	emptyCart := &pb.Cart{
-		UserId:    req.UserId,
-		Cart:      []*pb.CartItem{},
-		CreatedAt: time.Now().Format(timeFormat),
-		UpdatedAt: time.Now().Format(timeFormat),
+	// Get the emptied cart, its version moved on with the clear
+	cart, err := s.service.GetCart(ctx, owner.key())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get cart: %v", err)
 	}
 
 	return &pb.CartResponse{
-		Cart: emptyCart,
+		Cart: s.stockedCart(ctx, *cart, owner),
 	}, nil
 }
+
+// MergeCarts moves a guest cart into the cart of the user who just signed in. Quantities of a product variant
+// both carts hold are added up; the guest cart is emptied. Merging a guest cart that is already gone or empty is a no-op.
+func (s *CartServiceServer) MergeCarts(
+	ctx context.Context,
+	req *pb.MergeCartsRequest) (*pb.CartResponse, error) {
+
+	log := logger.WithContext(s.logger, ctx)
+	log.Info("merge carts", slog.String("guest_id", req.GuestId), slog.Int64("user_id", req.UserId))
+
+	if req.UserId <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id must be greater than 0")
+	}
+	if err := validateGuestID(req.GuestId); err != nil {
+		return nil, err
+	}
+
+	owner := cartOwner{userID: req.UserId}
+	cart, err := s.service.MergeCarts(ctx, domain.GuestCartKey(req.GuestId), owner.key(), req.ExpectedVersion)
+	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return nil, errStaleVersion
+		}
+		return nil, status.Errorf(codes.Internal, "failed to merge carts: %v", err)
+	}
+
+	return &pb.CartResponse{
+		Cart: s.stockedCart(ctx, *cart, owner),
+	}, nil
+}
+
+// variantSKU checks the requested variant against the product: a product sold per variant needs one of its
+// variants that is still on sale, a product sold by its id alone takes variant 0. It returns the variant SKU.
+func variantSKU(product *productpb.Product, variantID int64) (string, error) {
+	if len(product.Variants) == 0 {
+		if variantID != 0 {
+			return "", status.Errorf(codes.NotFound, "variant %d of product %d not found", variantID, product.Id)
+		}
+		return "", nil
+	}
+	if variantID == 0 {
+		return "", status.Errorf(codes.InvalidArgument, "variant_id is required, product %d is sold per variant", product.Id)
+	}
+	for _, v := range product.Variants {
+		if v.Id != variantID {
+			continue
+		}
+		if v.ArchivedAt != "" {
+			return "", status.Error(codes.FailedPrecondition, "variant is no longer available")
+		}
+		return v.Sku, nil
+	}
+	return "", status.Errorf(codes.NotFound, "variant %d of product %d not found", variantID, product.Id)
+}
+
+// lookupProducts fetches every listed product in one product-service call.
+// A missing product is reported as NotFound, so callers can return the error as is.
+func (s *CartServiceServer) lookupProducts(ctx context.Context, ids ...int64) (map[int64]*productpb.Product, error) {
+	products, err := s.fetchProducts(ctx, ids)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to validate product: %v", err)
+	}
+	for _, id := range ids {
+		if _, ok := products[id]; !ok {
+			return nil, status.Errorf(codes.NotFound, "product %d not found", id)
+		}
+	}
+	return products, nil
+}
+
+// fetchProducts gets the listed products in one product-service call, products that are gone are not in the map
+func (s *CartServiceServer) fetchProducts(ctx context.Context, ids []int64) (map[int64]*productpb.Product, error) {
+	resp, err := s.productClient.GetProductsByIds(ctx, &productpb.GetProductsByIdsRequest{Ids: ids})
+	if err != nil {
+		return nil, err
+	}
+
+	products := make(map[int64]*productpb.Product, len(resp.Products))
+	for _, p := range resp.Products {
+		products[p.Id] = p
+	}
+	return products, nil
+}
@@ -34,7 +34,7 @@ func setupTestDB(t *testing.T) (r.CartRepository, func()) {
 	require.NoError(t, err)
 
 	// Create repository
-	repo := r.NewMongoRepository(db)
+	repo := r.NewMongoRepository(db, r.DefaultGuestCartTTL)
 
 	cleanup := func() {
 		if err := mongoContainer.Terminate(ctx); err != nil {
@@ -89,7 +89,7 @@ func TestAddItemToCart_Success(t *testing.T) {
 		},
 	}
 
-	server := NewCartServiceServer(service, mockProductClient, slog.Default())
+	server := NewCartServiceServer(service, mockProductClient, nil, StockCheck{}, slog.Default())
 	ret, err := server.AddItem(context.Background(), &pb.AddCartItemRequest{
 		UserId:    123,
 		ProductId: 1,
@@ -124,7 +124,7 @@ func TestGetCart_Integration(t *testing.T) {
 			},
 		},
 	}
-	server := NewCartServiceServer(service, mockProductClient, slog.Default())
+	server := NewCartServiceServer(service, mockProductClient, nil, StockCheck{}, slog.Default())
 
 	// First add some items to the cart
 	_, err := server.AddItem(context.Background(), &pb.AddCartItemRequest{
@@ -170,7 +170,7 @@ func TestUpdateQuantity_Integration(t *testing.T) {
 			},
 		},
 	}
-	server := NewCartServiceServer(service, mockProductClient, slog.Default())
+	server := NewCartServiceServer(service, mockProductClient, nil, StockCheck{}, slog.Default())
 
 	// First add an item
 	_, err := server.AddItem(context.Background(), &pb.AddCartItemRequest{
@@ -210,7 +210,7 @@ func TestRemoveItem_Integration(t *testing.T) {
 			},
 		},
 	}
-	server := NewCartServiceServer(service, mockProductClient, slog.Default())
+	server := NewCartServiceServer(service, mockProductClient, nil, StockCheck{}, slog.Default())
 
 	// Add two items
 	_, err := server.AddItem(context.Background(), &pb.AddCartItemRequest{
@@ -257,7 +257,7 @@ func TestClearCart_Integration(t *testing.T) {
 			},
 		},
 	}
-	server := NewCartServiceServer(service, mockProductClient, slog.Default())
+	server := NewCartServiceServer(service, mockProductClient, nil, StockCheck{}, slog.Default())
 
 	// Add items
 	_, err := server.AddItem(context.Background(), &pb.AddCartItemRequest{
@@ -10,6 +10,7 @@ import (
 
 	"github.com/fjod/go_cart/cart-service/internal/cache"
 	"github.com/fjod/go_cart/cart-service/internal/domain"
+	"github.com/fjod/go_cart/cart-service/internal/repository"
 	s "github.com/fjod/go_cart/cart-service/internal/service"
 	pb "github.com/fjod/go_cart/cart-service/pkg/proto"
 	productpb "github.com/fjod/go_cart/product-service/pkg/proto"
@@ -35,63 +36,114 @@ func (m *mockRepository) GetCart(context.Context, string) (*domain.Cart, error)
 	return m.cart, nil
 }
 
-func (m *mockRepository) UpsertCart(_ context.Context, c *domain.Cart) error {
+// checkVersion fails like MongoDB does when the cart is not at the expected version
+func (m *mockRepository) checkVersion(version *int64) error {
+	if version != nil && m.cart.Version != *version {
+		return repository.ErrVersionConflict
+	}
+	return nil
+}
+
+func (m *mockRepository) UpsertCart(_ context.Context, c *domain.Cart, version *int64) error {
 	m.m.Lock()
 	defer m.m.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	if m.cart != nil {
+		if err := m.checkVersion(version); err != nil {
+			return err
+		}
+	}
+	c.Version++
 	m.cart = c
-	return m.err
+	return nil
 }
 
-func (m *mockRepository) AddItem(_ context.Context, _ string, item domain.CartItem) error {
+func (m *mockRepository) AddItem(_ context.Context, _ string, item domain.CartItem, version *int64) error {
 	m.m.Lock()
 	defer m.m.Unlock()
 	if m.err != nil {
 		return m.err
 	}
+	if err := m.checkVersion(version); err != nil {
+		return err
+	}
 	m.cart.Items = append(m.cart.Items, item)
+	m.cart.Version++
 	return nil
 }
 
-func (m *mockRepository) UpdateItemQuantity(_ context.Context, _ string, productID int64, quantity int) error {
+func (m *mockRepository) UpdateItemQuantity(_ context.Context, _ string, productID, variantID int64, quantity int, version *int64) error {
 	m.m.Lock()
 	defer m.m.Unlock()
 	if m.err != nil {
 		return m.err
 	}
+	if err := m.checkVersion(version); err != nil {
+		return err
+	}
 	// Find and update the item
 	for i := range m.cart.Items {
-		if m.cart.Items[i].ProductID == productID {
+		if m.cart.Items[i].Is(productID, variantID) {
 			m.cart.Items[i].Quantity = quantity
+			m.cart.Version++
 			return nil
 		}
 	}
 	return fmt.Errorf("item not found")
 }
 
-func (m *mockRepository) RemoveItem(_ context.Context, _ string, productID int64) error {
+func (m *mockRepository) RemoveItem(_ context.Context, _ string, productID, variantID int64, version *int64) error {
 	m.m.Lock()
 	defer m.m.Unlock()
 	if m.err != nil {
 		return m.err
 	}
+	if err := m.checkVersion(version); err != nil {
+		return err
+	}
 	// Find and remove the item
 	for i, item := range m.cart.Items {
-		if item.ProductID == productID {
+		if item.Is(productID, variantID) {
 			m.cart.Items = append(m.cart.Items[:i], m.cart.Items[i+1:]...)
+			m.cart.Version++
 			return nil
 		}
 	}
 	return fmt.Errorf("item not found")
 }
 
-func (m *mockRepository) DeleteCart(_ context.Context, _ string) error {
+func (m *mockRepository) DeleteCart(_ context.Context, _ string, version *int64) error {
 	m.m.Lock()
 	defer m.m.Unlock()
 	if m.err != nil {
 		return m.err
 	}
-	// Clear all items
+	if err := m.checkVersion(version); err != nil {
+		return err
+	}
+	// Clear all items, the cart stays at the next version
 	m.cart.Items = []domain.CartItem{}
+	m.cart.PromoCode = ""
+	m.cart.Version++
+	return nil
+}
+
+func (m *mockRepository) SetPromoCode(_ context.Context, _ string, code string, version *int64) error {
+	m.m.Lock()
+	defer m.m.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	if m.cart == nil {
+		return repository.ErrCartNotFound
+	}
+	if err := m.checkVersion(version); err != nil {
+		return err
+	}
+	m.cart.PromoCode = code
+	m.cart.Version++
 	return nil
 }
 
@@ -129,8 +181,12 @@ func (m *mockCache) Delete(context.Context, string) error {
 
 // mockProductServiceClient implements productpb.ProductServiceClient
 type mockProductServiceClient struct {
-	getProductResp *productpb.GetProductResponse
-	getProductErr  error
+	productpb.ProductServiceClient // admin RPCs are not called by the cart
+	getProductResp                 *productpb.GetProductResponse
+	getProductErr                  error
+	evaluateResp                   *productpb.EvaluatePromotionResponse
+	evaluateErr                    error
+	evaluateReq                    *productpb.EvaluatePromotionRequest
 }
 
 func (m *mockProductServiceClient) GetProduct(context.Context, *productpb.GetProductRequest, ...grpc.CallOption) (*productpb.GetProductResponse, error) {
@@ -140,11 +196,35 @@ func (m *mockProductServiceClient) GetProduct(context.Context, *productpb.GetPro
 	return m.getProductResp, nil
 }
 
+func (m *mockProductServiceClient) GetProductsByIds(_ context.Context, req *productpb.GetProductsByIdsRequest, _ ...grpc.CallOption) (*productpb.GetProductsByIdsResponse, error) {
+	if m.getProductErr != nil {
+		return nil, m.getProductErr
+	}
+	resp := &productpb.GetProductsByIdsResponse{}
+	if m.getProductResp == nil || m.getProductResp.Product == nil {
+		return resp, nil
+	}
+	for _, id := range req.Ids {
+		if id == m.getProductResp.Product.Id {
+			resp.Products = append(resp.Products, m.getProductResp.Product)
+		}
+	}
+	return resp, nil
+}
+
 func (m *mockProductServiceClient) GetProducts(context.Context, *productpb.GetProductsRequest, ...grpc.CallOption) (*productpb.GetProductsResponse, error) {
 	// Not needed for current tests
 	return nil, nil
 }
 
+func (m *mockProductServiceClient) EvaluatePromotion(_ context.Context, req *productpb.EvaluatePromotionRequest, _ ...grpc.CallOption) (*productpb.EvaluatePromotionResponse, error) {
+	m.evaluateReq = req
+	if m.evaluateErr != nil {
+		return nil, m.evaluateErr
+	}
+	return m.evaluateResp, nil
+}
+
 func createCacheAndRepo(c *domain.Cart) *s.CartService {
 	mockRepo := &mockRepository{
 		cart: c,
@@ -167,7 +247,7 @@ func TestGetCart_Success(t *testing.T) {
 	}
 	service := createCacheAndRepo(cart)
 	mockProductClient := &mockProductServiceClient{}
-	server := NewCartServiceServer(service, mockProductClient, slog.Default())
+	server := NewCartServiceServer(service, mockProductClient, nil, StockCheck{}, slog.Default())
 	ret, err := server.GetCart(context.Background(), &pb.GetCartRequest{
 		UserId: 123,
 	})
@@ -204,7 +284,7 @@ func TestAddItem_Success(t *testing.T) {
 		},
 	}
 
-	server := NewCartServiceServer(service, mockProductClient, slog.Default())
+	server := NewCartServiceServer(service, mockProductClient, nil, StockCheck{}, slog.Default())
 	ret, err := server.AddItem(context.Background(), &pb.AddCartItemRequest{
 		UserId:    123,
 		ProductId: 1,
@@ -232,15 +312,14 @@ func TestAddItem_NotFound(t *testing.T) {
 	}
 	service := createCacheAndRepo(cart)
 
-	// Create mock for ProductServiceClient that returns a valid product
+	// product-service knows nothing about the requested product
 	mockProductClient := &mockProductServiceClient{
 		getProductResp: &productpb.GetProductResponse{
 			Product: nil,
 		},
-		getProductErr: status.Error(codes.NotFound, "product not found"),
 	}
 
-	server := NewCartServiceServer(service, mockProductClient, slog.Default())
+	server := NewCartServiceServer(service, mockProductClient, nil, StockCheck{}, slog.Default())
 	ret, err := server.AddItem(context.Background(), &pb.AddCartItemRequest{
 		UserId:    123,
 		ProductId: 1,
@@ -251,6 +330,56 @@ func TestAddItem_NotFound(t *testing.T) {
 	assert.True(t, status.Code(err) == codes.NotFound)
 }
 
+func TestAddItem_ArchivedProduct(t *testing.T) {
+	cart := &domain.Cart{
+		Items:     []domain.CartItem{},
+		UserID:    "123",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	service := createCacheAndRepo(cart)
+
+	mockProductClient := &mockProductServiceClient{
+		getProductResp: &productpb.GetProductResponse{
+			Product: &productpb.Product{Id: 1, Name: "Old Lamp", ArchivedAt: "2026-01-01T00:00:00Z"},
+		},
+	}
+
+	server := NewCartServiceServer(service, mockProductClient, nil, StockCheck{}, slog.Default())
+	ret, err := server.AddItem(context.Background(), &pb.AddCartItemRequest{
+		UserId:    123,
+		ProductId: 1,
+		Quantity:  1,
+	})
+
+	assert.Nil(t, ret)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestAddItem_ProductServiceDown(t *testing.T) {
+	cart := &domain.Cart{
+		Items:     []domain.CartItem{},
+		UserID:    "123",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	service := createCacheAndRepo(cart)
+
+	mockProductClient := &mockProductServiceClient{
+		getProductErr: status.Error(codes.Unavailable, "connection refused"),
+	}
+
+	server := NewCartServiceServer(service, mockProductClient, nil, StockCheck{}, slog.Default())
+	ret, err := server.AddItem(context.Background(), &pb.AddCartItemRequest{
+		UserId:    123,
+		ProductId: 1,
+		Quantity:  5,
+	})
+
+	assert.Nil(t, ret)
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
 func TestUpdateQuantity_Success(t *testing.T) {
 	cart := &domain.Cart{
 		Items: []domain.CartItem{
@@ -264,7 +393,7 @@ func TestUpdateQuantity_Success(t *testing.T) {
 	service := createCacheAndRepo(cart)
 
 	mockProductClient := &mockProductServiceClient{}
-	server := NewCartServiceServer(service, mockProductClient, slog.Default())
+	server := NewCartServiceServer(service, mockProductClient, nil, StockCheck{}, slog.Default())
 
 	ret, err := server.UpdateQuantity(context.Background(), &pb.UpdateQuantityRequest{
 		UserId:    123,
@@ -290,7 +419,7 @@ func TestUpdateQuantity_InvalidInput(t *testing.T) {
 	service := createCacheAndRepo(cart)
 
 	mockProductClient := &mockProductServiceClient{}
-	server := NewCartServiceServer(service, mockProductClient, slog.Default())
+	server := NewCartServiceServer(service, mockProductClient, nil, StockCheck{}, slog.Default())
 
 	tests := []struct {
 		name     string
@@ -341,7 +470,7 @@ func TestRemoveItem_Success(t *testing.T) {
 	service := createCacheAndRepo(cart)
 
 	mockProductClient := &mockProductServiceClient{}
-	server := NewCartServiceServer(service, mockProductClient, slog.Default())
+	server := NewCartServiceServer(service, mockProductClient, nil, StockCheck{}, slog.Default())
 
 	ret, err := server.RemoveItem(context.Background(), &pb.RemoveItemRequest{
 		UserId:    123,
@@ -367,7 +496,7 @@ func TestRemoveItem_InvalidInput(t *testing.T) {
 	service := createCacheAndRepo(cart)
 
 	mockProductClient := &mockProductServiceClient{}
-	server := NewCartServiceServer(service, mockProductClient, slog.Default())
+	server := NewCartServiceServer(service, mockProductClient, nil, StockCheck{}, slog.Default())
 
 	tests := []struct {
 		name     string
@@ -404,11 +533,12 @@ func TestClearCart_Success(t *testing.T) {
 		UserID:    "123",
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
+		Version:   3,
 	}
 	service := createCacheAndRepo(cart)
 
 	mockProductClient := &mockProductServiceClient{}
-	server := NewCartServiceServer(service, mockProductClient, slog.Default())
+	server := NewCartServiceServer(service, mockProductClient, nil, StockCheck{}, slog.Default())
 
 	ret, err := server.ClearCart(context.Background(), &pb.ClearCartRequest{
 		UserId: 123,
@@ -419,6 +549,7 @@ func TestClearCart_Success(t *testing.T) {
 	// Cart should be empty
 	assert.Equal(t, 0, len(ret.Cart.Cart))
 	assert.Equal(t, int64(123), ret.Cart.UserId)
+	assert.Equal(t, int64(4), ret.Cart.Version, "the reply carries the version the clear moved the cart to")
 }
 
 func TestClearCart_InvalidInput(t *testing.T) {
@@ -431,7 +562,7 @@ func TestClearCart_InvalidInput(t *testing.T) {
 	service := createCacheAndRepo(cart)
 
 	mockProductClient := &mockProductServiceClient{}
-	server := NewCartServiceServer(service, mockProductClient, slog.Default())
+	server := NewCartServiceServer(service, mockProductClient, nil, StockCheck{}, slog.Default())
 
 	ret, err := server.ClearCart(context.Background(), &pb.ClearCartRequest{
 		UserId: 0,
@@ -440,3 +571,197 @@ func TestClearCart_InvalidInput(t *testing.T) {
 	assert.Nil(t, ret)
 	assert.Equal(t, codes.InvalidArgument, status.Code(err))
 }
+
+func laptopWithVariants() *productpb.Product {
+	return &productpb.Product{
+		Id:   1,
+		Name: "Laptop",
+		Variants: []*productpb.ProductVariant{
+			{Id: 1, Sku: "LAPTOP-16GB-512GB"},
+			{Id: 2, Sku: "LAPTOP-32GB-1TB"},
+			{Id: 3, Sku: "LAPTOP-8GB-256GB", ArchivedAt: "2026-01-01T00:00:00Z"},
+		},
+	}
+}
+
+func TestAddItem_Variant(t *testing.T) {
+	cart := &domain.Cart{
+		Items:     []domain.CartItem{{ProductID: 1, VariantID: 1, SKU: "LAPTOP-16GB-512GB", Quantity: 1}},
+		UserID:    "123",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	service := createCacheAndRepo(cart)
+	mockProductClient := &mockProductServiceClient{
+		getProductResp: &productpb.GetProductResponse{Product: laptopWithVariants()},
+	}
+
+	server := NewCartServiceServer(service, mockProductClient, nil, StockCheck{}, slog.Default())
+	ret, err := server.AddItem(context.Background(), &pb.AddCartItemRequest{
+		UserId:    123,
+		ProductId: 1,
+		VariantId: 2,
+		Quantity:  1,
+	})
+
+	require.NoError(t, err)
+	// each variant is a line of its own
+	require.Len(t, ret.Cart.Cart, 2)
+	assert.Equal(t, int64(2), ret.Cart.Cart[1].VariantId)
+	assert.Equal(t, "LAPTOP-32GB-1TB", ret.Cart.Cart[1].Sku)
+}
+
+func TestAddItem_VariantRules(t *testing.T) {
+	tests := []struct {
+		name      string
+		product   *productpb.Product
+		variantID int64
+		wantCode  codes.Code
+	}{
+		{"variant required", laptopWithVariants(), 0, codes.InvalidArgument},
+		{"unknown variant", laptopWithVariants(), 42, codes.NotFound},
+		{"archived variant", laptopWithVariants(), 3, codes.FailedPrecondition},
+		{"product without variants", &productpb.Product{Id: 1, Name: "Mouse"}, 1, codes.NotFound},
+		{"negative variant", laptopWithVariants(), -1, codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := createCacheAndRepo(&domain.Cart{UserID: "123"})
+			mockProductClient := &mockProductServiceClient{
+				getProductResp: &productpb.GetProductResponse{Product: tt.product},
+			}
+			server := NewCartServiceServer(service, mockProductClient, nil, StockCheck{}, slog.Default())
+
+			ret, err := server.AddItem(context.Background(), &pb.AddCartItemRequest{
+				UserId:    123,
+				ProductId: 1,
+				VariantId: tt.variantID,
+				Quantity:  1,
+			})
+
+			assert.Nil(t, ret)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestUpdateQuantity_TargetsVariant(t *testing.T) {
+	cart := &domain.Cart{
+		Items: []domain.CartItem{
+			{ProductID: 1, VariantID: 1, Quantity: 1},
+			{ProductID: 1, VariantID: 2, Quantity: 1},
+		},
+		UserID: "123",
+	}
+	service := createCacheAndRepo(cart)
+	server := NewCartServiceServer(service, &mockProductServiceClient{}, nil, StockCheck{}, slog.Default())
+
+	ret, err := server.UpdateQuantity(context.Background(), &pb.UpdateQuantityRequest{
+		UserId:    123,
+		ProductId: 1,
+		VariantId: 2,
+		Quantity:  3,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), ret.Cart.Cart[0].Quantity)
+	assert.Equal(t, int32(3), ret.Cart.Cart[1].Quantity)
+}
+
+func TestGetCart_Guest(t *testing.T) {
+	cart := &domain.Cart{
+		Items:  []domain.CartItem{{ProductID: 1, Quantity: 2}},
+		UserID: domain.GuestCartKey("3f2a9c"),
+	}
+	service := createCacheAndRepo(cart)
+	server := NewCartServiceServer(service, &mockProductServiceClient{}, nil, StockCheck{}, slog.Default())
+
+	ret, err := server.GetCart(context.Background(), &pb.GetCartRequest{GuestId: "3f2a9c"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "3f2a9c", ret.Cart.GuestId)
+	assert.Zero(t, ret.Cart.UserId)
+	assert.Len(t, ret.Cart.Cart, 1)
+}
+
+func TestCartOwner_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  int64
+		guestID string
+	}{
+		{"neither", 0, ""},
+		{"negative user", -1, ""},
+		{"both", 123, "3f2a9c"},
+		{"guest id charset", 0, "3f2a/../9c"},
+		{"guest id too long", 0, fmt.Sprintf("%065d", 0)},
+	}
+	service := createCacheAndRepo(&domain.Cart{})
+	server := NewCartServiceServer(service, &mockProductServiceClient{}, nil, StockCheck{}, slog.Default())
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ret, err := server.GetCart(context.Background(), &pb.GetCartRequest{UserId: tt.userID, GuestId: tt.guestID})
+			assert.Nil(t, ret)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+			ret, err = server.RemoveItem(context.Background(), &pb.RemoveItemRequest{UserId: tt.userID, GuestId: tt.guestID, ProductId: 1})
+			assert.Nil(t, ret)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
+
+func TestMergeCarts_InvalidInput(t *testing.T) {
+	service := createCacheAndRepo(&domain.Cart{})
+	server := NewCartServiceServer(service, &mockProductServiceClient{}, nil, StockCheck{}, slog.Default())
+
+	_, err := server.MergeCarts(context.Background(), &pb.MergeCartsRequest{GuestId: "3f2a9c"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = server.MergeCarts(context.Background(), &pb.MergeCartsRequest{UserId: 123})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestMutations_ExpectedVersion(t *testing.T) {
+	stale, current := int64(2), int64(3)
+	mouse := &productpb.GetProductResponse{Product: &productpb.Product{Id: 1, Name: "Mouse", Price: 25.5}}
+
+	for name, call := range map[string]func(*CartServiceServer, *int64) (*pb.CartResponse, error){
+		"add item": func(server *CartServiceServer, v *int64) (*pb.CartResponse, error) {
+			return server.AddItem(context.Background(), &pb.AddCartItemRequest{UserId: 123, ProductId: 1, Quantity: 2, ExpectedVersion: v})
+		},
+		"update quantity": func(server *CartServiceServer, v *int64) (*pb.CartResponse, error) {
+			return server.UpdateQuantity(context.Background(), &pb.UpdateQuantityRequest{UserId: 123, ProductId: 1, Quantity: 4, ExpectedVersion: v})
+		},
+		"remove item": func(server *CartServiceServer, v *int64) (*pb.CartResponse, error) {
+			return server.RemoveItem(context.Background(), &pb.RemoveItemRequest{UserId: 123, ProductId: 1, ExpectedVersion: v})
+		},
+		"clear cart": func(server *CartServiceServer, v *int64) (*pb.CartResponse, error) {
+			return server.ClearCart(context.Background(), &pb.ClearCartRequest{UserId: 123, ExpectedVersion: v})
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cart := &domain.Cart{Items: []domain.CartItem{{ProductID: 1, Quantity: 1}}, UserID: "123", Version: current}
+			server := NewCartServiceServer(createCacheAndRepo(cart), &mockProductServiceClient{getProductResp: mouse}, nil, StockCheck{}, slog.Default())
+
+			_, err := call(server, &stale)
+			assert.Equal(t, codes.Aborted, status.Code(err))
+			assert.Equal(t, current, cart.Version, "a stale version changes nothing")
+
+			_, err = call(server, &current)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestGetCart_ReturnsVersion(t *testing.T) {
+	cart := &domain.Cart{Items: []domain.CartItem{{ProductID: 1, Quantity: 1}}, UserID: "123", Version: 7}
+	server := NewCartServiceServer(createCacheAndRepo(cart), &mockProductServiceClient{}, nil, StockCheck{}, slog.Default())
+
+	ret, err := server.GetCart(context.Background(), &pb.GetCartRequest{UserId: 123})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), ret.Cart.Version)
+}
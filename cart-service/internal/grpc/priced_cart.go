@@ -0,0 +1,157 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/fjod/go_cart/cart-service/internal/domain"
+	pb "github.com/fjod/go_cart/cart-service/pkg/proto"
+	"github.com/fjod/go_cart/pkg/logger"
+	"github.com/fjod/go_cart/pkg/money"
+	productpb "github.com/fjod/go_cart/product-service/pkg/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetPricedCart returns the cart with the product details and current price of every line, so a client can
+// render it without a product call per line. Lines whose product or variant is gone or archived are flagged
+// and left out of the totals.
+func (s *CartServiceServer) GetPricedCart(
+	ctx context.Context,
+	req *pb.GetCartRequest) (*pb.PricedCartResponse, error) {
+
+	log := logger.WithContext(s.logger, ctx)
+	owner, err := resolveOwner(req.UserId, req.GuestId)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("get priced cart", owner.logAttr())
+
+	cart, err := s.service.GetCart(ctx, owner.key())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get cart: %v", err)
+	}
+
+	return s.pricedCart(ctx, *cart, owner)
+}
+
+// pricedCart prices the cart and takes off the discount of its promo code. A code that no longer applies
+// is reported in promo_error and the cart is priced without it.
+func (s *CartServiceServer) pricedCart(ctx context.Context, cart domain.Cart, owner cartOwner) (*pb.PricedCartResponse, error) {
+	log := logger.WithContext(s.logger, ctx)
+	products := map[int64]*productpb.Product{}
+	if len(cart.Items) > 0 {
+		ids := make([]int64, len(cart.Items))
+		for i, item := range cart.Items {
+			ids[i] = item.ProductID
+		}
+		var err error
+		products, err = s.fetchProducts(ctx, ids)
+		if err != nil {
+			log.Error("failed to price cart", slog.Any("error", err))
+			return nil, status.Errorf(codes.Unavailable, "failed to get product prices: %v", err)
+		}
+	}
+
+	resp, err := priceCart(cart, products)
+	if err != nil {
+		return nil, err
+	}
+	resp.Cart = s.stockedCart(ctx, cart, owner)
+	for i, item := range resp.Cart.Cart {
+		resp.Items[i].StockStatus, resp.Items[i].AvailableQuantity = item.StockStatus, item.AvailableQuantity
+	}
+
+	if cart.PromoCode != "" {
+		if err := s.applyPromotion(ctx, cart.PromoCode, resp); err != nil {
+			log.Warn("promo code no longer applies", slog.String("promo_code", cart.PromoCode), slog.Any("error", err))
+			resp.PromoError = status.Convert(err).Message()
+		}
+	}
+	return resp, nil
+}
+
+// priceCart prices every cart line with the products fetched from product-service
+func priceCart(cart domain.Cart, products map[int64]*productpb.Product) (*pb.PricedCartResponse, error) {
+	resp := &pb.PricedCartResponse{
+		Items:    make([]*pb.PricedCartItem, len(cart.Items)),
+		Currency: money.DefaultCurrency,
+	}
+
+	var total *money.Money
+	for i, item := range cart.Items {
+		line := &pb.PricedCartItem{
+			ProductId: item.ProductID,
+			VariantId: item.VariantID,
+			Sku:       item.SKU,
+			Quantity:  int32(item.Quantity),
+			AddedAt:   item.AddedAt.Format(timeFormat),
+		}
+		resp.Items[i] = line
+
+		product, ok := products[item.ProductID]
+		if !ok {
+			line.Status = pb.PricedItemStatus_PRICED_ITEM_STATUS_PRODUCT_NOT_FOUND
+			continue
+		}
+		line.ProductName = product.Name
+		line.ImageUrl = product.ImageUrl
+
+		unitPrice, variantName, ok := linePrice(product, item.VariantID)
+		if !ok {
+			line.Status = pb.PricedItemStatus_PRICED_ITEM_STATUS_UNAVAILABLE
+			continue
+		}
+		line.VariantName = variantName
+		subtotal := unitPrice.Multiply(int64(item.Quantity))
+		line.UnitPriceMinor = unitPrice.Amount
+		line.SubtotalMinor = subtotal.Amount
+		line.Currency = unitPrice.Currency
+
+		if total == nil {
+			total = &subtotal
+		} else {
+			sum, err := total.Add(subtotal)
+			if errors.Is(err, money.ErrCurrencyMismatch) {
+				return nil, status.Errorf(codes.FailedPrecondition, "cart mixes currencies: %v", err)
+			}
+			total = &sum
+		}
+		resp.ItemCount += int32(item.Quantity)
+	}
+
+	if total != nil {
+		resp.SubtotalMinor = total.Amount
+		resp.TotalMinor = total.Amount
+		resp.Currency = total.Currency
+	}
+	return resp, nil
+}
+
+// linePrice is what a cart line sells for: the variant price for a product sold per variant, the product price
+// otherwise. It reports false when the product, or the variant, can no longer be bought.
+func linePrice(product *productpb.Product, variantID int64) (money.Money, string, bool) {
+	if product.ArchivedAt != "" {
+		return money.Money{}, "", false
+	}
+	if variantID == 0 {
+		if len(product.Variants) > 0 {
+			return money.Money{}, "", false
+		}
+		if product.Currency == "" {
+			// product services without minor units only send the float price
+			return money.FromFloat(product.Price, money.DefaultCurrency), "", true
+		}
+		return money.New(product.PriceMinor, product.Currency), "", true
+	}
+	for _, v := range product.Variants {
+		if v.Id == variantID {
+			if v.ArchivedAt != "" {
+				return money.Money{}, "", false
+			}
+			return money.New(v.PriceMinor, v.Currency), v.Name, true
+		}
+	}
+	return money.Money{}, "", false
+}
@@ -0,0 +1,105 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/fjod/go_cart/cart-service/internal/domain"
+	pb "github.com/fjod/go_cart/cart-service/pkg/proto"
+	productpb "github.com/fjod/go_cart/product-service/pkg/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPriceCart(t *testing.T) {
+	laptop := laptopWithVariants()
+	laptop.ImageUrl = "https://img/laptop.png"
+	laptop.Variants[0].Name, laptop.Variants[0].PriceMinor, laptop.Variants[0].Currency = "16GB / 512GB", 129999, "USD"
+	products := map[int64]*productpb.Product{
+		1: laptop,
+		2: {Id: 2, Name: "Mouse", PriceMinor: 2550, Currency: "USD"},
+		3: {Id: 3, Name: "Old Mouse", PriceMinor: 999, Currency: "USD", ArchivedAt: "2026-01-01T00:00:00Z"},
+	}
+	cart := domain.Cart{Items: []domain.CartItem{
+		{ProductID: 1, VariantID: 1, SKU: "LAPTOP-16GB-512GB", Quantity: 1},
+		{ProductID: 2, Quantity: 2},
+		{ProductID: 3, Quantity: 1},
+		{ProductID: 1, VariantID: 3, SKU: "LAPTOP-8GB-256GB", Quantity: 1},
+		{ProductID: 4, Quantity: 5},
+	}}
+
+	resp, err := priceCart(cart, products)
+	require.NoError(t, err)
+
+	require.Len(t, resp.Items, 5)
+	assert.Equal(t, "Laptop", resp.Items[0].ProductName)
+	assert.Equal(t, "16GB / 512GB", resp.Items[0].VariantName)
+	assert.Equal(t, "https://img/laptop.png", resp.Items[0].ImageUrl)
+	assert.Equal(t, int64(129999), resp.Items[0].SubtotalMinor)
+	assert.Equal(t, int64(2550), resp.Items[1].UnitPriceMinor)
+	assert.Equal(t, int64(5100), resp.Items[1].SubtotalMinor)
+	assert.Equal(t, pb.PricedItemStatus_PRICED_ITEM_STATUS_AVAILABLE, resp.Items[1].Status)
+	assert.Equal(t, pb.PricedItemStatus_PRICED_ITEM_STATUS_UNAVAILABLE, resp.Items[2].Status)
+	assert.Equal(t, "Old Mouse", resp.Items[2].ProductName)
+	assert.Zero(t, resp.Items[2].SubtotalMinor)
+	assert.Equal(t, pb.PricedItemStatus_PRICED_ITEM_STATUS_UNAVAILABLE, resp.Items[3].Status)
+	assert.Equal(t, pb.PricedItemStatus_PRICED_ITEM_STATUS_PRODUCT_NOT_FOUND, resp.Items[4].Status)
+	assert.Equal(t, int64(4), resp.Items[4].ProductId)
+
+	assert.Equal(t, int64(129999+5100), resp.TotalMinor)
+	assert.Equal(t, "USD", resp.Currency)
+	assert.Equal(t, int32(3), resp.ItemCount)
+}
+
+func TestPriceCart_MixedCurrencies(t *testing.T) {
+	products := map[int64]*productpb.Product{
+		1: {Id: 1, PriceMinor: 100, Currency: "USD"},
+		2: {Id: 2, PriceMinor: 100, Currency: "EUR"},
+	}
+	cart := domain.Cart{Items: []domain.CartItem{{ProductID: 1, Quantity: 1}, {ProductID: 2, Quantity: 1}}}
+
+	_, err := priceCart(cart, products)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestGetPricedCart(t *testing.T) {
+	cart := &domain.Cart{
+		Items:  []domain.CartItem{{ProductID: 1, Quantity: 3}},
+		UserID: "123",
+	}
+	service := createCacheAndRepo(cart)
+	mockProductClient := &mockProductServiceClient{
+		getProductResp: &productpb.GetProductResponse{
+			Product: &productpb.Product{Id: 1, Name: "Mouse", PriceMinor: 2550, Currency: "USD"},
+		},
+	}
+	server := NewCartServiceServer(service, mockProductClient, nil, StockCheck{}, slog.Default())
+
+	ret, err := server.GetPricedCart(context.Background(), &pb.GetCartRequest{UserId: 123})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(123), ret.Cart.UserId)
+	require.Len(t, ret.Items, 1)
+	assert.Equal(t, "Mouse", ret.Items[0].ProductName)
+	assert.Equal(t, int64(7650), ret.TotalMinor)
+	assert.Equal(t, int32(3), ret.ItemCount)
+}
+
+func TestGetPricedCart_ProductServiceDown(t *testing.T) {
+	cart := &domain.Cart{
+		Items:  []domain.CartItem{{ProductID: 1, Quantity: 3}},
+		UserID: "123",
+	}
+	service := createCacheAndRepo(cart)
+	mockProductClient := &mockProductServiceClient{getProductErr: errors.New("connection refused")}
+	server := NewCartServiceServer(service, mockProductClient, nil, StockCheck{}, slog.Default())
+
+	ret, err := server.GetPricedCart(context.Background(), &pb.GetCartRequest{UserId: 123})
+
+	assert.Nil(t, ret)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
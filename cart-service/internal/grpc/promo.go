@@ -0,0 +1,142 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/fjod/go_cart/cart-service/internal/repository"
+	pb "github.com/fjod/go_cart/cart-service/pkg/proto"
+	"github.com/fjod/go_cart/pkg/logger"
+	productpb "github.com/fjod/go_cart/product-service/pkg/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxPromoCodeLength matches the longest code product-service accepts
+const maxPromoCodeLength = 32
+
+// ApplyPromoCode checks the code against the current cart lines with product-service and stores it on the cart.
+// The discount is worked out again every time the cart is priced and once more at checkout.
+func (s *CartServiceServer) ApplyPromoCode(
+	ctx context.Context,
+	req *pb.ApplyPromoCodeRequest) (*pb.PricedCartResponse, error) {
+
+	log := logger.WithContext(s.logger, ctx)
+	owner, err := resolveOwner(req.UserId, req.GuestId)
+	if err != nil {
+		return nil, err
+	}
+	code := strings.TrimSpace(req.Code)
+	if code == "" || len(code) > maxPromoCodeLength {
+		return nil, status.Errorf(codes.InvalidArgument, "code must be 1 to %d characters", maxPromoCodeLength)
+	}
+	log.Info("apply promo code", owner.logAttr(), slog.String("promo_code", code))
+
+	cart, err := s.service.GetCart(ctx, owner.key())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get cart: %v", err)
+	}
+	if len(cart.Items) == 0 {
+		return nil, status.Error(codes.FailedPrecondition, "cart is empty")
+	}
+	if req.ExpectedVersion != nil && *req.ExpectedVersion != cart.Version {
+		return nil, errStaleVersion
+	}
+
+	// price the cart without its current code, the new one replaces it
+	candidate := *cart
+	candidate.PromoCode = ""
+	resp, err := s.pricedCart(ctx, candidate, owner)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyPromotion(ctx, code, resp); err != nil {
+		log.Info("promo code rejected", slog.String("promo_code", code), slog.Any("error", err))
+		return nil, err
+	}
+
+	// the code was checked against the lines of this version, it is stored only while the cart still has them
+	if err := s.service.ApplyPromoCode(ctx, owner.key(), resp.PromoCode, &cart.Version); err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return nil, errStaleVersion
+		}
+		if errors.Is(err, repository.ErrCartNotFound) {
+			return nil, status.Error(codes.FailedPrecondition, "cart is empty")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to apply promo code: %v", err)
+	}
+	resp.Cart.PromoCode = resp.PromoCode
+	resp.Cart.Version++
+	return resp, nil
+}
+
+// RemovePromoCode takes the promo code off the cart, removing it from a cart without one is a no-op
+func (s *CartServiceServer) RemovePromoCode(
+	ctx context.Context,
+	req *pb.RemovePromoCodeRequest) (*pb.PricedCartResponse, error) {
+
+	log := logger.WithContext(s.logger, ctx)
+	owner, err := resolveOwner(req.UserId, req.GuestId)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("remove promo code", owner.logAttr())
+
+	err = s.service.RemovePromoCode(ctx, owner.key(), req.ExpectedVersion)
+	if errors.Is(err, repository.ErrVersionConflict) {
+		return nil, errStaleVersion
+	}
+	if err != nil && !errors.Is(err, repository.ErrCartNotFound) {
+		return nil, status.Errorf(codes.Internal, "failed to remove promo code: %v", err)
+	}
+
+	cart, err := s.service.GetCart(ctx, owner.key())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get cart: %v", err)
+	}
+	return s.pricedCart(ctx, *cart, owner)
+}
+
+// applyPromotion asks product-service what the code takes off the available lines of the priced cart
+// and adds the discounts to it. When the code can't be used the error says why and resp is left as it was.
+func (s *CartServiceServer) applyPromotion(ctx context.Context, code string, resp *pb.PricedCartResponse) error {
+	var lines []*productpb.PromotionLine
+	var indexes []int
+	for i, item := range resp.Items {
+		if item.Status != pb.PricedItemStatus_PRICED_ITEM_STATUS_AVAILABLE {
+			continue
+		}
+		lines = append(lines, &productpb.PromotionLine{
+			ProductId: item.ProductId,
+			VariantId: item.VariantId,
+			Quantity:  item.Quantity,
+		})
+		indexes = append(indexes, i)
+	}
+	if len(lines) == 0 {
+		return status.Error(codes.FailedPrecondition, "promo code cannot be applied: no item in the cart can be bought")
+	}
+
+	eval, err := s.productClient.EvaluatePromotion(ctx, &productpb.EvaluatePromotionRequest{Code: code, Lines: lines})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound, codes.FailedPrecondition, codes.InvalidArgument:
+			return err
+		default:
+			return status.Errorf(codes.Unavailable, "failed to check promo code: %v", err)
+		}
+	}
+	if len(eval.Lines) != len(lines) {
+		return status.Error(codes.Internal, "promo code evaluation does not match the cart lines")
+	}
+
+	for i, line := range eval.Lines {
+		resp.Items[indexes[i]].DiscountMinor = line.DiscountMinor
+	}
+	resp.PromoCode = eval.Promotion.GetCode()
+	resp.DiscountMinor = eval.DiscountMinor
+	resp.TotalMinor = resp.SubtotalMinor - eval.DiscountMinor
+	return nil
+}
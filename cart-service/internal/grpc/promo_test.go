@@ -0,0 +1,137 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/fjod/go_cart/cart-service/internal/domain"
+	pb "github.com/fjod/go_cart/cart-service/pkg/proto"
+	productpb "github.com/fjod/go_cart/product-service/pkg/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func mouseClient() *mockProductServiceClient {
+	return &mockProductServiceClient{
+		getProductResp: &productpb.GetProductResponse{
+			Product: &productpb.Product{Id: 1, Name: "Mouse", PriceMinor: 2550, Currency: "USD"},
+		},
+		evaluateResp: &productpb.EvaluatePromotionResponse{
+			Promotion:     &productpb.Promotion{Code: "TEN"},
+			Lines:         []*productpb.PromotionLineDiscount{{ProductId: 1, DiscountMinor: 765}},
+			SubtotalMinor: 7650,
+			DiscountMinor: 765,
+			TotalMinor:    6885,
+			Currency:      "USD",
+		},
+	}
+}
+
+func TestApplyPromoCode(t *testing.T) {
+	cart := &domain.Cart{
+		Items:  []domain.CartItem{{ProductID: 1, Quantity: 3}, {ProductID: 9, Quantity: 1}},
+		UserID: "123",
+	}
+	service := createCacheAndRepo(cart)
+	productClient := mouseClient()
+	server := NewCartServiceServer(service, productClient, nil, StockCheck{}, slog.Default())
+
+	resp, err := server.ApplyPromoCode(context.Background(), &pb.ApplyPromoCodeRequest{UserId: 123, Code: " ten "})
+	require.NoError(t, err)
+
+	// only the line that can be bought is sent to product-service
+	require.Len(t, productClient.evaluateReq.Lines, 1)
+	assert.Equal(t, "ten", productClient.evaluateReq.Code)
+	assert.Equal(t, int32(3), productClient.evaluateReq.Lines[0].Quantity)
+
+	assert.Equal(t, "TEN", resp.PromoCode)
+	assert.Equal(t, "TEN", resp.Cart.PromoCode)
+	assert.Equal(t, int64(7650), resp.SubtotalMinor)
+	assert.Equal(t, int64(765), resp.DiscountMinor)
+	assert.Equal(t, int64(6885), resp.TotalMinor)
+	assert.Equal(t, int64(765), resp.Items[0].DiscountMinor)
+	assert.Zero(t, resp.Items[1].DiscountMinor)
+	assert.Equal(t, "TEN", cart.PromoCode, "the normalized code is stored on the cart")
+}
+
+func TestApplyPromoCode_Rejected(t *testing.T) {
+	for name, tc := range map[string]struct {
+		err  error
+		want codes.Code
+	}{
+		"unknown code":   {status.Error(codes.NotFound, "promo code not found"), codes.NotFound},
+		"does not apply": {status.Error(codes.FailedPrecondition, "promo code cannot be applied: it has expired"), codes.FailedPrecondition},
+		"service down":   {errors.New("connection refused"), codes.Unavailable},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cart := &domain.Cart{Items: []domain.CartItem{{ProductID: 1, Quantity: 3}}, UserID: "123"}
+			productClient := mouseClient()
+			productClient.evaluateErr = tc.err
+			server := NewCartServiceServer(createCacheAndRepo(cart), productClient, nil, StockCheck{}, slog.Default())
+
+			_, err := server.ApplyPromoCode(context.Background(), &pb.ApplyPromoCodeRequest{UserId: 123, Code: "TEN"})
+			assert.Equal(t, tc.want, status.Code(err))
+			assert.Empty(t, cart.PromoCode)
+		})
+	}
+}
+
+func TestApplyPromoCode_InvalidInput(t *testing.T) {
+	server := NewCartServiceServer(createCacheAndRepo(&domain.Cart{UserID: "123"}), mouseClient(), nil, StockCheck{}, slog.Default())
+
+	_, err := server.ApplyPromoCode(context.Background(), &pb.ApplyPromoCodeRequest{UserId: 123, Code: "  "})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = server.ApplyPromoCode(context.Background(), &pb.ApplyPromoCodeRequest{UserId: 123, Code: "TEN"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err), "an empty cart takes no code")
+}
+
+func TestGetPricedCart_PromoCodeNoLongerApplies(t *testing.T) {
+	cart := &domain.Cart{Items: []domain.CartItem{{ProductID: 1, Quantity: 3}}, UserID: "123", PromoCode: "TEN"}
+	productClient := mouseClient()
+	productClient.evaluateErr = status.Error(codes.FailedPrecondition, "promo code cannot be applied: it has expired")
+	server := NewCartServiceServer(createCacheAndRepo(cart), productClient, nil, StockCheck{}, slog.Default())
+
+	resp, err := server.GetPricedCart(context.Background(), &pb.GetCartRequest{UserId: 123})
+	require.NoError(t, err)
+	assert.Equal(t, "promo code cannot be applied: it has expired", resp.PromoError)
+	assert.Equal(t, "TEN", resp.Cart.PromoCode)
+	assert.Empty(t, resp.PromoCode)
+	assert.Zero(t, resp.DiscountMinor)
+	assert.Equal(t, int64(7650), resp.TotalMinor)
+}
+
+func TestRemovePromoCode(t *testing.T) {
+	cart := &domain.Cart{Items: []domain.CartItem{{ProductID: 1, Quantity: 3}}, UserID: "123", PromoCode: "TEN"}
+	productClient := mouseClient()
+	server := NewCartServiceServer(createCacheAndRepo(cart), productClient, nil, StockCheck{}, slog.Default())
+
+	resp, err := server.RemovePromoCode(context.Background(), &pb.RemovePromoCodeRequest{UserId: 123})
+	require.NoError(t, err)
+	assert.Empty(t, cart.PromoCode)
+	assert.Empty(t, resp.Cart.PromoCode)
+	assert.Zero(t, resp.DiscountMinor)
+	assert.Nil(t, productClient.evaluateReq)
+}
+
+func TestApplyPromoCode_StaleVersion(t *testing.T) {
+	cart := &domain.Cart{Items: []domain.CartItem{{ProductID: 1, Quantity: 3}}, UserID: "123", Version: 4}
+	productClient := mouseClient()
+	server := NewCartServiceServer(createCacheAndRepo(cart), productClient, nil, StockCheck{}, slog.Default())
+	stale := int64(3)
+
+	_, err := server.ApplyPromoCode(context.Background(), &pb.ApplyPromoCodeRequest{UserId: 123, Code: "TEN", ExpectedVersion: &stale})
+
+	assert.Equal(t, codes.Aborted, status.Code(err))
+	assert.Nil(t, productClient.evaluateReq, "a stale version is refused before the code is checked")
+	assert.Empty(t, cart.PromoCode)
+
+	resp, err := server.ApplyPromoCode(context.Background(), &pb.ApplyPromoCodeRequest{UserId: 123, Code: "TEN"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), resp.Cart.Version)
+	assert.Equal(t, int64(5), cart.Version)
+}
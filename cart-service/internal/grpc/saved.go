@@ -0,0 +1,208 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/fjod/go_cart/cart-service/internal/domain"
+	"github.com/fjod/go_cart/cart-service/internal/repository"
+	s "github.com/fjod/go_cart/cart-service/internal/service"
+	pb "github.com/fjod/go_cart/cart-service/pkg/proto"
+	"github.com/fjod/go_cart/pkg/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxListNameLength bounds wishlist names
+const maxListNameLength = 64
+
+// savedOwner checks the user of a saved list request, saved lists belong to signed-in users only
+func savedOwner(userID int64) (cartOwner, error) {
+	if userID <= 0 {
+		return cartOwner{}, status.Error(codes.InvalidArgument, "user_id must be greater than 0")
+	}
+	return cartOwner{userID: userID}, nil
+}
+
+// listName trims the list name of a request, the empty name is the saved-for-later list
+func listName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if len(name) > maxListNameLength {
+		return "", status.Errorf(codes.InvalidArgument, "list must be at most %d characters", maxListNameLength)
+	}
+	return name, nil
+}
+
+func validateSavedItem(productID, variantID int64) error {
+	if productID <= 0 {
+		return status.Error(codes.InvalidArgument, "product_id must be greater than 0")
+	}
+	if variantID < 0 {
+		return status.Error(codes.InvalidArgument, "variant_id must not be negative")
+	}
+	return nil
+}
+
+func convertLists(lists []domain.SavedList) []*pb.SavedList {
+	out := make([]*pb.SavedList, len(lists))
+	for i, list := range lists {
+		out[i] = &pb.SavedList{
+			Name:  list.Name,
+			Items: convertItems(list.Items),
+		}
+	}
+	return out
+}
+
+func (s *CartServiceServer) savedItemsResponse(ctx context.Context, cart domain.Cart, owner cartOwner) *pb.SavedItemsResponse {
+	return &pb.SavedItemsResponse{
+		Cart:  s.stockedCart(ctx, cart, owner),
+		Lists: convertLists(cart.Lists),
+	}
+}
+
+// savedError maps the errors of a saved list change to gRPC statuses
+func savedError(err error, what string) error {
+	switch {
+	case errors.Is(err, repository.ErrVersionConflict):
+		return errStaleVersion
+	case errors.Is(err, repository.ErrItemNotFound):
+		return status.Error(codes.NotFound, "item not found")
+	case errors.Is(err, repository.ErrListNotFound):
+		return status.Error(codes.NotFound, "list not found")
+	case errors.Is(err, s.ErrTooManyLists):
+		return status.Errorf(codes.FailedPrecondition, "at most %d lists can be kept", s.MaxSavedLists)
+	default:
+		return status.Errorf(codes.Internal, "failed to %s: %v", what, err)
+	}
+}
+
+// GetSavedItems returns the user's cart with the saved-for-later list and wishlists
+func (s *CartServiceServer) GetSavedItems(
+	ctx context.Context,
+	req *pb.GetSavedItemsRequest) (*pb.SavedItemsResponse, error) {
+
+	owner, err := savedOwner(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	logger.WithContext(s.logger, ctx).Info("get saved items", owner.logAttr())
+
+	cart, err := s.service.GetCart(ctx, owner.key())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get cart: %v", err)
+	}
+	return s.savedItemsResponse(ctx, *cart, owner), nil
+}
+
+// SaveForLater moves a cart line to a saved list in the same write that takes it out of the cart
+func (s *CartServiceServer) SaveForLater(
+	ctx context.Context,
+	req *pb.SaveForLaterRequest) (*pb.SavedItemsResponse, error) {
+
+	owner, err := savedOwner(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSavedItem(req.ProductId, req.VariantId); err != nil {
+		return nil, err
+	}
+	list, err := listName(req.List)
+	if err != nil {
+		return nil, err
+	}
+	logger.WithContext(s.logger, ctx).Info("save for later", owner.logAttr(),
+		slog.Int64("product_id", req.ProductId), slog.String("list", list))
+
+	cart, err := s.service.SaveForLater(ctx, owner.key(), req.ProductId, req.VariantId, list, req.ExpectedVersion)
+	if err != nil {
+		return nil, savedError(err, "save item for later")
+	}
+	return s.savedItemsResponse(ctx, *cart, owner), nil
+}
+
+// MoveToCart moves an item of a saved list back into the cart. Like AddItem it refuses products that
+// are no longer on sale, those stay on the list.
+func (s *CartServiceServer) MoveToCart(
+	ctx context.Context,
+	req *pb.MoveToCartRequest) (*pb.SavedItemsResponse, error) {
+
+	owner, err := savedOwner(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSavedItem(req.ProductId, req.VariantId); err != nil {
+		return nil, err
+	}
+	list, err := listName(req.List)
+	if err != nil {
+		return nil, err
+	}
+	logger.WithContext(s.logger, ctx).Info("move to cart", owner.logAttr(),
+		slog.Int64("product_id", req.ProductId), slog.String("list", list))
+
+	products, err := s.lookupProducts(ctx, req.ProductId)
+	if err != nil {
+		return nil, err
+	}
+	if products[req.ProductId].ArchivedAt != "" {
+		return nil, status.Error(codes.FailedPrecondition, "product is no longer available")
+	}
+	if _, err := variantSKU(products[req.ProductId], req.VariantId); err != nil {
+		return nil, err
+	}
+
+	cart, err := s.service.MoveToCart(ctx, owner.key(), req.ProductId, req.VariantId, list, req.ExpectedVersion)
+	if err != nil {
+		return nil, savedError(err, "move item to cart")
+	}
+	return s.savedItemsResponse(ctx, *cart, owner), nil
+}
+
+func (s *CartServiceServer) RemoveSavedItem(
+	ctx context.Context,
+	req *pb.RemoveSavedItemRequest) (*pb.SavedItemsResponse, error) {
+
+	owner, err := savedOwner(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSavedItem(req.ProductId, req.VariantId); err != nil {
+		return nil, err
+	}
+	list, err := listName(req.List)
+	if err != nil {
+		return nil, err
+	}
+	logger.WithContext(s.logger, ctx).Info("remove saved item", owner.logAttr(),
+		slog.Int64("product_id", req.ProductId), slog.String("list", list))
+
+	cart, err := s.service.RemoveSaved(ctx, owner.key(), req.ProductId, req.VariantId, list, req.ExpectedVersion)
+	if err != nil {
+		return nil, savedError(err, "remove saved item")
+	}
+	return s.savedItemsResponse(ctx, *cart, owner), nil
+}
+
+func (s *CartServiceServer) DeleteSavedList(
+	ctx context.Context,
+	req *pb.DeleteSavedListRequest) (*pb.SavedItemsResponse, error) {
+
+	owner, err := savedOwner(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	list, err := listName(req.List)
+	if err != nil {
+		return nil, err
+	}
+	logger.WithContext(s.logger, ctx).Info("delete saved list", owner.logAttr(), slog.String("list", list))
+
+	cart, err := s.service.DeleteList(ctx, owner.key(), list, req.ExpectedVersion)
+	if err != nil {
+		return nil, savedError(err, "delete saved list")
+	}
+	return s.savedItemsResponse(ctx, *cart, owner), nil
+}
@@ -0,0 +1,133 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/fjod/go_cart/cart-service/internal/domain"
+	pb "github.com/fjod/go_cart/cart-service/pkg/proto"
+	productpb "github.com/fjod/go_cart/product-service/pkg/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSaveForLater_LeavesTheCart(t *testing.T) {
+	cart := &domain.Cart{UserID: "123", Items: []domain.CartItem{{ProductID: 1, Quantity: 2}, {ProductID: 5, Quantity: 1}}}
+	server := NewCartServiceServer(createCacheAndRepo(cart), mouseClient(), nil, StockCheck{}, slog.Default())
+
+	resp, err := server.SaveForLater(context.Background(), &pb.SaveForLaterRequest{UserId: 123, ProductId: 1, List: " birthday "})
+	require.NoError(t, err)
+	require.Len(t, resp.Lists, 1)
+	assert.Equal(t, "birthday", resp.Lists[0].Name)
+	assert.Equal(t, int64(1), resp.Lists[0].Items[0].ProductId)
+	assert.Equal(t, int32(2), resp.Lists[0].Items[0].Quantity)
+
+	// checkout reads the cart through GetCart, the saved item is not among its lines
+	got, err := server.GetCart(context.Background(), &pb.GetCartRequest{UserId: 123})
+	require.NoError(t, err)
+	require.Len(t, got.Cart.Cart, 1)
+	assert.Equal(t, int64(5), got.Cart.Cart[0].ProductId)
+
+	saved, err := server.GetSavedItems(context.Background(), &pb.GetSavedItemsRequest{UserId: 123})
+	require.NoError(t, err)
+	require.Len(t, saved.Lists, 1)
+	assert.Len(t, saved.Lists[0].Items, 1)
+}
+
+func TestMoveToCart(t *testing.T) {
+	cart := &domain.Cart{UserID: "123", Lists: []domain.SavedList{{Items: []domain.CartItem{{ProductID: 1, Quantity: 3}}}}}
+	server := NewCartServiceServer(createCacheAndRepo(cart), mouseClient(), nil, StockCheck{}, slog.Default())
+
+	resp, err := server.MoveToCart(context.Background(), &pb.MoveToCartRequest{UserId: 123, ProductId: 1})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Cart.Cart, 1)
+	assert.Equal(t, int32(3), resp.Cart.Cart[0].Quantity)
+	require.Len(t, resp.Lists, 1)
+	assert.Empty(t, resp.Lists[0].Items)
+	assert.Equal(t, int64(1), resp.Cart.Version)
+}
+
+func TestMoveToCart_ArchivedProductStaysSaved(t *testing.T) {
+	cart := &domain.Cart{UserID: "123", Lists: []domain.SavedList{{Items: []domain.CartItem{{ProductID: 1, Quantity: 3}}}}}
+	products := &mockProductServiceClient{getProductResp: &productpb.GetProductResponse{
+		Product: &productpb.Product{Id: 1, ArchivedAt: "2026-01-01T00:00:00Z"},
+	}}
+	server := NewCartServiceServer(createCacheAndRepo(cart), products, nil, StockCheck{}, slog.Default())
+
+	_, err := server.MoveToCart(context.Background(), &pb.MoveToCartRequest{UserId: 123, ProductId: 1})
+
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Len(t, cart.List("").Items, 1)
+	assert.Empty(t, cart.Items)
+}
+
+func TestSavedItems_Errors(t *testing.T) {
+	newServer := func() (*CartServiceServer, *domain.Cart) {
+		cart := &domain.Cart{
+			UserID:  "123",
+			Items:   []domain.CartItem{{ProductID: 1, Quantity: 2}},
+			Version: 4,
+		}
+		return NewCartServiceServer(createCacheAndRepo(cart), mouseClient(), nil, StockCheck{}, slog.Default()), cart
+	}
+	tests := []struct {
+		name     string
+		call     func(*CartServiceServer) error
+		wantCode codes.Code
+	}{
+		{"guest", func(s *CartServiceServer) error {
+			_, err := s.SaveForLater(context.Background(), &pb.SaveForLaterRequest{ProductId: 1})
+			return err
+		}, codes.InvalidArgument},
+		{"not in cart", func(s *CartServiceServer) error {
+			_, err := s.SaveForLater(context.Background(), &pb.SaveForLaterRequest{UserId: 123, ProductId: 2})
+			return err
+		}, codes.NotFound},
+		{"not on list", func(s *CartServiceServer) error {
+			_, err := s.MoveToCart(context.Background(), &pb.MoveToCartRequest{UserId: 123, ProductId: 1, List: "birthday"})
+			return err
+		}, codes.NotFound},
+		{"no such list", func(s *CartServiceServer) error {
+			_, err := s.DeleteSavedList(context.Background(), &pb.DeleteSavedListRequest{UserId: 123, List: "birthday"})
+			return err
+		}, codes.NotFound},
+		{"stale version", func(s *CartServiceServer) error {
+			_, err := s.SaveForLater(context.Background(), &pb.SaveForLaterRequest{UserId: 123, ProductId: 1, ExpectedVersion: proto.Int64(3)})
+			return err
+		}, codes.Aborted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, cart := newServer()
+
+			err := tt.call(server)
+
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Len(t, cart.Items, 1)
+			assert.Empty(t, cart.Lists)
+			assert.Equal(t, int64(4), cart.Version)
+		})
+	}
+}
+
+func TestSaveForLater_TooManyLists(t *testing.T) {
+	cart := &domain.Cart{UserID: "123", Items: []domain.CartItem{{ProductID: 1, Quantity: 2}}}
+	for i := 0; i < 20; i++ {
+		cart.Lists = append(cart.Lists, domain.SavedList{Name: fmt.Sprintf("list %d", i)})
+	}
+	server := NewCartServiceServer(createCacheAndRepo(cart), mouseClient(), nil, StockCheck{}, slog.Default())
+
+	_, err := server.SaveForLater(context.Background(), &pb.SaveForLaterRequest{UserId: 123, ProductId: 1, List: "one more"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// an existing list still takes items
+	_, err = server.SaveForLater(context.Background(), &pb.SaveForLaterRequest{UserId: 123, ProductId: 1, List: "list 3"})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,113 @@
+package grpc
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/fjod/go_cart/cart-service/internal/domain"
+	pb "github.com/fjod/go_cart/cart-service/pkg/proto"
+	inventorypb "github.com/fjod/go_cart/inventory-service/pkg/proto"
+	"github.com/fjod/go_cart/pkg/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultLowStockThreshold is the LowStockThreshold used when none is configured
+const DefaultLowStockThreshold = 5
+
+// StockCheck configures how cart lines are checked against inventory-service
+type StockCheck struct {
+	LowStockThreshold int32 // a line is low on stock when at most this many units are available
+	RejectOverStock   bool  // AddItem and UpdateQuantity fail with FailedPrecondition beyond the available stock
+}
+
+type stockKey struct {
+	productID int64
+	variantID int64
+}
+
+// fetchStock gets the available units of every variant of the listed products in one inventory-service call
+func (s *CartServiceServer) fetchStock(ctx context.Context, productIDs []int64) (map[stockKey]int32, error) {
+	resp, err := s.inventoryClient.GetStock(ctx, &inventorypb.GetStockRequest{ProductIds: productIDs})
+	if err != nil {
+		return nil, err
+	}
+	stock := make(map[stockKey]int32, len(resp.Stocks))
+	for _, st := range resp.Stocks {
+		stock[stockKey{st.ProductId, st.VariantId}] = st.Available
+	}
+	return stock, nil
+}
+
+// lineStock gets the stock of the products in the cart. It returns nil when there is no inventory client
+// or inventory-service fails: stock is only advice here, checkout reserves it for real.
+func (s *CartServiceServer) lineStock(ctx context.Context, items []domain.CartItem) map[stockKey]int32 {
+	if s.inventoryClient == nil || len(items) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(items))
+	for i, item := range items {
+		ids[i] = item.ProductID
+	}
+	stock, err := s.fetchStock(ctx, ids)
+	if err != nil {
+		logger.WithContext(s.logger, ctx).Warn("failed to get stock, cart lines are not annotated", slog.Any("error", err))
+		return nil
+	}
+	return stock
+}
+
+// stockStatus rates a line of quantity units against the stock, a product variant inventory-service
+// doesn't know has nothing available. Without stock the status is unknown.
+func (s *CartServiceServer) stockStatus(stock map[stockKey]int32, productID, variantID int64, quantity int32) (pb.StockStatus, int32) {
+	if stock == nil {
+		return pb.StockStatus_STOCK_STATUS_UNKNOWN, 0
+	}
+	available := stock[stockKey{productID, variantID}]
+	switch {
+	case available < quantity:
+		return pb.StockStatus_STOCK_STATUS_INSUFFICIENT, available
+	case available <= s.stockCheck.LowStockThreshold:
+		return pb.StockStatus_STOCK_STATUS_LOW_STOCK, available
+	default:
+		return pb.StockStatus_STOCK_STATUS_IN_STOCK, available
+	}
+}
+
+// stockedCart converts the cart and annotates every line with its stock
+func (s *CartServiceServer) stockedCart(ctx context.Context, c domain.Cart, owner cartOwner) *pb.Cart {
+	cart := convertCart(c, owner)
+	stock := s.lineStock(ctx, c.Items)
+	for _, item := range cart.Cart {
+		item.StockStatus, item.AvailableQuantity = s.stockStatus(stock, item.ProductId, item.VariantId, item.Quantity)
+	}
+	return cart
+}
+
+// checkStock rejects a line quantity beyond the available stock when RejectOverStock is on.
+// When inventory-service fails the quantity is let through, checkout still reserves the stock.
+func (s *CartServiceServer) checkStock(ctx context.Context, productID, variantID int64, quantity int32) error {
+	return s.checkStockLines(ctx, []domain.CartItem{{ProductID: productID, VariantID: variantID, Quantity: int(quantity)}})
+}
+
+// checkStockLines is checkStock for several lines in one inventory-service call
+func (s *CartServiceServer) checkStockLines(ctx context.Context, items []domain.CartItem) error {
+	if !s.stockCheck.RejectOverStock || s.inventoryClient == nil || len(items) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(items))
+	for i, item := range items {
+		ids[i] = item.ProductID
+	}
+	stock, err := s.fetchStock(ctx, ids)
+	if err != nil {
+		logger.WithContext(s.logger, ctx).Warn("failed to check stock, quantity not checked", slog.Any("error", err))
+		return nil
+	}
+	for _, item := range items {
+		if available := stock[stockKey{item.ProductID, item.VariantID}]; available < int32(item.Quantity) {
+			return status.Errorf(codes.FailedPrecondition, "only %d units of product %d are in stock", available, item.ProductID)
+		}
+	}
+	return nil
+}
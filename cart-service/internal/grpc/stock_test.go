@@ -0,0 +1,141 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/fjod/go_cart/cart-service/internal/domain"
+	pb "github.com/fjod/go_cart/cart-service/pkg/proto"
+	inventorypb "github.com/fjod/go_cart/inventory-service/pkg/proto"
+	productpb "github.com/fjod/go_cart/product-service/pkg/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// mockInventoryServiceClient implements inventorypb.InventoryServiceClient
+type mockInventoryServiceClient struct {
+	inventorypb.InventoryServiceClient // reservations are not made by the cart
+	stocks                             []*inventorypb.StockInfo
+	err                                error
+}
+
+func (m *mockInventoryServiceClient) GetStock(_ context.Context, req *inventorypb.GetStockRequest, _ ...grpc.CallOption) (*inventorypb.GetStockResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	resp := &inventorypb.GetStockResponse{}
+	for _, id := range req.ProductIds {
+		for _, st := range m.stocks {
+			if st.ProductId == id {
+				resp.Stocks = append(resp.Stocks, st)
+			}
+		}
+	}
+	return resp, nil
+}
+
+func TestGetCart_StockAnnotations(t *testing.T) {
+	cart := &domain.Cart{
+		Items: []domain.CartItem{
+			{ProductID: 1, Quantity: 2},
+			{ProductID: 2, VariantID: 7, Quantity: 2},
+			{ProductID: 3, Quantity: 5},
+			{ProductID: 4, Quantity: 1},
+		},
+		UserID: "123",
+	}
+	inventory := &mockInventoryServiceClient{stocks: []*inventorypb.StockInfo{
+		{ProductId: 1, Available: 50},
+		{ProductId: 2, VariantId: 7, Available: 3},
+		{ProductId: 3, Available: 4},
+	}}
+	server := NewCartServiceServer(createCacheAndRepo(cart), &mockProductServiceClient{}, inventory,
+		StockCheck{LowStockThreshold: 5}, slog.Default())
+
+	ret, err := server.GetCart(context.Background(), &pb.GetCartRequest{UserId: 123})
+
+	require.NoError(t, err)
+	lines := ret.Cart.Cart
+	assert.Equal(t, pb.StockStatus_STOCK_STATUS_IN_STOCK, lines[0].StockStatus)
+	assert.Equal(t, int32(50), lines[0].AvailableQuantity)
+	assert.Equal(t, pb.StockStatus_STOCK_STATUS_LOW_STOCK, lines[1].StockStatus)
+	assert.Equal(t, int32(3), lines[1].AvailableQuantity)
+	assert.Equal(t, pb.StockStatus_STOCK_STATUS_INSUFFICIENT, lines[2].StockStatus)
+	assert.Equal(t, int32(4), lines[2].AvailableQuantity)
+	// inventory-service doesn't stock product 4, checkout could not reserve it
+	assert.Equal(t, pb.StockStatus_STOCK_STATUS_INSUFFICIENT, lines[3].StockStatus)
+	assert.Zero(t, lines[3].AvailableQuantity)
+}
+
+func TestGetCart_InventoryDown_StockUnknown(t *testing.T) {
+	cart := &domain.Cart{Items: []domain.CartItem{{ProductID: 1, Quantity: 2}}, UserID: "123"}
+	inventory := &mockInventoryServiceClient{err: errors.New("connection refused")}
+	server := NewCartServiceServer(createCacheAndRepo(cart), &mockProductServiceClient{}, inventory,
+		StockCheck{LowStockThreshold: 5}, slog.Default())
+
+	ret, err := server.GetCart(context.Background(), &pb.GetCartRequest{UserId: 123})
+
+	require.NoError(t, err)
+	assert.Equal(t, pb.StockStatus_STOCK_STATUS_UNKNOWN, ret.Cart.Cart[0].StockStatus)
+}
+
+func TestGetPricedCart_StockAnnotations(t *testing.T) {
+	cart := &domain.Cart{Items: []domain.CartItem{{ProductID: 1, Quantity: 3}}, UserID: "123"}
+	products := &mockProductServiceClient{getProductResp: &productpb.GetProductResponse{
+		Product: &productpb.Product{Id: 1, Name: "Mouse", PriceMinor: 2550, Currency: "USD"},
+	}}
+	inventory := &mockInventoryServiceClient{stocks: []*inventorypb.StockInfo{{ProductId: 1, Available: 2}}}
+	server := NewCartServiceServer(createCacheAndRepo(cart), products, inventory, StockCheck{}, slog.Default())
+
+	ret, err := server.GetPricedCart(context.Background(), &pb.GetCartRequest{UserId: 123})
+
+	require.NoError(t, err)
+	assert.Equal(t, pb.StockStatus_STOCK_STATUS_INSUFFICIENT, ret.Items[0].StockStatus)
+	assert.Equal(t, int32(2), ret.Items[0].AvailableQuantity)
+}
+
+func TestRejectOverStock(t *testing.T) {
+	inventory := &mockInventoryServiceClient{stocks: []*inventorypb.StockInfo{{ProductId: 1, Available: 3}}}
+	products := &mockProductServiceClient{getProductResp: &productpb.GetProductResponse{
+		Product: &productpb.Product{Id: 1, Name: "Mouse"},
+	}}
+
+	tests := []struct {
+		name     string
+		check    StockCheck
+		quantity int32
+		wantCode codes.Code
+	}{
+		{"within stock", StockCheck{RejectOverStock: true}, 3, codes.OK},
+		{"beyond stock", StockCheck{RejectOverStock: true}, 4, codes.FailedPrecondition},
+		{"beyond stock, rejection off", StockCheck{}, 4, codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cart := &domain.Cart{Items: []domain.CartItem{{ProductID: 1, Quantity: 1}}, UserID: "123"}
+			server := NewCartServiceServer(createCacheAndRepo(cart), products, inventory, tt.check, slog.Default())
+
+			_, err := server.AddItem(context.Background(), &pb.AddCartItemRequest{UserId: 123, ProductId: 1, Quantity: tt.quantity})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+
+			_, err = server.UpdateQuantity(context.Background(), &pb.UpdateQuantityRequest{UserId: 123, ProductId: 1, Quantity: tt.quantity})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestRejectOverStock_InventoryDown_LetsThrough(t *testing.T) {
+	cart := &domain.Cart{Items: []domain.CartItem{{ProductID: 1, Quantity: 1}}, UserID: "123"}
+	inventory := &mockInventoryServiceClient{err: errors.New("connection refused")}
+	server := NewCartServiceServer(createCacheAndRepo(cart), &mockProductServiceClient{}, inventory,
+		StockCheck{RejectOverStock: true}, slog.Default())
+
+	_, err := server.UpdateQuantity(context.Background(), &pb.UpdateQuantityRequest{UserId: 123, ProductId: 1, Quantity: 10})
+	assert.NoError(t, err)
+}
@@ -54,7 +54,12 @@ func (p *Poller) getMessagesAndEmptyCart(ctx context.Context) {
 		p.logger.Error("error reading kafka message", "error", err)
 		return
 	}
+	p.handleMessage(ctx, m)
+}
 
+// handleMessage empties the cart of the user who completed a checkout.
+// An emptied cart is no abandoned cart, see AbandonedCartRepository.
+func (p *Poller) handleMessage(ctx context.Context, m kafka.Message) {
 	mapping := make(map[string]string)
 	for _, h := range m.Headers {
 		mapping[h.Key] = string(h.Value)
@@ -76,7 +81,7 @@ func (p *Poller) getMessagesAndEmptyCart(ctx context.Context) {
 		return
 	}
 
-	errDelete := p.repo.DeleteCart(ctx, userID)
+	errDelete := p.repo.DeleteCart(ctx, userID, nil)
 	if errDelete != nil && !errors.Is(errDelete, r.ErrCartNotFound) {
 		log.Error("failed to delete cart after checkout", "user_id", userID, "error", errDelete)
 	}
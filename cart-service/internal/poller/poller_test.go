@@ -57,7 +57,7 @@ func setupTestDB(t *testing.T) (r.CartRepository, func()) {
 	require.NoError(t, err)
 
 	// Create repository
-	repo := r.NewMongoRepository(db)
+	repo := r.NewMongoRepository(db, r.DefaultGuestCartTTL)
 
 	cleanup := func() {
 		if err := mongoContainer.Terminate(ctx); err != nil {
@@ -132,7 +132,7 @@ func TestPoller_Start(t *testing.T) {
 		ProductID: 1,
 		Quantity:  1,
 		AddedAt:   time.Time{},
-	})
+	}, nil)
 	cart, errGetCart := dbRepo.GetCart(ctx, "123")
 	require.NoError(t, errGetCart)
 	require.NotNil(t, cart)
@@ -172,8 +172,8 @@ func TestPoller_Start(t *testing.T) {
 
 	go poller.Run(ctx) // start poller
 	require.Eventually(t, func() bool {
-		_, eClearCart := dbRepo.GetCart(ctx, "123")
-		return errors.Is(eClearCart, r.ErrCartNotFound) // cart is cleared
+		cleared, eClearCart := dbRepo.GetCart(ctx, "123")
+		return eClearCart == nil && len(cleared.Items) == 0 // cart is cleared
 	}, 15*time.Second, 500*time.Millisecond)
 
 	require.Eventually(t, func() bool {
@@ -183,3 +183,31 @@ func TestPoller_Start(t *testing.T) {
 
 	fmt.Println("Poller run finished")
 }
+
+func TestHandleMessage_SuppressesPendingAbandonment(t *testing.T) {
+	ctx := context.Background()
+	cache, _, cleanupRedis := setupTestRedis(t)
+	defer cleanupRedis()
+	dbRepo, cleanupDb := setupTestDB(t)
+	defer cleanupDb()
+	abandoned := dbRepo.(r.AbandonedCartRepository)
+
+	require.NoError(t, dbRepo.AddItem(ctx, "123", domain.CartItem{ProductID: 1, Quantity: 1}, nil))
+	idleSince, notBefore := time.Now().Add(time.Minute), time.Now().Add(-time.Hour)
+	found, err := abandoned.FindAbandoned(ctx, idleSince, notBefore, 10)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+
+	payloadJSON, err := json.Marshal(map[string]interface{}{"checkout_id": "chId", "user_id": "123"})
+	require.NoError(t, err)
+	poller := &Poller{repo: dbRepo, cache: cache, logger: slog.Default()}
+	poller.handleMessage(ctx, kafkaGo.Message{Key: []byte("chId"), Value: payloadJSON})
+
+	// the scanner found the cart before the checkout completed, its mark no longer takes
+	marked, err := abandoned.MarkAbandoned(ctx, found[0], time.Now())
+	require.NoError(t, err)
+	assert.Assert(t, !marked)
+	found, err = abandoned.FindAbandoned(ctx, idleSince, notBefore, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(found))
+}
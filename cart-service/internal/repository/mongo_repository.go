@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/fjod/go_cart/cart-service/internal/domain"
@@ -13,12 +14,89 @@ import (
 )
 
 var (
-	ErrCartNotFound = errors.New("cart not found")
-	ErrItemNotFound = errors.New("item not found in cart")
+	ErrCartNotFound    = errors.New("cart not found")
+	ErrItemNotFound    = errors.New("item not found in cart")
+	ErrListNotFound    = errors.New("saved list not found")
+	ErrVersionConflict = errors.New("cart is at another version")
 )
 
+// maxAddAttempts bounds how often AddItem starts over when the cart changes under it
+const maxAddAttempts = 3
+
+// DefaultGuestCartTTL is how long a guest cart is kept after its last change
+const DefaultGuestCartTTL = 30 * 24 * time.Hour
+
+// cartDocument is a cart as stored. has_lists keeps the carts that hold saved items or wishlists out of the
+// inactivity TTL, so they are kept however long the user stays away.
+type cartDocument struct {
+	domain.Cart `bson:",inline"`
+	HasLists    bool `bson:"has_lists"`
+}
+
+// inactivityTTL is how long a cart without saved lists is kept after its last change
+const inactivityTTL = 90 * 24 * time.Hour
+
 type mongoRepository struct {
 	collection *mongo.Collection
+	guestTTL   time.Duration
+}
+
+// expiresAt is the expiry of a cart written at now, nil for the carts of signed-in users
+func (m mongoRepository) expiresAt(userID string, now time.Time) *time.Time {
+	if !domain.IsGuestCartKey(userID) {
+		return nil
+	}
+	expires := now.Add(m.guestTTL)
+	return &expires
+}
+
+// touch is the $set that marks a cart as changed at now, a guest cart's expiry moves along with it
+func (m mongoRepository) touch(userID string, now time.Time, set bson.M) bson.M {
+	set["updated_at"] = now
+	if expires := m.expiresAt(userID, now); expires != nil {
+		set["expires_at"] = *expires
+	}
+	return set
+}
+
+// bump is the $inc every change of a cart carries
+var bump = bson.M{"version": int64(1)}
+
+// versionFilter adds the version precondition to filter, carts written before versions match version 0
+func versionFilter(filter bson.M, version *int64) bson.M {
+	if version == nil {
+		return filter
+	}
+	if *version == 0 {
+		filter["version"] = bson.M{"$in": bson.A{0, nil}}
+	} else {
+		filter["version"] = *version
+	}
+	return filter
+}
+
+// missed tells why a change matched no cart: ErrVersionConflict when the cart is at another version
+// than the precondition, notFound otherwise
+func (m mongoRepository) missed(ctx context.Context, userID string, version *int64, notFound error) error {
+	if version == nil {
+		return notFound
+	}
+	var cart domain.Cart
+	opts := options.FindOne().SetProjection(bson.M{"version": 1})
+	err := m.collection.FindOne(ctx, bson.M{"user_id": userID}, opts).Decode(&cart)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		if *version != 0 {
+			return ErrVersionConflict
+		}
+		return notFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check cart version: %w", err)
+	}
+	if cart.Version != *version {
+		return ErrVersionConflict
+	}
+	return notFound
 }
 
 func (m mongoRepository) GetCart(ctx context.Context, userID string) (*domain.Cart, error) {
@@ -37,7 +115,8 @@ func (m mongoRepository) GetCart(ctx context.Context, userID string) (*domain.Ca
 	return &cart, nil
 }
 
-func (m mongoRepository) UpsertCart(ctx context.Context, cart *domain.Cart) error {
+// UpsertCart writes the whole cart and sets cart.Version to the version it was stored at
+func (m mongoRepository) UpsertCart(ctx context.Context, cart *domain.Cart, version *int64) error {
 	now := time.Now()
 
 	// Set timestamps
@@ -45,108 +124,139 @@ func (m mongoRepository) UpsertCart(ctx context.Context, cart *domain.Cart) erro
 		cart.CreatedAt = now
 	}
 	cart.UpdatedAt = now
+	cart.ExpiresAt = m.expiresAt(cart.UserID, now)
 
-	filter := bson.M{"user_id": cart.UserID}
-	update := bson.M{"$set": cart}
-	opts := options.Update().SetUpsert(true)
+	items := cart.Items
+	if items == nil {
+		items = []domain.CartItem{} // null would make a later $push fail
+	}
+	lists := cart.Lists
+	if lists == nil {
+		lists = []domain.SavedList{}
+	}
+	filter := versionFilter(bson.M{"user_id": cart.UserID}, version)
+	update := bson.M{
+		"$set": bson.M{
+			"items":      items,
+			"lists":      lists,
+			"has_lists":  len(lists) > 0,
+			"promo_code": cart.PromoCode,
+			"created_at": cart.CreatedAt,
+			"updated_at": cart.UpdatedAt,
+			"expires_at": cart.ExpiresAt,
+		},
+		"$inc": bump,
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
 
-	_, err := m.collection.UpdateOne(ctx, filter, update, opts)
+	var stored domain.Cart
+	err := m.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&stored)
 	if err != nil {
+		// with a precondition that doesn't hold the upsert tries to insert a second cart for the user
+		if version != nil && mongo.IsDuplicateKeyError(err) {
+			return ErrVersionConflict
+		}
 		return fmt.Errorf("failed to upsert cart: %w", err)
 	}
-
+	cart.ID = stored.ID
+	cart.Version = stored.Version
 	return nil
 }
 
-func (m mongoRepository) AddItem(ctx context.Context, userID string, item domain.CartItem) error {
+// AddItem sets the quantity of the line when the cart holds the product variant and appends the item otherwise.
+// Each step is a single conditional update, so concurrent adds can't lose each other's lines.
+func (m mongoRepository) AddItem(ctx context.Context, userID string, item domain.CartItem, version *int64) error {
 	now := time.Now()
 	item.AddedAt = now
 
-	filter := bson.M{"user_id": userID}
-
-	// First, check if cart exists
-	var existingCart domain.Cart
-	err := m.collection.FindOne(ctx, filter).Decode(&existingCart)
-
-	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			// Cart doesn't exist, create it with the item
-			cart := &domain.Cart{
-				UserID:    userID,
-				Items:     []domain.CartItem{item},
-				CreatedAt: now,
-				UpdatedAt: now,
-			}
-
-			_, err = m.collection.InsertOne(ctx, cart)
-			if err != nil {
-				return fmt.Errorf("failed to create cart with item: %w", err)
-			}
-			return nil
-		}
-		return fmt.Errorf("failed to check existing cart: %w", err)
-	}
-
-	// Cart exists, check if item with same product_id exists
-	itemExists := false
-	for _, existingItem := range existingCart.Items {
-		if existingItem.ProductID == item.ProductID {
-			itemExists = true
-			break
-		}
-	}
-
-	if itemExists {
-		// Update existing item
+	for attempt := 0; attempt < maxAddAttempts; attempt++ {
+		// the cart holds the product variant: update its line
+		filter := versionFilter(bson.M{
+			"user_id": userID,
+			"items":   bson.M{"$elemMatch": itemMatch("", item.ProductID, item.VariantID)},
+		}, version)
 		update := bson.M{
-			"$set": bson.M{
+			"$set": m.touch(userID, now, bson.M{
 				"items.$[elem].quantity": item.Quantity,
 				"items.$[elem].added_at": now,
-				"updated_at":             now,
-			},
+			}),
+			"$inc": bump,
 		}
 		arrayFilters := options.Update().SetArrayFilters(options.ArrayFilters{
 			Filters: []interface{}{
-				bson.M{"elem.product_id": item.ProductID},
+				itemMatch("elem.", item.ProductID, item.VariantID),
 			},
 		})
-
-		_, err = m.collection.UpdateOne(ctx, filter, update, arrayFilters)
+		result, err := m.collection.UpdateOne(ctx, filter, update, arrayFilters)
 		if err != nil {
 			return fmt.Errorf("failed to update existing item: %w", err)
 		}
-	} else {
-		// Add new item
-		update := bson.M{
-			"$push": bson.M{"items": item},
-			"$set":  bson.M{"updated_at": now},
+		if result.MatchedCount > 0 {
+			return nil
 		}
 
-		_, err = m.collection.UpdateOne(ctx, filter, update)
+		// the cart exists without the product variant: append the item
+		filter = versionFilter(bson.M{
+			"user_id": userID,
+			"items":   bson.M{"$not": bson.M{"$elemMatch": itemMatch("", item.ProductID, item.VariantID)}},
+		}, version)
+		update = bson.M{
+			"$push": bson.M{"items": item},
+			"$set":  m.touch(userID, now, bson.M{}),
+			"$inc":  bump,
+		}
+		result, err = m.collection.UpdateOne(ctx, filter, update)
 		if err != nil {
 			return fmt.Errorf("failed to add new item: %w", err)
 		}
-	}
+		if result.MatchedCount > 0 {
+			return nil
+		}
 
-	return nil
-}
+		if err := m.missed(ctx, userID, version, nil); err != nil {
+			return err
+		}
 
-func (m mongoRepository) UpdateItemQuantity(ctx context.Context, userID string, productID int64, quantity int) error {
-	filter := bson.M{
-		"user_id":          userID,
-		"items.product_id": productID,
+		// no cart yet: create it with the item
+		cart := &domain.Cart{
+			UserID:    userID,
+			Items:     []domain.CartItem{item},
+			CreatedAt: now,
+			UpdatedAt: now,
+			ExpiresAt: m.expiresAt(userID, now),
+			Version:   1,
+		}
+		_, err = m.collection.InsertOne(ctx, cartDocument{Cart: *cart})
+		if err == nil {
+			return nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("failed to create cart with item: %w", err)
+		}
+		// another request created the cart first
+		if version != nil {
+			return ErrVersionConflict
+		}
 	}
+	return fmt.Errorf("failed to add item: cart changed %d times while adding", maxAddAttempts)
+}
+
+func (m mongoRepository) UpdateItemQuantity(ctx context.Context, userID string, productID, variantID int64, quantity int, version *int64) error {
+	filter := versionFilter(bson.M{
+		"user_id": userID,
+		"items":   bson.M{"$elemMatch": itemMatch("", productID, variantID)},
+	}, version)
 
 	update := bson.M{
-		"$set": bson.M{
+		"$set": m.touch(userID, time.Now(), bson.M{
 			"items.$[elem].quantity": quantity,
-			"updated_at":             time.Now(),
-		},
+		}),
+		"$inc": bump,
 	}
 
 	arrayFilters := options.Update().SetArrayFilters(options.ArrayFilters{
 		Filters: []interface{}{
-			bson.M{"elem.product_id": productID},
+			itemMatch("elem.", productID, variantID),
 		},
 	})
 
@@ -156,18 +266,19 @@ func (m mongoRepository) UpdateItemQuantity(ctx context.Context, userID string,
 	}
 
 	if result.MatchedCount == 0 {
-		return ErrItemNotFound
+		return m.missed(ctx, userID, version, ErrItemNotFound)
 	}
 	return nil
 }
 
-func (m mongoRepository) RemoveItem(ctx context.Context, userID string, productID int64) error {
-	filter := bson.M{"user_id": userID}
+func (m mongoRepository) RemoveItem(ctx context.Context, userID string, productID, variantID int64, version *int64) error {
+	filter := versionFilter(bson.M{"user_id": userID}, version)
 	update := bson.M{
 		"$pull": bson.M{
-			"items": bson.M{"product_id": productID},
+			"items": itemMatch("", productID, variantID),
 		},
-		"$set": bson.M{"updated_at": time.Now()},
+		"$set": m.touch(userID, time.Now(), bson.M{}),
+		"$inc": bump,
 	}
 
 	result, err := m.collection.UpdateOne(ctx, filter, update)
@@ -176,40 +287,157 @@ func (m mongoRepository) RemoveItem(ctx context.Context, userID string, productI
 	}
 
 	if result.MatchedCount == 0 {
-		return ErrCartNotFound
+		return m.missed(ctx, userID, version, ErrCartNotFound)
 	}
 
 	return nil
 }
 
-func (m mongoRepository) DeleteCart(ctx context.Context, userID string) error {
-	filter := bson.M{"user_id": userID}
+// DeleteCart empties the cart: the lines and the promo code go, saved items and wishlists stay.
+// The document is kept with its version bumped, so versions never repeat and a stale If-Match
+// from before the clear can't match a cart built again afterwards.
+func (m mongoRepository) DeleteCart(ctx context.Context, userID string, version *int64) error {
+	filter := versionFilter(bson.M{"user_id": userID}, version)
+	update := bson.M{
+		"$set":   m.touch(userID, time.Now(), bson.M{"items": []domain.CartItem{}}),
+		"$unset": bson.M{"promo_code": ""},
+		"$inc":   bump,
+	}
+	result, err := m.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to empty cart: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return m.missed(ctx, userID, version, ErrCartNotFound)
+	}
+
+	return nil
+}
+
+// SetPromoCode stores the promo code applied to the cart, an empty code removes it
+func (m mongoRepository) SetPromoCode(ctx context.Context, userID string, code string, version *int64) error {
+	filter := versionFilter(bson.M{"user_id": userID}, version)
+	update := bson.M{"$set": m.touch(userID, time.Now(), bson.M{"promo_code": code}), "$inc": bump}
+	if code == "" {
+		update = bson.M{
+			"$set":   m.touch(userID, time.Now(), bson.M{}),
+			"$unset": bson.M{"promo_code": ""},
+			"$inc":   bump,
+		}
+	}
+
+	result, err := m.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to set promo code: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return m.missed(ctx, userID, version, ErrCartNotFound)
+	}
+
+	return nil
+}
+
+// abandonable selects the carts of signed-in users that have lines and no abandoned mark for their last change
+func abandonable(filter bson.M) bson.M {
+	filter["items.0"] = bson.M{"$exists": true}
+	// a missing abandoned_at sorts before any date, so unmarked carts pass
+	filter["$expr"] = bson.M{"$lt": bson.A{"$abandoned_at", "$updated_at"}}
+	return filter
+}
+
+func (m mongoRepository) FindAbandoned(ctx context.Context, idleSince, notBefore time.Time, limit int) ([]domain.Cart, error) {
+	filter := abandonable(bson.M{
+		"updated_at": bson.M{"$lt": idleSince, "$gte": notBefore},
+		"user_id":    bson.M{"$not": bson.M{"$regex": "^" + regexp.QuoteMeta(domain.GuestCartKey(""))}},
+	})
+	opts := options.Find().SetSort(bson.D{{Key: "updated_at", Value: 1}}).SetLimit(int64(limit))
 
-	result, err := m.collection.DeleteOne(ctx, filter)
+	cursor, err := m.collection.Find(ctx, filter, opts)
 	if err != nil {
-		return fmt.Errorf("failed to delete cart: %w", err)
+		return nil, fmt.Errorf("failed to find abandoned carts: %w", err)
+	}
+	var carts []domain.Cart
+	if err := cursor.All(ctx, &carts); err != nil {
+		return nil, fmt.Errorf("failed to read abandoned carts: %w", err)
 	}
+	return carts, nil
+}
 
-	if result.DeletedCount == 0 {
-		return ErrCartNotFound
+func (m mongoRepository) MarkAbandoned(ctx context.Context, cart domain.Cart, at time.Time) (bool, error) {
+	filter := abandonable(bson.M{"user_id": cart.UserID, "updated_at": cart.UpdatedAt})
+	result, err := m.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"abandoned_at": at}})
+	if err != nil {
+		return false, fmt.Errorf("failed to mark cart abandoned: %w", err)
 	}
+	return result.ModifiedCount > 0, nil
+}
 
+func (m mongoRepository) UnmarkAbandoned(ctx context.Context, userID string, at time.Time) error {
+	filter := bson.M{"user_id": userID, "abandoned_at": at}
+	_, err := m.collection.UpdateOne(ctx, filter, bson.M{"$unset": bson.M{"abandoned_at": ""}})
+	if err != nil {
+		return fmt.Errorf("failed to unmark abandoned cart: %w", err)
+	}
 	return nil
 }
 
+// itemMatch selects the cart item of a product variant, prefix names the array element ("elem." in array filters).
+// Items written before variants existed have no variant_id field, they match variant 0.
+func itemMatch(prefix string, productID, variantID int64) bson.M {
+	match := bson.M{prefix + "product_id": productID}
+	if variantID == 0 {
+		match[prefix+"variant_id"] = bson.M{"$in": bson.A{0, nil}}
+	} else {
+		match[prefix+"variant_id"] = variantID
+	}
+	return match
+}
+
 func (m *mongoRepository) CreateIndexes(ctx context.Context) error {
+	return CreateIndexes(ctx, m.collection.Database())
+}
+
+// CreateIndexes creates the indexes of the carts collection, guest carts are removed by the TTL index on expires_at.
+// Carts without saved lists are removed inactivityTTL after their last change, carts holding lists are kept.
+func CreateIndexes(ctx context.Context, db *mongo.Database) error {
+	carts := db.Collection("carts")
+
+	// carts written before has_lists existed get it from their lists
+	_, err := carts.UpdateMany(ctx, bson.M{"has_lists": bson.M{"$exists": false}}, mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"has_lists": bson.M{"$gt": bson.A{bson.M{"$size": bson.M{"$ifNull": bson.A{"$lists", bson.A{}}}}, 0}}}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to backfill has_lists: %w", err)
+	}
+
+	// the inactivity TTL used to cover every cart, saved lists went with it
+	if err := dropIndex(ctx, carts, "updated_at_1"); err != nil {
+		return err
+	}
+
 	indexes := []mongo.IndexModel{
 		{
 			Keys:    bson.D{{Key: "user_id", Value: 1}},
 			Options: options.Index().SetUnique(true),
 		},
 		{
-			Keys:    bson.D{{Key: "updated_at", Value: 1}},
-			Options: options.Index().SetExpireAfterSeconds(90 * 24 * 60 * 60), // 90 days TTL
+			Keys: bson.D{{Key: "updated_at", Value: 1}},
+			Options: options.Index().
+				SetName("inactive_carts_ttl").
+				SetExpireAfterSeconds(int32(inactivityTTL.Seconds())).
+				SetPartialFilterExpression(bson.M{"has_lists": false}),
+		},
+		{
+			Keys: bson.D{{Key: "updated_at", Value: 1}, {Key: "user_id", Value: 1}}, // the abandoned cart scan
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0), // carts without expires_at never expire
 		},
 	}
 
-	_, err := m.collection.Indexes().CreateMany(ctx, indexes)
+	_, err = carts.Indexes().CreateMany(ctx, indexes)
 	if err != nil {
 		return fmt.Errorf("failed to create indexes: %w", err)
 	}
@@ -217,8 +445,28 @@ func (m *mongoRepository) CreateIndexes(ctx context.Context) error {
 	return nil
 }
 
-func NewMongoRepository(db *mongo.Database) CartRepository {
+// dropIndex drops the named index, an index that is already gone is fine
+func dropIndex(ctx context.Context, coll *mongo.Collection, name string) error {
+	_, err := coll.Indexes().DropOne(ctx, name)
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && (cmdErr.Code == 26 || cmdErr.Code == 27) { // NamespaceNotFound, IndexNotFound
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to drop index %s: %w", name, err)
+	}
+	return nil
+}
+
+// NewAbandonedCartRepository looks for abandoned carts in the carts collection
+func NewAbandonedCartRepository(db *mongo.Database) AbandonedCartRepository {
+	return &mongoRepository{collection: db.Collection("carts")}
+}
+
+// NewMongoRepository stores carts in the carts collection, guest carts expire guestTTL after their last change
+func NewMongoRepository(db *mongo.Database, guestTTL time.Duration) CartRepository {
 	return &mongoRepository{
 		collection: db.Collection("carts"),
+		guestTTL:   guestTTL,
 	}
 }
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 func setupTestDB(t *testing.T) (CartRepository, func()) {
@@ -27,7 +29,7 @@ func setupTestDB(t *testing.T) (CartRepository, func()) {
 	require.NoError(t, err)
 
 	// Create repository
-	repo := NewMongoRepository(db)
+	repo := NewMongoRepository(db, DefaultGuestCartTTL)
 
 	// Create indexes
 	mongoRepo := repo.(*mongoRepository)
@@ -63,7 +65,7 @@ func TestAddItem_NewCart(t *testing.T) {
 		ProductID: 1,
 		Quantity:  3,
 	}
-	err := repo.AddItem(ctx, userID, item)
+	err := repo.AddItem(ctx, userID, item, nil)
 	require.NoError(t, err)
 
 	cart, err := repo.GetCart(ctx, userID)
@@ -83,12 +85,12 @@ func TestAddItem_ExistingItem_UpdatesQuantity(t *testing.T) {
 
 	// Add item first time
 	item1 := domain.CartItem{ProductID: 1, Quantity: 2}
-	err := repo.AddItem(ctx, userID, item1)
+	err := repo.AddItem(ctx, userID, item1, nil)
 	require.NoError(t, err)
 
 	// Add same item again with different quantity
 	item2 := domain.CartItem{ProductID: 1, Quantity: 5}
-	err = repo.AddItem(ctx, userID, item2)
+	err = repo.AddItem(ctx, userID, item2, nil)
 	require.NoError(t, err)
 
 	// Verify quantity was updated, not added
@@ -107,11 +109,11 @@ func TestUpdateItemQuantity(t *testing.T) {
 
 	// Add item
 	item := domain.CartItem{ProductID: 1, Quantity: 2}
-	err := repo.AddItem(ctx, userID, item)
+	err := repo.AddItem(ctx, userID, item, nil)
 	require.NoError(t, err)
 
 	// Update quantity
-	err = repo.UpdateItemQuantity(ctx, userID, 1, 10)
+	err = repo.UpdateItemQuantity(ctx, userID, 1, 0, 10, nil)
 	require.NoError(t, err)
 
 	// Verify
@@ -128,13 +130,13 @@ func TestRemoveItem(t *testing.T) {
 	userID := "user123"
 
 	// Add two items
-	err := repo.AddItem(ctx, userID, domain.CartItem{ProductID: 1, Quantity: 2})
+	err := repo.AddItem(ctx, userID, domain.CartItem{ProductID: 1, Quantity: 2}, nil)
 	require.NoError(t, err)
-	err = repo.AddItem(ctx, userID, domain.CartItem{ProductID: 2, Quantity: 3})
+	err = repo.AddItem(ctx, userID, domain.CartItem{ProductID: 2, Quantity: 3}, nil)
 	require.NoError(t, err)
 
 	// Remove one item
-	err = repo.RemoveItem(ctx, userID, 1)
+	err = repo.RemoveItem(ctx, userID, 1, 0, nil)
 	require.NoError(t, err)
 
 	// Verify only one item remains
@@ -144,6 +146,30 @@ func TestRemoveItem(t *testing.T) {
 	assert.Equal(t, int64(2), cart.Items[0].ProductID)
 }
 
+func TestVariantsAreSeparateItems(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	userID := "user123"
+
+	require.NoError(t, repo.AddItem(ctx, userID, domain.CartItem{ProductID: 1, VariantID: 1, SKU: "LAPTOP-16GB-512GB", Quantity: 1}, nil))
+	require.NoError(t, repo.AddItem(ctx, userID, domain.CartItem{ProductID: 1, VariantID: 2, SKU: "LAPTOP-32GB-1TB", Quantity: 1}, nil))
+	require.NoError(t, repo.AddItem(ctx, userID, domain.CartItem{ProductID: 2, Quantity: 1}, nil))
+
+	require.NoError(t, repo.UpdateItemQuantity(ctx, userID, 1, 2, 4, nil))
+	require.NoError(t, repo.UpdateItemQuantity(ctx, userID, 2, 0, 3, nil))
+	require.NoError(t, repo.RemoveItem(ctx, userID, 1, 1, nil))
+	assert.ErrorIs(t, repo.UpdateItemQuantity(ctx, userID, 1, 1, 5, nil), ErrItemNotFound)
+
+	cart, err := repo.GetCart(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, cart.Items, 2)
+	assert.Equal(t, "LAPTOP-32GB-1TB", cart.Items[0].SKU)
+	assert.Equal(t, 4, cart.Items[0].Quantity)
+	assert.Equal(t, 3, cart.Items[1].Quantity)
+}
+
 func TestDeleteCart(t *testing.T) {
 	repo, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -152,18 +178,280 @@ func TestDeleteCart(t *testing.T) {
 	userID := "user123"
 
 	// Add item to create cart
-	err := repo.AddItem(ctx, userID, domain.CartItem{ProductID: 1, Quantity: 2})
+	err := repo.AddItem(ctx, userID, domain.CartItem{ProductID: 1, Quantity: 2}, nil)
 	require.NoError(t, err)
 
 	// Delete cart
-	err = repo.DeleteCart(ctx, userID)
+	err = repo.DeleteCart(ctx, userID, nil)
+	require.NoError(t, err)
+
+	// Verify cart is empty and kept its version history
+	cart, err := repo.GetCart(ctx, userID)
 	require.NoError(t, err)
+	assert.Empty(t, cart.Items)
+	assert.Equal(t, int64(2), cart.Version)
+}
 
-	// Verify cart is gone
-	_, err = repo.GetCart(ctx, userID)
+func TestDeleteCart_StaleVersionDoesNotMatchRebuiltCart(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	userID := "user123"
+
+	require.NoError(t, repo.AddItem(ctx, userID, domain.CartItem{ProductID: 1, Quantity: 1}, nil))
+	require.NoError(t, repo.AddItem(ctx, userID, domain.CartItem{ProductID: 2, Quantity: 1}, nil))
+	stale := int64(2) // the ETag a client holds from before the clear
+
+	require.NoError(t, repo.DeleteCart(ctx, userID, nil))
+	require.NoError(t, repo.AddItem(ctx, userID, domain.CartItem{ProductID: 3, Quantity: 1}, nil))
+	require.NoError(t, repo.AddItem(ctx, userID, domain.CartItem{ProductID: 4, Quantity: 1}, nil))
+
+	assert.ErrorIs(t, repo.RemoveItem(ctx, userID, 3, 0, &stale), ErrVersionConflict)
+	assert.ErrorIs(t, repo.UpsertCart(ctx, &domain.Cart{UserID: userID}, &stale), ErrVersionConflict)
+
+	cart, err := repo.GetCart(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), cart.Version)
+	assert.Len(t, cart.Items, 2)
+}
+
+func TestUpsertCart_VersionedWriteDoesNotRecreateMissingCart(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	version := int64(3)
+
+	err := repo.UpsertCart(ctx, &domain.Cart{UserID: "user123", Items: []domain.CartItem{{ProductID: 1, Quantity: 1}}}, &version)
+	assert.ErrorIs(t, err, ErrVersionConflict)
+
+	_, err = repo.GetCart(ctx, "user123")
 	assert.ErrorIs(t, err, ErrCartNotFound)
 }
 
+func TestInactivityTTL_KeepsCartsWithSavedLists(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	carts := repo.(*mongoRepository).collection
+
+	hasLists := func(userID string) bool {
+		var doc cartDocument
+		require.NoError(t, carts.FindOne(ctx, bson.M{"user_id": userID}).Decode(&doc))
+		return doc.HasLists
+	}
+
+	require.NoError(t, repo.AddItem(ctx, "plain", domain.CartItem{ProductID: 1, Quantity: 1}, nil))
+	assert.False(t, hasLists("plain"))
+
+	saved := &domain.Cart{UserID: "saver", Lists: []domain.SavedList{{Items: []domain.CartItem{{ProductID: 2, Quantity: 1}}}}}
+	require.NoError(t, repo.UpsertCart(ctx, saved, nil))
+	assert.True(t, hasLists("saver"))
+	require.NoError(t, repo.DeleteCart(ctx, "saver", nil))
+	assert.True(t, hasLists("saver"), "emptying the cart keeps the lists out of the TTL")
+
+	saved.Lists = nil
+	require.NoError(t, repo.UpsertCart(ctx, saved, nil))
+	assert.False(t, hasLists("saver"))
+
+	// a cart stored before has_lists existed gets it when the indexes are created
+	_, err := carts.InsertOne(ctx, bson.M{"user_id": "legacy", "items": bson.A{}, "lists": bson.A{bson.M{"name": "birthday"}}, "updated_at": time.Now()})
+	require.NoError(t, err)
+	require.NoError(t, repo.(*mongoRepository).CreateIndexes(ctx))
+	assert.True(t, hasLists("legacy"))
+
+	cursor, err := carts.Indexes().List(ctx)
+	require.NoError(t, err)
+	var indexes []struct {
+		Name    string `bson:"name"`
+		Partial struct {
+			HasLists *bool `bson:"has_lists"`
+		} `bson:"partialFilterExpression"`
+	}
+	require.NoError(t, cursor.All(ctx, &indexes))
+	found := false
+	for _, index := range indexes {
+		assert.NotEqual(t, "updated_at_1", index.Name, "the TTL over every cart is gone")
+		if index.Name == "inactive_carts_ttl" {
+			found = true
+			require.NotNil(t, index.Partial.HasLists)
+			assert.False(t, *index.Partial.HasLists)
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestDeleteCart_KeepsSavedLists(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	userID := "user123"
+
+	cart := &domain.Cart{
+		UserID:    userID,
+		Items:     []domain.CartItem{{ProductID: 1, Quantity: 2}},
+		PromoCode: "TEN",
+		Lists:     []domain.SavedList{{Name: "birthday", Items: []domain.CartItem{{ProductID: 2, Quantity: 1}}}},
+	}
+	require.NoError(t, repo.UpsertCart(ctx, cart, nil))
+
+	require.NoError(t, repo.DeleteCart(ctx, userID, &cart.Version))
+
+	kept, err := repo.GetCart(ctx, userID)
+	require.NoError(t, err)
+	assert.Empty(t, kept.Items)
+	assert.Empty(t, kept.PromoCode)
+	require.Len(t, kept.Lists, 1)
+	assert.Equal(t, "birthday", kept.Lists[0].Name)
+	assert.Len(t, kept.Lists[0].Items, 1)
+	assert.Equal(t, cart.Version+1, kept.Version)
+}
+
+func TestGuestCartExpiry(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	guestKey := domain.GuestCartKey("3f2a9c")
+
+	err := repo.AddItem(ctx, guestKey, domain.CartItem{ProductID: 1, Quantity: 2}, nil)
+	require.NoError(t, err)
+	err = repo.AddItem(ctx, "user123", domain.CartItem{ProductID: 1, Quantity: 2}, nil)
+	require.NoError(t, err)
+
+	guest, err := repo.GetCart(ctx, guestKey)
+	require.NoError(t, err)
+	require.NotNil(t, guest.ExpiresAt)
+	assert.WithinDuration(t, guest.UpdatedAt.Add(DefaultGuestCartTTL), *guest.ExpiresAt, time.Second)
+	first := *guest.ExpiresAt
+
+	// every change pushes the expiry back
+	time.Sleep(10 * time.Millisecond)
+	err = repo.UpdateItemQuantity(ctx, guestKey, 1, 0, 5, nil)
+	require.NoError(t, err)
+	guest, err = repo.GetCart(ctx, guestKey)
+	require.NoError(t, err)
+	assert.True(t, guest.ExpiresAt.After(first))
+
+	user, err := repo.GetCart(ctx, "user123")
+	require.NoError(t, err)
+	assert.Nil(t, user.ExpiresAt)
+}
+
+func TestSetPromoCode(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	userID := "user123"
+
+	assert.ErrorIs(t, repo.SetPromoCode(ctx, userID, "TEN", nil), ErrCartNotFound)
+
+	require.NoError(t, repo.AddItem(ctx, userID, domain.CartItem{ProductID: 1, Quantity: 2}, nil))
+	require.NoError(t, repo.SetPromoCode(ctx, userID, "TEN", nil))
+	cart, err := repo.GetCart(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, "TEN", cart.PromoCode)
+
+	require.NoError(t, repo.SetPromoCode(ctx, userID, "", nil))
+	cart, err = repo.GetCart(ctx, userID)
+	require.NoError(t, err)
+	assert.Empty(t, cart.PromoCode)
+	assert.Len(t, cart.Items, 1)
+}
+
+func TestVersion_EveryChangeBumpsIt(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	userID := "user123"
+	version := func() int64 {
+		cart, err := repo.GetCart(ctx, userID)
+		require.NoError(t, err)
+		return cart.Version
+	}
+
+	require.NoError(t, repo.AddItem(ctx, userID, domain.CartItem{ProductID: 1, Quantity: 2}, nil))
+	assert.Equal(t, int64(1), version())
+	require.NoError(t, repo.AddItem(ctx, userID, domain.CartItem{ProductID: 1, Quantity: 3}, nil))
+	require.NoError(t, repo.AddItem(ctx, userID, domain.CartItem{ProductID: 2, Quantity: 1}, nil))
+	require.NoError(t, repo.UpdateItemQuantity(ctx, userID, 2, 0, 4, nil))
+	require.NoError(t, repo.SetPromoCode(ctx, userID, "TEN", nil))
+	require.NoError(t, repo.RemoveItem(ctx, userID, 2, 0, nil))
+	assert.Equal(t, int64(6), version())
+
+	cart := &domain.Cart{UserID: userID, Items: []domain.CartItem{{ProductID: 3, Quantity: 1}}}
+	require.NoError(t, repo.UpsertCart(ctx, cart, nil))
+	assert.Equal(t, int64(7), cart.Version)
+}
+
+func TestVersion_StalePreconditionChangesNothing(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	userID := "user123"
+	stale, current := int64(1), int64(2)
+
+	require.NoError(t, repo.AddItem(ctx, userID, domain.CartItem{ProductID: 1, Quantity: 2}, nil))
+	require.NoError(t, repo.AddItem(ctx, userID, domain.CartItem{ProductID: 2, Quantity: 1}, &stale))
+
+	assert.ErrorIs(t, repo.AddItem(ctx, userID, domain.CartItem{ProductID: 3, Quantity: 1}, &stale), ErrVersionConflict)
+	assert.ErrorIs(t, repo.UpdateItemQuantity(ctx, userID, 1, 0, 5, &stale), ErrVersionConflict)
+	assert.ErrorIs(t, repo.RemoveItem(ctx, userID, 1, 0, &stale), ErrVersionConflict)
+	assert.ErrorIs(t, repo.SetPromoCode(ctx, userID, "TEN", &stale), ErrVersionConflict)
+	assert.ErrorIs(t, repo.DeleteCart(ctx, userID, &stale), ErrVersionConflict)
+	assert.ErrorIs(t, repo.UpsertCart(ctx, &domain.Cart{UserID: userID}, &stale), ErrVersionConflict)
+
+	// a precondition that holds still tells a missing item apart from a stale version
+	assert.ErrorIs(t, repo.UpdateItemQuantity(ctx, userID, 9, 0, 5, &current), ErrItemNotFound)
+
+	cart, err := repo.GetCart(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, current, cart.Version)
+	assert.Len(t, cart.Items, 2)
+	assert.Equal(t, 2, cart.Items[0].Quantity)
+}
+
+func TestVersion_MissingCartIsVersionZero(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	zero, one := int64(0), int64(1)
+
+	assert.ErrorIs(t, repo.AddItem(ctx, "user123", domain.CartItem{ProductID: 1, Quantity: 1}, &one), ErrVersionConflict)
+	require.NoError(t, repo.AddItem(ctx, "user123", domain.CartItem{ProductID: 1, Quantity: 1}, &zero))
+	assert.ErrorIs(t, repo.AddItem(ctx, "user123", domain.CartItem{ProductID: 2, Quantity: 1}, &zero), ErrVersionConflict)
+	assert.ErrorIs(t, repo.DeleteCart(ctx, "user456", &zero), ErrCartNotFound)
+}
+
+func TestAddItem_ConcurrentAddsKeepEveryLine(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	userID := "user123"
+	var wg sync.WaitGroup
+	for id := int64(1); id <= 10; id++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, repo.AddItem(ctx, userID, domain.CartItem{ProductID: id, Quantity: 1}, nil))
+		}()
+	}
+	wg.Wait()
+
+	cart, err := repo.GetCart(ctx, userID)
+	require.NoError(t, err)
+	assert.Len(t, cart.Items, 10)
+	assert.Equal(t, int64(10), cart.Version)
+}
+
 func TestContextCancellation(t *testing.T) {
 	repo, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -177,3 +465,59 @@ func TestContextCancellation(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "context")
 }
+
+func TestAbandonedCarts(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+	abandoned := repo.(AbandonedCartRepository)
+
+	ctx := context.Background()
+	require.NoError(t, repo.AddItem(ctx, "user123", domain.CartItem{ProductID: 1, Quantity: 2}, nil))
+	require.NoError(t, repo.AddItem(ctx, domain.GuestCartKey("3f2a9c"), domain.CartItem{ProductID: 1, Quantity: 2}, nil))
+	require.NoError(t, repo.UpsertCart(ctx, &domain.Cart{
+		UserID: "user456",
+		Lists:  []domain.SavedList{{Items: []domain.CartItem{{ProductID: 1, Quantity: 1}}}},
+	}, nil))
+
+	// everything written so far counts as idle
+	idleSince, notBefore := time.Now().Add(time.Minute), time.Now().Add(-time.Hour)
+	carts, err := abandoned.FindAbandoned(ctx, idleSince, notBefore, 10)
+	require.NoError(t, err)
+	require.Len(t, carts, 1, "guest carts and carts without lines are not reported")
+	assert.Equal(t, "user123", carts[0].UserID)
+
+	at := time.Now()
+	marked, err := abandoned.MarkAbandoned(ctx, carts[0], at)
+	require.NoError(t, err)
+	assert.True(t, marked)
+	marked, err = abandoned.MarkAbandoned(ctx, carts[0], at)
+	require.NoError(t, err)
+	assert.False(t, marked, "a cart is marked once")
+
+	carts2, err := abandoned.FindAbandoned(ctx, idleSince, notBefore, 10)
+	require.NoError(t, err)
+	assert.Empty(t, carts2)
+
+	stored, err := repo.GetCart(ctx, "user123")
+	require.NoError(t, err)
+	assert.Equal(t, carts[0].Version, stored.Version, "marking is no change of the cart")
+
+	// a cart taken back and changed is reported again when it sits untouched once more
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, repo.UpdateItemQuantity(ctx, "user123", 1, 0, 3, nil))
+	marked, err = abandoned.MarkAbandoned(ctx, carts[0], time.Now())
+	require.NoError(t, err)
+	assert.False(t, marked, "the cart changed since it was found")
+	carts, err = abandoned.FindAbandoned(ctx, idleSince, notBefore, 10)
+	require.NoError(t, err)
+	require.Len(t, carts, 1)
+
+	at = time.Now()
+	marked, err = abandoned.MarkAbandoned(ctx, carts[0], at)
+	require.NoError(t, err)
+	require.True(t, marked)
+	require.NoError(t, abandoned.UnmarkAbandoned(ctx, "user123", at))
+	carts, err = abandoned.FindAbandoned(ctx, idleSince, notBefore, 10)
+	require.NoError(t, err)
+	assert.Len(t, carts, 1)
+}
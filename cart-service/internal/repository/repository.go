@@ -2,17 +2,36 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/fjod/go_cart/cart-service/internal/domain"
 )
 
 // CartRepository defines the interface for cart data operations
-// Consumers define this interface, not the MongoDB implementation
+// Consumers define this interface, not the MongoDB implementation.
+// Every change bumps the cart version. A non-nil version is a precondition: when the cart is at another
+// version the change fails with ErrVersionConflict and nothing is written.
 type CartRepository interface {
 	GetCart(ctx context.Context, userID string) (*domain.Cart, error)
-	UpsertCart(ctx context.Context, cart *domain.Cart) error
-	AddItem(ctx context.Context, userID string, item domain.CartItem) error
-	UpdateItemQuantity(ctx context.Context, userID string, productID int64, quantity int) error
-	RemoveItem(ctx context.Context, userID string, productID int64) error
-	DeleteCart(ctx context.Context, userID string) error
+	UpsertCart(ctx context.Context, cart *domain.Cart, version *int64) error
+	AddItem(ctx context.Context, userID string, item domain.CartItem, version *int64) error
+	UpdateItemQuantity(ctx context.Context, userID string, productID, variantID int64, quantity int, version *int64) error
+	RemoveItem(ctx context.Context, userID string, productID, variantID int64, version *int64) error
+	DeleteCart(ctx context.Context, userID string, version *int64) error
+	SetPromoCode(ctx context.Context, userID string, code string, version *int64) error
+}
+
+// AbandonedCartRepository finds the carts that sit untouched and marks the ones reported as abandoned.
+// Marking a cart is no change of the cart: its version and updated_at stay as they are.
+// Only carts with lines count, so completing a checkout suppresses a pending abandonment by emptying
+// the cart and needs no mark of its own; lines added afterwards are a new change that may be reported.
+type AbandonedCartRepository interface {
+	// FindAbandoned lists the signed-in users' carts with lines that were last changed between notBefore
+	// and idleSince and are not marked for that change yet, least recently changed first
+	FindAbandoned(ctx context.Context, idleSince, notBefore time.Time, limit int) ([]domain.Cart, error)
+	// MarkAbandoned marks the cart as reported at, it reports false when the cart changed or was emptied
+	// since it was found, or another scanner marked it first
+	MarkAbandoned(ctx context.Context, cart domain.Cart, at time.Time) (bool, error)
+	// UnmarkAbandoned takes back the mark set at, for an event that could not be sent
+	UnmarkAbandoned(ctx context.Context, userID string, at time.Time) error
 }
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"time"
 
@@ -73,8 +74,9 @@ func (s *CartService) GetCart(ctx context.Context, userID string) (*domain.Cart,
 	return v.(*domain.Cart), nil
 }
 
-func (s *CartService) AddItem(ctx context.Context, userID string, item domain.CartItem) error {
-	errAdd := s.repo.AddItem(ctx, userID, item)
+// AddItem and the other changes take the cart version the caller expects, nil skips the check
+func (s *CartService) AddItem(ctx context.Context, userID string, item domain.CartItem, version *int64) error {
+	errAdd := s.repo.AddItem(ctx, userID, item, version)
 	if errAdd != nil {
 		l := logger.WithContext(s.logger, ctx)
 		l.Error("repo add item error", "error", errAdd)
@@ -85,8 +87,8 @@ func (s *CartService) AddItem(ctx context.Context, userID string, item domain.Ca
 	return nil
 }
 
-func (s *CartService) UpdateQuantity(ctx context.Context, userID string, productID int64, quantity int) error {
-	errUpdate := s.repo.UpdateItemQuantity(ctx, userID, productID, quantity)
+func (s *CartService) UpdateQuantity(ctx context.Context, userID string, productID, variantID int64, quantity int, version *int64) error {
+	errUpdate := s.repo.UpdateItemQuantity(ctx, userID, productID, variantID, quantity, version)
 	if errUpdate != nil {
 		l := logger.WithContext(s.logger, ctx)
 		l.Error("repo update item quantity error", "error", errUpdate)
@@ -97,8 +99,8 @@ func (s *CartService) UpdateQuantity(ctx context.Context, userID string, product
 	return nil
 }
 
-func (s *CartService) RemoveItem(ctx context.Context, userID string, productID int64) error {
-	errRemove := s.repo.RemoveItem(ctx, userID, productID)
+func (s *CartService) RemoveItem(ctx context.Context, userID string, productID, variantID int64, version *int64) error {
+	errRemove := s.repo.RemoveItem(ctx, userID, productID, variantID, version)
 	if errRemove != nil {
 		l := logger.WithContext(s.logger, ctx)
 		l.Error("repo remove item error", "error", errRemove)
@@ -109,8 +111,8 @@ func (s *CartService) RemoveItem(ctx context.Context, userID string, productID i
 	return nil
 }
 
-func (s *CartService) ClearCart(ctx context.Context, userID string) error {
-	errDelete := s.repo.DeleteCart(ctx, userID)
+func (s *CartService) ClearCart(ctx context.Context, userID string, version *int64) error {
+	errDelete := s.repo.DeleteCart(ctx, userID, version)
 	if errDelete != nil {
 		l := logger.WithContext(s.logger, ctx)
 		l.Error("repo delete cart error", "error", errDelete)
@@ -121,6 +123,215 @@ func (s *CartService) ClearCart(ctx context.Context, userID string) error {
 	return nil
 }
 
+// ApplyPromoCode stores the promo code on the cart, the caller has checked that the code applies to it
+func (s *CartService) ApplyPromoCode(ctx context.Context, userID string, code string, version *int64) error {
+	errSet := s.repo.SetPromoCode(ctx, userID, code, version)
+	if errSet != nil {
+		l := logger.WithContext(s.logger, ctx)
+		l.Error("repo set promo code error", "error", errSet)
+		return errSet
+	}
+
+	invalidateCache(s, userID)
+	return nil
+}
+
+func (s *CartService) RemovePromoCode(ctx context.Context, userID string, version *int64) error {
+	errSet := s.repo.SetPromoCode(ctx, userID, "", version)
+	if errSet != nil {
+		l := logger.WithContext(s.logger, ctx)
+		l.Error("repo remove promo code error", "error", errSet)
+		return errSet
+	}
+
+	invalidateCache(s, userID)
+	return nil
+}
+
+// maxRewriteAttempts bounds how often a whole-cart change starts over when the cart changes under it
+const maxRewriteAttempts = 3
+
+// AddItems adds all the items in one write, a line the cart already holds takes the item's quantity
+func (s *CartService) AddItems(ctx context.Context, userID string, items []domain.CartItem, version *int64) (*domain.Cart, error) {
+	return s.rewrite(ctx, userID, version, func(cart *domain.Cart) error {
+		cart.Put(items)
+		return nil
+	})
+}
+
+// ReplaceCart swaps the lines of the cart for the items in one write. The promo code stays,
+// it is checked against the new lines whenever the cart is priced.
+func (s *CartService) ReplaceCart(ctx context.Context, userID string, items []domain.CartItem, version *int64) (*domain.Cart, error) {
+	return s.rewrite(ctx, userID, version, func(cart *domain.Cart) error {
+		cart.Items = items
+		return nil
+	})
+}
+
+// rewrite reads the cart, applies change and writes the whole cart back only if it is still at the
+// version it was read at. Without a version from the caller a concurrent change makes it start over.
+// An error from change is returned as is and nothing is written.
+func (s *CartService) rewrite(ctx context.Context, userID string, version *int64, change func(*domain.Cart) error) (*domain.Cart, error) {
+	l := logger.WithContext(s.logger, ctx)
+	for attempt := 0; attempt < maxRewriteAttempts; attempt++ {
+		cart, err := s.repo.GetCart(ctx, userID)
+		if errors.Is(err, repository.ErrCartNotFound) {
+			cart, err = &domain.Cart{UserID: userID}, nil
+		}
+		if err != nil {
+			l.Error("repo get cart error", "error", err)
+			return nil, err
+		}
+		if version != nil && cart.Version != *version {
+			return nil, repository.ErrVersionConflict
+		}
+
+		read := cart.Version
+		if err := change(cart); err != nil {
+			return nil, err
+		}
+		err = s.repo.UpsertCart(ctx, cart, &read)
+		if errors.Is(err, repository.ErrVersionConflict) && version == nil {
+			continue
+		}
+		if err != nil {
+			l.Error("repo upsert cart error", "error", err)
+			return nil, err
+		}
+		invalidateCache(s, userID)
+		return cart, nil
+	}
+	return nil, fmt.Errorf("cart changed %d times while it was written", maxRewriteAttempts)
+}
+
+// MaxSavedLists bounds the saved-for-later list and wishlists one user can have
+const MaxSavedLists = 20
+
+// ErrTooManyLists is returned when saving an item would create a list beyond MaxSavedLists
+var ErrTooManyLists = errors.New("too many saved lists")
+
+// SaveForLater moves a cart line to the saved-for-later list (list "") or a wishlist, creating the
+// wishlist when needed. Cart and lists are written together, so the item is never in both or neither.
+func (s *CartService) SaveForLater(ctx context.Context, userID string, productID, variantID int64, list string, version *int64) (*domain.Cart, error) {
+	return s.rewrite(ctx, userID, version, func(cart *domain.Cart) error {
+		if cart.List(list) == nil && len(cart.Lists) >= MaxSavedLists {
+			return ErrTooManyLists
+		}
+		if !cart.SaveForLater(productID, variantID, list) {
+			return repository.ErrItemNotFound
+		}
+		return nil
+	})
+}
+
+// MoveToCart moves an item of a saved list back into the cart
+func (s *CartService) MoveToCart(ctx context.Context, userID string, productID, variantID int64, list string, version *int64) (*domain.Cart, error) {
+	return s.rewrite(ctx, userID, version, func(cart *domain.Cart) error {
+		if !cart.MoveToCart(productID, variantID, list) {
+			return repository.ErrItemNotFound
+		}
+		return nil
+	})
+}
+
+// RemoveSaved drops an item from a saved list
+func (s *CartService) RemoveSaved(ctx context.Context, userID string, productID, variantID int64, list string, version *int64) (*domain.Cart, error) {
+	return s.rewrite(ctx, userID, version, func(cart *domain.Cart) error {
+		if !cart.RemoveSaved(productID, variantID, list) {
+			return repository.ErrItemNotFound
+		}
+		return nil
+	})
+}
+
+// DeleteList drops a saved list with its items
+func (s *CartService) DeleteList(ctx context.Context, userID string, list string, version *int64) (*domain.Cart, error) {
+	return s.rewrite(ctx, userID, version, func(cart *domain.Cart) error {
+		if !cart.DeleteList(list) {
+			return repository.ErrListNotFound
+		}
+		return nil
+	})
+}
+
+// MergeCarts moves the items of a guest cart into the user's cart and empties the guest cart.
+// The guest items are claimed first by emptying the guest cart at the version they were read at, so each
+// of them is merged once: a retried or concurrent merge finds the guest cart empty and leaves the user's
+// cart as it is. version is the user's cart version the caller expects; without one a concurrent change
+// of the user's cart makes the merge start over like rewrite does.
+func (s *CartService) MergeCarts(ctx context.Context, guestKey, userID string, version *int64) (*domain.Cart, error) {
+	l := logger.WithContext(s.logger, ctx)
+	if version != nil {
+		// a stale version fails before the guest items are claimed
+		cart, err := s.GetCart(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if cart.Version != *version {
+			return nil, repository.ErrVersionConflict
+		}
+	}
+
+	guest, err := s.claimGuestCart(ctx, guestKey)
+	if err != nil {
+		l.Error("repo claim guest cart error", "error", err)
+		return nil, err
+	}
+	if guest == nil {
+		cart, err := s.GetCart(ctx, userID)
+		if err == nil && version != nil && cart.Version != *version {
+			return nil, repository.ErrVersionConflict
+		}
+		return cart, err
+	}
+
+	cart, err := s.rewrite(ctx, userID, version, func(cart *domain.Cart) error {
+		cart.Merge(guest.Items)
+		if cart.PromoCode == "" {
+			cart.PromoCode = guest.PromoCode
+		}
+		return nil
+	})
+	if err != nil {
+		// the user's cart didn't take the items, give them back to the guest cart
+		claimed := guest.Version + 1
+		if restoreErr := s.repo.UpsertCart(ctx, guest, &claimed); restoreErr != nil {
+			l.Error("repo restore guest cart error", "guest_key", guestKey, "error", restoreErr)
+		}
+		invalidateCache(s, guestKey)
+		return nil, err
+	}
+	return cart, nil
+}
+
+// claimGuestCart empties the guest cart and returns it as it was read, nil when there is nothing to merge.
+// The cart is emptied only at the version it was read at, so two merges can't both claim its items.
+func (s *CartService) claimGuestCart(ctx context.Context, guestKey string) (*domain.Cart, error) {
+	for attempt := 0; attempt < maxRewriteAttempts; attempt++ {
+		guest, err := s.repo.GetCart(ctx, guestKey)
+		if errors.Is(err, repository.ErrCartNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(guest.Items) == 0 && guest.PromoCode == "" {
+			return nil, nil
+		}
+
+		err = s.repo.DeleteCart(ctx, guestKey, &guest.Version)
+		if errors.Is(err, repository.ErrVersionConflict) {
+			continue // the guest cart changed or another merge claimed it
+		}
+		if err != nil {
+			return nil, err
+		}
+		invalidateCache(s, guestKey)
+		return guest, nil
+	}
+	return nil, fmt.Errorf("guest cart changed %d times while it was claimed", maxRewriteAttempts)
+}
+
 func invalidateCache(s *CartService, userID string) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
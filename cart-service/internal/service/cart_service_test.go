@@ -30,66 +30,106 @@ func (m *mockRepository) GetCart(context.Context, string) (*domain.Cart, error)
 	return m.cart, nil
 }
 
-func (m *mockRepository) UpsertCart(_ context.Context, c *domain.Cart) error {
+// checkVersion fails like MongoDB does when the cart is not at the expected version
+func (m *mockRepository) checkVersion(version *int64) error {
+	if version != nil && m.cart.Version != *version {
+		return repository.ErrVersionConflict
+	}
+	return nil
+}
+
+func (m *mockRepository) UpsertCart(_ context.Context, c *domain.Cart, _ *int64) error {
 	m.m.Lock()
 	defer m.m.Unlock()
 	m.cart = c
 	return m.err
 }
 
-func (m *mockRepository) AddItem(_ context.Context, _ string, item domain.CartItem) error {
+func (m *mockRepository) AddItem(_ context.Context, _ string, item domain.CartItem, version *int64) error {
 	m.m.Lock()
 	defer m.m.Unlock()
 	if m.err != nil {
 		return m.err
 	}
+	if err := m.checkVersion(version); err != nil {
+		return err
+	}
 	m.cart.Items = append(m.cart.Items, item)
+	m.cart.Version++
 	return nil
 }
 
-func (m *mockRepository) UpdateItemQuantity(_ context.Context, _ string, productID int64, quantity int) error {
+func (m *mockRepository) UpdateItemQuantity(_ context.Context, _ string, productID, variantID int64, quantity int, version *int64) error {
 	m.m.Lock()
 	defer m.m.Unlock()
 	if m.err != nil {
 		return m.err
 	}
+	if err := m.checkVersion(version); err != nil {
+		return err
+	}
 	// Find and update the item
 	for i := range m.cart.Items {
-		if m.cart.Items[i].ProductID == productID {
+		if m.cart.Items[i].Is(productID, variantID) {
 			m.cart.Items[i].Quantity = quantity
+			m.cart.Version++
 			return nil
 		}
 	}
 	return fmt.Errorf("item not found")
 }
 
-func (m *mockRepository) RemoveItem(_ context.Context, _ string, productID int64) error {
+func (m *mockRepository) RemoveItem(_ context.Context, _ string, productID, variantID int64, version *int64) error {
 	m.m.Lock()
 	defer m.m.Unlock()
 	if m.err != nil {
 		return m.err
 	}
+	if err := m.checkVersion(version); err != nil {
+		return err
+	}
 	// Find and remove the item
 	for i, item := range m.cart.Items {
-		if item.ProductID == productID {
+		if item.Is(productID, variantID) {
 			m.cart.Items = append(m.cart.Items[:i], m.cart.Items[i+1:]...)
+			m.cart.Version++
 			return nil
 		}
 	}
 	return fmt.Errorf("item not found")
 }
 
-func (m *mockRepository) DeleteCart(_ context.Context, _ string) error {
+func (m *mockRepository) DeleteCart(_ context.Context, _ string, version *int64) error {
 	m.m.Lock()
 	defer m.m.Unlock()
 	if m.err != nil {
 		return m.err
 	}
+	if err := m.checkVersion(version); err != nil {
+		return err
+	}
 	// Clear all items
 	m.cart.Items = []domain.CartItem{}
 	return nil
 }
 
+func (m *mockRepository) SetPromoCode(_ context.Context, _ string, code string, version *int64) error {
+	m.m.Lock()
+	defer m.m.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	if m.cart == nil {
+		return repository.ErrCartNotFound
+	}
+	if err := m.checkVersion(version); err != nil {
+		return err
+	}
+	m.cart.PromoCode = code
+	m.cart.Version++
+	return nil
+}
+
 type mockCache struct {
 	m    sync.RWMutex
 	cart *domain.Cart
@@ -230,7 +270,7 @@ func TestAddItem_Success(t *testing.T) {
 		ProductID: 1,
 		Quantity:  5,
 		AddedAt:   time.Now(),
-	})
+	}, nil)
 	require.NoError(t, err)
 	assert.Equal(t, 1, len(mockRepo.cart.Items))
 	assert.Equal(t, int64(1), mockRepo.cart.Items[0].ProductID)
@@ -250,7 +290,7 @@ func TestAddItem_RepoError(t *testing.T) {
 	mockC := &mockCache{cart: nil}
 
 	sut := NewCartService(mockRepo, mockC, slog.Default())
-	err := sut.AddItem(context.Background(), "123", domain.CartItem{ProductID: 1, Quantity: 5})
+	err := sut.AddItem(context.Background(), "123", domain.CartItem{ProductID: 1, Quantity: 5}, nil)
 	require.ErrorContains(t, err, "database error")
 }
 
@@ -266,7 +306,7 @@ func TestUpdateQuantity_Success(t *testing.T) {
 	mockC := &mockCache{cart: cart}
 
 	sut := NewCartService(mockRepo, mockC, slog.Default())
-	err := sut.UpdateQuantity(context.Background(), "123", 1, 20)
+	err := sut.UpdateQuantity(context.Background(), "123", 1, 0, 20, nil)
 	require.NoError(t, err)
 	assert.Equal(t, 20, mockRepo.cart.Items[0].Quantity)
 
@@ -284,7 +324,7 @@ func TestUpdateQuantity_RepoError(t *testing.T) {
 	mockC := &mockCache{}
 
 	sut := NewCartService(mockRepo, mockC, slog.Default())
-	err := sut.UpdateQuantity(context.Background(), "123", 1, 20)
+	err := sut.UpdateQuantity(context.Background(), "123", 1, 0, 20, nil)
 	require.ErrorContains(t, err, "database error")
 }
 
@@ -300,7 +340,7 @@ func TestRemoveItem_Success(t *testing.T) {
 	mockC := &mockCache{cart: cart}
 
 	sut := NewCartService(mockRepo, mockC, slog.Default())
-	err := sut.RemoveItem(context.Background(), "123", 1)
+	err := sut.RemoveItem(context.Background(), "123", 1, 0, nil)
 	require.NoError(t, err)
 	assert.Equal(t, 1, len(mockRepo.cart.Items))
 	assert.Equal(t, int64(2), mockRepo.cart.Items[0].ProductID)
@@ -319,7 +359,7 @@ func TestRemoveItem_RepoError(t *testing.T) {
 	mockC := &mockCache{}
 
 	sut := NewCartService(mockRepo, mockC, slog.Default())
-	err := sut.RemoveItem(context.Background(), "123", 1)
+	err := sut.RemoveItem(context.Background(), "123", 1, 0, nil)
 	require.ErrorContains(t, err, "database error")
 }
 
@@ -335,7 +375,7 @@ func TestClearCart_Success(t *testing.T) {
 	mockC := &mockCache{cart: cart}
 
 	sut := NewCartService(mockRepo, mockC, slog.Default())
-	err := sut.ClearCart(context.Background(), "123")
+	err := sut.ClearCart(context.Background(), "123", nil)
 	require.NoError(t, err)
 	assert.Empty(t, mockRepo.cart.Items)
 
@@ -353,6 +393,221 @@ func TestClearCart_RepoError(t *testing.T) {
 	mockC := &mockCache{}
 
 	sut := NewCartService(mockRepo, mockC, slog.Default())
-	err := sut.ClearCart(context.Background(), "123")
+	err := sut.ClearCart(context.Background(), "123", nil)
 	require.ErrorContains(t, err, "database error")
 }
+
+// mapRepository keeps one cart per key, the item-level methods come from mockRepository
+type mapRepository struct {
+	*mockRepository
+	carts map[string]*domain.Cart
+}
+
+func (m *mapRepository) GetCart(_ context.Context, key string) (*domain.Cart, error) {
+	m.m.RLock()
+	defer m.m.RUnlock()
+	cart, ok := m.carts[key]
+	if !ok {
+		return nil, repository.ErrCartNotFound
+	}
+	// a copy, as MongoDB decodes a fresh cart on every read and a lost write must not change the stored one
+	read := *cart
+	read.Items = append([]domain.CartItem(nil), cart.Items...)
+	return &read, nil
+}
+
+func (m *mapRepository) UpsertCart(_ context.Context, c *domain.Cart, version *int64) error {
+	m.m.Lock()
+	defer m.m.Unlock()
+	if stored, ok := m.carts[c.UserID]; version != nil && ok && stored.Version != *version {
+		return repository.ErrVersionConflict
+	}
+	c.Version++
+	m.carts[c.UserID] = c
+	return nil
+}
+
+// DeleteCart empties the cart at the next version like MongoDB does, the stored cart is replaced rather than changed
+func (m *mapRepository) DeleteCart(_ context.Context, key string, version *int64) error {
+	m.m.Lock()
+	defer m.m.Unlock()
+	stored, ok := m.carts[key]
+	if !ok {
+		return repository.ErrCartNotFound
+	}
+	if version != nil && stored.Version != *version {
+		return repository.ErrVersionConflict
+	}
+	m.carts[key] = &domain.Cart{UserID: key, Items: []domain.CartItem{}, Lists: stored.Lists, Version: stored.Version + 1}
+	return nil
+}
+
+func TestMergeCarts_AddsGuestItemsAndDeletesGuestCart(t *testing.T) {
+	guestKey := domain.GuestCartKey("3f2a9c")
+	mockRepo := &mapRepository{mockRepository: &mockRepository{}, carts: map[string]*domain.Cart{
+		"123": {UserID: "123", Items: []domain.CartItem{{ProductID: 1, Quantity: 2}}},
+		guestKey: {UserID: guestKey, Items: []domain.CartItem{
+			{ProductID: 1, Quantity: 3},
+			{ProductID: 2, VariantID: 7, Quantity: 1},
+		}},
+	}}
+
+	sut := NewCartService(mockRepo, &mockCache{}, slog.Default())
+	ret, err := sut.MergeCarts(context.Background(), guestKey, "123", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.CartItem{
+		{ProductID: 1, Quantity: 5},
+		{ProductID: 2, VariantID: 7, Quantity: 1},
+	}, ret.Items)
+	assert.Equal(t, ret, mockRepo.carts["123"])
+	assert.Empty(t, mockRepo.carts[guestKey].Items)
+}
+
+func TestMergeCarts_KeepsUserPromoCode(t *testing.T) {
+	guestKey := domain.GuestCartKey("3f2a9c")
+	mockRepo := &mapRepository{mockRepository: &mockRepository{}, carts: map[string]*domain.Cart{
+		"123":    {UserID: "123", PromoCode: "USER10"},
+		guestKey: {UserID: guestKey, PromoCode: "GUEST5", Items: []domain.CartItem{{ProductID: 1, Quantity: 3}}},
+		"456":    {UserID: "456"},
+	}}
+
+	sut := NewCartService(mockRepo, &mockCache{}, slog.Default())
+	ret, err := sut.MergeCarts(context.Background(), guestKey, "123", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "USER10", ret.PromoCode)
+
+	mockRepo.carts[guestKey] = &domain.Cart{UserID: guestKey, PromoCode: "GUEST5"}
+	ret, err = sut.MergeCarts(context.Background(), guestKey, "456", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "GUEST5", ret.PromoCode, "the guest code carries over to a cart without one")
+}
+
+func TestMergeCarts_NoUserCart(t *testing.T) {
+	guestKey := domain.GuestCartKey("3f2a9c")
+	mockRepo := &mapRepository{mockRepository: &mockRepository{}, carts: map[string]*domain.Cart{
+		guestKey: {UserID: guestKey, Items: []domain.CartItem{{ProductID: 1, Quantity: 3}}},
+	}}
+
+	sut := NewCartService(mockRepo, &mockCache{}, slog.Default())
+	ret, err := sut.MergeCarts(context.Background(), guestKey, "123", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "123", ret.UserID)
+	assert.Equal(t, []domain.CartItem{{ProductID: 1, Quantity: 3}}, ret.Items)
+	assert.Empty(t, mockRepo.carts[guestKey].Items)
+}
+
+func TestMergeCarts_GuestCartGone_ReturnsUserCart(t *testing.T) {
+	mockRepo := &mapRepository{mockRepository: &mockRepository{}, carts: map[string]*domain.Cart{
+		"123": {UserID: "123", Items: []domain.CartItem{{ProductID: 1, Quantity: 2}}},
+	}}
+
+	sut := NewCartService(mockRepo, &mockCache{}, slog.Default())
+	ret, err := sut.MergeCarts(context.Background(), domain.GuestCartKey("3f2a9c"), "123", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.CartItem{{ProductID: 1, Quantity: 2}}, ret.Items)
+}
+
+func TestMergeCarts_StaleVersion(t *testing.T) {
+	guestKey := domain.GuestCartKey("3f2a9c")
+	mockRepo := &mapRepository{mockRepository: &mockRepository{}, carts: map[string]*domain.Cart{
+		"123":    {UserID: "123", Items: []domain.CartItem{{ProductID: 1, Quantity: 2}}, Version: 4},
+		guestKey: {UserID: guestKey, Items: []domain.CartItem{{ProductID: 2, Quantity: 1}}},
+	}}
+	sut := NewCartService(mockRepo, &mockCache{}, slog.Default())
+	stale, current := int64(3), int64(4)
+
+	_, err := sut.MergeCarts(context.Background(), guestKey, "123", &stale)
+	require.ErrorIs(t, err, repository.ErrVersionConflict)
+	assert.Len(t, mockRepo.carts[guestKey].Items, 1, "nothing is merged on a stale version")
+
+	ret, err := sut.MergeCarts(context.Background(), guestKey, "123", &current)
+	require.NoError(t, err)
+	assert.Len(t, ret.Items, 2)
+	assert.Equal(t, int64(5), ret.Version)
+}
+
+// racingRepository loses the first conflicts writes to a concurrent change of the cart
+type racingRepository struct {
+	*mapRepository
+	conflicts int
+}
+
+func (m *racingRepository) UpsertCart(ctx context.Context, c *domain.Cart, version *int64) error {
+	if m.conflicts > 0 {
+		m.conflicts--
+		return repository.ErrVersionConflict
+	}
+	return m.mapRepository.UpsertCart(ctx, c, version)
+}
+
+func TestMergeCarts_RetriesConcurrentChangeWithoutVersion(t *testing.T) {
+	guestKey := domain.GuestCartKey("3f2a9c")
+	mockRepo := &racingRepository{conflicts: 1, mapRepository: &mapRepository{mockRepository: &mockRepository{}, carts: map[string]*domain.Cart{
+		"123":    {UserID: "123", Items: []domain.CartItem{{ProductID: 1, Quantity: 2}}},
+		guestKey: {UserID: guestKey, Items: []domain.CartItem{{ProductID: 1, Quantity: 3}}},
+	}}}
+	sut := NewCartService(mockRepo, &mockCache{}, slog.Default())
+
+	ret, err := sut.MergeCarts(context.Background(), guestKey, "123", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, []domain.CartItem{{ProductID: 1, Quantity: 5}}, ret.Items)
+	assert.Zero(t, mockRepo.conflicts)
+}
+
+func TestMergeCarts_RetriedMergeCountsGuestItemsOnce(t *testing.T) {
+	guestKey := domain.GuestCartKey("3f2a9c")
+	mockRepo := &mapRepository{mockRepository: &mockRepository{}, carts: map[string]*domain.Cart{
+		"123":    {UserID: "123", Items: []domain.CartItem{{ProductID: 1, Quantity: 2}}},
+		guestKey: {UserID: guestKey, Items: []domain.CartItem{{ProductID: 1, Quantity: 3}}},
+	}}
+	sut := NewCartService(mockRepo, &mockCache{}, slog.Default())
+
+	_, err := sut.MergeCarts(context.Background(), guestKey, "123", nil)
+	require.NoError(t, err)
+	ret, err := sut.MergeCarts(context.Background(), guestKey, "123", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, []domain.CartItem{{ProductID: 1, Quantity: 5}}, ret.Items)
+}
+
+func TestMergeCarts_GivesGuestItemsBackWhenUserCartChanged(t *testing.T) {
+	guestKey := domain.GuestCartKey("3f2a9c")
+	mockRepo := &racingRepository{conflicts: 1, mapRepository: &mapRepository{mockRepository: &mockRepository{}, carts: map[string]*domain.Cart{
+		"123":    {UserID: "123", Version: 4},
+		guestKey: {UserID: guestKey, Items: []domain.CartItem{{ProductID: 1, Quantity: 3}}, Version: 1},
+	}}}
+	sut := NewCartService(mockRepo, &mockCache{}, slog.Default())
+	version := int64(4)
+
+	_, err := sut.MergeCarts(context.Background(), guestKey, "123", &version)
+
+	require.ErrorIs(t, err, repository.ErrVersionConflict)
+	assert.Equal(t, []domain.CartItem{{ProductID: 1, Quantity: 3}}, mockRepo.carts[guestKey].Items)
+}
+
+func TestReplaceCart_RetriesConcurrentChange(t *testing.T) {
+	mockRepo := &racingRepository{conflicts: 1, mapRepository: &mapRepository{mockRepository: &mockRepository{}, carts: map[string]*domain.Cart{
+		"123": {UserID: "123", Items: []domain.CartItem{{ProductID: 1, Quantity: 2}}, PromoCode: "TEN"},
+	}}}
+	sut := NewCartService(mockRepo, &mockCache{}, slog.Default())
+
+	ret, err := sut.ReplaceCart(context.Background(), "123", []domain.CartItem{{ProductID: 2, Quantity: 1}}, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, []domain.CartItem{{ProductID: 2, Quantity: 1}}, ret.Items)
+	assert.Equal(t, "TEN", ret.PromoCode)
+	assert.Zero(t, mockRepo.conflicts)
+}
+
+func TestAddItems_ConcurrentChangeWithVersion(t *testing.T) {
+	mockRepo := &racingRepository{conflicts: 1, mapRepository: &mapRepository{mockRepository: &mockRepository{}, carts: map[string]*domain.Cart{}}}
+	sut := NewCartService(mockRepo, &mockCache{}, slog.Default())
+	version := int64(0)
+
+	_, err := sut.AddItems(context.Background(), "123", []domain.CartItem{{ProductID: 2, Quantity: 1}}, &version)
+
+	// the caller named the version, so the change is not retried on top of another one
+	require.ErrorIs(t, err, repository.ErrVersionConflict)
+	assert.Empty(t, mockRepo.carts)
+}
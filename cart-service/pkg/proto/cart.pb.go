@@ -21,14 +21,119 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type StockStatus int32
+
+const (
+	StockStatus_STOCK_STATUS_UNKNOWN      StockStatus = 0 // inventory-service could not be asked
+	StockStatus_STOCK_STATUS_IN_STOCK     StockStatus = 1
+	StockStatus_STOCK_STATUS_LOW_STOCK    StockStatus = 2 // enough for the line, but only a few units left
+	StockStatus_STOCK_STATUS_INSUFFICIENT StockStatus = 3 // fewer units available than the line holds, checkout would fail
+)
+
+// Enum value maps for StockStatus.
+var (
+	StockStatus_name = map[int32]string{
+		0: "STOCK_STATUS_UNKNOWN",
+		1: "STOCK_STATUS_IN_STOCK",
+		2: "STOCK_STATUS_LOW_STOCK",
+		3: "STOCK_STATUS_INSUFFICIENT",
+	}
+	StockStatus_value = map[string]int32{
+		"STOCK_STATUS_UNKNOWN":      0,
+		"STOCK_STATUS_IN_STOCK":     1,
+		"STOCK_STATUS_LOW_STOCK":    2,
+		"STOCK_STATUS_INSUFFICIENT": 3,
+	}
+)
+
+func (x StockStatus) Enum() *StockStatus {
+	p := new(StockStatus)
+	*p = x
+	return p
+}
+
+func (x StockStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StockStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_proto_cart_proto_enumTypes[0].Descriptor()
+}
+
+func (StockStatus) Type() protoreflect.EnumType {
+	return &file_pkg_proto_cart_proto_enumTypes[0]
+}
+
+func (x StockStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StockStatus.Descriptor instead.
+func (StockStatus) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_proto_cart_proto_rawDescGZIP(), []int{0}
+}
+
+type PricedItemStatus int32
+
+const (
+	PricedItemStatus_PRICED_ITEM_STATUS_AVAILABLE         PricedItemStatus = 0
+	PricedItemStatus_PRICED_ITEM_STATUS_PRODUCT_NOT_FOUND PricedItemStatus = 1 // the product is gone from the catalog
+	PricedItemStatus_PRICED_ITEM_STATUS_UNAVAILABLE       PricedItemStatus = 2 // the product or its variant is archived, or the variant is gone
+)
+
+// Enum value maps for PricedItemStatus.
+var (
+	PricedItemStatus_name = map[int32]string{
+		0: "PRICED_ITEM_STATUS_AVAILABLE",
+		1: "PRICED_ITEM_STATUS_PRODUCT_NOT_FOUND",
+		2: "PRICED_ITEM_STATUS_UNAVAILABLE",
+	}
+	PricedItemStatus_value = map[string]int32{
+		"PRICED_ITEM_STATUS_AVAILABLE":         0,
+		"PRICED_ITEM_STATUS_PRODUCT_NOT_FOUND": 1,
+		"PRICED_ITEM_STATUS_UNAVAILABLE":       2,
+	}
+)
+
+func (x PricedItemStatus) Enum() *PricedItemStatus {
+	p := new(PricedItemStatus)
+	*p = x
+	return p
+}
+
+func (x PricedItemStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PricedItemStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_proto_cart_proto_enumTypes[1].Descriptor()
+}
+
+func (PricedItemStatus) Type() protoreflect.EnumType {
+	return &file_pkg_proto_cart_proto_enumTypes[1]
+}
+
+func (x PricedItemStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PricedItemStatus.Descriptor instead.
+func (PricedItemStatus) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_proto_cart_proto_rawDescGZIP(), []int{1}
+}
+
 // Cart item represents what user adds to cart
 type CartItem struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProductId     int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
-	AddedAt       string                 `protobuf:"bytes,3,opt,name=added_at,json=addedAt,proto3" json:"added_at,omitempty"` // RFC3339 format
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	ProductId         int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity          int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	AddedAt           string                 `protobuf:"bytes,3,opt,name=added_at,json=addedAt,proto3" json:"added_at,omitempty"`        // RFC3339 format
+	VariantId         int64                  `protobuf:"varint,4,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"` // 0 for a product sold by its id alone
+	Sku               string                 `protobuf:"bytes,5,opt,name=sku,proto3" json:"sku,omitempty"`                               // empty for a product sold by its id alone
+	StockStatus       StockStatus            `protobuf:"varint,6,opt,name=stock_status,json=stockStatus,proto3,enum=cart.StockStatus" json:"stock_status,omitempty"`
+	AvailableQuantity int32                  `protobuf:"varint,7,opt,name=available_quantity,json=availableQuantity,proto3" json:"available_quantity,omitempty"` // units inventory-service has available, set unless stock_status is unknown
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *CartItem) Reset() {
@@ -82,6 +187,34 @@ func (x *CartItem) GetAddedAt() string {
 	return ""
 }
 
+func (x *CartItem) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *CartItem) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *CartItem) GetStockStatus() StockStatus {
+	if x != nil {
+		return x.StockStatus
+	}
+	return StockStatus_STOCK_STATUS_UNKNOWN
+}
+
+func (x *CartItem) GetAvailableQuantity() int32 {
+	if x != nil {
+		return x.AvailableQuantity
+	}
+	return 0
+}
+
 type Cart struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -89,6 +222,9 @@ type Cart struct {
 	Cart          []*CartItem            `protobuf:"bytes,3,rep,name=cart,proto3" json:"cart,omitempty"`
 	CreatedAt     string                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // RFC3339 format
 	UpdatedAt     string                 `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // RFC3339 format
+	GuestId       string                 `protobuf:"bytes,6,opt,name=guest_id,json=guestId,proto3" json:"guest_id,omitempty"`       // set instead of user_id on a guest cart
+	PromoCode     string                 `protobuf:"bytes,7,opt,name=promo_code,json=promoCode,proto3" json:"promo_code,omitempty"` // applied promo code, GetPricedCart reports when it no longer applies
+	Version       int64                  `protobuf:"varint,8,opt,name=version,proto3" json:"version,omitempty"`                     // goes up with every change, a cart that doesn't exist yet is at version 0
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -158,14 +294,38 @@ func (x *Cart) GetUpdatedAt() string {
 	return ""
 }
 
+func (x *Cart) GetGuestId() string {
+	if x != nil {
+		return x.GuestId
+	}
+	return ""
+}
+
+func (x *Cart) GetPromoCode() string {
+	if x != nil {
+		return x.PromoCode
+	}
+	return ""
+}
+
+func (x *Cart) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
 // Request to add item
 type AddCartItemRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	ProductId     int64                  `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ProductId       int64                  `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity        int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	VariantId       int64                  `protobuf:"varint,4,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"` // required when the product has variants, 0 otherwise
+	GuestId         string                 `protobuf:"bytes,5,opt,name=guest_id,json=guestId,proto3" json:"guest_id,omitempty"`
+	ExpectedVersion *int64                 `protobuf:"varint,6,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *AddCartItemRequest) Reset() {
@@ -219,9 +379,31 @@ func (x *AddCartItemRequest) GetQuantity() int32 {
 	return 0
 }
 
+func (x *AddCartItemRequest) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *AddCartItemRequest) GetGuestId() string {
+	if x != nil {
+		return x.GuestId
+	}
+	return ""
+}
+
+func (x *AddCartItemRequest) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
 type GetCartRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	GuestId       string                 `protobuf:"bytes,2,opt,name=guest_id,json=guestId,proto3" json:"guest_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -263,14 +445,24 @@ func (x *GetCartRequest) GetUserId() int64 {
 	return 0
 }
 
+func (x *GetCartRequest) GetGuestId() string {
+	if x != nil {
+		return x.GuestId
+	}
+	return ""
+}
+
 // Request to update item quantity
 type UpdateQuantityRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	ProductId     int64                  `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ProductId       int64                  `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity        int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	VariantId       int64                  `protobuf:"varint,4,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	GuestId         string                 `protobuf:"bytes,5,opt,name=guest_id,json=guestId,proto3" json:"guest_id,omitempty"`
+	ExpectedVersion *int64                 `protobuf:"varint,6,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *UpdateQuantityRequest) Reset() {
@@ -324,13 +516,37 @@ func (x *UpdateQuantityRequest) GetQuantity() int32 {
 	return 0
 }
 
+func (x *UpdateQuantityRequest) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *UpdateQuantityRequest) GetGuestId() string {
+	if x != nil {
+		return x.GuestId
+	}
+	return ""
+}
+
+func (x *UpdateQuantityRequest) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
 // Request to remove item from cart
 type RemoveItemRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	ProductId     int64                  `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ProductId       int64                  `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	VariantId       int64                  `protobuf:"varint,3,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	GuestId         string                 `protobuf:"bytes,4,opt,name=guest_id,json=guestId,proto3" json:"guest_id,omitempty"`
+	ExpectedVersion *int64                 `protobuf:"varint,5,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *RemoveItemRequest) Reset() {
@@ -377,12 +593,35 @@ func (x *RemoveItemRequest) GetProductId() int64 {
 	return 0
 }
 
+func (x *RemoveItemRequest) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *RemoveItemRequest) GetGuestId() string {
+	if x != nil {
+		return x.GuestId
+	}
+	return ""
+}
+
+func (x *RemoveItemRequest) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
 // Request to clear entire cart
 type ClearCartRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	GuestId         string                 `protobuf:"bytes,2,opt,name=guest_id,json=guestId,proto3" json:"guest_id,omitempty"`
+	ExpectedVersion *int64                 `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *ClearCartRequest) Reset() {
@@ -422,28 +661,44 @@ func (x *ClearCartRequest) GetUserId() int64 {
 	return 0
 }
 
-// Response
-type CartResponse struct {
+func (x *ClearCartRequest) GetGuestId() string {
+	if x != nil {
+		return x.GuestId
+	}
+	return ""
+}
+
+func (x *ClearCartRequest) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
+// CartItemInput is one line of a bulk cart change
+type CartItemInput struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Cart          *Cart                  `protobuf:"bytes,1,opt,name=cart,proto3" json:"cart,omitempty"`
+	ProductId     int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	VariantId     int64                  `protobuf:"varint,2,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"` // required when the product has variants, 0 otherwise
+	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CartResponse) Reset() {
-	*x = CartResponse{}
+func (x *CartItemInput) Reset() {
+	*x = CartItemInput{}
 	mi := &file_pkg_proto_cart_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CartResponse) String() string {
+func (x *CartItemInput) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CartResponse) ProtoMessage() {}
+func (*CartItemInput) ProtoMessage() {}
 
-func (x *CartResponse) ProtoReflect() protoreflect.Message {
+func (x *CartItemInput) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_proto_cart_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -455,64 +710,1318 @@ func (x *CartResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CartResponse.ProtoReflect.Descriptor instead.
-func (*CartResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use CartItemInput.ProtoReflect.Descriptor instead.
+func (*CartItemInput) Descriptor() ([]byte, []int) {
 	return file_pkg_proto_cart_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *CartResponse) GetCart() *Cart {
+func (x *CartItemInput) GetProductId() int64 {
 	if x != nil {
-		return x.Cart
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *CartItemInput) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *CartItemInput) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+// Adds all the items or none: every line is checked against product-service before the cart is written.
+// A product variant the cart already holds takes the line's quantity, as with AddItem.
+type AddItemsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	GuestId         string                 `protobuf:"bytes,2,opt,name=guest_id,json=guestId,proto3" json:"guest_id,omitempty"`
+	Items           []*CartItemInput       `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"` // at most one line per product variant
+	ExpectedVersion *int64                 `protobuf:"varint,4,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AddItemsRequest) Reset() {
+	*x = AddItemsRequest{}
+	mi := &file_pkg_proto_cart_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddItemsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddItemsRequest) ProtoMessage() {}
+
+func (x *AddItemsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cart_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddItemsRequest.ProtoReflect.Descriptor instead.
+func (*AddItemsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cart_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AddItemsRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *AddItemsRequest) GetGuestId() string {
+	if x != nil {
+		return x.GuestId
+	}
+	return ""
+}
+
+func (x *AddItemsRequest) GetItems() []*CartItemInput {
+	if x != nil {
+		return x.Items
 	}
 	return nil
 }
 
-var File_pkg_proto_cart_proto protoreflect.FileDescriptor
+func (x *AddItemsRequest) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
 
-const file_pkg_proto_cart_proto_rawDesc = "" +
-	"\n" +
-	"\x14pkg/proto/cart.proto\x12\x04cart\"`\n" +
-	"\bCartItem\x12\x1d\n" +
-	"\n" +
-	"product_id\x18\x01 \x01(\x03R\tproductId\x12\x1a\n" +
-	"\bquantity\x18\x02 \x01(\x05R\bquantity\x12\x19\n" +
-	"\badded_at\x18\x03 \x01(\tR\aaddedAt\"\x91\x01\n" +
-	"\x04Cart\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
-	"\auser_id\x18\x02 \x01(\x03R\x06userId\x12\"\n" +
-	"\x04cart\x18\x03 \x03(\v2\x0e.cart.CartItemR\x04cart\x12\x1d\n" +
-	"\n" +
-	"created_at\x18\x04 \x01(\tR\tcreatedAt\x12\x1d\n" +
-	"\n" +
-	"updated_at\x18\x05 \x01(\tR\tupdatedAt\"h\n" +
-	"\x12AddCartItemRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1d\n" +
-	"\n" +
-	"product_id\x18\x02 \x01(\x03R\tproductId\x12\x1a\n" +
-	"\bquantity\x18\x03 \x01(\x05R\bquantity\")\n" +
-	"\x0eGetCartRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\x03R\x06userId\"k\n" +
-	"\x15UpdateQuantityRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1d\n" +
-	"\n" +
-	"product_id\x18\x02 \x01(\x03R\tproductId\x12\x1a\n" +
-	"\bquantity\x18\x03 \x01(\x05R\bquantity\"K\n" +
-	"\x11RemoveItemRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1d\n" +
-	"\n" +
-	"product_id\x18\x02 \x01(\x03R\tproductId\"+\n" +
-	"\x10ClearCartRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\x03R\x06userId\".\n" +
-	"\fCartResponse\x12\x1e\n" +
-	"\x04cart\x18\x01 \x01(\v2\n" +
-	".cart.CartR\x04cart2\xb2\x02\n" +
-	"\vCartService\x127\n" +
-	"\aAddItem\x12\x18.cart.AddCartItemRequest\x1a\x12.cart.CartResponse\x123\n" +
-	"\aGetCart\x12\x14.cart.GetCartRequest\x1a\x12.cart.CartResponse\x12A\n" +
-	"\x0eUpdateQuantity\x12\x1b.cart.UpdateQuantityRequest\x1a\x12.cart.CartResponse\x129\n" +
-	"\n" +
-	"RemoveItem\x12\x17.cart.RemoveItemRequest\x1a\x12.cart.CartResponse\x127\n" +
-	"\tClearCart\x12\x16.cart.ClearCartRequest\x1a\x12.cart.CartResponseB0Z.github.com/fjod/go_cart/cart-service/pkg/protob\x06proto3"
+// Swaps all the lines of the cart for the items, checked like AddItems. No items empties the cart.
+// The promo code stays on the cart.
+type ReplaceCartRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	GuestId         string                 `protobuf:"bytes,2,opt,name=guest_id,json=guestId,proto3" json:"guest_id,omitempty"`
+	Items           []*CartItemInput       `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"` // at most one line per product variant
+	ExpectedVersion *int64                 `protobuf:"varint,4,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ReplaceCartRequest) Reset() {
+	*x = ReplaceCartRequest{}
+	mi := &file_pkg_proto_cart_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplaceCartRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplaceCartRequest) ProtoMessage() {}
+
+func (x *ReplaceCartRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cart_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplaceCartRequest.ProtoReflect.Descriptor instead.
+func (*ReplaceCartRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cart_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ReplaceCartRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ReplaceCartRequest) GetGuestId() string {
+	if x != nil {
+		return x.GuestId
+	}
+	return ""
+}
+
+func (x *ReplaceCartRequest) GetItems() []*CartItemInput {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ReplaceCartRequest) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
+// Moves the guest cart into the cart of the user who just signed in, the guest cart is deleted
+type MergeCartsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	GuestId         string                 `protobuf:"bytes,1,opt,name=guest_id,json=guestId,proto3" json:"guest_id,omitempty"`
+	UserId          int64                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ExpectedVersion *int64                 `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"` // of the user's cart
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *MergeCartsRequest) Reset() {
+	*x = MergeCartsRequest{}
+	mi := &file_pkg_proto_cart_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergeCartsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeCartsRequest) ProtoMessage() {}
+
+func (x *MergeCartsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cart_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeCartsRequest.ProtoReflect.Descriptor instead.
+func (*MergeCartsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cart_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *MergeCartsRequest) GetGuestId() string {
+	if x != nil {
+		return x.GuestId
+	}
+	return ""
+}
+
+func (x *MergeCartsRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *MergeCartsRequest) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
+// Applies a promo code to the cart, fails unless product-service accepts it for the current lines
+type ApplyPromoCodeRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	GuestId         string                 `protobuf:"bytes,2,opt,name=guest_id,json=guestId,proto3" json:"guest_id,omitempty"`
+	Code            string                 `protobuf:"bytes,3,opt,name=code,proto3" json:"code,omitempty"`
+	ExpectedVersion *int64                 `protobuf:"varint,4,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ApplyPromoCodeRequest) Reset() {
+	*x = ApplyPromoCodeRequest{}
+	mi := &file_pkg_proto_cart_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyPromoCodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyPromoCodeRequest) ProtoMessage() {}
+
+func (x *ApplyPromoCodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cart_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyPromoCodeRequest.ProtoReflect.Descriptor instead.
+func (*ApplyPromoCodeRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cart_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ApplyPromoCodeRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ApplyPromoCodeRequest) GetGuestId() string {
+	if x != nil {
+		return x.GuestId
+	}
+	return ""
+}
+
+func (x *ApplyPromoCodeRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *ApplyPromoCodeRequest) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
+type RemovePromoCodeRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	GuestId         string                 `protobuf:"bytes,2,opt,name=guest_id,json=guestId,proto3" json:"guest_id,omitempty"`
+	ExpectedVersion *int64                 `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RemovePromoCodeRequest) Reset() {
+	*x = RemovePromoCodeRequest{}
+	mi := &file_pkg_proto_cart_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemovePromoCodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemovePromoCodeRequest) ProtoMessage() {}
+
+func (x *RemovePromoCodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cart_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemovePromoCodeRequest.ProtoReflect.Descriptor instead.
+func (*RemovePromoCodeRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cart_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RemovePromoCodeRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *RemovePromoCodeRequest) GetGuestId() string {
+	if x != nil {
+		return x.GuestId
+	}
+	return ""
+}
+
+func (x *RemovePromoCodeRequest) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
+// SavedList holds items kept out of the cart
+type SavedList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // empty for the saved-for-later list
+	Items         []*CartItem            `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SavedList) Reset() {
+	*x = SavedList{}
+	mi := &file_pkg_proto_cart_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SavedList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SavedList) ProtoMessage() {}
+
+func (x *SavedList) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cart_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SavedList.ProtoReflect.Descriptor instead.
+func (*SavedList) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cart_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SavedList) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SavedList) GetItems() []*CartItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type GetSavedItemsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSavedItemsRequest) Reset() {
+	*x = GetSavedItemsRequest{}
+	mi := &file_pkg_proto_cart_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSavedItemsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSavedItemsRequest) ProtoMessage() {}
+
+func (x *GetSavedItemsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cart_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSavedItemsRequest.ProtoReflect.Descriptor instead.
+func (*GetSavedItemsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cart_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetSavedItemsRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+// Moves a cart line to the list, a wishlist that doesn't exist yet is created
+type SaveForLaterRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ProductId       int64                  `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	VariantId       int64                  `protobuf:"varint,3,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	List            string                 `protobuf:"bytes,4,opt,name=list,proto3" json:"list,omitempty"`
+	ExpectedVersion *int64                 `protobuf:"varint,5,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SaveForLaterRequest) Reset() {
+	*x = SaveForLaterRequest{}
+	mi := &file_pkg_proto_cart_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SaveForLaterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SaveForLaterRequest) ProtoMessage() {}
+
+func (x *SaveForLaterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cart_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SaveForLaterRequest.ProtoReflect.Descriptor instead.
+func (*SaveForLaterRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cart_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SaveForLaterRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *SaveForLaterRequest) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *SaveForLaterRequest) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *SaveForLaterRequest) GetList() string {
+	if x != nil {
+		return x.List
+	}
+	return ""
+}
+
+func (x *SaveForLaterRequest) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
+// Moves an item of the list back into the cart, a line the cart holds takes the item's quantity
+type MoveToCartRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ProductId       int64                  `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	VariantId       int64                  `protobuf:"varint,3,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	List            string                 `protobuf:"bytes,4,opt,name=list,proto3" json:"list,omitempty"`
+	ExpectedVersion *int64                 `protobuf:"varint,5,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *MoveToCartRequest) Reset() {
+	*x = MoveToCartRequest{}
+	mi := &file_pkg_proto_cart_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveToCartRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveToCartRequest) ProtoMessage() {}
+
+func (x *MoveToCartRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cart_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveToCartRequest.ProtoReflect.Descriptor instead.
+func (*MoveToCartRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cart_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *MoveToCartRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *MoveToCartRequest) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *MoveToCartRequest) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *MoveToCartRequest) GetList() string {
+	if x != nil {
+		return x.List
+	}
+	return ""
+}
+
+func (x *MoveToCartRequest) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
+type RemoveSavedItemRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ProductId       int64                  `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	VariantId       int64                  `protobuf:"varint,3,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	List            string                 `protobuf:"bytes,4,opt,name=list,proto3" json:"list,omitempty"`
+	ExpectedVersion *int64                 `protobuf:"varint,5,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RemoveSavedItemRequest) Reset() {
+	*x = RemoveSavedItemRequest{}
+	mi := &file_pkg_proto_cart_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveSavedItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveSavedItemRequest) ProtoMessage() {}
+
+func (x *RemoveSavedItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cart_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveSavedItemRequest.ProtoReflect.Descriptor instead.
+func (*RemoveSavedItemRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cart_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *RemoveSavedItemRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *RemoveSavedItemRequest) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *RemoveSavedItemRequest) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *RemoveSavedItemRequest) GetList() string {
+	if x != nil {
+		return x.List
+	}
+	return ""
+}
+
+func (x *RemoveSavedItemRequest) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
+// Deletes a list with its items
+type DeleteSavedListRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	List            string                 `protobuf:"bytes,2,opt,name=list,proto3" json:"list,omitempty"`
+	ExpectedVersion *int64                 `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DeleteSavedListRequest) Reset() {
+	*x = DeleteSavedListRequest{}
+	mi := &file_pkg_proto_cart_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSavedListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSavedListRequest) ProtoMessage() {}
+
+func (x *DeleteSavedListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cart_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSavedListRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSavedListRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cart_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DeleteSavedListRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *DeleteSavedListRequest) GetList() string {
+	if x != nil {
+		return x.List
+	}
+	return ""
+}
+
+func (x *DeleteSavedListRequest) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
+// SavedItemsResponse is the cart together with the saved lists, so a move shows both sides
+type SavedItemsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cart          *Cart                  `protobuf:"bytes,1,opt,name=cart,proto3" json:"cart,omitempty"`
+	Lists         []*SavedList           `protobuf:"bytes,2,rep,name=lists,proto3" json:"lists,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SavedItemsResponse) Reset() {
+	*x = SavedItemsResponse{}
+	mi := &file_pkg_proto_cart_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SavedItemsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SavedItemsResponse) ProtoMessage() {}
+
+func (x *SavedItemsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cart_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SavedItemsResponse.ProtoReflect.Descriptor instead.
+func (*SavedItemsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cart_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SavedItemsResponse) GetCart() *Cart {
+	if x != nil {
+		return x.Cart
+	}
+	return nil
+}
+
+func (x *SavedItemsResponse) GetLists() []*SavedList {
+	if x != nil {
+		return x.Lists
+	}
+	return nil
+}
+
+// Response
+type CartResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cart          *Cart                  `protobuf:"bytes,1,opt,name=cart,proto3" json:"cart,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CartResponse) Reset() {
+	*x = CartResponse{}
+	mi := &file_pkg_proto_cart_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CartResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CartResponse) ProtoMessage() {}
+
+func (x *CartResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cart_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CartResponse.ProtoReflect.Descriptor instead.
+func (*CartResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cart_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CartResponse) GetCart() *Cart {
+	if x != nil {
+		return x.Cart
+	}
+	return nil
+}
+
+// PricedCartItem is a cart line with the product details and what it sells for right now
+type PricedCartItem struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	ProductId         int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	VariantId         int64                  `protobuf:"varint,2,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	Sku               string                 `protobuf:"bytes,3,opt,name=sku,proto3" json:"sku,omitempty"`
+	Quantity          int32                  `protobuf:"varint,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	AddedAt           string                 `protobuf:"bytes,5,opt,name=added_at,json=addedAt,proto3" json:"added_at,omitempty"`             // RFC3339 format
+	ProductName       string                 `protobuf:"bytes,6,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"` // empty when the product is not found
+	VariantName       string                 `protobuf:"bytes,7,opt,name=variant_name,json=variantName,proto3" json:"variant_name,omitempty"`
+	ImageUrl          string                 `protobuf:"bytes,8,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	UnitPriceMinor    int64                  `protobuf:"varint,9,opt,name=unit_price_minor,json=unitPriceMinor,proto3" json:"unit_price_minor,omitempty"` // minor units of currency, 0 unless the line is available
+	SubtotalMinor     int64                  `protobuf:"varint,10,opt,name=subtotal_minor,json=subtotalMinor,proto3" json:"subtotal_minor,omitempty"`     // unit_price_minor * quantity
+	Currency          string                 `protobuf:"bytes,11,opt,name=currency,proto3" json:"currency,omitempty"`
+	Status            PricedItemStatus       `protobuf:"varint,12,opt,name=status,proto3,enum=cart.PricedItemStatus" json:"status,omitempty"` // lines that are not available are left out of the totals
+	StockStatus       StockStatus            `protobuf:"varint,13,opt,name=stock_status,json=stockStatus,proto3,enum=cart.StockStatus" json:"stock_status,omitempty"`
+	AvailableQuantity int32                  `protobuf:"varint,14,opt,name=available_quantity,json=availableQuantity,proto3" json:"available_quantity,omitempty"`
+	DiscountMinor     int64                  `protobuf:"varint,15,opt,name=discount_minor,json=discountMinor,proto3" json:"discount_minor,omitempty"` // share of the promo code discount, 0 when the code doesn't cover the line
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *PricedCartItem) Reset() {
+	*x = PricedCartItem{}
+	mi := &file_pkg_proto_cart_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PricedCartItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PricedCartItem) ProtoMessage() {}
+
+func (x *PricedCartItem) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cart_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PricedCartItem.ProtoReflect.Descriptor instead.
+func (*PricedCartItem) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cart_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *PricedCartItem) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *PricedCartItem) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *PricedCartItem) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *PricedCartItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *PricedCartItem) GetAddedAt() string {
+	if x != nil {
+		return x.AddedAt
+	}
+	return ""
+}
+
+func (x *PricedCartItem) GetProductName() string {
+	if x != nil {
+		return x.ProductName
+	}
+	return ""
+}
+
+func (x *PricedCartItem) GetVariantName() string {
+	if x != nil {
+		return x.VariantName
+	}
+	return ""
+}
+
+func (x *PricedCartItem) GetImageUrl() string {
+	if x != nil {
+		return x.ImageUrl
+	}
+	return ""
+}
+
+func (x *PricedCartItem) GetUnitPriceMinor() int64 {
+	if x != nil {
+		return x.UnitPriceMinor
+	}
+	return 0
+}
+
+func (x *PricedCartItem) GetSubtotalMinor() int64 {
+	if x != nil {
+		return x.SubtotalMinor
+	}
+	return 0
+}
+
+func (x *PricedCartItem) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *PricedCartItem) GetStatus() PricedItemStatus {
+	if x != nil {
+		return x.Status
+	}
+	return PricedItemStatus_PRICED_ITEM_STATUS_AVAILABLE
+}
+
+func (x *PricedCartItem) GetStockStatus() StockStatus {
+	if x != nil {
+		return x.StockStatus
+	}
+	return StockStatus_STOCK_STATUS_UNKNOWN
+}
+
+func (x *PricedCartItem) GetAvailableQuantity() int32 {
+	if x != nil {
+		return x.AvailableQuantity
+	}
+	return 0
+}
+
+func (x *PricedCartItem) GetDiscountMinor() int64 {
+	if x != nil {
+		return x.DiscountMinor
+	}
+	return 0
+}
+
+type PricedCartResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cart          *Cart                  `protobuf:"bytes,1,opt,name=cart,proto3" json:"cart,omitempty"`
+	Items         []*PricedCartItem      `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`                              // in cart order
+	TotalMinor    int64                  `protobuf:"varint,3,opt,name=total_minor,json=totalMinor,proto3" json:"total_minor,omitempty"` // subtotal_minor - discount_minor
+	Currency      string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	ItemCount     int32                  `protobuf:"varint,5,opt,name=item_count,json=itemCount,proto3" json:"item_count,omitempty"`             // units on the available lines
+	SubtotalMinor int64                  `protobuf:"varint,6,opt,name=subtotal_minor,json=subtotalMinor,proto3" json:"subtotal_minor,omitempty"` // sum of the available lines
+	DiscountMinor int64                  `protobuf:"varint,7,opt,name=discount_minor,json=discountMinor,proto3" json:"discount_minor,omitempty"` // what the promo code takes off
+	PromoCode     string                 `protobuf:"bytes,8,opt,name=promo_code,json=promoCode,proto3" json:"promo_code,omitempty"`              // the code the discount comes from
+	PromoError    string                 `protobuf:"bytes,9,opt,name=promo_error,json=promoError,proto3" json:"promo_error,omitempty"`           // why the cart's promo code no longer applies, the cart is then not discounted
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PricedCartResponse) Reset() {
+	*x = PricedCartResponse{}
+	mi := &file_pkg_proto_cart_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PricedCartResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PricedCartResponse) ProtoMessage() {}
+
+func (x *PricedCartResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_cart_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PricedCartResponse.ProtoReflect.Descriptor instead.
+func (*PricedCartResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_cart_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *PricedCartResponse) GetCart() *Cart {
+	if x != nil {
+		return x.Cart
+	}
+	return nil
+}
+
+func (x *PricedCartResponse) GetItems() []*PricedCartItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *PricedCartResponse) GetTotalMinor() int64 {
+	if x != nil {
+		return x.TotalMinor
+	}
+	return 0
+}
+
+func (x *PricedCartResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *PricedCartResponse) GetItemCount() int32 {
+	if x != nil {
+		return x.ItemCount
+	}
+	return 0
+}
+
+func (x *PricedCartResponse) GetSubtotalMinor() int64 {
+	if x != nil {
+		return x.SubtotalMinor
+	}
+	return 0
+}
+
+func (x *PricedCartResponse) GetDiscountMinor() int64 {
+	if x != nil {
+		return x.DiscountMinor
+	}
+	return 0
+}
+
+func (x *PricedCartResponse) GetPromoCode() string {
+	if x != nil {
+		return x.PromoCode
+	}
+	return ""
+}
+
+func (x *PricedCartResponse) GetPromoError() string {
+	if x != nil {
+		return x.PromoError
+	}
+	return ""
+}
+
+var File_pkg_proto_cart_proto protoreflect.FileDescriptor
+
+const file_pkg_proto_cart_proto_rawDesc = "" +
+	"\n" +
+	"\x14pkg/proto/cart.proto\x12\x04cart\"\xf6\x01\n" +
+	"\bCartItem\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x03R\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\x12\x19\n" +
+	"\badded_at\x18\x03 \x01(\tR\aaddedAt\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x04 \x01(\x03R\tvariantId\x12\x10\n" +
+	"\x03sku\x18\x05 \x01(\tR\x03sku\x124\n" +
+	"\fstock_status\x18\x06 \x01(\x0e2\x11.cart.StockStatusR\vstockStatus\x12-\n" +
+	"\x12available_quantity\x18\a \x01(\x05R\x11availableQuantity\"\xe5\x01\n" +
+	"\x04Cart\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x03R\x06userId\x12\"\n" +
+	"\x04cart\x18\x03 \x03(\v2\x0e.cart.CartItemR\x04cart\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\tR\tupdatedAt\x12\x19\n" +
+	"\bguest_id\x18\x06 \x01(\tR\aguestId\x12\x1d\n" +
+	"\n" +
+	"promo_code\x18\a \x01(\tR\tpromoCode\x12\x18\n" +
+	"\aversion\x18\b \x01(\x03R\aversion\"\xe7\x01\n" +
+	"\x12AddCartItemRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\x03R\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x04 \x01(\x03R\tvariantId\x12\x19\n" +
+	"\bguest_id\x18\x05 \x01(\tR\aguestId\x12.\n" +
+	"\x10expected_version\x18\x06 \x01(\x03H\x00R\x0fexpectedVersion\x88\x01\x01B\x13\n" +
+	"\x11_expected_version\"D\n" +
+	"\x0eGetCartRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x19\n" +
+	"\bguest_id\x18\x02 \x01(\tR\aguestId\"\xea\x01\n" +
+	"\x15UpdateQuantityRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\x03R\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x04 \x01(\x03R\tvariantId\x12\x19\n" +
+	"\bguest_id\x18\x05 \x01(\tR\aguestId\x12.\n" +
+	"\x10expected_version\x18\x06 \x01(\x03H\x00R\x0fexpectedVersion\x88\x01\x01B\x13\n" +
+	"\x11_expected_version\"\xca\x01\n" +
+	"\x11RemoveItemRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\x03R\tproductId\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x03 \x01(\x03R\tvariantId\x12\x19\n" +
+	"\bguest_id\x18\x04 \x01(\tR\aguestId\x12.\n" +
+	"\x10expected_version\x18\x05 \x01(\x03H\x00R\x0fexpectedVersion\x88\x01\x01B\x13\n" +
+	"\x11_expected_version\"\x8b\x01\n" +
+	"\x10ClearCartRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x19\n" +
+	"\bguest_id\x18\x02 \x01(\tR\aguestId\x12.\n" +
+	"\x10expected_version\x18\x03 \x01(\x03H\x00R\x0fexpectedVersion\x88\x01\x01B\x13\n" +
+	"\x11_expected_version\"i\n" +
+	"\rCartItemInput\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x03R\tproductId\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x02 \x01(\x03R\tvariantId\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\"\xb5\x01\n" +
+	"\x0fAddItemsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x19\n" +
+	"\bguest_id\x18\x02 \x01(\tR\aguestId\x12)\n" +
+	"\x05items\x18\x03 \x03(\v2\x13.cart.CartItemInputR\x05items\x12.\n" +
+	"\x10expected_version\x18\x04 \x01(\x03H\x00R\x0fexpectedVersion\x88\x01\x01B\x13\n" +
+	"\x11_expected_version\"\xb8\x01\n" +
+	"\x12ReplaceCartRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x19\n" +
+	"\bguest_id\x18\x02 \x01(\tR\aguestId\x12)\n" +
+	"\x05items\x18\x03 \x03(\v2\x13.cart.CartItemInputR\x05items\x12.\n" +
+	"\x10expected_version\x18\x04 \x01(\x03H\x00R\x0fexpectedVersion\x88\x01\x01B\x13\n" +
+	"\x11_expected_version\"\x8c\x01\n" +
+	"\x11MergeCartsRequest\x12\x19\n" +
+	"\bguest_id\x18\x01 \x01(\tR\aguestId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x03R\x06userId\x12.\n" +
+	"\x10expected_version\x18\x03 \x01(\x03H\x00R\x0fexpectedVersion\x88\x01\x01B\x13\n" +
+	"\x11_expected_version\"\xa4\x01\n" +
+	"\x15ApplyPromoCodeRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x19\n" +
+	"\bguest_id\x18\x02 \x01(\tR\aguestId\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\tR\x04code\x12.\n" +
+	"\x10expected_version\x18\x04 \x01(\x03H\x00R\x0fexpectedVersion\x88\x01\x01B\x13\n" +
+	"\x11_expected_version\"\x91\x01\n" +
+	"\x16RemovePromoCodeRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x19\n" +
+	"\bguest_id\x18\x02 \x01(\tR\aguestId\x12.\n" +
+	"\x10expected_version\x18\x03 \x01(\x03H\x00R\x0fexpectedVersion\x88\x01\x01B\x13\n" +
+	"\x11_expected_version\"E\n" +
+	"\tSavedList\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12$\n" +
+	"\x05items\x18\x02 \x03(\v2\x0e.cart.CartItemR\x05items\"/\n" +
+	"\x14GetSavedItemsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\"\xc5\x01\n" +
+	"\x13SaveForLaterRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\x03R\tproductId\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x03 \x01(\x03R\tvariantId\x12\x12\n" +
+	"\x04list\x18\x04 \x01(\tR\x04list\x12.\n" +
+	"\x10expected_version\x18\x05 \x01(\x03H\x00R\x0fexpectedVersion\x88\x01\x01B\x13\n" +
+	"\x11_expected_version\"\xc3\x01\n" +
+	"\x11MoveToCartRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\x03R\tproductId\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x03 \x01(\x03R\tvariantId\x12\x12\n" +
+	"\x04list\x18\x04 \x01(\tR\x04list\x12.\n" +
+	"\x10expected_version\x18\x05 \x01(\x03H\x00R\x0fexpectedVersion\x88\x01\x01B\x13\n" +
+	"\x11_expected_version\"\xc8\x01\n" +
+	"\x16RemoveSavedItemRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\x03R\tproductId\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x03 \x01(\x03R\tvariantId\x12\x12\n" +
+	"\x04list\x18\x04 \x01(\tR\x04list\x12.\n" +
+	"\x10expected_version\x18\x05 \x01(\x03H\x00R\x0fexpectedVersion\x88\x01\x01B\x13\n" +
+	"\x11_expected_version\"\x8a\x01\n" +
+	"\x16DeleteSavedListRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x12\n" +
+	"\x04list\x18\x02 \x01(\tR\x04list\x12.\n" +
+	"\x10expected_version\x18\x03 \x01(\x03H\x00R\x0fexpectedVersion\x88\x01\x01B\x13\n" +
+	"\x11_expected_version\"[\n" +
+	"\x12SavedItemsResponse\x12\x1e\n" +
+	"\x04cart\x18\x01 \x01(\v2\n" +
+	".cart.CartR\x04cart\x12%\n" +
+	"\x05lists\x18\x02 \x03(\v2\x0f.cart.SavedListR\x05lists\".\n" +
+	"\fCartResponse\x12\x1e\n" +
+	"\x04cart\x18\x01 \x01(\v2\n" +
+	".cart.CartR\x04cart\"\xa3\x04\n" +
+	"\x0ePricedCartItem\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x03R\tproductId\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x02 \x01(\x03R\tvariantId\x12\x10\n" +
+	"\x03sku\x18\x03 \x01(\tR\x03sku\x12\x1a\n" +
+	"\bquantity\x18\x04 \x01(\x05R\bquantity\x12\x19\n" +
+	"\badded_at\x18\x05 \x01(\tR\aaddedAt\x12!\n" +
+	"\fproduct_name\x18\x06 \x01(\tR\vproductName\x12!\n" +
+	"\fvariant_name\x18\a \x01(\tR\vvariantName\x12\x1b\n" +
+	"\timage_url\x18\b \x01(\tR\bimageUrl\x12(\n" +
+	"\x10unit_price_minor\x18\t \x01(\x03R\x0eunitPriceMinor\x12%\n" +
+	"\x0esubtotal_minor\x18\n" +
+	" \x01(\x03R\rsubtotalMinor\x12\x1a\n" +
+	"\bcurrency\x18\v \x01(\tR\bcurrency\x12.\n" +
+	"\x06status\x18\f \x01(\x0e2\x16.cart.PricedItemStatusR\x06status\x124\n" +
+	"\fstock_status\x18\r \x01(\x0e2\x11.cart.StockStatusR\vstockStatus\x12-\n" +
+	"\x12available_quantity\x18\x0e \x01(\x05R\x11availableQuantity\x12%\n" +
+	"\x0ediscount_minor\x18\x0f \x01(\x03R\rdiscountMinor\"\xca\x02\n" +
+	"\x12PricedCartResponse\x12\x1e\n" +
+	"\x04cart\x18\x01 \x01(\v2\n" +
+	".cart.CartR\x04cart\x12*\n" +
+	"\x05items\x18\x02 \x03(\v2\x14.cart.PricedCartItemR\x05items\x12\x1f\n" +
+	"\vtotal_minor\x18\x03 \x01(\x03R\n" +
+	"totalMinor\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12\x1d\n" +
+	"\n" +
+	"item_count\x18\x05 \x01(\x05R\titemCount\x12%\n" +
+	"\x0esubtotal_minor\x18\x06 \x01(\x03R\rsubtotalMinor\x12%\n" +
+	"\x0ediscount_minor\x18\a \x01(\x03R\rdiscountMinor\x12\x1d\n" +
+	"\n" +
+	"promo_code\x18\b \x01(\tR\tpromoCode\x12\x1f\n" +
+	"\vpromo_error\x18\t \x01(\tR\n" +
+	"promoError*}\n" +
+	"\vStockStatus\x12\x18\n" +
+	"\x14STOCK_STATUS_UNKNOWN\x10\x00\x12\x19\n" +
+	"\x15STOCK_STATUS_IN_STOCK\x10\x01\x12\x1a\n" +
+	"\x16STOCK_STATUS_LOW_STOCK\x10\x02\x12\x1d\n" +
+	"\x19STOCK_STATUS_INSUFFICIENT\x10\x03*\x82\x01\n" +
+	"\x10PricedItemStatus\x12 \n" +
+	"\x1cPRICED_ITEM_STATUS_AVAILABLE\x10\x00\x12(\n" +
+	"$PRICED_ITEM_STATUS_PRODUCT_NOT_FOUND\x10\x01\x12\"\n" +
+	"\x1ePRICED_ITEM_STATUS_UNAVAILABLE\x10\x022\x99\b\n" +
+	"\vCartService\x127\n" +
+	"\aAddItem\x12\x18.cart.AddCartItemRequest\x1a\x12.cart.CartResponse\x125\n" +
+	"\bAddItems\x12\x15.cart.AddItemsRequest\x1a\x12.cart.CartResponse\x12;\n" +
+	"\vReplaceCart\x12\x18.cart.ReplaceCartRequest\x1a\x12.cart.CartResponse\x123\n" +
+	"\aGetCart\x12\x14.cart.GetCartRequest\x1a\x12.cart.CartResponse\x12?\n" +
+	"\rGetPricedCart\x12\x14.cart.GetCartRequest\x1a\x18.cart.PricedCartResponse\x12A\n" +
+	"\x0eUpdateQuantity\x12\x1b.cart.UpdateQuantityRequest\x1a\x12.cart.CartResponse\x129\n" +
+	"\n" +
+	"RemoveItem\x12\x17.cart.RemoveItemRequest\x1a\x12.cart.CartResponse\x127\n" +
+	"\tClearCart\x12\x16.cart.ClearCartRequest\x1a\x12.cart.CartResponse\x129\n" +
+	"\n" +
+	"MergeCarts\x12\x17.cart.MergeCartsRequest\x1a\x12.cart.CartResponse\x12G\n" +
+	"\x0eApplyPromoCode\x12\x1b.cart.ApplyPromoCodeRequest\x1a\x18.cart.PricedCartResponse\x12I\n" +
+	"\x0fRemovePromoCode\x12\x1c.cart.RemovePromoCodeRequest\x1a\x18.cart.PricedCartResponse\x12E\n" +
+	"\rGetSavedItems\x12\x1a.cart.GetSavedItemsRequest\x1a\x18.cart.SavedItemsResponse\x12C\n" +
+	"\fSaveForLater\x12\x19.cart.SaveForLaterRequest\x1a\x18.cart.SavedItemsResponse\x12?\n" +
+	"\n" +
+	"MoveToCart\x12\x17.cart.MoveToCartRequest\x1a\x18.cart.SavedItemsResponse\x12I\n" +
+	"\x0fRemoveSavedItem\x12\x1c.cart.RemoveSavedItemRequest\x1a\x18.cart.SavedItemsResponse\x12I\n" +
+	"\x0fDeleteSavedList\x12\x1c.cart.DeleteSavedListRequest\x1a\x18.cart.SavedItemsResponseB0Z.github.com/fjod/go_cart/cart-service/pkg/protob\x06proto3"
 
 var (
 	file_pkg_proto_cart_proto_rawDescOnce sync.Once
@@ -526,35 +2035,85 @@ func file_pkg_proto_cart_proto_rawDescGZIP() []byte {
 	return file_pkg_proto_cart_proto_rawDescData
 }
 
-var file_pkg_proto_cart_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_pkg_proto_cart_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_pkg_proto_cart_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
 var file_pkg_proto_cart_proto_goTypes = []any{
-	(*CartItem)(nil),              // 0: cart.CartItem
-	(*Cart)(nil),                  // 1: cart.Cart
-	(*AddCartItemRequest)(nil),    // 2: cart.AddCartItemRequest
-	(*GetCartRequest)(nil),        // 3: cart.GetCartRequest
-	(*UpdateQuantityRequest)(nil), // 4: cart.UpdateQuantityRequest
-	(*RemoveItemRequest)(nil),     // 5: cart.RemoveItemRequest
-	(*ClearCartRequest)(nil),      // 6: cart.ClearCartRequest
-	(*CartResponse)(nil),          // 7: cart.CartResponse
+	(StockStatus)(0),               // 0: cart.StockStatus
+	(PricedItemStatus)(0),          // 1: cart.PricedItemStatus
+	(*CartItem)(nil),               // 2: cart.CartItem
+	(*Cart)(nil),                   // 3: cart.Cart
+	(*AddCartItemRequest)(nil),     // 4: cart.AddCartItemRequest
+	(*GetCartRequest)(nil),         // 5: cart.GetCartRequest
+	(*UpdateQuantityRequest)(nil),  // 6: cart.UpdateQuantityRequest
+	(*RemoveItemRequest)(nil),      // 7: cart.RemoveItemRequest
+	(*ClearCartRequest)(nil),       // 8: cart.ClearCartRequest
+	(*CartItemInput)(nil),          // 9: cart.CartItemInput
+	(*AddItemsRequest)(nil),        // 10: cart.AddItemsRequest
+	(*ReplaceCartRequest)(nil),     // 11: cart.ReplaceCartRequest
+	(*MergeCartsRequest)(nil),      // 12: cart.MergeCartsRequest
+	(*ApplyPromoCodeRequest)(nil),  // 13: cart.ApplyPromoCodeRequest
+	(*RemovePromoCodeRequest)(nil), // 14: cart.RemovePromoCodeRequest
+	(*SavedList)(nil),              // 15: cart.SavedList
+	(*GetSavedItemsRequest)(nil),   // 16: cart.GetSavedItemsRequest
+	(*SaveForLaterRequest)(nil),    // 17: cart.SaveForLaterRequest
+	(*MoveToCartRequest)(nil),      // 18: cart.MoveToCartRequest
+	(*RemoveSavedItemRequest)(nil), // 19: cart.RemoveSavedItemRequest
+	(*DeleteSavedListRequest)(nil), // 20: cart.DeleteSavedListRequest
+	(*SavedItemsResponse)(nil),     // 21: cart.SavedItemsResponse
+	(*CartResponse)(nil),           // 22: cart.CartResponse
+	(*PricedCartItem)(nil),         // 23: cart.PricedCartItem
+	(*PricedCartResponse)(nil),     // 24: cart.PricedCartResponse
 }
 var file_pkg_proto_cart_proto_depIdxs = []int32{
-	0, // 0: cart.Cart.cart:type_name -> cart.CartItem
-	1, // 1: cart.CartResponse.cart:type_name -> cart.Cart
-	2, // 2: cart.CartService.AddItem:input_type -> cart.AddCartItemRequest
-	3, // 3: cart.CartService.GetCart:input_type -> cart.GetCartRequest
-	4, // 4: cart.CartService.UpdateQuantity:input_type -> cart.UpdateQuantityRequest
-	5, // 5: cart.CartService.RemoveItem:input_type -> cart.RemoveItemRequest
-	6, // 6: cart.CartService.ClearCart:input_type -> cart.ClearCartRequest
-	7, // 7: cart.CartService.AddItem:output_type -> cart.CartResponse
-	7, // 8: cart.CartService.GetCart:output_type -> cart.CartResponse
-	7, // 9: cart.CartService.UpdateQuantity:output_type -> cart.CartResponse
-	7, // 10: cart.CartService.RemoveItem:output_type -> cart.CartResponse
-	7, // 11: cart.CartService.ClearCart:output_type -> cart.CartResponse
-	7, // [7:12] is the sub-list for method output_type
-	2, // [2:7] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	0,  // 0: cart.CartItem.stock_status:type_name -> cart.StockStatus
+	2,  // 1: cart.Cart.cart:type_name -> cart.CartItem
+	9,  // 2: cart.AddItemsRequest.items:type_name -> cart.CartItemInput
+	9,  // 3: cart.ReplaceCartRequest.items:type_name -> cart.CartItemInput
+	2,  // 4: cart.SavedList.items:type_name -> cart.CartItem
+	3,  // 5: cart.SavedItemsResponse.cart:type_name -> cart.Cart
+	15, // 6: cart.SavedItemsResponse.lists:type_name -> cart.SavedList
+	3,  // 7: cart.CartResponse.cart:type_name -> cart.Cart
+	1,  // 8: cart.PricedCartItem.status:type_name -> cart.PricedItemStatus
+	0,  // 9: cart.PricedCartItem.stock_status:type_name -> cart.StockStatus
+	3,  // 10: cart.PricedCartResponse.cart:type_name -> cart.Cart
+	23, // 11: cart.PricedCartResponse.items:type_name -> cart.PricedCartItem
+	4,  // 12: cart.CartService.AddItem:input_type -> cart.AddCartItemRequest
+	10, // 13: cart.CartService.AddItems:input_type -> cart.AddItemsRequest
+	11, // 14: cart.CartService.ReplaceCart:input_type -> cart.ReplaceCartRequest
+	5,  // 15: cart.CartService.GetCart:input_type -> cart.GetCartRequest
+	5,  // 16: cart.CartService.GetPricedCart:input_type -> cart.GetCartRequest
+	6,  // 17: cart.CartService.UpdateQuantity:input_type -> cart.UpdateQuantityRequest
+	7,  // 18: cart.CartService.RemoveItem:input_type -> cart.RemoveItemRequest
+	8,  // 19: cart.CartService.ClearCart:input_type -> cart.ClearCartRequest
+	12, // 20: cart.CartService.MergeCarts:input_type -> cart.MergeCartsRequest
+	13, // 21: cart.CartService.ApplyPromoCode:input_type -> cart.ApplyPromoCodeRequest
+	14, // 22: cart.CartService.RemovePromoCode:input_type -> cart.RemovePromoCodeRequest
+	16, // 23: cart.CartService.GetSavedItems:input_type -> cart.GetSavedItemsRequest
+	17, // 24: cart.CartService.SaveForLater:input_type -> cart.SaveForLaterRequest
+	18, // 25: cart.CartService.MoveToCart:input_type -> cart.MoveToCartRequest
+	19, // 26: cart.CartService.RemoveSavedItem:input_type -> cart.RemoveSavedItemRequest
+	20, // 27: cart.CartService.DeleteSavedList:input_type -> cart.DeleteSavedListRequest
+	22, // 28: cart.CartService.AddItem:output_type -> cart.CartResponse
+	22, // 29: cart.CartService.AddItems:output_type -> cart.CartResponse
+	22, // 30: cart.CartService.ReplaceCart:output_type -> cart.CartResponse
+	22, // 31: cart.CartService.GetCart:output_type -> cart.CartResponse
+	24, // 32: cart.CartService.GetPricedCart:output_type -> cart.PricedCartResponse
+	22, // 33: cart.CartService.UpdateQuantity:output_type -> cart.CartResponse
+	22, // 34: cart.CartService.RemoveItem:output_type -> cart.CartResponse
+	22, // 35: cart.CartService.ClearCart:output_type -> cart.CartResponse
+	22, // 36: cart.CartService.MergeCarts:output_type -> cart.CartResponse
+	24, // 37: cart.CartService.ApplyPromoCode:output_type -> cart.PricedCartResponse
+	24, // 38: cart.CartService.RemovePromoCode:output_type -> cart.PricedCartResponse
+	21, // 39: cart.CartService.GetSavedItems:output_type -> cart.SavedItemsResponse
+	21, // 40: cart.CartService.SaveForLater:output_type -> cart.SavedItemsResponse
+	21, // 41: cart.CartService.MoveToCart:output_type -> cart.SavedItemsResponse
+	21, // 42: cart.CartService.RemoveSavedItem:output_type -> cart.SavedItemsResponse
+	21, // 43: cart.CartService.DeleteSavedList:output_type -> cart.SavedItemsResponse
+	28, // [28:44] is the sub-list for method output_type
+	12, // [12:28] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
 }
 
 func init() { file_pkg_proto_cart_proto_init() }
@@ -562,18 +2121,32 @@ func file_pkg_proto_cart_proto_init() {
 	if File_pkg_proto_cart_proto != nil {
 		return
 	}
+	file_pkg_proto_cart_proto_msgTypes[2].OneofWrappers = []any{}
+	file_pkg_proto_cart_proto_msgTypes[4].OneofWrappers = []any{}
+	file_pkg_proto_cart_proto_msgTypes[5].OneofWrappers = []any{}
+	file_pkg_proto_cart_proto_msgTypes[6].OneofWrappers = []any{}
+	file_pkg_proto_cart_proto_msgTypes[8].OneofWrappers = []any{}
+	file_pkg_proto_cart_proto_msgTypes[9].OneofWrappers = []any{}
+	file_pkg_proto_cart_proto_msgTypes[10].OneofWrappers = []any{}
+	file_pkg_proto_cart_proto_msgTypes[11].OneofWrappers = []any{}
+	file_pkg_proto_cart_proto_msgTypes[12].OneofWrappers = []any{}
+	file_pkg_proto_cart_proto_msgTypes[15].OneofWrappers = []any{}
+	file_pkg_proto_cart_proto_msgTypes[16].OneofWrappers = []any{}
+	file_pkg_proto_cart_proto_msgTypes[17].OneofWrappers = []any{}
+	file_pkg_proto_cart_proto_msgTypes[18].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_proto_cart_proto_rawDesc), len(file_pkg_proto_cart_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   8,
+			NumEnums:      2,
+			NumMessages:   23,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_pkg_proto_cart_proto_goTypes,
 		DependencyIndexes: file_pkg_proto_cart_proto_depIdxs,
+		EnumInfos:         file_pkg_proto_cart_proto_enumTypes,
 		MessageInfos:      file_pkg_proto_cart_proto_msgTypes,
 	}.Build()
 	File_pkg_proto_cart_proto = out.File
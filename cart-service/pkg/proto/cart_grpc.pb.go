@@ -19,11 +19,22 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	CartService_AddItem_FullMethodName        = "/cart.CartService/AddItem"
-	CartService_GetCart_FullMethodName        = "/cart.CartService/GetCart"
-	CartService_UpdateQuantity_FullMethodName = "/cart.CartService/UpdateQuantity"
-	CartService_RemoveItem_FullMethodName     = "/cart.CartService/RemoveItem"
-	CartService_ClearCart_FullMethodName      = "/cart.CartService/ClearCart"
+	CartService_AddItem_FullMethodName         = "/cart.CartService/AddItem"
+	CartService_AddItems_FullMethodName        = "/cart.CartService/AddItems"
+	CartService_ReplaceCart_FullMethodName     = "/cart.CartService/ReplaceCart"
+	CartService_GetCart_FullMethodName         = "/cart.CartService/GetCart"
+	CartService_GetPricedCart_FullMethodName   = "/cart.CartService/GetPricedCart"
+	CartService_UpdateQuantity_FullMethodName  = "/cart.CartService/UpdateQuantity"
+	CartService_RemoveItem_FullMethodName      = "/cart.CartService/RemoveItem"
+	CartService_ClearCart_FullMethodName       = "/cart.CartService/ClearCart"
+	CartService_MergeCarts_FullMethodName      = "/cart.CartService/MergeCarts"
+	CartService_ApplyPromoCode_FullMethodName  = "/cart.CartService/ApplyPromoCode"
+	CartService_RemovePromoCode_FullMethodName = "/cart.CartService/RemovePromoCode"
+	CartService_GetSavedItems_FullMethodName   = "/cart.CartService/GetSavedItems"
+	CartService_SaveForLater_FullMethodName    = "/cart.CartService/SaveForLater"
+	CartService_MoveToCart_FullMethodName      = "/cart.CartService/MoveToCart"
+	CartService_RemoveSavedItem_FullMethodName = "/cart.CartService/RemoveSavedItem"
+	CartService_DeleteSavedList_FullMethodName = "/cart.CartService/DeleteSavedList"
 )
 
 // CartServiceClient is the client API for CartService service.
@@ -33,10 +44,21 @@ const (
 // Cart service definition
 type CartServiceClient interface {
 	AddItem(ctx context.Context, in *AddCartItemRequest, opts ...grpc.CallOption) (*CartResponse, error)
+	AddItems(ctx context.Context, in *AddItemsRequest, opts ...grpc.CallOption) (*CartResponse, error)
+	ReplaceCart(ctx context.Context, in *ReplaceCartRequest, opts ...grpc.CallOption) (*CartResponse, error)
 	GetCart(ctx context.Context, in *GetCartRequest, opts ...grpc.CallOption) (*CartResponse, error)
+	GetPricedCart(ctx context.Context, in *GetCartRequest, opts ...grpc.CallOption) (*PricedCartResponse, error)
 	UpdateQuantity(ctx context.Context, in *UpdateQuantityRequest, opts ...grpc.CallOption) (*CartResponse, error)
 	RemoveItem(ctx context.Context, in *RemoveItemRequest, opts ...grpc.CallOption) (*CartResponse, error)
 	ClearCart(ctx context.Context, in *ClearCartRequest, opts ...grpc.CallOption) (*CartResponse, error)
+	MergeCarts(ctx context.Context, in *MergeCartsRequest, opts ...grpc.CallOption) (*CartResponse, error)
+	ApplyPromoCode(ctx context.Context, in *ApplyPromoCodeRequest, opts ...grpc.CallOption) (*PricedCartResponse, error)
+	RemovePromoCode(ctx context.Context, in *RemovePromoCodeRequest, opts ...grpc.CallOption) (*PricedCartResponse, error)
+	GetSavedItems(ctx context.Context, in *GetSavedItemsRequest, opts ...grpc.CallOption) (*SavedItemsResponse, error)
+	SaveForLater(ctx context.Context, in *SaveForLaterRequest, opts ...grpc.CallOption) (*SavedItemsResponse, error)
+	MoveToCart(ctx context.Context, in *MoveToCartRequest, opts ...grpc.CallOption) (*SavedItemsResponse, error)
+	RemoveSavedItem(ctx context.Context, in *RemoveSavedItemRequest, opts ...grpc.CallOption) (*SavedItemsResponse, error)
+	DeleteSavedList(ctx context.Context, in *DeleteSavedListRequest, opts ...grpc.CallOption) (*SavedItemsResponse, error)
 }
 
 type cartServiceClient struct {
@@ -57,6 +79,26 @@ func (c *cartServiceClient) AddItem(ctx context.Context, in *AddCartItemRequest,
 	return out, nil
 }
 
+func (c *cartServiceClient) AddItems(ctx context.Context, in *AddItemsRequest, opts ...grpc.CallOption) (*CartResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CartResponse)
+	err := c.cc.Invoke(ctx, CartService_AddItems_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartServiceClient) ReplaceCart(ctx context.Context, in *ReplaceCartRequest, opts ...grpc.CallOption) (*CartResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CartResponse)
+	err := c.cc.Invoke(ctx, CartService_ReplaceCart_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *cartServiceClient) GetCart(ctx context.Context, in *GetCartRequest, opts ...grpc.CallOption) (*CartResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(CartResponse)
@@ -67,6 +109,16 @@ func (c *cartServiceClient) GetCart(ctx context.Context, in *GetCartRequest, opt
 	return out, nil
 }
 
+func (c *cartServiceClient) GetPricedCart(ctx context.Context, in *GetCartRequest, opts ...grpc.CallOption) (*PricedCartResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PricedCartResponse)
+	err := c.cc.Invoke(ctx, CartService_GetPricedCart_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *cartServiceClient) UpdateQuantity(ctx context.Context, in *UpdateQuantityRequest, opts ...grpc.CallOption) (*CartResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(CartResponse)
@@ -97,6 +149,86 @@ func (c *cartServiceClient) ClearCart(ctx context.Context, in *ClearCartRequest,
 	return out, nil
 }
 
+func (c *cartServiceClient) MergeCarts(ctx context.Context, in *MergeCartsRequest, opts ...grpc.CallOption) (*CartResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CartResponse)
+	err := c.cc.Invoke(ctx, CartService_MergeCarts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartServiceClient) ApplyPromoCode(ctx context.Context, in *ApplyPromoCodeRequest, opts ...grpc.CallOption) (*PricedCartResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PricedCartResponse)
+	err := c.cc.Invoke(ctx, CartService_ApplyPromoCode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartServiceClient) RemovePromoCode(ctx context.Context, in *RemovePromoCodeRequest, opts ...grpc.CallOption) (*PricedCartResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PricedCartResponse)
+	err := c.cc.Invoke(ctx, CartService_RemovePromoCode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartServiceClient) GetSavedItems(ctx context.Context, in *GetSavedItemsRequest, opts ...grpc.CallOption) (*SavedItemsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SavedItemsResponse)
+	err := c.cc.Invoke(ctx, CartService_GetSavedItems_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartServiceClient) SaveForLater(ctx context.Context, in *SaveForLaterRequest, opts ...grpc.CallOption) (*SavedItemsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SavedItemsResponse)
+	err := c.cc.Invoke(ctx, CartService_SaveForLater_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartServiceClient) MoveToCart(ctx context.Context, in *MoveToCartRequest, opts ...grpc.CallOption) (*SavedItemsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SavedItemsResponse)
+	err := c.cc.Invoke(ctx, CartService_MoveToCart_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartServiceClient) RemoveSavedItem(ctx context.Context, in *RemoveSavedItemRequest, opts ...grpc.CallOption) (*SavedItemsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SavedItemsResponse)
+	err := c.cc.Invoke(ctx, CartService_RemoveSavedItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartServiceClient) DeleteSavedList(ctx context.Context, in *DeleteSavedListRequest, opts ...grpc.CallOption) (*SavedItemsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SavedItemsResponse)
+	err := c.cc.Invoke(ctx, CartService_DeleteSavedList_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CartServiceServer is the server API for CartService service.
 // All implementations must embed UnimplementedCartServiceServer
 // for forward compatibility.
@@ -104,10 +236,21 @@ func (c *cartServiceClient) ClearCart(ctx context.Context, in *ClearCartRequest,
 // Cart service definition
 type CartServiceServer interface {
 	AddItem(context.Context, *AddCartItemRequest) (*CartResponse, error)
+	AddItems(context.Context, *AddItemsRequest) (*CartResponse, error)
+	ReplaceCart(context.Context, *ReplaceCartRequest) (*CartResponse, error)
 	GetCart(context.Context, *GetCartRequest) (*CartResponse, error)
+	GetPricedCart(context.Context, *GetCartRequest) (*PricedCartResponse, error)
 	UpdateQuantity(context.Context, *UpdateQuantityRequest) (*CartResponse, error)
 	RemoveItem(context.Context, *RemoveItemRequest) (*CartResponse, error)
 	ClearCart(context.Context, *ClearCartRequest) (*CartResponse, error)
+	MergeCarts(context.Context, *MergeCartsRequest) (*CartResponse, error)
+	ApplyPromoCode(context.Context, *ApplyPromoCodeRequest) (*PricedCartResponse, error)
+	RemovePromoCode(context.Context, *RemovePromoCodeRequest) (*PricedCartResponse, error)
+	GetSavedItems(context.Context, *GetSavedItemsRequest) (*SavedItemsResponse, error)
+	SaveForLater(context.Context, *SaveForLaterRequest) (*SavedItemsResponse, error)
+	MoveToCart(context.Context, *MoveToCartRequest) (*SavedItemsResponse, error)
+	RemoveSavedItem(context.Context, *RemoveSavedItemRequest) (*SavedItemsResponse, error)
+	DeleteSavedList(context.Context, *DeleteSavedListRequest) (*SavedItemsResponse, error)
 	mustEmbedUnimplementedCartServiceServer()
 }
 
@@ -121,9 +264,18 @@ type UnimplementedCartServiceServer struct{}
 func (UnimplementedCartServiceServer) AddItem(context.Context, *AddCartItemRequest) (*CartResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method AddItem not implemented")
 }
+func (UnimplementedCartServiceServer) AddItems(context.Context, *AddItemsRequest) (*CartResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AddItems not implemented")
+}
+func (UnimplementedCartServiceServer) ReplaceCart(context.Context, *ReplaceCartRequest) (*CartResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReplaceCart not implemented")
+}
 func (UnimplementedCartServiceServer) GetCart(context.Context, *GetCartRequest) (*CartResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetCart not implemented")
 }
+func (UnimplementedCartServiceServer) GetPricedCart(context.Context, *GetCartRequest) (*PricedCartResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetPricedCart not implemented")
+}
 func (UnimplementedCartServiceServer) UpdateQuantity(context.Context, *UpdateQuantityRequest) (*CartResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method UpdateQuantity not implemented")
 }
@@ -133,6 +285,30 @@ func (UnimplementedCartServiceServer) RemoveItem(context.Context, *RemoveItemReq
 func (UnimplementedCartServiceServer) ClearCart(context.Context, *ClearCartRequest) (*CartResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ClearCart not implemented")
 }
+func (UnimplementedCartServiceServer) MergeCarts(context.Context, *MergeCartsRequest) (*CartResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method MergeCarts not implemented")
+}
+func (UnimplementedCartServiceServer) ApplyPromoCode(context.Context, *ApplyPromoCodeRequest) (*PricedCartResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ApplyPromoCode not implemented")
+}
+func (UnimplementedCartServiceServer) RemovePromoCode(context.Context, *RemovePromoCodeRequest) (*PricedCartResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RemovePromoCode not implemented")
+}
+func (UnimplementedCartServiceServer) GetSavedItems(context.Context, *GetSavedItemsRequest) (*SavedItemsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetSavedItems not implemented")
+}
+func (UnimplementedCartServiceServer) SaveForLater(context.Context, *SaveForLaterRequest) (*SavedItemsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SaveForLater not implemented")
+}
+func (UnimplementedCartServiceServer) MoveToCart(context.Context, *MoveToCartRequest) (*SavedItemsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method MoveToCart not implemented")
+}
+func (UnimplementedCartServiceServer) RemoveSavedItem(context.Context, *RemoveSavedItemRequest) (*SavedItemsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RemoveSavedItem not implemented")
+}
+func (UnimplementedCartServiceServer) DeleteSavedList(context.Context, *DeleteSavedListRequest) (*SavedItemsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteSavedList not implemented")
+}
 func (UnimplementedCartServiceServer) mustEmbedUnimplementedCartServiceServer() {}
 func (UnimplementedCartServiceServer) testEmbeddedByValue()                     {}
 
@@ -172,6 +348,42 @@ func _CartService_AddItem_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CartService_AddItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServiceServer).AddItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CartService_AddItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServiceServer).AddItems(ctx, req.(*AddItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CartService_ReplaceCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplaceCartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServiceServer).ReplaceCart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CartService_ReplaceCart_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServiceServer).ReplaceCart(ctx, req.(*ReplaceCartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _CartService_GetCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetCartRequest)
 	if err := dec(in); err != nil {
@@ -190,6 +402,24 @@ func _CartService_GetCart_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CartService_GetPricedCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServiceServer).GetPricedCart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CartService_GetPricedCart_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServiceServer).GetPricedCart(ctx, req.(*GetCartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _CartService_UpdateQuantity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UpdateQuantityRequest)
 	if err := dec(in); err != nil {
@@ -244,6 +474,150 @@ func _CartService_ClearCart_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CartService_MergeCarts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergeCartsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServiceServer).MergeCarts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CartService_MergeCarts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServiceServer).MergeCarts(ctx, req.(*MergeCartsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CartService_ApplyPromoCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyPromoCodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServiceServer).ApplyPromoCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CartService_ApplyPromoCode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServiceServer).ApplyPromoCode(ctx, req.(*ApplyPromoCodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CartService_RemovePromoCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemovePromoCodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServiceServer).RemovePromoCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CartService_RemovePromoCode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServiceServer).RemovePromoCode(ctx, req.(*RemovePromoCodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CartService_GetSavedItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSavedItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServiceServer).GetSavedItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CartService_GetSavedItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServiceServer).GetSavedItems(ctx, req.(*GetSavedItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CartService_SaveForLater_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveForLaterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServiceServer).SaveForLater(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CartService_SaveForLater_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServiceServer).SaveForLater(ctx, req.(*SaveForLaterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CartService_MoveToCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveToCartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServiceServer).MoveToCart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CartService_MoveToCart_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServiceServer).MoveToCart(ctx, req.(*MoveToCartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CartService_RemoveSavedItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveSavedItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServiceServer).RemoveSavedItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CartService_RemoveSavedItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServiceServer).RemoveSavedItem(ctx, req.(*RemoveSavedItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CartService_DeleteSavedList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSavedListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServiceServer).DeleteSavedList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CartService_DeleteSavedList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServiceServer).DeleteSavedList(ctx, req.(*DeleteSavedListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CartService_ServiceDesc is the grpc.ServiceDesc for CartService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -255,10 +629,22 @@ var CartService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "AddItem",
 			Handler:    _CartService_AddItem_Handler,
 		},
+		{
+			MethodName: "AddItems",
+			Handler:    _CartService_AddItems_Handler,
+		},
+		{
+			MethodName: "ReplaceCart",
+			Handler:    _CartService_ReplaceCart_Handler,
+		},
 		{
 			MethodName: "GetCart",
 			Handler:    _CartService_GetCart_Handler,
 		},
+		{
+			MethodName: "GetPricedCart",
+			Handler:    _CartService_GetPricedCart_Handler,
+		},
 		{
 			MethodName: "UpdateQuantity",
 			Handler:    _CartService_UpdateQuantity_Handler,
@@ -271,6 +657,38 @@ var CartService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ClearCart",
 			Handler:    _CartService_ClearCart_Handler,
 		},
+		{
+			MethodName: "MergeCarts",
+			Handler:    _CartService_MergeCarts_Handler,
+		},
+		{
+			MethodName: "ApplyPromoCode",
+			Handler:    _CartService_ApplyPromoCode_Handler,
+		},
+		{
+			MethodName: "RemovePromoCode",
+			Handler:    _CartService_RemovePromoCode_Handler,
+		},
+		{
+			MethodName: "GetSavedItems",
+			Handler:    _CartService_GetSavedItems_Handler,
+		},
+		{
+			MethodName: "SaveForLater",
+			Handler:    _CartService_SaveForLater_Handler,
+		},
+		{
+			MethodName: "MoveToCart",
+			Handler:    _CartService_MoveToCart_Handler,
+		},
+		{
+			MethodName: "RemoveSavedItem",
+			Handler:    _CartService_RemoveSavedItem_Handler,
+		},
+		{
+			MethodName: "DeleteSavedList",
+			Handler:    _CartService_DeleteSavedList_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/proto/cart.proto",
@@ -96,15 +96,6 @@ func main() {
 	}
 	defer shutdown(context.Background())
 
-	kafkaPort := getEnv("KAFKA_PORT", "localhost:9092")
-	poller := pub.NewOutboxPoller(repo, log, kafkaPort)
-	pollerCtx, pollerCancel := context.WithCancel(context.Background())
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		poller.Run(pollerCtx)
-	}()
-
 	cartCb := circuitbreaker.New(circuitbreaker.DefaultSettings("cart-service", log))
 	cartConn, err := grpc.NewClient(cartServiceAddr,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
@@ -157,6 +148,15 @@ func main() {
 	paymentClient := paymentpb.NewPaymentServiceClient(paymentConn)
 	log.Info("connected to payment service", "addr", paymentServiceAddr)
 
+	kafkaPort := getEnv("KAFKA_PORT", "localhost:9092")
+	poller := pub.NewOutboxPoller(repo, inventoryClient, paymentClient, productClient, log, kafkaPort)
+	pollerCtx, pollerCancel := context.WithCancel(context.Background())
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		poller.Run(pollerCtx)
+	}()
+
 	cartHandler := service.NewCartHandler(cartClient, requestTimeout)
 	productHandler := service.NewProductHandler(productClient, requestTimeout)
 	inventoryHandler := service.NewInventoryHandler(inventoryClient, requestTimeout)
@@ -171,6 +171,18 @@ func main() {
 		log,
 	)
 
+	workers, err := strconv.Atoi(getEnv("CHECKOUT_WORKERS", "4"))
+	if err != nil || workers <= 0 {
+		log.Error("invalid CHECKOUT_WORKERS", "value", os.Getenv("CHECKOUT_WORKERS"))
+		os.Exit(1)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		checkoutService.RunWorkers(pollerCtx, workers)
+	}()
+	log.Info("async checkout workers started", "workers", workers)
+
 	checkoutServer := checkoutgrpc.NewCheckoutServiceServer(checkoutService)
 
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", grpcPort))
@@ -184,6 +196,9 @@ func main() {
 		grpc.ChainUnaryInterceptor(
 			logger.UnaryServerInterceptor(log),
 		),
+		grpc.ChainStreamInterceptor(
+			logger.StreamServerInterceptor(log),
+		),
 	)
 
 	pb.RegisterCheckoutServiceServer(grpcServer, checkoutServer)
@@ -202,7 +217,17 @@ func main() {
 	<-quit
 
 	log.Info("shutting down checkout service")
-	grpcServer.GracefulStop()
+	// WatchCheckout streams can stay open for a long time, don't let them block shutdown
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(10 * time.Second):
+		grpcServer.Stop()
+	}
 	pollerCancel()
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer shutdownCancel()
@@ -215,9 +240,9 @@ func main() {
 
 	select {
 	case <-doneChan:
-		log.Info("poller stopped cleanly")
+		log.Info("poller and workers stopped cleanly")
 	case <-shutdownCtx.Done():
-		log.Warn("poller did not stop within timeout")
+		log.Warn("poller and workers did not stop within timeout")
 	}
 
 	log.Info("checkout service stopped")
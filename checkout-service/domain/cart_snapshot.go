@@ -1,19 +1,143 @@
 package domain
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/fjod/go_cart/pkg/money"
+)
 
 type CartSnapshotItem struct {
-	ProductID   int64   `json:"product_id"`
-	ProductName string  `json:"product_name"`
-	Quantity    int32   `json:"quantity"`
-	UnitPrice   float64 `json:"unit_price"`
-	Subtotal    float64 `json:"subtotal"`
+	ProductID   int64
+	VariantID   int64  // 0 for a product sold by its id alone
+	SKU         string // empty for a product sold by its id alone
+	ProductName string
+	Quantity    int32
+	UnitPrice   money.Money
+	Subtotal    money.Money
+	Discount    money.Money // share of the promo code discount, zero when the code doesn't cover the line
+}
+
+// cartSnapshotItemJSON is the stored form of an item: exact minor units next to the legacy float fields,
+// so snapshots written before minor units existed can still be read
+type cartSnapshotItemJSON struct {
+	ProductID      int64   `json:"product_id"`
+	VariantID      int64   `json:"variant_id,omitempty"`
+	SKU            string  `json:"sku,omitempty"`
+	ProductName    string  `json:"product_name"`
+	Quantity       int32   `json:"quantity"`
+	UnitPrice      float64 `json:"unit_price"`
+	Subtotal       float64 `json:"subtotal"`
+	UnitPriceMinor *int64  `json:"unit_price_minor,omitempty"`
+	SubtotalMinor  *int64  `json:"subtotal_minor,omitempty"`
+	Currency       string  `json:"currency,omitempty"`
+	DiscountMinor  int64   `json:"discount_minor,omitempty"`
+}
+
+func (i CartSnapshotItem) MarshalJSON() ([]byte, error) {
+	return json.Marshal(cartSnapshotItemJSON{
+		ProductID:      i.ProductID,
+		VariantID:      i.VariantID,
+		SKU:            i.SKU,
+		ProductName:    i.ProductName,
+		Quantity:       i.Quantity,
+		UnitPrice:      i.UnitPrice.Float64(),
+		Subtotal:       i.Subtotal.Float64(),
+		UnitPriceMinor: &i.UnitPrice.Amount,
+		SubtotalMinor:  &i.Subtotal.Amount,
+		Currency:       i.UnitPrice.Currency,
+		DiscountMinor:  i.Discount.Amount,
+	})
+}
+
+func (i *CartSnapshotItem) UnmarshalJSON(data []byte) error {
+	var raw cartSnapshotItemJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	currency := raw.Currency
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+	*i = CartSnapshotItem{
+		ProductID:   raw.ProductID,
+		VariantID:   raw.VariantID,
+		SKU:         raw.SKU,
+		ProductName: raw.ProductName,
+		Quantity:    raw.Quantity,
+		UnitPrice:   minorOrFloat(raw.UnitPriceMinor, raw.UnitPrice, currency),
+		Subtotal:    minorOrFloat(raw.SubtotalMinor, raw.Subtotal, currency),
+		Discount:    money.New(raw.DiscountMinor, currency),
+	}
+	return nil
 }
 
-// CartSnapshot represents the full cart state at checkout time
+// CartSnapshot represents the full cart state at checkout time.
+// With a promo code the discount is frozen here, TotalAmount is what the customer pays: Subtotal - Discount.
 type CartSnapshot struct {
-	Items       []CartSnapshotItem `json:"items"`
-	TotalAmount float64            `json:"total_amount"`
-	Currency    string             `json:"currency"`
-	CapturedAt  time.Time          `json:"captured_at"`
+	Items       []CartSnapshotItem
+	Subtotal    money.Money
+	Discount    money.Money
+	PromoCode   string // empty when no code was applied
+	TotalAmount money.Money
+	Currency    string
+	CapturedAt  time.Time
+}
+
+type cartSnapshotJSON struct {
+	Items            []CartSnapshotItem `json:"items"`
+	TotalAmount      float64            `json:"total_amount"`
+	TotalAmountMinor *int64             `json:"total_amount_minor,omitempty"`
+	Currency         string             `json:"currency"`
+	CapturedAt       time.Time          `json:"captured_at"`
+	PromoCode        string             `json:"promo_code,omitempty"`
+	SubtotalMinor    *int64             `json:"subtotal_minor,omitempty"` // missing on snapshots taken before promo codes
+	DiscountMinor    int64              `json:"discount_minor,omitempty"`
+}
+
+func (s CartSnapshot) MarshalJSON() ([]byte, error) {
+	return json.Marshal(cartSnapshotJSON{
+		Items:            s.Items,
+		TotalAmount:      s.TotalAmount.Float64(),
+		TotalAmountMinor: &s.TotalAmount.Amount,
+		Currency:         s.Currency,
+		CapturedAt:       s.CapturedAt,
+		PromoCode:        s.PromoCode,
+		SubtotalMinor:    &s.Subtotal.Amount,
+		DiscountMinor:    s.Discount.Amount,
+	})
+}
+
+func (s *CartSnapshot) UnmarshalJSON(data []byte) error {
+	var raw cartSnapshotJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	currency := raw.Currency
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+	total := minorOrFloat(raw.TotalAmountMinor, raw.TotalAmount, currency)
+	subtotal := total
+	if raw.SubtotalMinor != nil {
+		subtotal = money.New(*raw.SubtotalMinor, currency)
+	}
+	*s = CartSnapshot{
+		Items:       raw.Items,
+		Subtotal:    subtotal,
+		Discount:    money.New(raw.DiscountMinor, currency),
+		PromoCode:   raw.PromoCode,
+		TotalAmount: total,
+		Currency:    currency,
+		CapturedAt:  raw.CapturedAt,
+	}
+	return nil
+}
+
+// minorOrFloat prefers the exact minor units and falls back to rounding the legacy float
+func minorOrFloat(minor *int64, legacy float64, currency string) money.Money {
+	if minor != nil {
+		return money.New(*minor, currency)
+	}
+	return money.FromFloat(legacy, currency)
 }
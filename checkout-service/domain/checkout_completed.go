@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// CheckoutCompletedEvent is the payload of the CheckoutCompleted outbox event consumed by orders-service.
+// total_amount stays a float for older consumers, total_amount_minor carries the exact amount.
+type CheckoutCompletedEvent struct {
+	CheckoutID       string             `json:"checkout_id"`
+	UserID           string             `json:"user_id"`
+	Items            []CartSnapshotItem `json:"items"`
+	TotalAmount      float64            `json:"total_amount"`
+	TotalAmountMinor int64              `json:"total_amount_minor"`
+	Currency         string             `json:"currency"`
+	CompletedAt      time.Time          `json:"completed_at"`
+	PromoCode        string             `json:"promo_code,omitempty"`
+	DiscountMinor    int64              `json:"discount_amount_minor,omitempty"` // already taken off total_amount_minor
+}
+
+// NewCheckoutCompletedEvent builds the completed payload from the snapshot captured at checkout time
+func NewCheckoutCompletedEvent(checkoutID, userID string, snapshot *CartSnapshot, completedAt time.Time) CheckoutCompletedEvent {
+	return CheckoutCompletedEvent{
+		CheckoutID:       checkoutID,
+		UserID:           userID,
+		Items:            snapshot.Items,
+		TotalAmount:      snapshot.TotalAmount.Float64(),
+		TotalAmountMinor: snapshot.TotalAmount.Amount,
+		Currency:         snapshot.Currency,
+		CompletedAt:      completedAt,
+		PromoCode:        snapshot.PromoCode,
+		DiscountMinor:    snapshot.Discount.Amount,
+	}
+}
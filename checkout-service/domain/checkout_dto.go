@@ -3,6 +3,7 @@ package domain
 type CheckoutRequest struct {
 	UserID         int64
 	IdempotencyKey string
+	Async          bool // return right after the session is persisted and run the saga in the background
 }
 
 type CheckoutResponse struct {
@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+// CheckoutEvent is a single state transition of a checkout session, pushed to WatchCheckout subscribers
+type CheckoutEvent struct {
+	CheckoutID    string
+	Status        CheckoutStatus
+	FailureReason string
+	OccurredAt    time.Time
+}
@@ -3,12 +3,13 @@ package domain
 type CheckoutStatus string
 
 const (
-	CheckoutStatusInitiated         CheckoutStatus = "INITIATED"
-	CheckoutStatusInventoryReserved CheckoutStatus = "INVENTORY_RESERVED"
-	CheckoutStatusPaymentPending    CheckoutStatus = "PAYMENT_PENDING"
-	CheckoutStatusPaymentCompleted  CheckoutStatus = "PAYMENT_COMPLETED"
-	CheckoutStatusCompleted         CheckoutStatus = "COMPLETED"
-	CheckoutStatusFailed            CheckoutStatus = "FAILED"
+	CheckoutStatusInitiated          CheckoutStatus = "INITIATED"
+	CheckoutStatusInventoryReserved  CheckoutStatus = "INVENTORY_RESERVED"
+	CheckoutStatusPaymentPending     CheckoutStatus = "PAYMENT_PENDING"
+	CheckoutStatusPaymentCompleted   CheckoutStatus = "PAYMENT_COMPLETED"
+	CheckoutStatusInventoryConfirmed CheckoutStatus = "INVENTORY_CONFIRMED"
+	CheckoutStatusCompleted          CheckoutStatus = "COMPLETED"
+	CheckoutStatusFailed             CheckoutStatus = "FAILED"
 )
 
 func (s CheckoutStatus) IsTerminal() bool {
@@ -31,6 +32,10 @@ var validTransitions = map[CheckoutStatus]map[CheckoutStatus]bool{
 		CheckoutStatusFailed:           true,
 	},
 	CheckoutStatusPaymentCompleted: {
+		CheckoutStatusInventoryConfirmed: true,
+		CheckoutStatusFailed:             true,
+	},
+	CheckoutStatusInventoryConfirmed: {
 		CheckoutStatusCompleted: true,
 		CheckoutStatusFailed:    true,
 	},
@@ -45,6 +50,23 @@ func CanTransitionTo(current, next CheckoutStatus) bool {
 	return allowedNextStates[next]
 }
 
+// statusOrder is the position of each status along the saga, FAILED can follow any of them.
+var statusOrder = map[CheckoutStatus]int{
+	CheckoutStatusInitiated:          0,
+	CheckoutStatusInventoryReserved:  1,
+	CheckoutStatusPaymentPending:     2,
+	CheckoutStatusPaymentCompleted:   3,
+	CheckoutStatusInventoryConfirmed: 4,
+	CheckoutStatusCompleted:          5,
+	CheckoutStatusFailed:             6,
+}
+
+// IsAfter reports whether s comes later in the saga than other.
+// Used to drop stale notifications that arrive out of order.
+func (s CheckoutStatus) IsAfter(other CheckoutStatus) bool {
+	return statusOrder[s] > statusOrder[other]
+}
+
 // String representation (for logger)
 func (s CheckoutStatus) String() string {
 	return string(s)
@@ -0,0 +1,21 @@
+package domain
+
+// CheckoutStep names a downstream call made by the checkout saga
+type CheckoutStep string
+
+const (
+	CheckoutStepReserveInventory CheckoutStep = "RESERVE_INVENTORY"
+	CheckoutStepProcessPayment   CheckoutStep = "PROCESS_PAYMENT"
+	CheckoutStepConfirmInventory CheckoutStep = "CONFIRM_INVENTORY"
+	CheckoutStepReleaseInventory CheckoutStep = "RELEASE_INVENTORY"
+	CheckoutStepComplete         CheckoutStep = "COMPLETE"
+	CheckoutStepRefund           CheckoutStep = "REFUND"
+)
+
+// StepOutcome is the result of a single step attempt
+type StepOutcome string
+
+const (
+	StepOutcomeSucceeded StepOutcome = "SUCCEEDED"
+	StepOutcomeFailed    StepOutcome = "FAILED"
+)
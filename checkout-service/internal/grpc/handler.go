@@ -2,20 +2,27 @@ package grpc
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"time"
 
 	d "github.com/fjod/go_cart/checkout-service/domain"
+	r "github.com/fjod/go_cart/checkout-service/internal/repository"
 	s "github.com/fjod/go_cart/checkout-service/internal/service"
 	pb "github.com/fjod/go_cart/checkout-service/pkg/proto"
+	"github.com/fjod/go_cart/pkg/money"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 type CheckoutServiceServer struct {
 	pb.UnimplementedCheckoutServiceServer
-	service *s.CheckoutServiceImpl
+	service s.CheckoutService
 }
 
-func NewCheckoutServiceServer(service *s.CheckoutServiceImpl) *CheckoutServiceServer {
+func NewCheckoutServiceServer(service s.CheckoutService) *CheckoutServiceServer {
 	return &CheckoutServiceServer{
 		service: service,
 	}
@@ -37,8 +44,15 @@ func (h *CheckoutServiceServer) InitiateCheckout(
 	resp, err := h.service.InitiateCheckout(ctx, &d.CheckoutRequest{
 		UserID:         req.UserId,
 		IdempotencyKey: req.IdempotencyKey,
+		Async:          req.Async,
 	})
 	if err != nil {
+		if errors.Is(err, s.ErrCheckoutQueueFull) {
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		}
+		if errors.Is(err, s.ErrPromoCodeInvalid) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
 		return nil, status.Errorf(codes.Internal, "checkout failed: %v", err)
 	}
 
@@ -49,6 +63,167 @@ func (h *CheckoutServiceServer) InitiateCheckout(
 	}, nil
 }
 
+func (h *CheckoutServiceServer) GetCheckout(
+	ctx context.Context,
+	req *pb.GetCheckoutRequest) (*pb.GetCheckoutResponse, error) {
+
+	if req.UserId <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id must be greater than 0")
+	}
+	if _, err := uuid.Parse(req.CheckoutId); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid checkout_id: %v", err)
+	}
+
+	session, err := h.service.GetCheckout(ctx, req.CheckoutId, req.UserId)
+	if err != nil {
+		if errors.Is(err, s.ErrCheckoutNotFound) {
+			return nil, status.Errorf(codes.NotFound, "checkout not found: %s", req.CheckoutId)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get checkout: %v", err)
+	}
+
+	return &pb.GetCheckoutResponse{Checkout: convertSessionToProto(session)}, nil
+}
+
+func (h *CheckoutServiceServer) ListCheckouts(
+	ctx context.Context,
+	req *pb.ListCheckoutsRequest) (*pb.ListCheckoutsResponse, error) {
+
+	if req.UserId <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id must be greater than 0")
+	}
+	if req.PageSize < 0 {
+		return nil, status.Error(codes.InvalidArgument, "page_size must not be negative")
+	}
+
+	sessions, nextToken, err := h.service.ListCheckouts(ctx, req.UserId, req.PageSize, req.PageToken)
+	if err != nil {
+		if errors.Is(err, s.ErrInvalidPageToken) {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to list checkouts: %v", err)
+	}
+
+	checkouts := make([]*pb.Checkout, 0, len(sessions))
+	for _, session := range sessions {
+		checkouts = append(checkouts, convertSessionToProto(session))
+	}
+
+	return &pb.ListCheckoutsResponse{
+		Checkouts:     checkouts,
+		NextPageToken: nextToken,
+	}, nil
+}
+
+func (h *CheckoutServiceServer) WatchCheckout(
+	req *pb.WatchCheckoutRequest,
+	stream grpc.ServerStreamingServer[pb.CheckoutEvent]) error {
+
+	if req.UserId <= 0 {
+		return status.Error(codes.InvalidArgument, "user_id must be greater than 0")
+	}
+	if _, err := uuid.Parse(req.CheckoutId); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid checkout_id: %v", err)
+	}
+
+	err := h.service.WatchCheckout(stream.Context(), req.CheckoutId, req.UserId, func(event d.CheckoutEvent) error {
+		return stream.Send(&pb.CheckoutEvent{
+			CheckoutId:    event.CheckoutID,
+			Status:        mapDomainStatusToProto(event.Status),
+			FailureReason: event.FailureReason,
+			OccurredAt:    event.OccurredAt.Format(time.RFC3339),
+		})
+	})
+	if err != nil {
+		if errors.Is(err, s.ErrCheckoutNotFound) {
+			return status.Errorf(codes.NotFound, "checkout not found: %s", req.CheckoutId)
+		}
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return status.FromContextError(err).Err()
+		}
+		return status.Errorf(codes.Internal, "failed to watch checkout: %v", err)
+	}
+	return nil
+}
+
+func (h *CheckoutServiceServer) GetCheckoutHistory(
+	ctx context.Context,
+	req *pb.GetCheckoutHistoryRequest) (*pb.GetCheckoutHistoryResponse, error) {
+
+	if req.UserId <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id must be greater than 0")
+	}
+	if _, err := uuid.Parse(req.CheckoutId); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid checkout_id: %v", err)
+	}
+
+	records, err := h.service.GetCheckoutHistory(ctx, req.CheckoutId, req.UserId)
+	if err != nil {
+		if errors.Is(err, s.ErrCheckoutNotFound) {
+			return nil, status.Errorf(codes.NotFound, "checkout not found: %s", req.CheckoutId)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get checkout history: %v", err)
+	}
+
+	steps := make([]*pb.CheckoutStep, 0, len(records))
+	for _, record := range records {
+		steps = append(steps, &pb.CheckoutStep{
+			Step:       string(record.Step),
+			Attempt:    int32(record.Attempt),
+			Outcome:    string(record.Outcome),
+			Error:      getStringValue(record.Error),
+			DurationMs: record.Duration.Milliseconds(),
+			RecordedAt: record.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return &pb.GetCheckoutHistoryResponse{
+		CheckoutId: req.CheckoutId,
+		Steps:      steps,
+	}, nil
+}
+
+func convertSessionToProto(session *r.CheckoutSession) *pb.Checkout {
+	// snapshot is written by checkout itself, a broken one still leaves the rest of the session readable
+	var snapshot d.CartSnapshot
+	_ = json.Unmarshal(session.CartSnapshot, &snapshot)
+
+	items := make([]*pb.CheckoutItem, 0, len(snapshot.Items))
+	for _, item := range snapshot.Items {
+		items = append(items, &pb.CheckoutItem{
+			ProductId:      item.ProductID,
+			VariantId:      item.VariantID,
+			Sku:            item.SKU,
+			ProductName:    item.ProductName,
+			Quantity:       item.Quantity,
+			UnitPrice:      item.UnitPrice.Float64(),
+			Subtotal:       item.Subtotal.Float64(),
+			UnitPriceMinor: item.UnitPrice.Amount,
+			SubtotalMinor:  item.Subtotal.Amount,
+		})
+	}
+
+	// total_amount is the DECIMAL that was charged, the snapshot total only covers sessions with a broken amount
+	total, err := money.Parse(session.TotalAmount, session.Currency)
+	if err != nil {
+		total = snapshot.TotalAmount
+	}
+
+	return &pb.Checkout{
+		CheckoutId:             session.ID,
+		Status:                 mapDomainStatusToProto(session.Status),
+		Items:                  items,
+		TotalAmount:            session.TotalAmount,
+		TotalAmountMinor:       total.Amount,
+		Currency:               session.Currency,
+		InventoryReservationId: getStringValue(session.InventoryReservationID),
+		PaymentId:              getStringValue(session.PaymentID),
+		FailureReason:          getStringValue(session.FailureReason),
+		CreatedAt:              session.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:              session.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
 func getStringValue(s *string) string {
 	if s == nil {
 		return ""
@@ -73,6 +248,8 @@ func mapDomainStatusToProto(ds d.CheckoutStatus) pb.CheckoutStatus {
 		return pb.CheckoutStatus_CHECKOUT_STATUS_PAYMENT_PENDING
 	case d.CheckoutStatusPaymentCompleted:
 		return pb.CheckoutStatus_CHECKOUT_STATUS_PAYMENT_COMPLETED
+	case d.CheckoutStatusInventoryConfirmed:
+		return pb.CheckoutStatus_CHECKOUT_STATUS_INVENTORY_CONFIRMED
 	case d.CheckoutStatusCompleted:
 		return pb.CheckoutStatus_CHECKOUT_STATUS_COMPLETED
 	case d.CheckoutStatusFailed:
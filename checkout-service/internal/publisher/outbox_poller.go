@@ -2,14 +2,15 @@ package publisher
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log/slog"
 	"time"
 
-	d "github.com/fjod/go_cart/checkout-service/domain"
 	r "github.com/fjod/go_cart/checkout-service/internal/repository"
+	inventorypb "github.com/fjod/go_cart/inventory-service/pkg/proto"
+	paymentpb "github.com/fjod/go_cart/payment-service/pkg/proto"
 	pk "github.com/fjod/go_cart/pkg/tracing"
+	productpb "github.com/fjod/go_cart/product-service/pkg/proto"
 	"github.com/segmentio/kafka-go"
 	"go.opentelemetry.io/otel"
 )
@@ -19,18 +20,27 @@ type OutboxPoller struct {
 	eventTick    time.Duration
 	recoveryTick time.Duration
 	repo         r.RepoInterface
+	inventory    inventorypb.InventoryServiceClient
+	payment      paymentpb.PaymentServiceClient
+	product      productpb.ProductServiceClient
 	writer       *kafka.Writer
 	logger       *slog.Logger
 }
 
-func NewOutboxPoller(repo r.RepoInterface, log *slog.Logger, brokers ...string) *OutboxPoller {
+func NewOutboxPoller(
+	repo r.RepoInterface,
+	inventory inventorypb.InventoryServiceClient,
+	payment paymentpb.PaymentServiceClient,
+	product productpb.ProductServiceClient,
+	log *slog.Logger,
+	brokers ...string) *OutboxPoller {
 	w := &kafka.Writer{
 		Addr:                   kafka.TCP(brokers...),
 		Topic:                  "checkout-outbox",
 		Balancer:               &kafka.LeastBytes{},
 		AllowAutoTopicCreation: true,
 	}
-	return &OutboxPoller{time.Second * 5, time.Second, time.Second * 5, repo, w, log}
+	return &OutboxPoller{time.Second * 5, time.Second, time.Second * 5, repo, inventory, payment, product, w, log}
 }
 
 func (p *OutboxPoller) Run(ctx context.Context) {
@@ -72,48 +82,6 @@ func (p *OutboxPoller) processUnpublishedEvents(ctx context.Context) {
 	}
 }
 
-func (p *OutboxPoller) recoverStuckSessions(ctx context.Context) {
-	// stuck session is when the checkout status is PAYMENT_COMPLETED but there is no outbox event for it.
-	sessions, err := p.repo.GetStuckSessions(ctx)
-	if err != nil {
-		p.logger.Error("failed to get stuck sessions", "error", err)
-		return
-	}
-	for _, session := range sessions {
-		p.logger.Info("recovering stuck session", "session_id", session.ID)
-
-		var s d.CartSnapshot
-		if err := json.Unmarshal(session.CartSnapshot, &s); err != nil {
-			p.logger.Error("failed to unmarshal cart snapshot", "session_id", session.ID, "error", err)
-			continue
-		}
-
-		payload := map[string]interface{}{
-			"checkout_id":  session.ID,
-			"user_id":      session.UserID,
-			"items":        s.Items,
-			"total_amount": s.TotalAmount,
-			"currency":     s.Currency,
-			"completed_at": session.UpdatedAt,
-		}
-
-		payloadJSON, err := json.Marshal(payload)
-		if err != nil {
-			p.logger.Error("failed to marshal checkout payload", "session_id", session.ID, "error", err)
-			continue
-		}
-
-		completedStatus := d.CheckoutStatusCompleted
-		err = p.repo.CompleteCheckoutSession(ctx, &session.ID, payloadJSON, &completedStatus)
-		if err != nil {
-			p.logger.Error("failed to complete checkout in recovery", "session_id", session.ID, "error", err)
-			continue
-		}
-
-		p.logger.Info("session recovered", "session_id", session.ID)
-	}
-}
-
 func (p *OutboxPoller) publishToKafka(ctx context.Context, event *r.OutboxEvent) error {
 	messageName := "checkout.processed"
 	tr := otel.Tracer("kafka")
@@ -11,10 +11,14 @@ import (
 
 	d "github.com/fjod/go_cart/checkout-service/domain"
 	r "github.com/fjod/go_cart/checkout-service/internal/repository"
+	ipb "github.com/fjod/go_cart/inventory-service/pkg/proto"
+	paymentpb "github.com/fjod/go_cart/payment-service/pkg/proto"
+	productpb "github.com/fjod/go_cart/product-service/pkg/proto"
 	kafkaGo "github.com/segmentio/kafka-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/testcontainers/testcontainers-go/modules/kafka"
+	"google.golang.org/grpc"
 )
 
 type MockRepository struct {
@@ -33,6 +37,10 @@ type MockRepository struct {
 	CompleteCheckoutCallCount int      // Track how many times CompleteCheckoutSession was called
 	OutboxEvents              []*r.OutboxEvent
 	ProcessedId               int
+	UpdatedStatuses           []d.CheckoutStatus
+	FailedIDs                 []string // Track all sessions passed to FailCheckoutSession
+	FailureReasons            []string
+	Steps                     []*r.CheckoutStepRecord
 }
 
 func (m *MockRepository) Close() error {
@@ -52,10 +60,21 @@ func (m *MockRepository) CreateCheckoutSession(_ context.Context, session *r.Che
 	return m.CreateErr
 }
 
-func (m *MockRepository) UpdateCheckoutSessionStatus(_ context.Context, _ *string, _ *d.CheckoutStatus) error {
+func (m *MockRepository) UpdateCheckoutSessionStatus(_ context.Context, _ *string, s *d.CheckoutStatus) error {
+	m.UpdatedStatuses = append(m.UpdatedStatuses, *s)
 	return nil
 }
 
+func (m *MockRepository) FailCheckoutSession(_ context.Context, id *string, reason string) error {
+	m.FailedIDs = append(m.FailedIDs, *id)
+	m.FailureReasons = append(m.FailureReasons, reason)
+	return nil
+}
+
+func (m *MockRepository) ClaimInitiatedSession(_ context.Context, _ *string) (bool, error) {
+	return false, nil
+}
+
 func (m *MockRepository) SetReservation(_ context.Context, _ *string, _ *d.CheckoutStatus, reserveId *string) error {
 	m.ReservationId = reserveId
 	return nil
@@ -97,6 +116,88 @@ func (m *MockRepository) GetStuckSessions(context.Context) ([]*r.CheckoutSession
 	return m.StuckSessions, nil
 }
 
+func (m *MockRepository) GetCheckoutSession(context.Context, string) (*r.CheckoutSession, error) {
+	return nil, r.ErrCheckoutNotFound
+}
+
+func (m *MockRepository) ListCheckoutSessions(context.Context, string, int, int) ([]*r.CheckoutSession, error) {
+	return nil, nil
+}
+
+func (m *MockRepository) RecordCheckoutStep(_ context.Context, step *r.CheckoutStepRecord) error {
+	m.Steps = append(m.Steps, step)
+	return nil
+}
+
+func (m *MockRepository) GetCheckoutSteps(context.Context, string) ([]*r.CheckoutStepRecord, error) {
+	return m.Steps, nil
+}
+
+// MockInventoryServiceClient implements ipb.InventoryServiceClient for testing
+type MockInventoryServiceClient struct {
+	ConfirmErr error
+	ConfirmIds []string
+	ReleaseErr error
+	ReleaseIds []string
+}
+
+func (m *MockInventoryServiceClient) GetStock(context.Context, *ipb.GetStockRequest, ...grpc.CallOption) (*ipb.GetStockResponse, error) {
+	return &ipb.GetStockResponse{}, nil
+}
+
+func (m *MockInventoryServiceClient) Reserve(context.Context, *ipb.ReserveRequest, ...grpc.CallOption) (*ipb.ReserveResponse, error) {
+	return &ipb.ReserveResponse{}, nil
+}
+
+func (m *MockInventoryServiceClient) Confirm(_ context.Context, req *ipb.ConfirmRequest, _ ...grpc.CallOption) (*ipb.ConfirmResponse, error) {
+	if m.ConfirmErr != nil {
+		return nil, m.ConfirmErr
+	}
+	m.ConfirmIds = append(m.ConfirmIds, req.ReservationId)
+	return &ipb.ConfirmResponse{Success: true}, nil
+}
+
+func (m *MockInventoryServiceClient) Release(_ context.Context, req *ipb.ReleaseRequest, _ ...grpc.CallOption) (*ipb.ReleaseResponse, error) {
+	m.ReleaseIds = append(m.ReleaseIds, req.ReservationId)
+	if m.ReleaseErr != nil {
+		return nil, m.ReleaseErr
+	}
+	return &ipb.ReleaseResponse{Success: true}, nil
+}
+
+// MockPaymentServiceClient implements paymentpb.PaymentServiceClient for testing
+type MockPaymentServiceClient struct {
+	RefundErr error
+	RefundIds []string
+}
+
+func (m *MockPaymentServiceClient) Charge(context.Context, *paymentpb.ChargeRequest, ...grpc.CallOption) (*paymentpb.ChargeResponse, error) {
+	return &paymentpb.ChargeResponse{}, nil
+}
+
+func (m *MockPaymentServiceClient) Refund(_ context.Context, req *paymentpb.RefundRequest, _ ...grpc.CallOption) (*paymentpb.RefundResponse, error) {
+	if m.RefundErr != nil {
+		return nil, m.RefundErr
+	}
+	m.RefundIds = append(m.RefundIds, req.CheckoutId)
+	return &paymentpb.RefundResponse{}, nil
+}
+
+// MockProductServiceClient implements productpb.ProductServiceClient for testing, recovery only releases promo codes
+type MockProductServiceClient struct {
+	productpb.ProductServiceClient
+	ReleaseErr error
+	Released   []*productpb.ReleasePromotionRequest
+}
+
+func (m *MockProductServiceClient) ReleasePromotion(_ context.Context, req *productpb.ReleasePromotionRequest, _ ...grpc.CallOption) (*productpb.ReleasePromotionResponse, error) {
+	if m.ReleaseErr != nil {
+		return nil, m.ReleaseErr
+	}
+	m.Released = append(m.Released, req)
+	return &productpb.ReleasePromotionResponse{}, nil
+}
+
 func setupKafka(t *testing.T) (string, func()) {
 	ctx := context.Background()
 
@@ -236,7 +337,7 @@ func TestRecoveringStuckSession(t *testing.T) {
 		ID:                     "checkout-id-1",
 		UserID:                 "userId",
 		CartSnapshot:           snapshotJSON,
-		Status:                 "status",
+		Status:                 d.CheckoutStatusPaymentCompleted,
 		IdempotencyKey:         "key",
 		InventoryReservationID: nil,
 		PaymentID:              nil,
@@ -251,7 +352,7 @@ func TestRecoveringStuckSession(t *testing.T) {
 		StuckSessions: sessions,
 	}
 
-	poller := NewOutboxPoller(mockRepo, slog.Default())
+	poller := NewOutboxPoller(mockRepo, &MockInventoryServiceClient{}, &MockPaymentServiceClient{}, &MockProductServiceClient{}, slog.Default())
 	poller.recoverStuckSessions(context.Background())
 	require.Equal(t, "checkout-id-1", *mockRepo.OutboxId)
 }
@@ -262,7 +363,7 @@ func TestRecoveringStuckSession_GetStuckSessionsError(t *testing.T) {
 		GetStuckSessionsErr: errors.New("database connection error"),
 	}
 
-	poller := NewOutboxPoller(mockRepo, slog.Default())
+	poller := NewOutboxPoller(mockRepo, &MockInventoryServiceClient{}, &MockPaymentServiceClient{}, &MockProductServiceClient{}, slog.Default())
 
 	// Should not panic, just log error and return
 	poller.recoverStuckSessions(context.Background())
@@ -277,7 +378,7 @@ func TestRecoveringStuckSession_EmptySessionsList(t *testing.T) {
 		StuckSessions: []*r.CheckoutSession{}, // Empty list
 	}
 
-	poller := NewOutboxPoller(mockRepo, slog.Default())
+	poller := NewOutboxPoller(mockRepo, &MockInventoryServiceClient{}, &MockPaymentServiceClient{}, &MockProductServiceClient{}, slog.Default())
 
 	// Should not panic, just return without doing anything
 	poller.recoverStuckSessions(context.Background())
@@ -301,7 +402,7 @@ func TestRecoveringStuckSession_InvalidCartSnapshot(t *testing.T) {
 		StuckSessions: []*r.CheckoutSession{session},
 	}
 
-	poller := NewOutboxPoller(mockRepo, slog.Default())
+	poller := NewOutboxPoller(mockRepo, &MockInventoryServiceClient{}, &MockPaymentServiceClient{}, &MockProductServiceClient{}, slog.Default())
 
 	// Should not panic - should log error and skip this session
 	poller.recoverStuckSessions(context.Background())
@@ -337,7 +438,7 @@ func TestRecoveringStuckSession_CompleteCheckoutError(t *testing.T) {
 		CompleteCheckoutErr: errors.New("database deadlock"),
 	}
 
-	poller := NewOutboxPoller(mockRepo, slog.Default())
+	poller := NewOutboxPoller(mockRepo, &MockInventoryServiceClient{}, &MockPaymentServiceClient{}, &MockProductServiceClient{}, slog.Default())
 
 	// Should NOT exit the process - should log error and continue
 	poller.recoverStuckSessions(context.Background())
@@ -396,7 +497,7 @@ func TestRecoveringStuckSession_MultipleSessionsWithPartialFailures(t *testing.T
 		CompletedCheckoutIDs: []string{},
 	}
 
-	poller := NewOutboxPoller(mockRepo, slog.Default())
+	poller := NewOutboxPoller(mockRepo, &MockInventoryServiceClient{}, &MockPaymentServiceClient{}, &MockProductServiceClient{}, slog.Default())
 	poller.recoverStuckSessions(context.Background())
 
 	// ✅ FIXED: Error handling now works correctly
@@ -418,10 +519,99 @@ func TestRecoveringStuckSession_NilSessionsList(t *testing.T) {
 		StuckSessions: nil, // Nil instead of empty slice
 	}
 
-	poller := NewOutboxPoller(mockRepo, slog.Default())
+	poller := NewOutboxPoller(mockRepo, &MockInventoryServiceClient{}, &MockPaymentServiceClient{}, &MockProductServiceClient{}, slog.Default())
 
 	// Should not panic
 	poller.recoverStuckSessions(context.Background())
 
 	assert.Equal(t, 0, mockRepo.CompleteCheckoutCallCount)
 }
+
+func TestRecoveringStuckSession_ConfirmsReservation(t *testing.T) {
+	snapshotJSON, _ := json.Marshal(&d.CartSnapshot{
+		Items:      []d.CartSnapshotItem{{ProductID: 1, Quantity: 1}},
+		Currency:   "USD",
+		CapturedAt: time.Now(),
+	})
+	reservationID := "reservation-1"
+	session := &r.CheckoutSession{
+		ID:                     "checkout-unconfirmed",
+		UserID:                 "user1",
+		CartSnapshot:           snapshotJSON,
+		Status:                 d.CheckoutStatusPaymentCompleted,
+		InventoryReservationID: &reservationID,
+		CreatedAt:              time.Now(),
+		UpdatedAt:              time.Now(),
+	}
+
+	mockRepo := &MockRepository{
+		StuckSessions: []*r.CheckoutSession{session},
+	}
+	mockInventory := &MockInventoryServiceClient{}
+
+	poller := NewOutboxPoller(mockRepo, mockInventory, &MockPaymentServiceClient{}, &MockProductServiceClient{}, slog.Default())
+	poller.recoverStuckSessions(context.Background())
+
+	assert.Equal(t, []string{reservationID}, mockInventory.ConfirmIds)
+	assert.Equal(t, []d.CheckoutStatus{d.CheckoutStatusInventoryConfirmed}, mockRepo.UpdatedStatuses)
+	assert.Equal(t, []string{"checkout-unconfirmed"}, mockRepo.CompletedCheckoutIDs)
+}
+
+func TestRecoveringStuckSession_ConfirmError(t *testing.T) {
+	snapshotJSON, _ := json.Marshal(&d.CartSnapshot{
+		Items:      []d.CartSnapshotItem{{ProductID: 1, Quantity: 1}},
+		Currency:   "USD",
+		CapturedAt: time.Now(),
+	})
+	reservationID := "reservation-1"
+	session := &r.CheckoutSession{
+		ID:                     "checkout-unconfirmed",
+		UserID:                 "user1",
+		CartSnapshot:           snapshotJSON,
+		Status:                 d.CheckoutStatusPaymentCompleted,
+		InventoryReservationID: &reservationID,
+		CreatedAt:              time.Now(),
+		UpdatedAt:              time.Now(),
+	}
+
+	mockRepo := &MockRepository{
+		StuckSessions: []*r.CheckoutSession{session},
+	}
+	mockInventory := &MockInventoryServiceClient{ConfirmErr: errors.New("inventory unavailable")}
+
+	poller := NewOutboxPoller(mockRepo, mockInventory, &MockPaymentServiceClient{}, &MockProductServiceClient{}, slog.Default())
+	poller.recoverStuckSessions(context.Background())
+
+	// session stays PAYMENT_COMPLETED and is retried on the next tick
+	assert.Empty(t, mockRepo.UpdatedStatuses)
+	assert.Equal(t, 0, mockRepo.CompleteCheckoutCallCount)
+}
+
+func TestRecoveringStuckSession_AlreadyConfirmed(t *testing.T) {
+	snapshotJSON, _ := json.Marshal(&d.CartSnapshot{
+		Items:      []d.CartSnapshotItem{{ProductID: 1, Quantity: 1}},
+		Currency:   "USD",
+		CapturedAt: time.Now(),
+	})
+	reservationID := "reservation-1"
+	session := &r.CheckoutSession{
+		ID:                     "checkout-confirmed",
+		UserID:                 "user1",
+		CartSnapshot:           snapshotJSON,
+		Status:                 d.CheckoutStatusInventoryConfirmed,
+		InventoryReservationID: &reservationID,
+		CreatedAt:              time.Now(),
+		UpdatedAt:              time.Now(),
+	}
+
+	mockRepo := &MockRepository{
+		StuckSessions: []*r.CheckoutSession{session},
+	}
+	mockInventory := &MockInventoryServiceClient{}
+
+	poller := NewOutboxPoller(mockRepo, mockInventory, &MockPaymentServiceClient{}, &MockProductServiceClient{}, slog.Default())
+	poller.recoverStuckSessions(context.Background())
+
+	assert.Empty(t, mockInventory.ConfirmIds)
+	assert.Equal(t, []string{"checkout-confirmed"}, mockRepo.CompletedCheckoutIDs)
+}
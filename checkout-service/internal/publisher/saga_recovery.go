@@ -0,0 +1,194 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	d "github.com/fjod/go_cart/checkout-service/domain"
+	r "github.com/fjod/go_cart/checkout-service/internal/repository"
+	inventorypb "github.com/fjod/go_cart/inventory-service/pkg/proto"
+	paymentpb "github.com/fjod/go_cart/payment-service/pkg/proto"
+	productpb "github.com/fjod/go_cart/product-service/pkg/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// recoverStuckSessions moves every session that stopped in a non-terminal status to COMPLETED or FAILED.
+// A session that fails to recover keeps its status and is picked up again on the next tick.
+func (p *OutboxPoller) recoverStuckSessions(ctx context.Context) {
+	sessions, err := p.repo.GetStuckSessions(ctx)
+	if err != nil {
+		p.logger.Error("failed to get stuck sessions", "error", err)
+		return
+	}
+	for _, session := range sessions {
+		p.logger.Info("recovering stuck session", "session_id", session.ID, "status", session.Status)
+
+		if err := p.recoverSession(ctx, session); err != nil {
+			p.logger.Error("failed to recover session", "session_id", session.ID, "status", session.Status, "error", err)
+			continue
+		}
+
+		p.logger.Info("session recovered", "session_id", session.ID)
+	}
+}
+
+// recoverSession resumes paid sessions and compensates the ones that stopped before the payment went through.
+// Nobody waits for a pre-payment session anymore, so it is failed instead of being charged behind the customer's back.
+func (p *OutboxPoller) recoverSession(ctx context.Context, session *r.CheckoutSession) error {
+	switch session.Status {
+	case d.CheckoutStatusInitiated, d.CheckoutStatusInventoryReserved, d.CheckoutStatusPaymentPending:
+		return p.compensate(ctx, session, fmt.Sprintf("checkout abandoned in %s", session.Status))
+	case d.CheckoutStatusPaymentCompleted:
+		if err := p.confirmReservation(ctx, session); err != nil {
+			if !isGone(err) {
+				return fmt.Errorf("confirm inventory: %w", err)
+			}
+			// the reservation expired while the session was stuck, the customer has to get the money back
+			return p.compensate(ctx, session, fmt.Sprintf("inventory reservation lost after payment: %v", err))
+		}
+		return p.completeSession(ctx, session)
+	case d.CheckoutStatusInventoryConfirmed:
+		return p.completeSession(ctx, session)
+	default:
+		return fmt.Errorf("unexpected status %s", session.Status)
+	}
+}
+
+// compensate undoes whatever the saga has done so far and marks the session FAILED.
+// Each step is safe to repeat, so a compensation interrupted halfway is finished on the next tick.
+func (p *OutboxPoller) compensate(ctx context.Context, session *r.CheckoutSession, reason string) error {
+	// payment_id is stored only after a successful charge, in PAYMENT_PENDING the charge may have
+	// gone through without being recorded, so refund by checkout id in both cases
+	if session.PaymentID != nil || session.Status == d.CheckoutStatusPaymentPending {
+		if err := p.refundPayment(ctx, session); err != nil {
+			return fmt.Errorf("refund payment: %w", err)
+		}
+	}
+
+	// an INITIATED session may hold a reservation that was never recorded, it expires by its TTL
+	if session.InventoryReservationID != nil {
+		if err := p.releaseReservation(ctx, session); err != nil {
+			return fmt.Errorf("release inventory: %w", err)
+		}
+	}
+
+	if err := p.releasePromoCode(ctx, session); err != nil {
+		return fmt.Errorf("release promo code: %w", err)
+	}
+
+	return p.repo.FailCheckoutSession(ctx, &session.ID, reason)
+}
+
+// confirmReservation confirms the inventory reservation of a paid session and records it.
+// Inventory treats a repeated confirm as a no-op, so it is safe when the first confirm succeeded but the status update didn't.
+func (p *OutboxPoller) confirmReservation(ctx context.Context, session *r.CheckoutSession) (err error) {
+	if session.InventoryReservationID == nil {
+		p.logger.Warn("stuck session has no reservation to confirm", "session_id", session.ID)
+		return nil
+	}
+	start := time.Now()
+	defer func() { p.recordStep(ctx, session.ID, d.CheckoutStepConfirmInventory, start, err) }()
+
+	confirmCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	_, err = p.inventory.Confirm(confirmCtx, &inventorypb.ConfirmRequest{
+		ReservationId: *session.InventoryReservationID,
+	})
+	if err != nil {
+		return fmt.Errorf("confirm reservation %s: %w", *session.InventoryReservationID, err)
+	}
+
+	confirmedStatus := d.CheckoutStatusInventoryConfirmed
+	return p.repo.UpdateCheckoutSessionStatus(ctx, &session.ID, &confirmedStatus)
+}
+
+func (p *OutboxPoller) releaseReservation(ctx context.Context, session *r.CheckoutSession) (err error) {
+	start := time.Now()
+	defer func() { p.recordStep(ctx, session.ID, d.CheckoutStepReleaseInventory, start, err) }()
+
+	releaseCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	_, err = p.inventory.Release(releaseCtx, &inventorypb.ReleaseRequest{
+		ReservationId: *session.InventoryReservationID,
+	})
+	if isGone(err) {
+		// already released or expired, the stock is back in the pool either way
+		p.logger.Info("reservation already gone", "session_id", session.ID, "reservation_id", *session.InventoryReservationID)
+		return nil
+	}
+	return err
+}
+
+func (p *OutboxPoller) refundPayment(ctx context.Context, session *r.CheckoutSession) (err error) {
+	start := time.Now()
+	defer func() { p.recordStep(ctx, session.ID, d.CheckoutStepRefund, start, err) }()
+
+	refundCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	_, err = p.payment.Refund(refundCtx, &paymentpb.RefundRequest{CheckoutId: session.ID})
+	return err
+}
+
+// completeSession writes the CheckoutCompleted outbox event of a session whose inventory is confirmed
+func (p *OutboxPoller) completeSession(ctx context.Context, session *r.CheckoutSession) (err error) {
+	start := time.Now()
+	defer func() { p.recordStep(ctx, session.ID, d.CheckoutStepComplete, start, err) }()
+
+	var s d.CartSnapshot
+	if err := json.Unmarshal(session.CartSnapshot, &s); err != nil {
+		return fmt.Errorf("unmarshal cart snapshot: %w", err)
+	}
+
+	payload := d.NewCheckoutCompletedEvent(session.ID, session.UserID, &s, session.UpdatedAt)
+
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal checkout payload: %w", err)
+	}
+
+	completedStatus := d.CheckoutStatusCompleted
+	if err := p.repo.CompleteCheckoutSession(ctx, &session.ID, payloadJSON, &completedStatus); err != nil {
+		return fmt.Errorf("complete checkout: %w", err)
+	}
+	return nil
+}
+
+// releasePromoCode gives back the use of the promo code the session took when its snapshot was built.
+// Product-service gives a checkout's use back once however often it is released.
+func (p *OutboxPoller) releasePromoCode(ctx context.Context, session *r.CheckoutSession) error {
+	var s d.CartSnapshot
+	if err := json.Unmarshal(session.CartSnapshot, &s); err != nil {
+		return fmt.Errorf("unmarshal cart snapshot: %w", err)
+	}
+	if s.PromoCode == "" {
+		return nil
+	}
+	releaseCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	_, err := p.product.ReleasePromotion(releaseCtx, &productpb.ReleasePromotionRequest{Code: s.PromoCode, CheckoutId: session.ID})
+	return err
+}
+
+// recordStep adds a recovery call to the saga step log, failing to write it doesn't stop the recovery
+func (p *OutboxPoller) recordStep(ctx context.Context, checkoutID string, step d.CheckoutStep, start time.Time, stepErr error) {
+	if err := p.repo.RecordCheckoutStep(ctx, r.NewStepRecord(checkoutID, step, start, stepErr)); err != nil {
+		p.logger.Warn("failed to record checkout step", "session_id", checkoutID, "step", step, "error", err)
+	}
+}
+
+// isGone reports whether inventory no longer holds the reservation (not found, expired or already released)
+func isGone(err error) bool {
+	if err == nil {
+		return false
+	}
+	var st interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &st) {
+		return false
+	}
+	code := st.GRPCStatus().Code()
+	return code == codes.NotFound || code == codes.FailedPrecondition
+}
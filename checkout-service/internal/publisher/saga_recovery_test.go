@@ -0,0 +1,272 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	d "github.com/fjod/go_cart/checkout-service/domain"
+	r "github.com/fjod/go_cart/checkout-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func stuckSession(id string, st d.CheckoutStatus, reservationID, paymentID *string) *r.CheckoutSession {
+	snapshotJSON, _ := json.Marshal(&d.CartSnapshot{
+		Items:      []d.CartSnapshotItem{{ProductID: 1, Quantity: 1}},
+		Currency:   "USD",
+		CapturedAt: time.Now(),
+	})
+	return &r.CheckoutSession{
+		ID:                     id,
+		UserID:                 "user1",
+		CartSnapshot:           snapshotJSON,
+		Status:                 st,
+		InventoryReservationID: reservationID,
+		PaymentID:              paymentID,
+		CreatedAt:              time.Now(),
+		UpdatedAt:              time.Now(),
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestRecovery_InitiatedIsFailed(t *testing.T) {
+	mockRepo := &MockRepository{
+		StuckSessions: []*r.CheckoutSession{stuckSession("checkout-1", d.CheckoutStatusInitiated, nil, nil)},
+	}
+	mockInventory := &MockInventoryServiceClient{}
+	mockPayment := &MockPaymentServiceClient{}
+
+	poller := NewOutboxPoller(mockRepo, mockInventory, mockPayment, &MockProductServiceClient{}, slog.Default())
+	poller.recoverStuckSessions(context.Background())
+
+	assert.Equal(t, []string{"checkout-1"}, mockRepo.FailedIDs)
+	assert.Empty(t, mockInventory.ReleaseIds)
+	assert.Empty(t, mockPayment.RefundIds)
+	assert.Equal(t, 0, mockRepo.CompleteCheckoutCallCount)
+}
+
+func TestRecovery_InventoryReservedReleasesReservation(t *testing.T) {
+	mockRepo := &MockRepository{
+		StuckSessions: []*r.CheckoutSession{
+			stuckSession("checkout-1", d.CheckoutStatusInventoryReserved, strPtr("reservation-1"), nil),
+		},
+	}
+	mockInventory := &MockInventoryServiceClient{}
+	mockPayment := &MockPaymentServiceClient{}
+
+	poller := NewOutboxPoller(mockRepo, mockInventory, mockPayment, &MockProductServiceClient{}, slog.Default())
+	poller.recoverStuckSessions(context.Background())
+
+	assert.Equal(t, []string{"reservation-1"}, mockInventory.ReleaseIds)
+	assert.Empty(t, mockPayment.RefundIds)
+	assert.Equal(t, []string{"checkout-1"}, mockRepo.FailedIDs)
+}
+
+func TestRecovery_PaymentPendingRefundsAndReleases(t *testing.T) {
+	mockRepo := &MockRepository{
+		StuckSessions: []*r.CheckoutSession{
+			stuckSession("checkout-1", d.CheckoutStatusPaymentPending, strPtr("reservation-1"), nil),
+		},
+	}
+	mockInventory := &MockInventoryServiceClient{}
+	mockPayment := &MockPaymentServiceClient{}
+
+	poller := NewOutboxPoller(mockRepo, mockInventory, mockPayment, &MockProductServiceClient{}, slog.Default())
+	poller.recoverStuckSessions(context.Background())
+
+	// the charge may have gone through without payment_id being stored
+	assert.Equal(t, []string{"checkout-1"}, mockPayment.RefundIds)
+	assert.Equal(t, []string{"reservation-1"}, mockInventory.ReleaseIds)
+	assert.Equal(t, []string{"checkout-1"}, mockRepo.FailedIDs)
+	assert.Contains(t, mockRepo.FailureReasons[0], string(d.CheckoutStatusPaymentPending))
+}
+
+func TestRecovery_RefundErrorKeepsSession(t *testing.T) {
+	mockRepo := &MockRepository{
+		StuckSessions: []*r.CheckoutSession{
+			stuckSession("checkout-1", d.CheckoutStatusPaymentPending, strPtr("reservation-1"), nil),
+		},
+	}
+	mockInventory := &MockInventoryServiceClient{}
+	mockPayment := &MockPaymentServiceClient{RefundErr: errors.New("payment unavailable")}
+
+	poller := NewOutboxPoller(mockRepo, mockInventory, mockPayment, &MockProductServiceClient{}, slog.Default())
+	poller.recoverStuckSessions(context.Background())
+
+	// retried on the next tick, nothing is released before the money is back
+	assert.Empty(t, mockInventory.ReleaseIds)
+	assert.Empty(t, mockRepo.FailedIDs)
+}
+
+func TestRecovery_ReleaseErrorKeepsSession(t *testing.T) {
+	mockRepo := &MockRepository{
+		StuckSessions: []*r.CheckoutSession{
+			stuckSession("checkout-1", d.CheckoutStatusInventoryReserved, strPtr("reservation-1"), nil),
+		},
+	}
+	mockInventory := &MockInventoryServiceClient{ReleaseErr: status.Error(codes.Unavailable, "inventory unavailable")}
+
+	poller := NewOutboxPoller(mockRepo, mockInventory, &MockPaymentServiceClient{}, &MockProductServiceClient{}, slog.Default())
+	poller.recoverStuckSessions(context.Background())
+
+	assert.Empty(t, mockRepo.FailedIDs)
+}
+
+func TestRecovery_ReservationAlreadyGone(t *testing.T) {
+	mockRepo := &MockRepository{
+		StuckSessions: []*r.CheckoutSession{
+			stuckSession("checkout-1", d.CheckoutStatusInventoryReserved, strPtr("reservation-1"), nil),
+		},
+	}
+	mockInventory := &MockInventoryServiceClient{ReleaseErr: status.Error(codes.FailedPrecondition, "reservation has expired")}
+
+	poller := NewOutboxPoller(mockRepo, mockInventory, &MockPaymentServiceClient{}, &MockProductServiceClient{}, slog.Default())
+	poller.recoverStuckSessions(context.Background())
+
+	assert.Equal(t, []string{"checkout-1"}, mockRepo.FailedIDs)
+}
+
+func TestRecovery_PaidWithExpiredReservationIsRefunded(t *testing.T) {
+	mockRepo := &MockRepository{
+		StuckSessions: []*r.CheckoutSession{
+			stuckSession("checkout-1", d.CheckoutStatusPaymentCompleted, strPtr("reservation-1"), strPtr("payment-1")),
+		},
+	}
+	mockInventory := &MockInventoryServiceClient{
+		ConfirmErr: status.Error(codes.FailedPrecondition, "reservation has expired"),
+		ReleaseErr: status.Error(codes.FailedPrecondition, "invalid reservation status"),
+	}
+	mockPayment := &MockPaymentServiceClient{}
+
+	poller := NewOutboxPoller(mockRepo, mockInventory, mockPayment, &MockProductServiceClient{}, slog.Default())
+	poller.recoverStuckSessions(context.Background())
+
+	assert.Equal(t, []string{"checkout-1"}, mockPayment.RefundIds)
+	assert.Equal(t, []string{"checkout-1"}, mockRepo.FailedIDs)
+	assert.Equal(t, 0, mockRepo.CompleteCheckoutCallCount)
+}
+
+func TestRecovery_UnknownStatusIsSkipped(t *testing.T) {
+	mockRepo := &MockRepository{
+		StuckSessions: []*r.CheckoutSession{stuckSession("checkout-1", "UNKNOWN", nil, nil)},
+	}
+
+	poller := NewOutboxPoller(mockRepo, &MockInventoryServiceClient{}, &MockPaymentServiceClient{}, &MockProductServiceClient{}, slog.Default())
+	poller.recoverStuckSessions(context.Background())
+
+	assert.Empty(t, mockRepo.FailedIDs)
+	assert.Equal(t, 0, mockRepo.CompleteCheckoutCallCount)
+}
+
+func TestRecovery_MixedStatuses(t *testing.T) {
+	mockRepo := &MockRepository{
+		StuckSessions: []*r.CheckoutSession{
+			stuckSession("checkout-reserved", d.CheckoutStatusInventoryReserved, strPtr("reservation-1"), nil),
+			stuckSession("checkout-paid", d.CheckoutStatusPaymentCompleted, strPtr("reservation-2"), strPtr("payment-2")),
+			stuckSession("checkout-confirmed", d.CheckoutStatusInventoryConfirmed, strPtr("reservation-3"), strPtr("payment-3")),
+		},
+	}
+	mockInventory := &MockInventoryServiceClient{}
+	mockPayment := &MockPaymentServiceClient{}
+
+	poller := NewOutboxPoller(mockRepo, mockInventory, mockPayment, &MockProductServiceClient{}, slog.Default())
+	poller.recoverStuckSessions(context.Background())
+
+	require.Equal(t, []string{"checkout-reserved"}, mockRepo.FailedIDs)
+	assert.Equal(t, []string{"reservation-1"}, mockInventory.ReleaseIds)
+	assert.Equal(t, []string{"reservation-2"}, mockInventory.ConfirmIds)
+	assert.Equal(t, []string{"checkout-paid", "checkout-confirmed"}, mockRepo.CompletedCheckoutIDs)
+	assert.Empty(t, mockPayment.RefundIds)
+}
+
+func TestRecovery_RecordsSteps(t *testing.T) {
+	mockRepo := &MockRepository{
+		StuckSessions: []*r.CheckoutSession{
+			stuckSession("checkout-1", d.CheckoutStatusPaymentPending, strPtr("reservation-1"), nil),
+		},
+	}
+	mockPayment := &MockPaymentServiceClient{}
+
+	poller := NewOutboxPoller(mockRepo, &MockInventoryServiceClient{}, mockPayment, &MockProductServiceClient{}, slog.Default())
+	poller.recoverStuckSessions(context.Background())
+
+	require.Len(t, mockRepo.Steps, 2)
+	assert.Equal(t, d.CheckoutStepRefund, mockRepo.Steps[0].Step)
+	assert.Equal(t, d.CheckoutStepReleaseInventory, mockRepo.Steps[1].Step)
+	for _, step := range mockRepo.Steps {
+		assert.Equal(t, "checkout-1", step.CheckoutID)
+		assert.Equal(t, d.StepOutcomeSucceeded, step.Outcome)
+	}
+}
+
+func TestRecovery_RecordsFailedStep(t *testing.T) {
+	mockRepo := &MockRepository{
+		StuckSessions: []*r.CheckoutSession{
+			stuckSession("checkout-1", d.CheckoutStatusPaymentCompleted, strPtr("reservation-1"), strPtr("payment-1")),
+		},
+	}
+	mockInventory := &MockInventoryServiceClient{ConfirmErr: status.Error(codes.Unavailable, "inventory unavailable")}
+
+	poller := NewOutboxPoller(mockRepo, mockInventory, &MockPaymentServiceClient{}, &MockProductServiceClient{}, slog.Default())
+	poller.recoverStuckSessions(context.Background())
+
+	require.Len(t, mockRepo.Steps, 1)
+	assert.Equal(t, d.CheckoutStepConfirmInventory, mockRepo.Steps[0].Step)
+	assert.Equal(t, d.StepOutcomeFailed, mockRepo.Steps[0].Outcome)
+	require.NotNil(t, mockRepo.Steps[0].Error)
+	assert.Contains(t, *mockRepo.Steps[0].Error, "inventory unavailable")
+}
+
+// promoSession is a stuck session whose snapshot took a use of the TEN code
+func promoSession(id string, st d.CheckoutStatus) *r.CheckoutSession {
+	session := stuckSession(id, st, strPtr("reservation-1"), nil)
+	session.CartSnapshot, _ = json.Marshal(&d.CartSnapshot{
+		Items:     []d.CartSnapshotItem{{ProductID: 1, Quantity: 1}},
+		Currency:  "USD",
+		PromoCode: "TEN",
+	})
+	return session
+}
+
+func TestRecovery_FailedSessionReleasesPromoCode(t *testing.T) {
+	mockRepo := &MockRepository{StuckSessions: []*r.CheckoutSession{promoSession("checkout-1", d.CheckoutStatusInventoryReserved)}}
+	mockProduct := &MockProductServiceClient{}
+
+	poller := NewOutboxPoller(mockRepo, &MockInventoryServiceClient{}, &MockPaymentServiceClient{}, mockProduct, slog.Default())
+	poller.recoverStuckSessions(context.Background())
+
+	assert.Equal(t, []string{"checkout-1"}, mockRepo.FailedIDs)
+	require.Len(t, mockProduct.Released, 1)
+	assert.Equal(t, "TEN", mockProduct.Released[0].Code)
+	assert.Equal(t, "checkout-1", mockProduct.Released[0].CheckoutId)
+}
+
+func TestRecovery_PromoReleaseErrorKeepsSession(t *testing.T) {
+	mockRepo := &MockRepository{StuckSessions: []*r.CheckoutSession{promoSession("checkout-1", d.CheckoutStatusInventoryReserved)}}
+	mockProduct := &MockProductServiceClient{ReleaseErr: status.Error(codes.Unavailable, "product unavailable")}
+
+	poller := NewOutboxPoller(mockRepo, &MockInventoryServiceClient{}, &MockPaymentServiceClient{}, mockProduct, slog.Default())
+	poller.recoverStuckSessions(context.Background())
+
+	assert.Empty(t, mockRepo.FailedIDs, "the session is failed once the use is given back")
+}
+
+func TestRecovery_CompletedSessionKeepsPromoCodeUse(t *testing.T) {
+	mockRepo := &MockRepository{StuckSessions: []*r.CheckoutSession{promoSession("checkout-1", d.CheckoutStatusInventoryConfirmed)}}
+	mockProduct := &MockProductServiceClient{}
+
+	poller := NewOutboxPoller(mockRepo, &MockInventoryServiceClient{}, &MockPaymentServiceClient{}, mockProduct, slog.Default())
+	poller.recoverStuckSessions(context.Background())
+
+	assert.Equal(t, []string{"checkout-1"}, mockRepo.CompletedCheckoutIDs)
+	assert.Empty(t, mockProduct.Released)
+}
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_checkout_user_created;
+ALTER TABLE checkout_sessions DROP COLUMN IF EXISTS failure_reason;
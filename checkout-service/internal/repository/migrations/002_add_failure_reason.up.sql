@@ -0,0 +1,5 @@
+ALTER TABLE checkout_sessions ADD COLUMN failure_reason TEXT;
+
+CREATE INDEX idx_checkout_user_created ON checkout_sessions(user_id, created_at DESC);
+
+COMMENT ON COLUMN checkout_sessions.failure_reason IS 'Why the checkout ended up FAILED, returned by GetCheckout';
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_checkout_in_progress;
@@ -0,0 +1,2 @@
+CREATE INDEX idx_checkout_in_progress ON checkout_sessions(updated_at)
+    WHERE status NOT IN ('COMPLETED', 'FAILED');
@@ -0,0 +1,15 @@
+CREATE TABLE checkout_steps (
+                                id BIGSERIAL PRIMARY KEY,
+                                checkout_id UUID NOT NULL REFERENCES checkout_sessions(id),
+                                step VARCHAR(50) NOT NULL,
+                                attempt INT NOT NULL,
+                                outcome VARCHAR(20) NOT NULL,
+                                error TEXT,
+                                duration_ms BIGINT NOT NULL,
+                                created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_checkout_steps_checkout ON checkout_steps(checkout_id, id);
+
+COMMENT ON TABLE checkout_steps IS 'Saga step log: one row per downstream call made for a checkout';
+COMMENT ON COLUMN checkout_steps.attempt IS 'Counts calls of the same step for the checkout, recovery retries get attempt > 1';
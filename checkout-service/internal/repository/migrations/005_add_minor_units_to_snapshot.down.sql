@@ -0,0 +1,8 @@
+UPDATE checkout_sessions cs
+SET cart_snapshot = (cs.cart_snapshot - 'total_amount_minor') || jsonb_build_object(
+        'items', COALESCE((
+            SELECT jsonb_agg(e.item - 'unit_price_minor' - 'subtotal_minor' - 'currency' ORDER BY e.ord)
+            FROM jsonb_array_elements(cs.cart_snapshot->'items') WITH ORDINALITY AS e(item, ord)
+        ), '[]'::JSONB));
+
+COMMENT ON COLUMN checkout_sessions.cart_snapshot IS 'Snapshot of cart items at checkout time for audit and compensation';
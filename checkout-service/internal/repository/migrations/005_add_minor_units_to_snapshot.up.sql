@@ -0,0 +1,14 @@
+-- Snapshots written before minor units only carry float prices; every one of them is in USD (2 fraction digits)
+UPDATE checkout_sessions cs
+SET cart_snapshot = cs.cart_snapshot || jsonb_build_object(
+        'total_amount_minor', ROUND((cs.cart_snapshot->>'total_amount')::NUMERIC * 100)::BIGINT,
+        'items', COALESCE((
+            SELECT jsonb_agg(e.item || jsonb_build_object(
+                       'unit_price_minor', ROUND((e.item->>'unit_price')::NUMERIC * 100)::BIGINT,
+                       'subtotal_minor', ROUND((e.item->>'subtotal')::NUMERIC * 100)::BIGINT,
+                       'currency', cs.currency) ORDER BY e.ord)
+            FROM jsonb_array_elements(cs.cart_snapshot->'items') WITH ORDINALITY AS e(item, ord)
+        ), '[]'::JSONB))
+WHERE NOT cs.cart_snapshot ? 'total_amount_minor';
+
+COMMENT ON COLUMN checkout_sessions.cart_snapshot IS 'Snapshot of cart items at checkout time for audit and compensation, prices in minor units (*_minor) next to legacy floats';
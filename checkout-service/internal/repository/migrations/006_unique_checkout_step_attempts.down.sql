@@ -0,0 +1 @@
+ALTER TABLE checkout_steps DROP CONSTRAINT IF EXISTS checkout_steps_attempt_unique;
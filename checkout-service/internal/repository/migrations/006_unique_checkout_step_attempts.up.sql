@@ -0,0 +1,10 @@
+-- renumber attempts that concurrent writers gave the same number before the constraint existed
+UPDATE checkout_steps s
+SET attempt = n.attempt
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY checkout_id, step ORDER BY id) AS attempt
+    FROM checkout_steps
+) n
+WHERE s.id = n.id AND s.attempt <> n.attempt;
+
+ALTER TABLE checkout_steps ADD CONSTRAINT checkout_steps_attempt_unique UNIQUE (checkout_id, step, attempt);
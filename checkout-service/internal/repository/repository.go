@@ -12,13 +12,22 @@ import (
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 var (
 	ErrIdempotencyKeyNotFound = errors.New("idempotencyKey not found")
+	ErrCheckoutNotFound       = errors.New("checkout session not found")
 )
 
+// sessionColumns lists checkout_sessions columns in the order scanSession expects them.
+// stuckGracePeriod is how long a session may sit in a non-terminal status before recovery takes it over,
+// it must stay below the inventory reservation TTL
+const stuckGracePeriod = `INTERVAL '1 minute'`
+
+const sessionColumns = `cs.id, cs.user_id, cs.cart_snapshot, cs.status, cs.idempotency_key, cs.inventory_reservation_id,
+        cs.payment_id, cs.total_amount, cs.currency, cs.failure_reason, cs.created_at, cs.updated_at`
+
 // CheckoutSession represents a checkout session in the database.
 // Maps to the checkout_sessions table.
 type CheckoutSession struct {
@@ -31,6 +40,7 @@ type CheckoutSession struct {
 	PaymentID              *string          `db:"payment_id"`
 	TotalAmount            string           `db:"total_amount"`
 	Currency               string           `db:"currency"`
+	FailureReason          *string          `db:"failure_reason"`
 	CreatedAt              time.Time        `db:"created_at"`
 	UpdatedAt              time.Time        `db:"updated_at"`
 }
@@ -44,6 +54,35 @@ type OutboxEvent struct {
 	ProcessedAt *time.Time `db:"processed_at"` // can be nil
 }
 
+// CheckoutStepRecord is one attempt of a saga step.
+// Maps to the checkout_steps table.
+type CheckoutStepRecord struct {
+	ID         int64          `db:"id"`
+	CheckoutID string         `db:"checkout_id"`
+	Step       d.CheckoutStep `db:"step"`
+	Attempt    int            `db:"attempt"`
+	Outcome    d.StepOutcome  `db:"outcome"`
+	Error      *string        `db:"error"`
+	Duration   time.Duration  `db:"duration_ms"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
+
+// NewStepRecord builds the record of a step attempt that started at start and ended with stepErr
+func NewStepRecord(checkoutID string, step d.CheckoutStep, start time.Time, stepErr error) *CheckoutStepRecord {
+	record := &CheckoutStepRecord{
+		CheckoutID: checkoutID,
+		Step:       step,
+		Outcome:    d.StepOutcomeSucceeded,
+		Duration:   time.Since(start),
+	}
+	if stepErr != nil {
+		msg := stepErr.Error()
+		record.Outcome = d.StepOutcomeFailed
+		record.Error = &msg
+	}
+	return record
+}
+
 type Credentials struct {
 	Host              string
 	Port              int
@@ -63,12 +102,18 @@ type RepoInterface interface {
 	GetCheckoutSessionByIdempotencyKey(ctx context.Context, key string) (*string, *d.CheckoutStatus, error)
 	CreateCheckoutSession(ctx context.Context, session *CheckoutSession) error
 	UpdateCheckoutSessionStatus(ctx context.Context, id *string, s *d.CheckoutStatus) error
+	FailCheckoutSession(ctx context.Context, id *string, reason string) error
+	ClaimInitiatedSession(ctx context.Context, id *string) (bool, error)
 	SetReservation(ctx context.Context, id *string, s *d.CheckoutStatus, reserveId *string) error
 	SetPayment(ctx context.Context, id *string, s *d.CheckoutStatus, payId *string) error
 	CompleteCheckoutSession(ctx context.Context, id *string, snapshot []byte, s *d.CheckoutStatus) error
 	GetUnprocessedEvents(ctx context.Context, limit int) ([]*OutboxEvent, error)
 	MarkEventAsProcessed(ctx context.Context, id int) error
 	GetStuckSessions(ctx context.Context) ([]*CheckoutSession, error)
+	GetCheckoutSession(ctx context.Context, id string) (*CheckoutSession, error)
+	ListCheckoutSessions(ctx context.Context, userID string, limit, offset int) ([]*CheckoutSession, error)
+	RecordCheckoutStep(ctx context.Context, step *CheckoutStepRecord) error
+	GetCheckoutSteps(ctx context.Context, checkoutID string) ([]*CheckoutStepRecord, error)
 }
 
 func NewRepository(cred *Credentials) (*Repository, error) {
@@ -172,6 +217,46 @@ func (r *Repository) UpdateCheckoutSessionStatus(ctx context.Context, id *string
 	return nil
 }
 
+func (r *Repository) FailCheckoutSession(ctx context.Context, id *string, reason string) error {
+	query := `UPDATE checkout_sessions SET status = $1, failure_reason = $2, updated_at = NOW() WHERE id = $3`
+	result, update := r.db.ExecContext(ctx, query,
+		d.CheckoutStatusFailed,
+		reason,
+		*id)
+
+	if update != nil {
+		return fmt.Errorf("fail checkout session: %w", update)
+	}
+	rows, e := result.RowsAffected()
+	if e != nil {
+		return fmt.Errorf("checking rows affected: %w", e)
+	}
+	if rows == 0 {
+		return fmt.Errorf("checkout session not found: %s", *id)
+	}
+	return nil
+}
+
+// ClaimInitiatedSession takes an INITIATED session for its saga and reports whether it got it.
+// Only a session recovery can't see yet is claimed, and the claim restarts its grace period, so a session is
+// either run by its saga or failed by recovery, never both.
+func (r *Repository) ClaimInitiatedSession(ctx context.Context, id *string) (bool, error) {
+	query := `UPDATE checkout_sessions SET updated_at = NOW()
+               WHERE id = $1 AND status = $2 AND updated_at >= NOW() - ` + stuckGracePeriod
+	result, update := r.db.ExecContext(ctx, query,
+		*id,
+		d.CheckoutStatusInitiated)
+
+	if update != nil {
+		return false, fmt.Errorf("claim checkout session: %w", update)
+	}
+	rows, e := result.RowsAffected()
+	if e != nil {
+		return false, fmt.Errorf("checking rows affected: %w", e)
+	}
+	return rows == 1, nil
+}
+
 func (r *Repository) SetReservation(ctx context.Context, id *string, s *d.CheckoutStatus, reserveId *string) error {
 	query := `UPDATE checkout_sessions SET status = $1, updated_at = NOW(), inventory_reservation_id = $2 WHERE id = $3`
 	result, update := r.db.ExecContext(ctx, query,
@@ -307,15 +392,14 @@ func (r *Repository) MarkEventAsProcessed(ctx context.Context, id int) error {
 	return nil
 }
 
+// GetStuckSessions returns sessions that stopped in a non-terminal status.
+// COMPLETED is written together with its outbox event, so a completed session is never stuck.
 func (r *Repository) GetStuckSessions(ctx context.Context) ([]*CheckoutSession, error) {
 	query := `
-        SELECT cs.*
+        SELECT ` + sessionColumns + `
         FROM checkout_sessions cs
-        LEFT JOIN outbox_events oe ON oe.aggregate_id = cs.id
-        WHERE cs.status = 'PAYMENT_COMPLETED'
-          AND cs.updated_at < NOW() - INTERVAL '5 minutes'  -- Grace period
-          AND oe.id IS NULL  -- No outbox event exists
-          `
+        WHERE cs.status NOT IN ('COMPLETED', 'FAILED')
+          AND cs.updated_at < NOW() - ` + stuckGracePeriod
 
 	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
@@ -323,21 +407,69 @@ func (r *Repository) GetStuckSessions(ctx context.Context) ([]*CheckoutSession,
 	}
 	defer rows.Close()
 
+	return scanSessions(rows)
+}
+
+func (r *Repository) GetCheckoutSession(ctx context.Context, id string) (*CheckoutSession, error) {
+	query := `SELECT ` + sessionColumns + ` FROM checkout_sessions cs WHERE cs.id = $1`
+
+	session, err := scanSession(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCheckoutNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query checkout session: %w", err)
+	}
+	return session, nil
+}
+
+func (r *Repository) ListCheckoutSessions(ctx context.Context, userID string, limit, offset int) ([]*CheckoutSession, error) {
+	query := `
+        SELECT ` + sessionColumns + `
+        FROM checkout_sessions cs
+        WHERE cs.user_id = $1
+        ORDER BY cs.created_at DESC, cs.id
+        LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query checkout sessions: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSessions(rows)
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSession(row rowScanner) (*CheckoutSession, error) {
+	p := &CheckoutSession{}
+	err := row.Scan(
+		&p.ID,
+		&p.UserID,
+		&p.CartSnapshot,
+		&p.Status,
+		&p.IdempotencyKey,
+		&p.InventoryReservationID,
+		&p.PaymentID,
+		&p.TotalAmount,
+		&p.Currency,
+		&p.FailureReason,
+		&p.CreatedAt,
+		&p.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func scanSessions(rows *sql.Rows) ([]*CheckoutSession, error) {
 	var sessions []*CheckoutSession
 	for rows.Next() {
-		p := &CheckoutSession{}
-		err := rows.Scan(
-			&p.ID,
-			&p.UserID,
-			&p.CartSnapshot,
-			&p.Status,
-			&p.IdempotencyKey,
-			&p.InventoryReservationID,
-			&p.PaymentID,
-			&p.TotalAmount,
-			&p.Currency,
-			&p.CreatedAt,
-			&p.UpdatedAt)
+		p, err := scanSession(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan session: %w", err)
 		}
@@ -350,3 +482,82 @@ func (r *Repository) GetStuckSessions(ctx context.Context) ([]*CheckoutSession,
 
 	return sessions, nil
 }
+
+// maxRecordStepTries bounds how often RecordCheckoutStep picks the next attempt number again
+// after another writer took it
+const maxRecordStepTries = 5
+
+// RecordCheckoutStep appends a step attempt, the attempt number is the next one for this checkout and step.
+// The saga and recovery may record the same step at once; the unique (checkout_id, step, attempt)
+// constraint turns away the second writer, which retries with the following number.
+func (r *Repository) RecordCheckoutStep(ctx context.Context, step *CheckoutStepRecord) error {
+	query := `
+        INSERT INTO checkout_steps (checkout_id, step, attempt, outcome, error, duration_ms, created_at)
+        SELECT $1, $2, COALESCE(MAX(attempt), 0) + 1, $3, $4, $5, NOW()
+        FROM checkout_steps
+        WHERE checkout_id = $1 AND step = $2
+        RETURNING id, attempt, created_at`
+
+	var err error
+	for try := 0; try < maxRecordStepTries; try++ {
+		err = r.db.QueryRowContext(ctx, query,
+			step.CheckoutID,
+			step.Step,
+			step.Outcome,
+			step.Error,
+			step.Duration.Milliseconds(),
+		).Scan(&step.ID, &step.Attempt, &step.CreatedAt)
+		if !isUniqueViolation(err) {
+			break
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("insert checkout step: %w", err)
+	}
+	return nil
+}
+
+// isUniqueViolation reports whether err is Postgres rejecting a duplicate key
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// GetCheckoutSteps returns the step log of a checkout in the order the steps were recorded
+func (r *Repository) GetCheckoutSteps(ctx context.Context, checkoutID string) ([]*CheckoutStepRecord, error) {
+	query := `
+        SELECT id, checkout_id, step, attempt, outcome, error, duration_ms, created_at
+        FROM checkout_steps
+        WHERE checkout_id = $1
+        ORDER BY id`
+
+	rows, err := r.db.QueryContext(ctx, query, checkoutID)
+	if err != nil {
+		return nil, fmt.Errorf("query checkout steps: %w", err)
+	}
+	defer rows.Close()
+
+	steps := make([]*CheckoutStepRecord, 0)
+	for rows.Next() {
+		var step CheckoutStepRecord
+		var durationMs int64
+		if err := rows.Scan(
+			&step.ID,
+			&step.CheckoutID,
+			&step.Step,
+			&step.Attempt,
+			&step.Outcome,
+			&step.Error,
+			&durationMs,
+			&step.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan checkout step: %w", err)
+		}
+		step.Duration = time.Duration(durationMs) * time.Millisecond
+		steps = append(steps, &step)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate checkout steps: %w", err)
+	}
+	return steps, nil
+}
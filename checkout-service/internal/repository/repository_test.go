@@ -3,6 +3,9 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -294,6 +297,7 @@ func TestUpdateCheckoutSession_StatusProgression(t *testing.T) {
 		d.CheckoutStatusInventoryReserved,
 		d.CheckoutStatusPaymentPending,
 		d.CheckoutStatusPaymentCompleted,
+		d.CheckoutStatusInventoryConfirmed,
 		d.CheckoutStatusCompleted,
 	}
 
@@ -487,3 +491,234 @@ func TestGetStuck_Success(t *testing.T) {
 	assert.Equal(t, 1, len(sessions))
 	assert.Equal(t, sessionID, sessions[0].ID)
 }
+
+func TestGetStuck_InventoryConfirmed(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sessionID := uuid.New().String()
+
+	query := `INSERT INTO checkout_sessions (id, user_id, cart_snapshot, idempotency_key,  status, total_amount, created_at, updated_at) 
+               VALUES ($1, $2, $3, $4, $5, $6, NOW(),  NOW() - INTERVAL '6 minutes')`
+
+	_, insertErr := repo.db.ExecContext(ctx, query,
+		sessionID,
+		"user-123",
+		[]byte(`{}`),
+		"confirmed-key",
+		"INVENTORY_CONFIRMED",
+		"100.00")
+	require.NoError(t, insertErr)
+
+	sessions, err := repo.GetStuckSessions(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(sessions))
+	assert.Equal(t, d.CheckoutStatusInventoryConfirmed, sessions[0].Status)
+}
+
+func TestGetStuck_EveryNonTerminalStatus(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	query := `INSERT INTO checkout_sessions (id, user_id, cart_snapshot, idempotency_key,  status, total_amount, created_at, updated_at) 
+               VALUES ($1, $2, $3, $4, $5, $6, NOW(),  NOW() - $7::INTERVAL)`
+
+	insert := func(status, age string) string {
+		id := uuid.New().String()
+		_, err := repo.db.ExecContext(ctx, query, id, "user-123", []byte(`{}`), id, status, "100.00", age)
+		require.NoError(t, err)
+		return id
+	}
+
+	initiated := insert("INITIATED", "6 minutes")
+	reserved := insert("INVENTORY_RESERVED", "6 minutes")
+	pending := insert("PAYMENT_PENDING", "6 minutes")
+	insert("COMPLETED", "6 minutes")
+	insert("FAILED", "6 minutes")
+	insert("INVENTORY_RESERVED", "0 minutes") // saga may still be running
+
+	sessions, err := repo.GetStuckSessions(ctx)
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(sessions))
+	for _, s := range sessions {
+		ids = append(ids, s.ID)
+	}
+	assert.ElementsMatch(t, []string{initiated, reserved, pending}, ids)
+}
+
+func TestClaimInitiatedSession(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	query := `INSERT INTO checkout_sessions (id, user_id, cart_snapshot, idempotency_key,  status, total_amount, created_at, updated_at) 
+               VALUES ($1, $2, $3, $4, $5, $6, NOW(),  NOW() - $7::INTERVAL)`
+
+	insert := func(status, age string) string {
+		id := uuid.New().String()
+		_, err := repo.db.ExecContext(ctx, query, id, "user-123", []byte(`{}`), id, status, "100.00", age)
+		require.NoError(t, err)
+		return id
+	}
+
+	fresh := insert("INITIATED", "0 minutes")
+	stuck := insert("INITIATED", "6 minutes") // recovery's now
+	failed := insert("FAILED", "0 minutes")
+
+	claimed, err := repo.ClaimInitiatedSession(ctx, &fresh)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+
+	claimed, err = repo.ClaimInitiatedSession(ctx, &stuck)
+	require.NoError(t, err)
+	assert.False(t, claimed)
+
+	claimed, err = repo.ClaimInitiatedSession(ctx, &failed)
+	require.NoError(t, err)
+	assert.False(t, claimed)
+
+	sessions, err := repo.GetStuckSessions(ctx)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, stuck, sessions[0].ID)
+}
+
+func TestGetCheckoutSession_Success(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sessionID := uuid.New().String()
+	session := &CheckoutSession{
+		ID:             sessionID,
+		UserID:         "user-123",
+		CartSnapshot:   []byte(`{"items": []}`),
+		IdempotencyKey: "get-test-key",
+		TotalAmount:    "100.00",
+	}
+	require.NoError(t, repo.CreateCheckoutSession(ctx, session))
+	require.NoError(t, repo.FailCheckoutSession(ctx, &sessionID, "payment failed"))
+
+	found, err := repo.GetCheckoutSession(ctx, sessionID)
+	require.NoError(t, err)
+	assert.Equal(t, "user-123", found.UserID)
+	assert.Equal(t, d.CheckoutStatusFailed, found.Status)
+	require.NotNil(t, found.FailureReason)
+	assert.Equal(t, "payment failed", *found.FailureReason)
+}
+
+func TestGetCheckoutSession_NotFound(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	_, err := repo.GetCheckoutSession(context.Background(), uuid.New().String())
+	assert.ErrorIs(t, err, ErrCheckoutNotFound)
+}
+
+func TestListCheckoutSessions_ByUser(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	for i, user := range []string{"user-1", "user-1", "user-1", "user-2"} {
+		session := &CheckoutSession{
+			ID:             uuid.New().String(),
+			UserID:         user,
+			CartSnapshot:   []byte(`{}`),
+			IdempotencyKey: fmt.Sprintf("list-key-%d", i),
+			TotalAmount:    "10.00",
+		}
+		require.NoError(t, repo.CreateCheckoutSession(ctx, session))
+	}
+
+	page, err := repo.ListCheckoutSessions(ctx, "user-1", 2, 0)
+	require.NoError(t, err)
+	assert.Len(t, page, 2)
+
+	page, err = repo.ListCheckoutSessions(ctx, "user-1", 2, 2)
+	require.NoError(t, err)
+	assert.Len(t, page, 1)
+}
+
+func TestCheckoutSteps_AttemptsAndOrder(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sessionID := uuid.New().String()
+	require.NoError(t, repo.CreateCheckoutSession(ctx, &CheckoutSession{
+		ID:             sessionID,
+		UserID:         "user-123",
+		CartSnapshot:   []byte(`{}`),
+		Status:         d.CheckoutStatusInitiated,
+		IdempotencyKey: "steps-key",
+		TotalAmount:    "100.00",
+		Currency:       "USD",
+	}))
+
+	start := time.Now()
+	first := NewStepRecord(sessionID, d.CheckoutStepReserveInventory, start, errors.New("inventory unavailable"))
+	require.NoError(t, repo.RecordCheckoutStep(ctx, first))
+	second := NewStepRecord(sessionID, d.CheckoutStepReserveInventory, start, nil)
+	require.NoError(t, repo.RecordCheckoutStep(ctx, second))
+	payment := NewStepRecord(sessionID, d.CheckoutStepProcessPayment, start, nil)
+	require.NoError(t, repo.RecordCheckoutStep(ctx, payment))
+
+	assert.Equal(t, 1, first.Attempt)
+	assert.Equal(t, 2, second.Attempt)
+	assert.Equal(t, 1, payment.Attempt)
+
+	steps, err := repo.GetCheckoutSteps(ctx, sessionID)
+	require.NoError(t, err)
+	require.Len(t, steps, 3)
+	assert.Equal(t, d.StepOutcomeFailed, steps[0].Outcome)
+	require.NotNil(t, steps[0].Error)
+	assert.Equal(t, "inventory unavailable", *steps[0].Error)
+	assert.Equal(t, d.StepOutcomeSucceeded, steps[1].Outcome)
+	assert.Nil(t, steps[1].Error)
+	assert.Equal(t, d.CheckoutStepProcessPayment, steps[2].Step)
+}
+
+func TestCheckoutSteps_ConcurrentWritersGetDistinctAttempts(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sessionID := uuid.New().String()
+	require.NoError(t, repo.CreateCheckoutSession(ctx, &CheckoutSession{
+		ID:             sessionID,
+		UserID:         "user-123",
+		CartSnapshot:   []byte(`{}`),
+		Status:         d.CheckoutStatusPaymentCompleted,
+		IdempotencyKey: "concurrent-steps-key",
+		TotalAmount:    "100.00",
+		Currency:       "USD",
+	}))
+
+	const writers = 4
+	var wg sync.WaitGroup
+	errs := make([]error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = repo.RecordCheckoutStep(ctx, NewStepRecord(sessionID, d.CheckoutStepConfirmInventory, time.Now(), nil))
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	steps, err := repo.GetCheckoutSteps(ctx, sessionID)
+	require.NoError(t, err)
+	require.Len(t, steps, writers)
+	attempts := make(map[int]bool)
+	for _, step := range steps {
+		attempts[step.Attempt] = true
+	}
+	assert.Len(t, attempts, writers, "every attempt number is taken once")
+}
@@ -8,12 +8,16 @@ import (
 
 	cartpb "github.com/fjod/go_cart/cart-service/pkg/proto"
 	d "github.com/fjod/go_cart/checkout-service/domain"
+	"github.com/fjod/go_cart/pkg/money"
 	productpb "github.com/fjod/go_cart/product-service/pkg/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // CartSnapshotItem represents an item in the cart snapshot with price captured at checkout time
 
-func (s *CheckoutServiceImpl) getCart(ctx context.Context, request *d.CheckoutRequest) (*d.CartSnapshot, []byte, error) {
+// getCart snapshots the cart of the request for the checkout with the given id
+func (s *CheckoutServiceImpl) getCart(ctx context.Context, request *d.CheckoutRequest, checkoutID string) (*d.CartSnapshot, []byte, error) {
 	cartRequest := &cartpb.GetCartRequest{
 		UserId: request.UserID,
 	}
@@ -31,50 +35,154 @@ func (s *CheckoutServiceImpl) getCart(ctx context.Context, request *d.CheckoutRe
 	}
 
 	// Fetch prices and build cart snapshot
-	snapshot, err := s.buildCartSnapshot(ctx, cartItems)
+	snapshot, err := s.buildCartSnapshot(ctx, cartItems, cart.GetCart().GetPromoCode(), checkoutID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to build cart snapshot: %w", err)
 	}
 
 	snapshotJSON, err := json.Marshal(snapshot)
 	if err != nil {
+		s.releasePromoCode(ctx, checkoutID, snapshot.PromoCode)
 		return nil, nil, fmt.Errorf("failed to marshal cart snapshot: %w", err)
 	}
 	return snapshot, snapshotJSON, nil
 }
 
-// buildCartSnapshot fetches current prices from product service and creates a snapshot
-func (s *CheckoutServiceImpl) buildCartSnapshot(ctx context.Context, cartItems []*cartpb.CartItem) (*d.CartSnapshot, error) {
+// buildCartSnapshot fetches current prices of every cart line in one product service call and creates a snapshot.
+// A promo code on the cart is checked again, its discount frozen into the snapshot and a use of it taken for the checkout.
+func (s *CheckoutServiceImpl) buildCartSnapshot(ctx context.Context, cartItems []*cartpb.CartItem, promoCode, checkoutID string) (*d.CartSnapshot, error) {
 	snapshot := &d.CartSnapshot{
 		Items:      make([]d.CartSnapshotItem, 0, len(cartItems)),
-		Currency:   "USD",
+		Currency:   money.DefaultCurrency,
 		CapturedAt: time.Now(),
 	}
 
-	var totalAmount float64
+	ids := make([]int64, len(cartItems))
+	for i, item := range cartItems {
+		ids[i] = item.ProductId
+	}
+
+	productCtx, cancel := context.WithTimeout(ctx, s.product.timeout)
+	defer cancel()
+	resp, err := s.product.productClient.GetProductsByIds(productCtx, &productpb.GetProductsByIdsRequest{Ids: ids})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products: %w", err)
+	}
+	products := make(map[int64]*productpb.Product, len(resp.Products))
+	for _, p := range resp.Products {
+		products[p.Id] = p
+	}
 
-	// TODO: add GetProducts request to fetch multiple products at once, use context.WithTimeout for request context
+	totalAmount := money.Zero(snapshot.Currency)
 	for _, item := range cartItems {
-		product, err := s.product.productClient.GetProduct(ctx, &productpb.GetProductRequest{
-			Id: item.ProductId,
-		})
-		if err != nil {
-			return nil, fmt.Errorf("failed to get product %d: %w", item.ProductId, err)
+		product, ok := products[item.ProductId]
+		if !ok {
+			return nil, fmt.Errorf("product %d not found", item.ProductId)
+		}
+		if product.ArchivedAt != "" {
+			return nil, fmt.Errorf("product %d is no longer available", item.ProductId)
 		}
 
-		subtotal := product.Product.Price * float64(item.Quantity)
+		unitPrice, sku, err := itemPrice(product, item.VariantId)
+		if err != nil {
+			return nil, err
+		}
+		subtotal := unitPrice.Multiply(int64(item.Quantity))
 
 		snapshot.Items = append(snapshot.Items, d.CartSnapshotItem{
 			ProductID:   item.ProductId,
-			ProductName: product.Product.Name,
+			VariantID:   item.VariantId,
+			SKU:         sku,
+			ProductName: product.Name,
 			Quantity:    item.Quantity,
-			UnitPrice:   product.Product.Price,
+			UnitPrice:   unitPrice,
 			Subtotal:    subtotal,
 		})
 
-		totalAmount += subtotal
+		totalAmount, err = totalAmount.Add(subtotal)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add product %d to total: %w", item.ProductId, err)
+		}
 	}
 
+	snapshot.Subtotal = totalAmount
+	snapshot.Discount = money.Zero(totalAmount.Currency)
 	snapshot.TotalAmount = totalAmount
+	if promoCode != "" {
+		if err := s.applyPromotion(ctx, snapshot, promoCode, checkoutID); err != nil {
+			return nil, err
+		}
+	}
 	return snapshot, nil
 }
+
+// applyPromotion asks product-service what the promo code takes off the snapshot lines, records it and
+// reserves a use of the code for the checkout. A code that no longer applies or is used up fails the checkout
+// rather than charging more than the cart showed.
+func (s *CheckoutServiceImpl) applyPromotion(ctx context.Context, snapshot *d.CartSnapshot, promoCode, checkoutID string) error {
+	lines := make([]*productpb.PromotionLine, len(snapshot.Items))
+	for i, item := range snapshot.Items {
+		lines[i] = &productpb.PromotionLine{ProductId: item.ProductID, VariantId: item.VariantID, Quantity: item.Quantity}
+	}
+
+	productCtx, cancel := context.WithTimeout(ctx, s.product.timeout)
+	defer cancel()
+	eval, err := s.product.productClient.EvaluatePromotion(productCtx, &productpb.EvaluatePromotionRequest{Code: promoCode, Lines: lines})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound, codes.FailedPrecondition:
+			return fmt.Errorf("%w: %s", ErrPromoCodeInvalid, status.Convert(err).Message())
+		default:
+			return fmt.Errorf("failed to evaluate promo code: %w", err)
+		}
+	}
+	if len(eval.Lines) != len(snapshot.Items) {
+		return fmt.Errorf("promo code evaluation returned %d lines for %d items", len(eval.Lines), len(snapshot.Items))
+	}
+	if eval.SubtotalMinor != snapshot.Subtotal.Amount || eval.Currency != snapshot.Subtotal.Currency {
+		// a price changed between the two product-service calls
+		return fmt.Errorf("promo code evaluated on %d %s, cart subtotal is %d %s",
+			eval.SubtotalMinor, eval.Currency, snapshot.Subtotal.Amount, snapshot.Subtotal.Currency)
+	}
+
+	if err := s.reservePromoCode(ctx, checkoutID, eval.Promotion.GetCode()); err != nil {
+		return err
+	}
+
+	for i, line := range eval.Lines {
+		snapshot.Items[i].Discount = money.New(line.DiscountMinor, snapshot.Subtotal.Currency)
+	}
+	snapshot.PromoCode = eval.Promotion.GetCode()
+	snapshot.Discount = money.New(eval.DiscountMinor, snapshot.Subtotal.Currency)
+	snapshot.TotalAmount = money.New(snapshot.Subtotal.Amount-eval.DiscountMinor, snapshot.Subtotal.Currency)
+	return nil
+}
+
+// itemPrice is the price of the cart line: the variant price for a product sold per variant, the product price
+// otherwise. It also returns the variant SKU.
+func itemPrice(product *productpb.Product, variantID int64) (money.Money, string, error) {
+	if variantID == 0 {
+		if len(product.Variants) > 0 {
+			return money.Money{}, "", fmt.Errorf("product %d is sold per variant, the cart item has none", product.Id)
+		}
+		return productPrice(product), "", nil
+	}
+	for _, v := range product.Variants {
+		if v.Id != variantID {
+			continue
+		}
+		if v.ArchivedAt != "" {
+			return money.Money{}, "", fmt.Errorf("variant %d of product %d is no longer available", variantID, product.Id)
+		}
+		return money.New(v.PriceMinor, v.Currency), v.Sku, nil
+	}
+	return money.Money{}, "", fmt.Errorf("variant %d of product %d not found", variantID, product.Id)
+}
+
+// productPrice reads the exact price, product services without minor units only send the float one
+func productPrice(p *productpb.Product) money.Money {
+	if p.Currency == "" {
+		return money.FromFloat(p.Price, money.DefaultCurrency)
+	}
+	return money.New(p.PriceMinor, p.Currency)
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	d "github.com/fjod/go_cart/checkout-service/domain"
+	t "go.opentelemetry.io/otel/trace"
+)
+
+// asyncQueueSize is how many async checkouts may wait for a free worker before new ones are rejected
+const asyncQueueSize = 100
+
+// sagaJob is an INITIATED session whose saga runs in the background
+type sagaJob struct {
+	sessionID   string
+	snapshot    *d.CartSnapshot
+	userID      int64
+	totalAmount string
+	spanContext t.SpanContext // links the background saga to the request that started it
+}
+
+// enqueueSaga hands the session to the worker pool and returns INITIATED right away.
+// Progress is reported through WatchCheckout and GetCheckout.
+func (s *CheckoutServiceImpl) enqueueSaga(
+	ctx context.Context,
+	sessionID string,
+	snapshot *d.CartSnapshot,
+	userID int64,
+	totalAmount string) (*d.CheckoutResponse, error) {
+
+	job := sagaJob{
+		sessionID:   sessionID,
+		snapshot:    snapshot,
+		userID:      userID,
+		totalAmount: totalAmount,
+		spanContext: t.SpanContextFromContext(ctx),
+	}
+
+	select {
+	case s.jobs <- job:
+		initiatedStatus := d.CheckoutStatusInitiated
+		return &d.CheckoutResponse{
+			CheckoutID: &sessionID,
+			Status:     &initiatedStatus,
+		}, nil
+	default:
+		// nothing but the promo code use was reserved or charged yet, so failing the session is enough
+		failedStatus := d.CheckoutStatusFailed
+		if err := s.fail(ctx, sessionID, ErrCheckoutQueueFull.Error()); err != nil {
+			return nil, err
+		}
+		s.releasePromoCode(ctx, sessionID, snapshot.PromoCode)
+		return &d.CheckoutResponse{
+			CheckoutID: &sessionID,
+			Status:     &failedStatus,
+		}, ErrCheckoutQueueFull
+	}
+}
+
+// RunWorkers processes async checkouts with the given number of workers until ctx is cancelled.
+// A saga that already started is finished even after cancellation, so it doesn't stop between charge and compensation.
+func (s *CheckoutServiceImpl) RunWorkers(ctx context.Context, workers int) {
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case job := <-s.jobs:
+					s.runJob(context.WithoutCancel(ctx), job)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func (s *CheckoutServiceImpl) runJob(ctx context.Context, job sagaJob) {
+	ctx = t.ContextWithRemoteSpanContext(ctx, job.spanContext)
+	ctx, span := s.tracer.Start(ctx, "checkout_saga_async")
+	defer span.End()
+
+	// a job that waited too long belongs to recovery, which may be failing it right now
+	claimed, err := s.repo.ClaimInitiatedSession(ctx, &job.sessionID)
+	if err != nil {
+		s.logger.Error("failed to claim async checkout", "checkout_id", job.sessionID, "error", err)
+		return
+	}
+	if !claimed {
+		s.logger.Warn("async checkout already moved on, skipping", "checkout_id", job.sessionID)
+		return
+	}
+
+	resp, err := s.runSaga(ctx, job.sessionID, job.snapshot, job.userID, job.totalAmount)
+	if err != nil {
+		s.logger.Error("async checkout failed", "checkout_id", job.sessionID, "error", err)
+		return
+	}
+	s.logger.Info("async checkout finished", "checkout_id", job.sessionID, "status", getStatus(resp))
+}
+
+func getStatus(resp *d.CheckoutResponse) d.CheckoutStatus {
+	if resp == nil || resp.Status == nil {
+		return ""
+	}
+	return *resp.Status
+}
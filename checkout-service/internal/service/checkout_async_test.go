@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	cartpb "github.com/fjod/go_cart/cart-service/pkg/proto"
+	d "github.com/fjod/go_cart/checkout-service/domain"
+	r "github.com/fjod/go_cart/checkout-service/internal/repository"
+	ipb "github.com/fjod/go_cart/inventory-service/pkg/proto"
+	paymentpb "github.com/fjod/go_cart/payment-service/pkg/proto"
+	productpb "github.com/fjod/go_cart/product-service/pkg/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAsyncTestService(repo *MockRepository) *CheckoutServiceImpl {
+	mockCart := &MockCartServiceClient{
+		CartResponse: &cartpb.CartResponse{
+			Cart: &cartpb.Cart{
+				Cart: []*cartpb.CartItem{{ProductId: 1, Quantity: 2}},
+			},
+		},
+	}
+	mockProduct := &MockProductServiceClient{
+		Products: map[int64]*productpb.Product{
+			1: {Id: 1, Name: "Widget", Price: 29.99},
+		},
+	}
+	mockInventory := &MockInventoryServiceClient{
+		reserveResponse: &ipb.ReserveResponse{ReservationId: "reserveId"},
+	}
+	mockPay := &MockPaymentServiceClient{
+		cr: &paymentpb.ChargeResponse{Status: paymentpb.ChargeStatus_CHARGE_STATUS_SUCCESS},
+	}
+	return newTestCheckoutService(repo, mockCart, mockProduct, mockInventory, mockPay)
+}
+
+func TestInitiateCheckout_Async(t *testing.T) {
+	mockRepo := &MockRepository{GetErr: r.ErrIdempotencyKeyNotFound}
+	svc := newAsyncTestService(mockRepo)
+
+	resp, err := svc.InitiateCheckout(context.Background(), &d.CheckoutRequest{
+		UserID:         123,
+		IdempotencyKey: "async-key",
+		Async:          true,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, d.CheckoutStatusInitiated, *resp.Status)
+	assert.Nil(t, mockRepo.ReservationId) // saga hasn't started yet
+	require.Len(t, svc.jobs, 1)
+
+	job := <-svc.jobs
+	assert.Equal(t, *resp.CheckoutID, job.sessionID)
+	mockRepo.Session = &r.CheckoutSession{ID: job.sessionID, Status: d.CheckoutStatusInitiated}
+	svc.runJob(context.Background(), job)
+
+	assert.Equal(t, "reserveId", *mockRepo.ReservationId)
+	assert.Contains(t, mockRepo.Statuses, d.CheckoutStatusInventoryConfirmed)
+	assert.Equal(t, resp.CheckoutID, mockRepo.OutboxId)
+}
+
+func TestRunJob_SkipsRecoveredSession(t *testing.T) {
+	mockRepo := &MockRepository{
+		Session: &r.CheckoutSession{ID: "checkout-1", Status: d.CheckoutStatusFailed},
+	}
+	svc := newAsyncTestService(mockRepo)
+
+	svc.runJob(context.Background(), sagaJob{sessionID: "checkout-1", snapshot: &d.CartSnapshot{}})
+
+	assert.Nil(t, mockRepo.ReservationId)
+	assert.Empty(t, mockRepo.Statuses)
+}
+
+func TestRunJob_SkipsSessionItCannotClaim(t *testing.T) {
+	mockRepo := &MockRepository{
+		Session:  &r.CheckoutSession{ID: "checkout-1", Status: d.CheckoutStatusInitiated},
+		ClaimErr: errors.New("connection reset"),
+	}
+	svc := newAsyncTestService(mockRepo)
+
+	svc.runJob(context.Background(), sagaJob{sessionID: "checkout-1", snapshot: &d.CartSnapshot{}})
+
+	assert.Nil(t, mockRepo.ReservationId)
+	assert.Empty(t, mockRepo.Statuses)
+}
+
+func TestInitiateCheckout_AsyncQueueFull(t *testing.T) {
+	mockRepo := &MockRepository{GetErr: r.ErrIdempotencyKeyNotFound}
+	svc := newAsyncTestService(mockRepo)
+	for i := 0; i < asyncQueueSize; i++ {
+		svc.jobs <- sagaJob{}
+	}
+
+	resp, err := svc.InitiateCheckout(context.Background(), &d.CheckoutRequest{
+		UserID:         123,
+		IdempotencyKey: "async-key",
+		Async:          true,
+	})
+
+	assert.ErrorIs(t, err, ErrCheckoutQueueFull)
+	assert.Equal(t, d.CheckoutStatusFailed, *resp.Status)
+	require.NotNil(t, mockRepo.FailureReason)
+	assert.Equal(t, ErrCheckoutQueueFull.Error(), *mockRepo.FailureReason)
+}
+
+func TestRunWorkers_StopsOnCancel(t *testing.T) {
+	svc := newAsyncTestService(&MockRepository{})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		svc.RunWorkers(ctx, 2)
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("workers did not stop after cancel")
+	}
+}
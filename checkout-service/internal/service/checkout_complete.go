@@ -9,19 +9,15 @@ import (
 	d "github.com/fjod/go_cart/checkout-service/domain"
 )
 
-func (s *CheckoutServiceImpl) complete(ctx context.Context, checkoutId string, status d.CheckoutStatus, snapshot *d.CartSnapshot, userId string) error {
+func (s *CheckoutServiceImpl) complete(ctx context.Context, checkoutId string, status d.CheckoutStatus, snapshot *d.CartSnapshot, userId string) (err error) {
 
 	if !d.CanTransitionTo(status, d.CheckoutStatusCompleted) {
 		return IllegalTransitionError
 	}
-	payload := map[string]interface{}{
-		"checkout_id":  checkoutId,
-		"user_id":      userId,
-		"items":        snapshot.Items,
-		"total_amount": snapshot.TotalAmount,
-		"currency":     snapshot.Currency,
-		"completed_at": time.Now(),
-	}
+	start := time.Now()
+	defer func() { s.recordStep(ctx, checkoutId, d.CheckoutStepComplete, start, err) }()
+
+	payload := d.NewCheckoutCompletedEvent(checkoutId, userId, snapshot, time.Now())
 
 	payloadJSON, err := json.Marshal(payload)
 	if err != nil {
@@ -33,5 +29,6 @@ func (s *CheckoutServiceImpl) complete(ctx context.Context, checkoutId string, s
 	if err != nil {
 		return err
 	}
+	s.notify(checkoutId, completedStatus, "")
 	return nil
 }
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	d "github.com/fjod/go_cart/checkout-service/domain"
+	inventorypb "github.com/fjod/go_cart/inventory-service/pkg/proto"
+)
+
+// confirmInventory turns the reservation into a permanent stock deduction once the payment went through.
+// Without it the reservation would expire after its TTL and the stock would return to the pool.
+func (s *CheckoutServiceImpl) confirmInventory(ctx context.Context, checkoutId string, status d.CheckoutStatus, reservationId string) (err error) {
+	if !d.CanTransitionTo(status, d.CheckoutStatusInventoryConfirmed) {
+		return IllegalTransitionError
+	}
+	start := time.Now()
+	defer func() { s.recordStep(ctx, checkoutId, d.CheckoutStepConfirmInventory, start, err) }()
+
+	confirmRequest := &inventorypb.ConfirmRequest{
+		ReservationId: reservationId,
+	}
+
+	inventoryCtx, cancel := context.WithTimeout(ctx, s.inventory.timeout)
+	defer cancel()
+	_, err = s.inventory.inventoryClient.Confirm(inventoryCtx, confirmRequest)
+	if err != nil {
+		return err
+	}
+
+	confirmedStatus := d.CheckoutStatusInventoryConfirmed
+	err = s.repo.UpdateCheckoutSessionStatus(ctx, &checkoutId, &confirmedStatus)
+	if err != nil {
+		return err
+	}
+	s.notify(checkoutId, confirmedStatus, "")
+	return nil
+}
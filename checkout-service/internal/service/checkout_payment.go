@@ -4,20 +4,25 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	d "github.com/fjod/go_cart/checkout-service/domain"
 	paymentpb "github.com/fjod/go_cart/payment-service/pkg/proto"
 )
 
-func (s *CheckoutServiceImpl) processPayment(ctx context.Context, checkoutId string, status d.CheckoutStatus, amount string) error {
+func (s *CheckoutServiceImpl) processPayment(ctx context.Context, checkoutId string, status d.CheckoutStatus, amount string) (err error) {
 	if !d.CanTransitionTo(status, d.CheckoutStatusPaymentPending) {
 		return IllegalTransitionError
 	}
+	start := time.Now()
+	defer func() { s.recordStep(ctx, checkoutId, d.CheckoutStepProcessPayment, start, err) }()
+
 	pendingStatus := d.CheckoutStatusPaymentPending
-	err := s.repo.UpdateCheckoutSessionStatus(ctx, &checkoutId, &pendingStatus)
+	err = s.repo.UpdateCheckoutSessionStatus(ctx, &checkoutId, &pendingStatus)
 	if err != nil {
 		return err
 	}
+	s.notify(checkoutId, pendingStatus, "")
 
 	paymentCtx, cancel := context.WithTimeout(ctx, s.payment.timeout)
 	defer cancel()
@@ -36,6 +41,7 @@ func (s *CheckoutServiceImpl) processPayment(ctx context.Context, checkoutId str
 		if dbError != nil {
 			return dbError
 		}
+		s.notify(checkoutId, paidStatus, "")
 		return nil
 	}
 
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	productpb "github.com/fjod/go_cart/product-service/pkg/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// reservePromoCode takes a use of the promo code for the checkout before its discount is frozen into the snapshot.
+// Product-service only counts it while the code is under its usage limit, so concurrent checkouts can't
+// take a single-use code twice.
+func (s *CheckoutServiceImpl) reservePromoCode(ctx context.Context, checkoutID, code string) error {
+	productCtx, cancel := context.WithTimeout(ctx, s.product.timeout)
+	defer cancel()
+	_, err := s.product.productClient.RedeemPromotion(productCtx, &productpb.RedeemPromotionRequest{Code: code, CheckoutId: checkoutID})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound, codes.FailedPrecondition:
+			return fmt.Errorf("%w: %s", ErrPromoCodeInvalid, status.Convert(err).Message())
+		default:
+			// the use may have been taken before the call failed
+			s.releasePromoCode(ctx, checkoutID, code)
+			return fmt.Errorf("failed to reserve promo code: %w", err)
+		}
+	}
+	return nil
+}
+
+// releasePromoCode gives back the use a failed checkout took. A use that can't be given back is only logged,
+// the code then counts one use too many.
+func (s *CheckoutServiceImpl) releasePromoCode(ctx context.Context, checkoutID, code string) {
+	if code == "" {
+		return
+	}
+	productCtx, cancel := context.WithTimeout(ctx, s.product.timeout)
+	defer cancel()
+	_, err := s.product.productClient.ReleasePromotion(productCtx, &productpb.ReleasePromotionRequest{Code: code, CheckoutId: checkoutID})
+	if err != nil {
+		s.logger.Warn("failed to release promo code", "session_id", checkoutID, "promo_code", code, "error", err)
+	}
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+
+	r "github.com/fjod/go_cart/checkout-service/internal/repository"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// GetCheckout returns the checkout session if it belongs to the user.
+// Sessions of other users are reported as not found, so checkout ids can't be probed.
+func (s *CheckoutServiceImpl) GetCheckout(ctx context.Context, checkoutID string, userID int64) (*r.CheckoutSession, error) {
+	session, err := s.repo.GetCheckoutSession(ctx, checkoutID)
+	if errors.Is(err, r.ErrCheckoutNotFound) {
+		return nil, ErrCheckoutNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checkout: %w", err)
+	}
+
+	if session.UserID != fmt.Sprintf("%d", userID) {
+		return nil, ErrCheckoutNotFound
+	}
+	return session, nil
+}
+
+// ListCheckouts returns a page of the user's checkouts, newest first, and the token for the next page.
+func (s *CheckoutServiceImpl) ListCheckouts(ctx context.Context, userID int64, pageSize int32, pageToken string) ([]*r.CheckoutSession, string, error) {
+	limit := int(pageSize)
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	offset, err := decodePageToken(pageToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// fetch one extra row to find out whether there is a next page
+	sessions, err := s.repo.ListCheckoutSessions(ctx, fmt.Sprintf("%d", userID), limit+1, offset)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list checkouts: %w", err)
+	}
+
+	nextToken := ""
+	if len(sessions) > limit {
+		sessions = sessions[:limit]
+		nextToken = encodePageToken(offset + limit)
+	}
+	return sessions, nextToken, nil
+}
+
+func encodePageToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+func decodePageToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, ErrInvalidPageToken
+	}
+	offset, err := strconv.Atoi(string(raw))
+	if err != nil || offset < 0 {
+		return 0, ErrInvalidPageToken
+	}
+	return offset, nil
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	r "github.com/fjod/go_cart/checkout-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newQueryTestService(repo *MockRepository) *CheckoutServiceImpl {
+	return newTestCheckoutService(repo, &MockCartServiceClient{}, &MockProductServiceClient{}, &MockInventoryServiceClient{}, &MockPaymentServiceClient{})
+}
+
+func TestGetCheckout_Found(t *testing.T) {
+	reason := "payment failed: NO_FUNDS"
+	mockRepo := &MockRepository{
+		Session: &r.CheckoutSession{ID: "checkout-1", UserID: "123", FailureReason: &reason},
+	}
+	svc := newQueryTestService(mockRepo)
+
+	session, err := svc.GetCheckout(context.Background(), "checkout-1", 123)
+
+	require.NoError(t, err)
+	assert.Equal(t, "checkout-1", session.ID)
+	assert.Equal(t, reason, *session.FailureReason)
+}
+
+func TestGetCheckout_NotFound(t *testing.T) {
+	svc := newQueryTestService(&MockRepository{})
+
+	_, err := svc.GetCheckout(context.Background(), "checkout-1", 123)
+
+	assert.ErrorIs(t, err, ErrCheckoutNotFound)
+}
+
+func TestGetCheckout_OtherUser(t *testing.T) {
+	mockRepo := &MockRepository{
+		Session: &r.CheckoutSession{ID: "checkout-1", UserID: "456"},
+	}
+	svc := newQueryTestService(mockRepo)
+
+	_, err := svc.GetCheckout(context.Background(), "checkout-1", 123)
+
+	assert.ErrorIs(t, err, ErrCheckoutNotFound)
+}
+
+func TestListCheckouts_Paging(t *testing.T) {
+	sessions := make([]*r.CheckoutSession, 5)
+	for i := range sessions {
+		sessions[i] = &r.CheckoutSession{ID: fmt.Sprintf("checkout-%d", i), UserID: "123"}
+	}
+	mockRepo := &MockRepository{Sessions: sessions}
+	svc := newQueryTestService(mockRepo)
+
+	page, next, err := svc.ListCheckouts(context.Background(), 123, 2, "")
+	require.NoError(t, err)
+	assert.Len(t, page, 2)
+	assert.Equal(t, "checkout-0", page[0].ID)
+	assert.NotEmpty(t, next)
+
+	page, next, err = svc.ListCheckouts(context.Background(), 123, 2, next)
+	require.NoError(t, err)
+	assert.Len(t, page, 2)
+	assert.Equal(t, "checkout-2", page[0].ID)
+	assert.NotEmpty(t, next)
+
+	page, next, err = svc.ListCheckouts(context.Background(), 123, 2, next)
+	require.NoError(t, err)
+	assert.Len(t, page, 1)
+	assert.Equal(t, "checkout-4", page[0].ID)
+	assert.Empty(t, next)
+}
+
+func TestListCheckouts_DefaultAndMaxPageSize(t *testing.T) {
+	mockRepo := &MockRepository{}
+	svc := newQueryTestService(mockRepo)
+
+	_, _, err := svc.ListCheckouts(context.Background(), 123, 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, defaultPageSize+1, mockRepo.ListLimit)
+
+	_, _, err = svc.ListCheckouts(context.Background(), 123, 1000, "")
+	require.NoError(t, err)
+	assert.Equal(t, maxPageSize+1, mockRepo.ListLimit)
+}
+
+func TestListCheckouts_InvalidPageToken(t *testing.T) {
+	svc := newQueryTestService(&MockRepository{})
+
+	_, _, err := svc.ListCheckouts(context.Background(), 123, 10, "not a token!")
+
+	assert.ErrorIs(t, err, ErrInvalidPageToken)
+}
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	d "github.com/fjod/go_cart/checkout-service/domain"
+	paymentpb "github.com/fjod/go_cart/payment-service/pkg/proto"
+)
+
+// refundPayment returns the money of a paid checkout that could not be completed
+func (s *CheckoutServiceImpl) refundPayment(ctx context.Context, checkoutId string) (err error) {
+	start := time.Now()
+	defer func() { s.recordStep(ctx, checkoutId, d.CheckoutStepRefund, start, err) }()
+
+	paymentCtx, cancel := context.WithTimeout(ctx, s.payment.timeout)
+	defer cancel()
+	_, err = s.payment.paymentClient.Refund(paymentCtx, &paymentpb.RefundRequest{CheckoutId: checkoutId})
+	return err
+}
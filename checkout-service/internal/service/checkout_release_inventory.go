@@ -2,18 +2,23 @@ package service
 
 import (
 	"context"
+	"time"
 
+	d "github.com/fjod/go_cart/checkout-service/domain"
 	inventorypb "github.com/fjod/go_cart/inventory-service/pkg/proto"
 )
 
-func (s *CheckoutServiceImpl) releaseInventory(ctx context.Context, reservationId string) error {
+func (s *CheckoutServiceImpl) releaseInventory(ctx context.Context, checkoutId string, reservationId string) (err error) {
+	start := time.Now()
+	defer func() { s.recordStep(ctx, checkoutId, d.CheckoutStepReleaseInventory, start, err) }()
+
 	releaseRequest := &inventorypb.ReleaseRequest{
 		ReservationId: reservationId,
 	}
 
 	inventoryCtx, cancel := context.WithTimeout(ctx, s.inventory.timeout)
 	defer cancel()
-	_, err := s.inventory.inventoryClient.Release(inventoryCtx, releaseRequest) // as inventory is stub, it will always return success.
+	_, err = s.inventory.inventoryClient.Release(inventoryCtx, releaseRequest) // as inventory is stub, it will always return success.
 	if err != nil {
 		return err
 	}
@@ -2,15 +2,19 @@ package service
 
 import (
 	"context"
+	"time"
 
 	d "github.com/fjod/go_cart/checkout-service/domain"
 	inventorypb "github.com/fjod/go_cart/inventory-service/pkg/proto"
 )
 
-func (s *CheckoutServiceImpl) reserveInventory(ctx context.Context, checkoutId string, items []*d.CartSnapshotItem, status d.CheckoutStatus) (*string, error) {
+func (s *CheckoutServiceImpl) reserveInventory(ctx context.Context, checkoutId string, items []*d.CartSnapshotItem, status d.CheckoutStatus) (_ *string, err error) {
 	if !d.CanTransitionTo(status, d.CheckoutStatusInventoryReserved) {
 		return nil, IllegalTransitionError
 	}
+	start := time.Now()
+	defer func() { s.recordStep(ctx, checkoutId, d.CheckoutStepReserveInventory, start, err) }()
+
 	reqItems := mapItems(items)
 	request := inventorypb.ReserveRequest{
 		CheckoutId: checkoutId,
@@ -28,6 +32,7 @@ func (s *CheckoutServiceImpl) reserveInventory(ctx context.Context, checkoutId s
 	if dbError != nil {
 		return nil, dbError
 	}
+	s.notify(checkoutId, newStatus, "")
 	return &result.ReservationId, nil
 }
 
@@ -36,6 +41,7 @@ func mapItems(items []*d.CartSnapshotItem) []*inventorypb.ReservationItem {
 	for i, item := range items {
 		resItems[i] = &inventorypb.ReservationItem{
 			ProductId: item.ProductID,
+			VariantId: item.VariantID,
 			Quantity:  item.Quantity,
 		}
 	}
@@ -4,10 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	d "github.com/fjod/go_cart/checkout-service/domain"
 	r "github.com/fjod/go_cart/checkout-service/internal/repository"
-	paymentpb "github.com/fjod/go_cart/payment-service/pkg/proto"
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel/attribute"
 	t "go.opentelemetry.io/otel/trace"
@@ -38,12 +38,12 @@ func (s *CheckoutServiceImpl) InitiateCheckout(
 		}, nil
 	}
 
-	snapshot, snapshotJSON, err2 := s.getCart(ctx, request)
+	sessionID := uuid.New().String()
+	snapshot, snapshotJSON, err2 := s.getCart(ctx, request, sessionID)
 	if err2 != nil {
 		return nil, err2
 	}
 
-	sessionID := uuid.New().String()
 	session := &r.CheckoutSession{
 		ID:                     sessionID,
 		UserID:                 fmt.Sprintf("%d", request.UserID),
@@ -52,7 +52,7 @@ func (s *CheckoutServiceImpl) InitiateCheckout(
 		IdempotencyKey:         request.IdempotencyKey,
 		InventoryReservationID: nil,
 		PaymentID:              nil,
-		TotalAmount:            fmt.Sprintf("%.2f", snapshot.TotalAmount),
+		TotalAmount:            snapshot.TotalAmount.String(),
 		Currency:               snapshot.Currency,
 	}
 
@@ -61,20 +61,38 @@ func (s *CheckoutServiceImpl) InitiateCheckout(
 	span.SetAttributes(attribute.String("IdempotencyKey", request.IdempotencyKey))
 
 	if err := s.repo.CreateCheckoutSession(ctx, session); err != nil {
+		s.releasePromoCode(ctx, sessionID, snapshot.PromoCode)
 		return nil, fmt.Errorf("failed to create checkout session: %w", err)
 	}
 
 	span.End()
+	s.notify(sessionID, d.CheckoutStatusInitiated, "")
+
+	if request.Async {
+		return s.enqueueSaga(ctx, sessionID, snapshot, request.UserID, session.TotalAmount)
+	}
+	return s.runSaga(ctx, sessionID, snapshot, request.UserID, session.TotalAmount)
+}
+
+// runSaga drives an INITIATED session through reserve → pay → confirm → complete, compensating on failure.
+// A failed session gives back the use of its promo code.
+func (s *CheckoutServiceImpl) runSaga(
+	ctx context.Context,
+	sessionID string,
+	snapshot *d.CartSnapshot,
+	userID int64,
+	totalAmount string) (*d.CheckoutResponse, error) {
 
 	reserveStatus := d.CheckoutStatusInitiated
 	items := mapItemsToItemPointers(snapshot.Items)
 	reserveId, reserveError := s.reserveInventory(ctx, sessionID, items, reserveStatus)
 	if reserveError != nil {
 		failedStatus := d.CheckoutStatusFailed
-		err := s.repo.UpdateCheckoutSessionStatus(ctx, &sessionID, &failedStatus)
+		err := s.fail(ctx, sessionID, fmt.Sprintf("inventory reservation failed: %v", reserveError))
 		if err != nil {
 			return nil, fmt.Errorf("failed to set failed status: %w", err)
 		}
+		s.releasePromoCode(ctx, sessionID, snapshot.PromoCode)
 		return &d.CheckoutResponse{
 			CheckoutID: &sessionID,
 			Status:     &failedStatus,
@@ -82,16 +100,17 @@ func (s *CheckoutServiceImpl) InitiateCheckout(
 	}
 
 	reservedStatus := d.CheckoutStatusInventoryReserved
-	payError := s.processPayment(ctx, sessionID, reservedStatus, session.TotalAmount)
+	payError := s.processPayment(ctx, sessionID, reservedStatus, totalAmount)
 	if payError != nil {
 		failedStatus := d.CheckoutStatusFailed
-		err := s.repo.UpdateCheckoutSessionStatus(ctx, &sessionID, &failedStatus)
+		err := s.fail(ctx, sessionID, fmt.Sprintf("payment failed: %v", payError))
 		if err != nil {
 			return nil, fmt.Errorf("failed to set failed status: %w", err)
 		}
+		s.releasePromoCode(ctx, sessionID, snapshot.PromoCode)
 
 		// compensate inventory reservation on payment failure
-		releaseError := s.releaseInventory(ctx, *reserveId)
+		releaseError := s.releaseInventory(ctx, sessionID, *reserveId)
 		if releaseError != nil {
 			return nil, fmt.Errorf("failed to release inventory: %w", releaseError)
 		}
@@ -103,21 +122,37 @@ func (s *CheckoutServiceImpl) InitiateCheckout(
 	}
 
 	paidStatus := d.CheckoutStatusPaymentCompleted
-	completeCheckoutError := s.complete(ctx, sessionID, paidStatus, snapshot, fmt.Sprintf("%d", request.UserID))
+	confirmError := s.confirmInventory(ctx, sessionID, paidStatus, *reserveId)
+	if confirmError != nil {
+		// the customer has already paid, so don't fail the checkout here:
+		// OutboxPoller picks up PAYMENT_COMPLETED sessions, confirms the reservation and completes them.
+		s.logger.Warn("failed to confirm inventory, leaving checkout for recovery",
+			"checkout_id", sessionID,
+			"reservation_id", *reserveId,
+			"error", confirmError,
+		)
+		return &d.CheckoutResponse{
+			CheckoutID: &sessionID,
+			Status:     &paidStatus,
+		}, nil
+	}
+
+	confirmedStatus := d.CheckoutStatusInventoryConfirmed
+	completeCheckoutError := s.complete(ctx, sessionID, confirmedStatus, snapshot, fmt.Sprintf("%d", userID))
 	if completeCheckoutError != nil {
-		refundRequest := &paymentpb.RefundRequest{CheckoutId: sessionID}
-		_, refundErr := s.payment.paymentClient.Refund(ctx, refundRequest)
+		refundErr := s.refundPayment(ctx, sessionID)
 		if refundErr != nil {
 			return nil, fmt.Errorf("failed to refund after failed checkout: %w", refundErr)
 		}
 		failedStatus := d.CheckoutStatusFailed
-		err := s.repo.UpdateCheckoutSessionStatus(ctx, &sessionID, &failedStatus)
+		err := s.fail(ctx, sessionID, fmt.Sprintf("checkout completion failed: %v", completeCheckoutError))
 		if err != nil {
 			return nil, fmt.Errorf("failed to set failed status: %w", err)
 		}
+		s.releasePromoCode(ctx, sessionID, snapshot.PromoCode)
 
 		// compensate inventory reservation on complete checkout failure
-		releaseError := s.releaseInventory(ctx, *reserveId)
+		releaseError := s.releaseInventory(ctx, sessionID, *reserveId)
 		if releaseError != nil {
 			return nil, fmt.Errorf("failed to release inventory: %w", releaseError)
 		}
@@ -135,6 +170,25 @@ func (s *CheckoutServiceImpl) InitiateCheckout(
 	}, nil
 }
 
+// fail marks the session FAILED with the reason and tells the watchers
+func (s *CheckoutServiceImpl) fail(ctx context.Context, sessionID string, reason string) error {
+	if err := s.repo.FailCheckoutSession(ctx, &sessionID, reason); err != nil {
+		return err
+	}
+	s.notify(sessionID, d.CheckoutStatusFailed, reason)
+	return nil
+}
+
+// notify pushes a state transition to the WatchCheckout streams of this instance
+func (s *CheckoutServiceImpl) notify(sessionID string, status d.CheckoutStatus, reason string) {
+	s.hub.publish(d.CheckoutEvent{
+		CheckoutID:    sessionID,
+		Status:        status,
+		FailureReason: reason,
+		OccurredAt:    time.Now(),
+	})
+}
+
 func mapItemsToItemPointers(input []d.CartSnapshotItem) []*d.CartSnapshotItem {
 	result := make([]*d.CartSnapshotItem, len(input))
 	for i, item := range input {
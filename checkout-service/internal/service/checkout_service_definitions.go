@@ -12,6 +12,10 @@ import (
 
 type CheckoutService interface {
 	InitiateCheckout(ctx context.Context, request *d.CheckoutRequest) (*d.CheckoutResponse, error)
+	GetCheckout(ctx context.Context, checkoutID string, userID int64) (*r.CheckoutSession, error)
+	ListCheckouts(ctx context.Context, userID int64, pageSize int32, pageToken string) ([]*r.CheckoutSession, string, error)
+	WatchCheckout(ctx context.Context, checkoutID string, userID int64, send func(d.CheckoutEvent) error) error
+	GetCheckoutHistory(ctx context.Context, checkoutID string, userID int64) ([]*r.CheckoutStepRecord, error)
 }
 
 type CheckoutServiceImpl struct {
@@ -22,6 +26,8 @@ type CheckoutServiceImpl struct {
 	payment   *PaymentHandler
 	tracer    t.Tracer
 	logger    *slog.Logger
+	hub       *statusHub
+	jobs      chan sagaJob // async checkouts waiting for a worker
 }
 
 func NewCheckoutService(
@@ -40,5 +46,7 @@ func NewCheckoutService(
 		payment:   payment,
 		tracer:    otel.Tracer("checkout"),
 		logger:    log,
+		hub:       newStatusHub(),
+		jobs:      make(chan sagaJob, asyncQueueSize),
 	}
 }
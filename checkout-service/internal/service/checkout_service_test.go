@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 
@@ -10,9 +11,12 @@ import (
 	r "github.com/fjod/go_cart/checkout-service/internal/repository"
 	ipb "github.com/fjod/go_cart/inventory-service/pkg/proto"
 	paymentpb "github.com/fjod/go_cart/payment-service/pkg/proto"
+	"github.com/fjod/go_cart/pkg/money"
 	productpb "github.com/fjod/go_cart/product-service/pkg/proto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestInitiateCheckout_NewRequest(t *testing.T) {
@@ -72,9 +76,61 @@ func TestInitiateCheckout_NewRequest(t *testing.T) {
 	// Verify cart snapshot was created with correct total
 	assert.NotNil(t, mockRepo.CreatedSession)
 	assert.Equal(t, "109.97", mockRepo.CreatedSession.TotalAmount)          // (29.99*2) + (49.99*1) snapshot sum is fine
+	assert.Equal(t, 1, mockProduct.ByIdsCalls)                              // one product lookup for the whole cart
 	assert.Equal(t, reserveResponse.ReservationId, *mockRepo.ReservationId) // reserved
 	assert.Equal(t, "109.97", mockPay.PaymentAmount)                        // paid
-	assert.Equal(t, resp.CheckoutID, mockRepo.OutboxId)                     // saved to outbox
+	assert.Equal(t, reserveResponse.ReservationId, mockInventory.ConfirmId) // reservation confirmed
+	assert.Contains(t, mockRepo.Statuses, d.CheckoutStatusInventoryConfirmed)
+	assert.Equal(t, resp.CheckoutID, mockRepo.OutboxId) // saved to outbox
+}
+
+func TestInitiateCheckout_ConfirmFailed(t *testing.T) {
+	mockRepo := &MockRepository{
+		GetKey:    nil,
+		GetStatus: nil,
+		GetErr:    r.ErrIdempotencyKeyNotFound,
+	}
+
+	mockCart := &MockCartServiceClient{
+		CartResponse: &cartpb.CartResponse{
+			Cart: &cartpb.Cart{
+				Cart: []*cartpb.CartItem{
+					{ProductId: 1, Quantity: 2},
+				},
+			},
+		},
+	}
+
+	mockProduct := &MockProductServiceClient{
+		Products: map[int64]*productpb.Product{
+			1: {Id: 1, Name: "Widget", Price: 29.99},
+		},
+	}
+
+	mockInventory := &MockInventoryServiceClient{
+		reserveResponse: &ipb.ReserveResponse{ReservationId: "reserveId-confirm"},
+		confirmErr:      errors.New("inventory unavailable"),
+	}
+	mockPay := &MockPaymentServiceClient{
+		cr: &paymentpb.ChargeResponse{
+			Status: paymentpb.ChargeStatus_CHARGE_STATUS_SUCCESS,
+		},
+	}
+	svc := newTestCheckoutService(mockRepo, mockCart, mockProduct, mockInventory, mockPay)
+
+	req := &d.CheckoutRequest{
+		UserID:         123,
+		IdempotencyKey: "confirm-failed-key",
+	}
+
+	resp, err := svc.InitiateCheckout(context.Background(), req)
+
+	// payment went through, so the checkout is left for recovery instead of being failed
+	require.NoError(t, err)
+	assert.Equal(t, d.CheckoutStatusPaymentCompleted, *resp.Status)
+	assert.Nil(t, mockRepo.OutboxId)
+	assert.Empty(t, mockInventory.ReleaseId)
+	assert.Nil(t, mockRepo.FailureReason)
 }
 
 func TestInitiateCheckout_ReleaseInventory(t *testing.T) {
@@ -137,6 +193,8 @@ func TestInitiateCheckout_ReleaseInventory(t *testing.T) {
 	assert.Equal(t, "109.97", mockRepo.CreatedSession.TotalAmount) // (29.99*2) + (49.99*1)
 	assert.Equal(t, "109.97", mockPay.PaymentAmount)
 	assert.Equal(t, "reserveId-1122", mockInventory.ReleaseId) // we called inventory release for reservationId
+	require.NotNil(t, mockRepo.FailureReason)
+	assert.Contains(t, *mockRepo.FailureReason, "NO_FUNDS")
 }
 
 func TestInitiateCheckout_ReserveFailed(t *testing.T) {
@@ -314,7 +372,7 @@ func TestInitiateCheckout_ProductNotFound(t *testing.T) {
 
 	assert.Error(t, err)
 	assert.Nil(t, resp)
-	assert.Contains(t, err.Error(), "failed to get product 999")
+	assert.Contains(t, err.Error(), "product 999 not found")
 }
 
 func TestReserveInventory(t *testing.T) {
@@ -419,7 +477,304 @@ func TestCompleteCheckout(t *testing.T) {
 	ctx := context.Background()
 
 	snapshot := &d.CartSnapshot{}
-	e := svc.complete(ctx, "checkoutId", d.CheckoutStatusPaymentCompleted, snapshot, "user")
+	e := svc.complete(ctx, "checkoutId", d.CheckoutStatusInventoryConfirmed, snapshot, "user")
 	require.NoError(t, e)
 	assert.Equal(t, "checkoutId", *mockRepo.OutboxId)
 }
+
+func TestCompleteCheckout_IllegalTransition(t *testing.T) {
+	mockRepo := &MockRepository{}
+	svc := newTestCheckoutService(mockRepo, &MockCartServiceClient{}, &MockProductServiceClient{}, &MockInventoryServiceClient{}, &MockPaymentServiceClient{})
+
+	// completing without confirming inventory first is not allowed
+	e := svc.complete(context.Background(), "checkoutId", d.CheckoutStatusPaymentCompleted, &d.CartSnapshot{}, "user")
+	assert.ErrorIs(t, e, IllegalTransitionError)
+	assert.Nil(t, mockRepo.OutboxId)
+}
+
+func TestConfirmInventory(t *testing.T) {
+	mockRepo := &MockRepository{}
+	mockInventory := &MockInventoryServiceClient{}
+	svc := newTestCheckoutService(mockRepo, &MockCartServiceClient{}, &MockProductServiceClient{}, mockInventory, &MockPaymentServiceClient{})
+
+	e := svc.confirmInventory(context.Background(), "checkoutId", d.CheckoutStatusPaymentCompleted, "reserveId")
+	require.NoError(t, e)
+	assert.Equal(t, "reserveId", mockInventory.ConfirmId)
+	assert.Equal(t, []d.CheckoutStatus{d.CheckoutStatusInventoryConfirmed}, mockRepo.Statuses)
+}
+
+func TestInitiateCheckout_TotalsInMinorUnits(t *testing.T) {
+	mockRepo := &MockRepository{GetErr: r.ErrIdempotencyKeyNotFound}
+	mockCart := &MockCartServiceClient{
+		CartResponse: &cartpb.CartResponse{
+			Cart: &cartpb.Cart{
+				Cart: []*cartpb.CartItem{
+					{ProductId: 1, Quantity: 3},
+				},
+			},
+		},
+	}
+	// 0.1 * 3 is 0.30000000000000004 in float64, minor units keep it exact
+	mockProduct := &MockProductServiceClient{
+		Products: map[int64]*productpb.Product{
+			1: {Id: 1, Name: "Sticker", Price: 0.1, PriceMinor: 10, Currency: "USD"},
+		},
+	}
+	mockInventory := &MockInventoryServiceClient{reserveResponse: &ipb.ReserveResponse{ReservationId: "reserveId"}}
+	mockPay := &MockPaymentServiceClient{
+		cr: &paymentpb.ChargeResponse{Status: paymentpb.ChargeStatus_CHARGE_STATUS_SUCCESS},
+	}
+	svc := newTestCheckoutService(mockRepo, mockCart, mockProduct, mockInventory, mockPay)
+
+	_, err := svc.InitiateCheckout(context.Background(), &d.CheckoutRequest{UserID: 123, IdempotencyKey: "minor-key"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "0.30", mockRepo.CreatedSession.TotalAmount)
+	assert.Equal(t, "0.30", mockPay.PaymentAmount)
+
+	var snapshot d.CartSnapshot
+	require.NoError(t, json.Unmarshal(mockRepo.CreatedSession.CartSnapshot, &snapshot))
+	assert.Equal(t, int64(30), snapshot.TotalAmount.Amount)
+	assert.Equal(t, int64(10), snapshot.Items[0].UnitPrice.Amount)
+	assert.Equal(t, int64(30), snapshot.Items[0].Subtotal.Amount)
+
+	var event d.CheckoutCompletedEvent
+	require.NoError(t, json.Unmarshal(mockRepo.OutboxPayload, &event))
+	assert.Equal(t, int64(30), event.TotalAmountMinor)
+	assert.Equal(t, "USD", event.Currency)
+}
+
+func TestCartSnapshot_ReadsLegacyFloatPrices(t *testing.T) {
+	legacy := []byte(`{"items":[{"product_id":1,"product_name":"Widget","quantity":2,"unit_price":29.99,"subtotal":59.98}],"total_amount":59.98,"currency":"USD"}`)
+
+	var snapshot d.CartSnapshot
+	require.NoError(t, json.Unmarshal(legacy, &snapshot))
+
+	assert.Equal(t, int64(5998), snapshot.TotalAmount.Amount)
+	assert.Equal(t, int64(2999), snapshot.Items[0].UnitPrice.Amount)
+	assert.Equal(t, int64(5998), snapshot.Items[0].Subtotal.Amount)
+}
+
+func TestCartSnapshot_DefaultsMissingCurrency(t *testing.T) {
+	legacy := []byte(`{"items":[{"product_id":1,"quantity":1,"unit_price":10}],"total_amount":10}`)
+
+	var snapshot d.CartSnapshot
+	require.NoError(t, json.Unmarshal(legacy, &snapshot))
+
+	assert.Equal(t, money.DefaultCurrency, snapshot.Currency)
+	assert.Equal(t, snapshot.Currency, snapshot.TotalAmount.Currency)
+}
+
+func TestInitiateCheckout_ArchivedProduct(t *testing.T) {
+	mockRepo := &MockRepository{GetErr: r.ErrIdempotencyKeyNotFound}
+	mockCart := &MockCartServiceClient{
+		CartResponse: &cartpb.CartResponse{
+			Cart: &cartpb.Cart{
+				Cart: []*cartpb.CartItem{{ProductId: 1, Quantity: 1}},
+			},
+		},
+	}
+	mockProduct := &MockProductServiceClient{
+		Products: map[int64]*productpb.Product{
+			1: {Id: 1, Name: "Widget", Price: 29.99, ArchivedAt: "2026-01-01T00:00:00Z"},
+		},
+	}
+	svc := newTestCheckoutService(mockRepo, mockCart, mockProduct, &MockInventoryServiceClient{}, &MockPaymentServiceClient{})
+
+	_, err := svc.InitiateCheckout(context.Background(), &d.CheckoutRequest{UserID: 123, IdempotencyKey: "archived-key"})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "product 1 is no longer available")
+	assert.Nil(t, mockRepo.CreatedSession)
+}
+
+func TestInitiateCheckout_VariantPriceAndSKU(t *testing.T) {
+	mockRepo := &MockRepository{GetErr: r.ErrIdempotencyKeyNotFound}
+	mockCart := &MockCartServiceClient{
+		CartResponse: &cartpb.CartResponse{
+			Cart: &cartpb.Cart{
+				Cart: []*cartpb.CartItem{{ProductId: 1, VariantId: 2, Sku: "LAPTOP-32GB-1TB", Quantity: 2}},
+			},
+		},
+	}
+	mockProduct := &MockProductServiceClient{
+		Products: map[int64]*productpb.Product{
+			1: {Id: 1, Name: "Laptop", PriceMinor: 129999, Currency: "USD", Variants: []*productpb.ProductVariant{
+				{Id: 1, Sku: "LAPTOP-16GB-512GB", PriceMinor: 129999, Currency: "USD"},
+				{Id: 2, Sku: "LAPTOP-32GB-1TB", PriceMinor: 159999, Currency: "USD"},
+			}},
+		},
+	}
+	mockInventory := &MockInventoryServiceClient{reserveResponse: &ipb.ReserveResponse{ReservationId: "reserveId"}}
+	mockPay := &MockPaymentServiceClient{
+		cr: &paymentpb.ChargeResponse{Status: paymentpb.ChargeStatus_CHARGE_STATUS_SUCCESS},
+	}
+	svc := newTestCheckoutService(mockRepo, mockCart, mockProduct, mockInventory, mockPay)
+
+	_, err := svc.InitiateCheckout(context.Background(), &d.CheckoutRequest{UserID: 123, IdempotencyKey: "variant-key"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "3199.98", mockRepo.CreatedSession.TotalAmount)
+	require.Len(t, mockInventory.ReserveRequest.Items, 1)
+	assert.Equal(t, int64(2), mockInventory.ReserveRequest.Items[0].VariantId)
+
+	var event d.CheckoutCompletedEvent
+	require.NoError(t, json.Unmarshal(mockRepo.OutboxPayload, &event))
+	require.Len(t, event.Items, 1)
+	assert.Equal(t, int64(2), event.Items[0].VariantID)
+	assert.Equal(t, "LAPTOP-32GB-1TB", event.Items[0].SKU)
+	assert.Equal(t, int64(159999), event.Items[0].UnitPrice.Amount)
+}
+
+func TestInitiateCheckout_VariantRequired(t *testing.T) {
+	mockRepo := &MockRepository{GetErr: r.ErrIdempotencyKeyNotFound}
+	mockCart := &MockCartServiceClient{
+		CartResponse: &cartpb.CartResponse{
+			Cart: &cartpb.Cart{
+				Cart: []*cartpb.CartItem{{ProductId: 1, Quantity: 1}},
+			},
+		},
+	}
+	mockProduct := &MockProductServiceClient{
+		Products: map[int64]*productpb.Product{
+			1: {Id: 1, Name: "Laptop", PriceMinor: 129999, Currency: "USD", Variants: []*productpb.ProductVariant{
+				{Id: 1, Sku: "LAPTOP-16GB-512GB", PriceMinor: 129999, Currency: "USD"},
+			}},
+		},
+	}
+	svc := newTestCheckoutService(mockRepo, mockCart, mockProduct, &MockInventoryServiceClient{}, &MockPaymentServiceClient{})
+
+	_, err := svc.InitiateCheckout(context.Background(), &d.CheckoutRequest{UserID: 123, IdempotencyKey: "no-variant-key"})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "product 1 is sold per variant")
+	assert.Nil(t, mockRepo.CreatedSession)
+}
+
+func TestInitiateCheckout_PromoCode(t *testing.T) {
+	mockRepo := &MockRepository{GetErr: r.ErrIdempotencyKeyNotFound}
+	mockCart := &MockCartServiceClient{
+		CartResponse: &cartpb.CartResponse{
+			Cart: &cartpb.Cart{
+				Cart:      []*cartpb.CartItem{{ProductId: 1, Quantity: 2}, {ProductId: 2, Quantity: 1}},
+				PromoCode: "TEN",
+			},
+		},
+	}
+	mockProduct := &MockProductServiceClient{
+		Products: map[int64]*productpb.Product{
+			1: {Id: 1, Name: "Mouse", PriceMinor: 2500, Currency: "USD"},
+			2: {Id: 2, Name: "Cable", PriceMinor: 1000, Currency: "USD"},
+		},
+		Evaluation: &productpb.EvaluatePromotionResponse{
+			Promotion:     &productpb.Promotion{Code: "TEN"},
+			Lines:         []*productpb.PromotionLineDiscount{{ProductId: 1, DiscountMinor: 500}, {ProductId: 2, DiscountMinor: 0}},
+			SubtotalMinor: 6000,
+			DiscountMinor: 500,
+			TotalMinor:    5500,
+			Currency:      "USD",
+		},
+	}
+	mockInventory := &MockInventoryServiceClient{reserveResponse: &ipb.ReserveResponse{ReservationId: "reserveId"}}
+	mockPay := &MockPaymentServiceClient{
+		cr: &paymentpb.ChargeResponse{Status: paymentpb.ChargeStatus_CHARGE_STATUS_SUCCESS},
+	}
+	svc := newTestCheckoutService(mockRepo, mockCart, mockProduct, mockInventory, mockPay)
+
+	_, err := svc.InitiateCheckout(context.Background(), &d.CheckoutRequest{UserID: 123, IdempotencyKey: "promo-key"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "55.00", mockRepo.CreatedSession.TotalAmount)
+	assert.Equal(t, "55.00", mockPay.PaymentAmount)
+
+	var snapshot d.CartSnapshot
+	require.NoError(t, json.Unmarshal(mockRepo.CreatedSession.CartSnapshot, &snapshot))
+	assert.Equal(t, "TEN", snapshot.PromoCode)
+	assert.Equal(t, int64(6000), snapshot.Subtotal.Amount)
+	assert.Equal(t, int64(500), snapshot.Discount.Amount)
+	assert.Equal(t, int64(500), snapshot.Items[0].Discount.Amount)
+
+	var event d.CheckoutCompletedEvent
+	require.NoError(t, json.Unmarshal(mockRepo.OutboxPayload, &event))
+	assert.Equal(t, "TEN", event.PromoCode)
+	assert.Equal(t, int64(500), event.DiscountMinor)
+	assert.Equal(t, int64(5500), event.TotalAmountMinor)
+
+	require.Len(t, mockProduct.Redeemed, 1)
+	assert.Equal(t, "TEN", mockProduct.Redeemed[0].Code)
+	assert.Equal(t, mockRepo.CreatedSession.ID, mockProduct.Redeemed[0].CheckoutId, "the use is taken when the snapshot is built")
+	assert.Empty(t, mockProduct.Released)
+}
+
+// promoCheckout is a cart with the TEN code applied, priced and evaluated by the product mock
+func promoCheckout() (*MockCartServiceClient, *MockProductServiceClient) {
+	mockCart := &MockCartServiceClient{
+		CartResponse: &cartpb.CartResponse{
+			Cart: &cartpb.Cart{Cart: []*cartpb.CartItem{{ProductId: 1, Quantity: 1}}, PromoCode: "TEN"},
+		},
+	}
+	mockProduct := &MockProductServiceClient{
+		Products: map[int64]*productpb.Product{1: {Id: 1, Name: "Mouse", PriceMinor: 2500, Currency: "USD"}},
+		Evaluation: &productpb.EvaluatePromotionResponse{
+			Promotion:     &productpb.Promotion{Code: "TEN"},
+			Lines:         []*productpb.PromotionLineDiscount{{ProductId: 1, DiscountMinor: 250}},
+			SubtotalMinor: 2500,
+			DiscountMinor: 250,
+			TotalMinor:    2250,
+			Currency:      "USD",
+		},
+	}
+	return mockCart, mockProduct
+}
+
+func TestInitiateCheckout_PromoCodeUsedUp(t *testing.T) {
+	mockRepo := &MockRepository{GetErr: r.ErrIdempotencyKeyNotFound}
+	mockCart, mockProduct := promoCheckout()
+	mockProduct.RedeemErr = status.Error(codes.FailedPrecondition, "promo code cannot be applied: it has reached its usage limit")
+	svc := newTestCheckoutService(mockRepo, mockCart, mockProduct, &MockInventoryServiceClient{}, &MockPaymentServiceClient{})
+
+	_, err := svc.InitiateCheckout(context.Background(), &d.CheckoutRequest{UserID: 123, IdempotencyKey: "used-up-key"})
+
+	require.ErrorIs(t, err, ErrPromoCodeInvalid)
+	assert.Contains(t, err.Error(), "usage limit")
+	assert.Nil(t, mockRepo.CreatedSession)
+	assert.Empty(t, mockProduct.Released)
+}
+
+func TestInitiateCheckout_FailedCheckoutReleasesPromoCode(t *testing.T) {
+	mockRepo := &MockRepository{GetErr: r.ErrIdempotencyKeyNotFound}
+	mockCart, mockProduct := promoCheckout()
+	mockInventory := &MockInventoryServiceClient{reserveResponse: &ipb.ReserveResponse{ReservationId: "reserveId"}}
+	mockPay := &MockPaymentServiceClient{
+		cr: &paymentpb.ChargeResponse{Status: paymentpb.ChargeStatus_CHARGE_STATUS_FAILED},
+	}
+	svc := newTestCheckoutService(mockRepo, mockCart, mockProduct, mockInventory, mockPay)
+
+	_, err := svc.InitiateCheckout(context.Background(), &d.CheckoutRequest{UserID: 123, IdempotencyKey: "declined-key"})
+
+	require.Error(t, err)
+	require.Len(t, mockProduct.Released, 1)
+	assert.Equal(t, "TEN", mockProduct.Released[0].Code)
+	assert.Equal(t, mockProduct.Redeemed[0].CheckoutId, mockProduct.Released[0].CheckoutId)
+}
+
+func TestInitiateCheckout_PromoCodeNoLongerApplies(t *testing.T) {
+	mockRepo := &MockRepository{GetErr: r.ErrIdempotencyKeyNotFound}
+	mockCart := &MockCartServiceClient{
+		CartResponse: &cartpb.CartResponse{
+			Cart: &cartpb.Cart{Cart: []*cartpb.CartItem{{ProductId: 1, Quantity: 1}}, PromoCode: "OLD"},
+		},
+	}
+	mockProduct := &MockProductServiceClient{
+		Products:    map[int64]*productpb.Product{1: {Id: 1, Name: "Mouse", PriceMinor: 2500, Currency: "USD"}},
+		EvaluateErr: status.Error(codes.FailedPrecondition, "promo code cannot be applied: it has expired"),
+	}
+	svc := newTestCheckoutService(mockRepo, mockCart, mockProduct, &MockInventoryServiceClient{}, &MockPaymentServiceClient{})
+
+	_, err := svc.InitiateCheckout(context.Background(), &d.CheckoutRequest{UserID: 123, IdempotencyKey: "old-promo-key"})
+
+	require.ErrorIs(t, err, ErrPromoCodeInvalid)
+	assert.Contains(t, err.Error(), "it has expired")
+	assert.Nil(t, mockRepo.CreatedSession)
+	assert.Empty(t, mockProduct.Redeemed)
+}
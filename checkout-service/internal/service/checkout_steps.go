@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	d "github.com/fjod/go_cart/checkout-service/domain"
+	r "github.com/fjod/go_cart/checkout-service/internal/repository"
+)
+
+// recordStep writes one attempt of a saga step to the step log.
+// The log is for support and debugging, so failing to write it never fails the checkout.
+func (s *CheckoutServiceImpl) recordStep(ctx context.Context, checkoutId string, step d.CheckoutStep, start time.Time, stepErr error) {
+	// the step is logged even when the caller has gone away in the meantime
+	ctx = context.WithoutCancel(ctx)
+	if err := s.repo.RecordCheckoutStep(ctx, r.NewStepRecord(checkoutId, step, start, stepErr)); err != nil {
+		s.logger.Warn("failed to record checkout step", "checkout_id", checkoutId, "step", step, "error", err)
+	}
+}
+
+// GetCheckoutHistory returns the step log of a checkout owned by the user
+func (s *CheckoutServiceImpl) GetCheckoutHistory(ctx context.Context, checkoutID string, userID int64) ([]*r.CheckoutStepRecord, error) {
+	if _, err := s.GetCheckout(ctx, checkoutID, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetCheckoutSteps(ctx, checkoutID)
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	cartpb "github.com/fjod/go_cart/cart-service/pkg/proto"
+	d "github.com/fjod/go_cart/checkout-service/domain"
+	r "github.com/fjod/go_cart/checkout-service/internal/repository"
+	ipb "github.com/fjod/go_cart/inventory-service/pkg/proto"
+	paymentpb "github.com/fjod/go_cart/payment-service/pkg/proto"
+	productpb "github.com/fjod/go_cart/product-service/pkg/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStepsTestService(repo *MockRepository, charge *paymentpb.ChargeResponse) *CheckoutServiceImpl {
+	mockCart := &MockCartServiceClient{
+		CartResponse: &cartpb.CartResponse{
+			Cart: &cartpb.Cart{Cart: []*cartpb.CartItem{{ProductId: 1, Quantity: 1}}},
+		},
+	}
+	mockProduct := &MockProductServiceClient{
+		Products: map[int64]*productpb.Product{1: {Id: 1, Name: "Widget", Price: 29.99}},
+	}
+	mockInventory := &MockInventoryServiceClient{
+		reserveResponse: &ipb.ReserveResponse{ReservationId: "reserveId"},
+	}
+	return newTestCheckoutService(repo, mockCart, mockProduct, mockInventory, &MockPaymentServiceClient{cr: charge})
+}
+
+func stepNames(steps []*r.CheckoutStepRecord) []d.CheckoutStep {
+	names := make([]d.CheckoutStep, 0, len(steps))
+	for _, step := range steps {
+		names = append(names, step.Step)
+	}
+	return names
+}
+
+func TestStepLog_SuccessfulCheckout(t *testing.T) {
+	mockRepo := &MockRepository{GetErr: r.ErrIdempotencyKeyNotFound}
+	svc := newStepsTestService(mockRepo, &paymentpb.ChargeResponse{Status: paymentpb.ChargeStatus_CHARGE_STATUS_SUCCESS})
+
+	resp, err := svc.InitiateCheckout(context.Background(), &d.CheckoutRequest{UserID: 123, IdempotencyKey: "key"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []d.CheckoutStep{
+		d.CheckoutStepReserveInventory,
+		d.CheckoutStepProcessPayment,
+		d.CheckoutStepConfirmInventory,
+		d.CheckoutStepComplete,
+	}, stepNames(mockRepo.Steps))
+	for _, step := range mockRepo.Steps {
+		assert.Equal(t, *resp.CheckoutID, step.CheckoutID)
+		assert.Equal(t, d.StepOutcomeSucceeded, step.Outcome)
+		assert.Nil(t, step.Error)
+	}
+}
+
+func TestStepLog_PaymentDeclined(t *testing.T) {
+	mockRepo := &MockRepository{GetErr: r.ErrIdempotencyKeyNotFound}
+	svc := newStepsTestService(mockRepo, &paymentpb.ChargeResponse{
+		Status:  paymentpb.ChargeStatus_CHARGE_STATUS_FAILED,
+		Refusal: &paymentpb.ChargeResponse_KnownReason{KnownReason: paymentpb.PaymentRefusal_NO_FUNDS},
+	})
+
+	_, err := svc.InitiateCheckout(context.Background(), &d.CheckoutRequest{UserID: 123, IdempotencyKey: "key"})
+	require.Error(t, err)
+
+	require.Equal(t, []d.CheckoutStep{
+		d.CheckoutStepReserveInventory,
+		d.CheckoutStepProcessPayment,
+		d.CheckoutStepReleaseInventory,
+	}, stepNames(mockRepo.Steps))
+
+	payment := mockRepo.Steps[1]
+	assert.Equal(t, d.StepOutcomeFailed, payment.Outcome)
+	require.NotNil(t, payment.Error)
+	assert.Contains(t, *payment.Error, "NO_FUNDS")
+	assert.Equal(t, d.StepOutcomeSucceeded, mockRepo.Steps[2].Outcome)
+}
+
+func TestStepLog_WriteErrorDoesNotFailCheckout(t *testing.T) {
+	mockRepo := &MockRepository{GetErr: r.ErrIdempotencyKeyNotFound, StepErr: errors.New("db down")}
+	svc := newStepsTestService(mockRepo, &paymentpb.ChargeResponse{Status: paymentpb.ChargeStatus_CHARGE_STATUS_SUCCESS})
+
+	resp, err := svc.InitiateCheckout(context.Background(), &d.CheckoutRequest{UserID: 123, IdempotencyKey: "key"})
+
+	require.NoError(t, err)
+	assert.Equal(t, d.CheckoutStatusCompleted, *resp.Status)
+}
+
+func TestGetCheckoutHistory(t *testing.T) {
+	mockRepo := &MockRepository{
+		Session: &r.CheckoutSession{ID: "checkout-1", UserID: "123"},
+		Steps: []*r.CheckoutStepRecord{
+			{CheckoutID: "checkout-1", Step: d.CheckoutStepReserveInventory, Attempt: 1, Outcome: d.StepOutcomeSucceeded},
+			{CheckoutID: "checkout-2", Step: d.CheckoutStepReserveInventory, Attempt: 1, Outcome: d.StepOutcomeSucceeded},
+		},
+	}
+	svc := newQueryTestService(mockRepo)
+
+	steps, err := svc.GetCheckoutHistory(context.Background(), "checkout-1", 123)
+
+	require.NoError(t, err)
+	require.Len(t, steps, 1)
+	assert.Equal(t, "checkout-1", steps[0].CheckoutID)
+}
+
+func TestGetCheckoutHistory_OtherUser(t *testing.T) {
+	mockRepo := &MockRepository{
+		Session: &r.CheckoutSession{ID: "checkout-1", UserID: "456"},
+	}
+	svc := newQueryTestService(mockRepo)
+
+	_, err := svc.GetCheckoutHistory(context.Background(), "checkout-1", 123)
+
+	assert.ErrorIs(t, err, ErrCheckoutNotFound)
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	d "github.com/fjod/go_cart/checkout-service/domain"
+	r "github.com/fjod/go_cart/checkout-service/internal/repository"
+)
+
+// watchPollInterval is how often WatchCheckout re-reads the session.
+// It catches transitions made by another instance or by recovery, which never reach the local hub.
+const watchPollInterval = time.Second
+
+// WatchCheckout sends the current state of the checkout and then every transition until it reaches a terminal status.
+func (s *CheckoutServiceImpl) WatchCheckout(ctx context.Context, checkoutID string, userID int64, send func(d.CheckoutEvent) error) error {
+	// subscribe before reading the current state, so a transition in between is not lost
+	events, unsubscribe := s.hub.subscribe(checkoutID)
+	defer unsubscribe()
+
+	session, err := s.GetCheckout(ctx, checkoutID, userID)
+	if err != nil {
+		return err
+	}
+
+	last := sessionEvent(session)
+	if err := send(last); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for !last.Status.IsTerminal() {
+		var next d.CheckoutEvent
+		select {
+		case next = <-events:
+		case <-ticker.C:
+			session, err := s.GetCheckout(ctx, checkoutID, userID)
+			if err != nil {
+				return err
+			}
+			next = sessionEvent(session)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		if !next.Status.IsAfter(last.Status) {
+			continue
+		}
+		if err := send(next); err != nil {
+			return err
+		}
+		last = next
+	}
+	return nil
+}
+
+func sessionEvent(session *r.CheckoutSession) d.CheckoutEvent {
+	event := d.CheckoutEvent{
+		CheckoutID: session.ID,
+		Status:     session.Status,
+		OccurredAt: session.UpdatedAt,
+	}
+	if session.FailureReason != nil {
+		event.FailureReason = *session.FailureReason
+	}
+	return event
+}
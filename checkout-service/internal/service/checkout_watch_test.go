@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	d "github.com/fjod/go_cart/checkout-service/domain"
+	r "github.com/fjod/go_cart/checkout-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitForSubscriber blocks until WatchCheckout has subscribed to the hub
+func waitForSubscriber(t *testing.T, svc *CheckoutServiceImpl, checkoutID string) {
+	require.Eventually(t, func() bool {
+		svc.hub.mu.RLock()
+		defer svc.hub.mu.RUnlock()
+		return len(svc.hub.subs[checkoutID]) > 0
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestWatchCheckout_AlreadyTerminal(t *testing.T) {
+	reason := "payment failed: NO_FUNDS"
+	mockRepo := &MockRepository{
+		Session: &r.CheckoutSession{ID: "checkout-1", UserID: "123", Status: d.CheckoutStatusFailed, FailureReason: &reason},
+	}
+	svc := newQueryTestService(mockRepo)
+
+	var events []d.CheckoutEvent
+	err := svc.WatchCheckout(context.Background(), "checkout-1", 123, func(e d.CheckoutEvent) error {
+		events = append(events, e)
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, d.CheckoutStatusFailed, events[0].Status)
+	assert.Equal(t, reason, events[0].FailureReason)
+}
+
+func TestWatchCheckout_StreamsUntilTerminal(t *testing.T) {
+	mockRepo := &MockRepository{
+		Session: &r.CheckoutSession{ID: "checkout-1", UserID: "123", Status: d.CheckoutStatusInitiated},
+	}
+	svc := newQueryTestService(mockRepo)
+
+	events := make(chan d.CheckoutEvent, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- svc.WatchCheckout(context.Background(), "checkout-1", 123, func(e d.CheckoutEvent) error {
+			events <- e
+			return nil
+		})
+	}()
+	waitForSubscriber(t, svc, "checkout-1")
+
+	svc.notify("checkout-1", d.CheckoutStatusInventoryReserved, "")
+	svc.notify("checkout-1", d.CheckoutStatusInitiated, "") // stale, must be skipped
+	svc.notify("checkout-1", d.CheckoutStatusCompleted, "")
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("watch did not finish on terminal status")
+	}
+
+	close(events)
+	var statuses []d.CheckoutStatus
+	for e := range events {
+		statuses = append(statuses, e.Status)
+	}
+	assert.Equal(t, []d.CheckoutStatus{
+		d.CheckoutStatusInitiated,
+		d.CheckoutStatusInventoryReserved,
+		d.CheckoutStatusCompleted,
+	}, statuses)
+}
+
+func TestWatchCheckout_ContextCancelled(t *testing.T) {
+	mockRepo := &MockRepository{
+		Session: &r.CheckoutSession{ID: "checkout-1", UserID: "123", Status: d.CheckoutStatusPaymentPending},
+	}
+	svc := newQueryTestService(mockRepo)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	err := svc.WatchCheckout(ctx, "checkout-1", 123, func(d.CheckoutEvent) error {
+		cancel()
+		return nil
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestWatchCheckout_OtherUser(t *testing.T) {
+	mockRepo := &MockRepository{
+		Session: &r.CheckoutSession{ID: "checkout-1", UserID: "456"},
+	}
+	svc := newQueryTestService(mockRepo)
+
+	err := svc.WatchCheckout(context.Background(), "checkout-1", 123, func(d.CheckoutEvent) error {
+		return nil
+	})
+
+	assert.ErrorIs(t, err, ErrCheckoutNotFound)
+}
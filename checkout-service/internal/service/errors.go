@@ -5,4 +5,8 @@ import "errors"
 var (
 	ErrEmptyCart           = errors.New("cart is empty, nothing to checkout")
 	IllegalTransitionError = errors.New("illegal transition of checkout status")
+	ErrCheckoutNotFound    = errors.New("checkout not found")
+	ErrInvalidPageToken    = errors.New("invalid page token")
+	ErrCheckoutQueueFull   = errors.New("too many checkouts in progress, try again later")
+	ErrPromoCodeInvalid    = errors.New("promo code no longer applies")
 )
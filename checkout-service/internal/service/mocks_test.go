@@ -25,6 +25,16 @@ type MockRepository struct {
 	ReservationId  *string
 	PaymentId      *string
 	OutboxId       *string
+	OutboxPayload  []byte             // Captures the CheckoutCompleted payload
+	Statuses       []d.CheckoutStatus // Captures every status passed to UpdateCheckoutSessionStatus
+	FailureReason  *string            // Captures the reason passed to FailCheckoutSession
+	Session        *r.CheckoutSession // Returned by GetCheckoutSession, claimed by ClaimInitiatedSession while INITIATED
+	ClaimErr       error
+	Sessions       []*r.CheckoutSession
+	ListLimit      int
+	ListOffset     int
+	Steps          []*r.CheckoutStepRecord // Captures every step passed to RecordCheckoutStep
+	StepErr        error
 }
 
 func (m *MockRepository) Close() error {
@@ -44,10 +54,23 @@ func (m *MockRepository) CreateCheckoutSession(_ context.Context, session *r.Che
 	return m.CreateErr
 }
 
-func (m *MockRepository) UpdateCheckoutSessionStatus(_ context.Context, _ *string, _ *d.CheckoutStatus) error {
+func (m *MockRepository) UpdateCheckoutSessionStatus(_ context.Context, _ *string, s *d.CheckoutStatus) error {
+	m.Statuses = append(m.Statuses, *s)
 	return nil
 }
 
+func (m *MockRepository) FailCheckoutSession(_ context.Context, _ *string, reason string) error {
+	m.FailureReason = &reason
+	return nil
+}
+
+func (m *MockRepository) ClaimInitiatedSession(_ context.Context, _ *string) (bool, error) {
+	if m.ClaimErr != nil {
+		return false, m.ClaimErr
+	}
+	return m.Session != nil && m.Session.Status == d.CheckoutStatusInitiated, nil
+}
+
 func (m *MockRepository) SetReservation(_ context.Context, _ *string, _ *d.CheckoutStatus, reserveId *string) error {
 	m.ReservationId = reserveId
 	return nil
@@ -58,8 +81,9 @@ func (m *MockRepository) SetPayment(_ context.Context, _ *string, _ *d.CheckoutS
 	return nil
 }
 
-func (m *MockRepository) CompleteCheckoutSession(_ context.Context, id *string, _ []byte, _ *d.CheckoutStatus) error {
+func (m *MockRepository) CompleteCheckoutSession(_ context.Context, id *string, payload []byte, _ *d.CheckoutStatus) error {
 	m.OutboxId = id
+	m.OutboxPayload = payload
 	return nil
 }
 func (m *MockRepository) GetUnprocessedEvents(context.Context, int) ([]*r.OutboxEvent, error) {
@@ -73,6 +97,44 @@ func (m *MockRepository) GetStuckSessions(context.Context) ([]*r.CheckoutSession
 	return nil, nil
 }
 
+func (m *MockRepository) GetCheckoutSession(_ context.Context, _ string) (*r.CheckoutSession, error) {
+	if m.Session == nil {
+		return nil, r.ErrCheckoutNotFound
+	}
+	return m.Session, nil
+}
+
+func (m *MockRepository) ListCheckoutSessions(_ context.Context, _ string, limit, offset int) ([]*r.CheckoutSession, error) {
+	m.ListLimit = limit
+	m.ListOffset = offset
+	if offset >= len(m.Sessions) {
+		return nil, nil
+	}
+	end := offset + limit
+	if end > len(m.Sessions) {
+		end = len(m.Sessions)
+	}
+	return m.Sessions[offset:end], nil
+}
+
+func (m *MockRepository) RecordCheckoutStep(_ context.Context, step *r.CheckoutStepRecord) error {
+	if m.StepErr != nil {
+		return m.StepErr
+	}
+	m.Steps = append(m.Steps, step)
+	return nil
+}
+
+func (m *MockRepository) GetCheckoutSteps(_ context.Context, checkoutID string) ([]*r.CheckoutStepRecord, error) {
+	var steps []*r.CheckoutStepRecord
+	for _, step := range m.Steps {
+		if step.CheckoutID == checkoutID {
+			steps = append(steps, step)
+		}
+	}
+	return steps, nil
+}
+
 // MockCartServiceClient implements cartpb.CartServiceClient for testing
 type MockCartServiceClient struct {
 	CartResponse *cartpb.CartResponse
@@ -99,10 +161,78 @@ func (m *MockCartServiceClient) ClearCart(_ context.Context, _ *cartpb.ClearCart
 	return m.CartResponse, m.Err
 }
 
+func (m *MockCartServiceClient) GetPricedCart(_ context.Context, _ *cartpb.GetCartRequest, _ ...grpc.CallOption) (*cartpb.PricedCartResponse, error) {
+	return nil, m.Err
+}
+
+func (m *MockCartServiceClient) MergeCarts(_ context.Context, _ *cartpb.MergeCartsRequest, _ ...grpc.CallOption) (*cartpb.CartResponse, error) {
+	return m.CartResponse, m.Err
+}
+
+func (m *MockCartServiceClient) ApplyPromoCode(_ context.Context, _ *cartpb.ApplyPromoCodeRequest, _ ...grpc.CallOption) (*cartpb.PricedCartResponse, error) {
+	return nil, m.Err
+}
+
+func (m *MockCartServiceClient) RemovePromoCode(_ context.Context, _ *cartpb.RemovePromoCodeRequest, _ ...grpc.CallOption) (*cartpb.PricedCartResponse, error) {
+	return nil, m.Err
+}
+
+func (m *MockCartServiceClient) AddItems(_ context.Context, _ *cartpb.AddItemsRequest, _ ...grpc.CallOption) (*cartpb.CartResponse, error) {
+	return m.CartResponse, m.Err
+}
+
+func (m *MockCartServiceClient) ReplaceCart(_ context.Context, _ *cartpb.ReplaceCartRequest, _ ...grpc.CallOption) (*cartpb.CartResponse, error) {
+	return m.CartResponse, m.Err
+}
+
+func (m *MockCartServiceClient) GetSavedItems(_ context.Context, _ *cartpb.GetSavedItemsRequest, _ ...grpc.CallOption) (*cartpb.SavedItemsResponse, error) {
+	return nil, m.Err
+}
+
+func (m *MockCartServiceClient) SaveForLater(_ context.Context, _ *cartpb.SaveForLaterRequest, _ ...grpc.CallOption) (*cartpb.SavedItemsResponse, error) {
+	return nil, m.Err
+}
+
+func (m *MockCartServiceClient) MoveToCart(_ context.Context, _ *cartpb.MoveToCartRequest, _ ...grpc.CallOption) (*cartpb.SavedItemsResponse, error) {
+	return nil, m.Err
+}
+
+func (m *MockCartServiceClient) RemoveSavedItem(_ context.Context, _ *cartpb.RemoveSavedItemRequest, _ ...grpc.CallOption) (*cartpb.SavedItemsResponse, error) {
+	return nil, m.Err
+}
+
+func (m *MockCartServiceClient) DeleteSavedList(_ context.Context, _ *cartpb.DeleteSavedListRequest, _ ...grpc.CallOption) (*cartpb.SavedItemsResponse, error) {
+	return nil, m.Err
+}
+
 // MockProductServiceClient implements productpb.ProductServiceClient for testing
 type MockProductServiceClient struct {
-	Products map[int64]*productpb.Product // Map of product ID to product
-	Err      error
+	productpb.ProductServiceClient                              // admin RPCs are not called by checkout
+	Products                       map[int64]*productpb.Product // Map of product ID to product
+	Err                            error
+	ByIdsCalls                     int // Counts GetProductsByIds calls
+	Evaluation                     *productpb.EvaluatePromotionResponse
+	EvaluateErr                    error
+	RedeemErr                      error
+	Redeemed                       []*productpb.RedeemPromotionRequest
+	Released                       []*productpb.ReleasePromotionRequest
+}
+
+func (m *MockProductServiceClient) EvaluatePromotion(_ context.Context, _ *productpb.EvaluatePromotionRequest, _ ...grpc.CallOption) (*productpb.EvaluatePromotionResponse, error) {
+	return m.Evaluation, m.EvaluateErr
+}
+
+func (m *MockProductServiceClient) RedeemPromotion(_ context.Context, req *productpb.RedeemPromotionRequest, _ ...grpc.CallOption) (*productpb.RedeemPromotionResponse, error) {
+	if m.RedeemErr != nil {
+		return nil, m.RedeemErr
+	}
+	m.Redeemed = append(m.Redeemed, req)
+	return &productpb.RedeemPromotionResponse{}, nil
+}
+
+func (m *MockProductServiceClient) ReleasePromotion(_ context.Context, req *productpb.ReleasePromotionRequest, _ ...grpc.CallOption) (*productpb.ReleasePromotionResponse, error) {
+	m.Released = append(m.Released, req)
+	return &productpb.ReleasePromotionResponse{}, nil
 }
 
 func (m *MockProductServiceClient) GetProducts(_ context.Context, _ *productpb.GetProductsRequest, _ ...grpc.CallOption) (*productpb.GetProductsResponse, error) {
@@ -124,6 +254,20 @@ func (m *MockProductServiceClient) GetProduct(_ context.Context, req *productpb.
 	return &productpb.GetProductResponse{Product: product}, nil
 }
 
+func (m *MockProductServiceClient) GetProductsByIds(_ context.Context, req *productpb.GetProductsByIdsRequest, _ ...grpc.CallOption) (*productpb.GetProductsByIdsResponse, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	m.ByIdsCalls++
+	var products []*productpb.Product
+	for _, id := range req.Ids {
+		if product, exists := m.Products[id]; exists {
+			products = append(products, product)
+		}
+	}
+	return &productpb.GetProductsByIdsResponse{Products: products}, nil
+}
+
 // MockInventoryServiceClient implements ipb.InventoryServiceClient for testing
 type MockInventoryServiceClient struct {
 	stockResponse   *ipb.GetStockResponse
@@ -131,7 +275,10 @@ type MockInventoryServiceClient struct {
 	confirmResponse *ipb.ConfirmResponse
 	releaseResponse *ipb.ReleaseResponse
 	err             error
+	confirmErr      error
 	ReleaseId       string
+	ConfirmId       string
+	ReserveRequest  *ipb.ReserveRequest
 }
 
 func (m *MockInventoryServiceClient) GetStock(_ context.Context, _ *ipb.GetStockRequest, _ ...grpc.CallOption) (*ipb.GetStockResponse, error) {
@@ -141,17 +288,22 @@ func (m *MockInventoryServiceClient) GetStock(_ context.Context, _ *ipb.GetStock
 	return m.stockResponse, nil
 }
 
-func (m *MockInventoryServiceClient) Reserve(_ context.Context, _ *ipb.ReserveRequest, _ ...grpc.CallOption) (*ipb.ReserveResponse, error) {
+func (m *MockInventoryServiceClient) Reserve(_ context.Context, req *ipb.ReserveRequest, _ ...grpc.CallOption) (*ipb.ReserveResponse, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
+	m.ReserveRequest = req
 	return m.reserveResponse, nil
 }
 
-func (m *MockInventoryServiceClient) Confirm(_ context.Context, _ *ipb.ConfirmRequest, _ ...grpc.CallOption) (*ipb.ConfirmResponse, error) {
+func (m *MockInventoryServiceClient) Confirm(_ context.Context, r *ipb.ConfirmRequest, _ ...grpc.CallOption) (*ipb.ConfirmResponse, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
+	if m.confirmErr != nil {
+		return nil, m.confirmErr
+	}
+	m.ConfirmId = r.ReservationId
 	return m.confirmResponse, nil
 }
 
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"sync"
+
+	d "github.com/fjod/go_cart/checkout-service/domain"
+)
+
+// subscriberBuffer is how many events a slow watcher may lag behind before events are dropped.
+// WatchCheckout also re-reads the session periodically, so a dropped event only delays the update.
+const subscriberBuffer = 8
+
+// statusHub fans out checkout state transitions to the WatchCheckout streams of this instance
+type statusHub struct {
+	mu   sync.RWMutex
+	subs map[string]map[chan d.CheckoutEvent]struct{} // checkoutID -> subscribers
+}
+
+func newStatusHub() *statusHub {
+	return &statusHub{
+		subs: make(map[string]map[chan d.CheckoutEvent]struct{}),
+	}
+}
+
+// subscribe registers a listener for one checkout, the returned func must be called to unregister it
+func (h *statusHub) subscribe(checkoutID string) (<-chan d.CheckoutEvent, func()) {
+	ch := make(chan d.CheckoutEvent, subscriberBuffer)
+
+	h.mu.Lock()
+	if h.subs[checkoutID] == nil {
+		h.subs[checkoutID] = make(map[chan d.CheckoutEvent]struct{})
+	}
+	h.subs[checkoutID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.subs[checkoutID], ch)
+		if len(h.subs[checkoutID]) == 0 {
+			delete(h.subs, checkoutID)
+		}
+	}
+}
+
+// publish never blocks the saga: subscribers with a full buffer miss the event
+func (h *statusHub) publish(event d.CheckoutEvent) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for ch := range h.subs[event.CheckoutID] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
@@ -24,12 +24,13 @@ const (
 type CheckoutStatus int32
 
 const (
-	CheckoutStatus_CHECKOUT_STATUS_INITIATED          CheckoutStatus = 0
-	CheckoutStatus_CHECKOUT_STATUS_INVENTORY_RESERVED CheckoutStatus = 1
-	CheckoutStatus_CHECKOUT_STATUS_PAYMENT_PENDING    CheckoutStatus = 2
-	CheckoutStatus_CHECKOUT_STATUS_PAYMENT_COMPLETED  CheckoutStatus = 3
-	CheckoutStatus_CHECKOUT_STATUS_COMPLETED          CheckoutStatus = 4
-	CheckoutStatus_CHECKOUT_STATUS_FAILED             CheckoutStatus = 5
+	CheckoutStatus_CHECKOUT_STATUS_INITIATED           CheckoutStatus = 0
+	CheckoutStatus_CHECKOUT_STATUS_INVENTORY_RESERVED  CheckoutStatus = 1
+	CheckoutStatus_CHECKOUT_STATUS_PAYMENT_PENDING     CheckoutStatus = 2
+	CheckoutStatus_CHECKOUT_STATUS_PAYMENT_COMPLETED   CheckoutStatus = 3
+	CheckoutStatus_CHECKOUT_STATUS_COMPLETED           CheckoutStatus = 4
+	CheckoutStatus_CHECKOUT_STATUS_FAILED              CheckoutStatus = 5
+	CheckoutStatus_CHECKOUT_STATUS_INVENTORY_CONFIRMED CheckoutStatus = 6
 )
 
 // Enum value maps for CheckoutStatus.
@@ -41,14 +42,16 @@ var (
 		3: "CHECKOUT_STATUS_PAYMENT_COMPLETED",
 		4: "CHECKOUT_STATUS_COMPLETED",
 		5: "CHECKOUT_STATUS_FAILED",
+		6: "CHECKOUT_STATUS_INVENTORY_CONFIRMED",
 	}
 	CheckoutStatus_value = map[string]int32{
-		"CHECKOUT_STATUS_INITIATED":          0,
-		"CHECKOUT_STATUS_INVENTORY_RESERVED": 1,
-		"CHECKOUT_STATUS_PAYMENT_PENDING":    2,
-		"CHECKOUT_STATUS_PAYMENT_COMPLETED":  3,
-		"CHECKOUT_STATUS_COMPLETED":          4,
-		"CHECKOUT_STATUS_FAILED":             5,
+		"CHECKOUT_STATUS_INITIATED":           0,
+		"CHECKOUT_STATUS_INVENTORY_RESERVED":  1,
+		"CHECKOUT_STATUS_PAYMENT_PENDING":     2,
+		"CHECKOUT_STATUS_PAYMENT_COMPLETED":   3,
+		"CHECKOUT_STATUS_COMPLETED":           4,
+		"CHECKOUT_STATUS_FAILED":              5,
+		"CHECKOUT_STATUS_INVENTORY_CONFIRMED": 6,
 	}
 )
 
@@ -83,6 +86,7 @@ type InitiateCheckoutRequest struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	UserId         int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	IdempotencyKey string                 `protobuf:"bytes,2,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	Async          bool                   `protobuf:"varint,3,opt,name=async,proto3" json:"async,omitempty"` // return INITIATED right away, follow progress with WatchCheckout
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
@@ -131,6 +135,13 @@ func (x *InitiateCheckoutRequest) GetIdempotencyKey() string {
 	return ""
 }
 
+func (x *InitiateCheckoutRequest) GetAsync() bool {
+	if x != nil {
+		return x.Async
+	}
+	return false
+}
+
 type InitiateCheckoutResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	CheckoutId    string                 `protobuf:"bytes,1,opt,name=checkout_id,json=checkoutId,proto3" json:"checkout_id,omitempty"`
@@ -183,27 +194,861 @@ func (x *InitiateCheckoutResponse) GetStatus() CheckoutStatus {
 	return CheckoutStatus_CHECKOUT_STATUS_INITIATED
 }
 
-var File_pkg_proto_checkout_proto protoreflect.FileDescriptor
+// Item captured in the cart snapshot at checkout time
+type CheckoutItem struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	ProductId   int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	ProductName string                 `protobuf:"bytes,2,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"`
+	Quantity    int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// Deprecated: Marked as deprecated in pkg/proto/checkout.proto.
+	UnitPrice float64 `protobuf:"fixed64,4,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"` // use unit_price_minor
+	// Deprecated: Marked as deprecated in pkg/proto/checkout.proto.
+	Subtotal       float64 `protobuf:"fixed64,5,opt,name=subtotal,proto3" json:"subtotal,omitempty"`                                    // use subtotal_minor
+	UnitPriceMinor int64   `protobuf:"varint,6,opt,name=unit_price_minor,json=unitPriceMinor,proto3" json:"unit_price_minor,omitempty"` // minor units of the checkout currency, e.g. cents
+	SubtotalMinor  int64   `protobuf:"varint,7,opt,name=subtotal_minor,json=subtotalMinor,proto3" json:"subtotal_minor,omitempty"`
+	VariantId      int64   `protobuf:"varint,8,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"` // 0 for a product sold by its id alone
+	Sku            string  `protobuf:"bytes,9,opt,name=sku,proto3" json:"sku,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
 
-const file_pkg_proto_checkout_proto_rawDesc = "" +
-	"\n" +
-	"\x18pkg/proto/checkout.proto\x12\bcheckout\"[\n" +
-	"\x17InitiateCheckoutRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12'\n" +
-	"\x0fidempotency_key\x18\x02 \x01(\tR\x0eidempotencyKey\"m\n" +
-	"\x18InitiateCheckoutResponse\x12\x1f\n" +
-	"\vcheckout_id\x18\x01 \x01(\tR\n" +
-	"checkoutId\x120\n" +
-	"\x06status\x18\x02 \x01(\x0e2\x18.checkout.CheckoutStatusR\x06status*\xde\x01\n" +
+func (x *CheckoutItem) Reset() {
+	*x = CheckoutItem{}
+	mi := &file_pkg_proto_checkout_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckoutItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckoutItem) ProtoMessage() {}
+
+func (x *CheckoutItem) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_checkout_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckoutItem.ProtoReflect.Descriptor instead.
+func (*CheckoutItem) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_checkout_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CheckoutItem) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *CheckoutItem) GetProductName() string {
+	if x != nil {
+		return x.ProductName
+	}
+	return ""
+}
+
+func (x *CheckoutItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+// Deprecated: Marked as deprecated in pkg/proto/checkout.proto.
+func (x *CheckoutItem) GetUnitPrice() float64 {
+	if x != nil {
+		return x.UnitPrice
+	}
+	return 0
+}
+
+// Deprecated: Marked as deprecated in pkg/proto/checkout.proto.
+func (x *CheckoutItem) GetSubtotal() float64 {
+	if x != nil {
+		return x.Subtotal
+	}
+	return 0
+}
+
+func (x *CheckoutItem) GetUnitPriceMinor() int64 {
+	if x != nil {
+		return x.UnitPriceMinor
+	}
+	return 0
+}
+
+func (x *CheckoutItem) GetSubtotalMinor() int64 {
+	if x != nil {
+		return x.SubtotalMinor
+	}
+	return 0
+}
+
+func (x *CheckoutItem) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *CheckoutItem) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+// Full state of a checkout session
+type Checkout struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	CheckoutId             string                 `protobuf:"bytes,1,opt,name=checkout_id,json=checkoutId,proto3" json:"checkout_id,omitempty"`
+	Status                 CheckoutStatus         `protobuf:"varint,2,opt,name=status,proto3,enum=checkout.CheckoutStatus" json:"status,omitempty"`
+	Items                  []*CheckoutItem        `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	TotalAmount            string                 `protobuf:"bytes,4,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"`
+	Currency               string                 `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	InventoryReservationId string                 `protobuf:"bytes,6,opt,name=inventory_reservation_id,json=inventoryReservationId,proto3" json:"inventory_reservation_id,omitempty"`
+	PaymentId              string                 `protobuf:"bytes,7,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	FailureReason          string                 `protobuf:"bytes,8,opt,name=failure_reason,json=failureReason,proto3" json:"failure_reason,omitempty"`              // empty unless status is FAILED
+	CreatedAt              string                 `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`                          // RFC3339 format
+	UpdatedAt              string                 `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`                         // RFC3339 format
+	TotalAmountMinor       int64                  `protobuf:"varint,11,opt,name=total_amount_minor,json=totalAmountMinor,proto3" json:"total_amount_minor,omitempty"` // total_amount in minor units of currency
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *Checkout) Reset() {
+	*x = Checkout{}
+	mi := &file_pkg_proto_checkout_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Checkout) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Checkout) ProtoMessage() {}
+
+func (x *Checkout) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_checkout_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Checkout.ProtoReflect.Descriptor instead.
+func (*Checkout) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_checkout_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Checkout) GetCheckoutId() string {
+	if x != nil {
+		return x.CheckoutId
+	}
+	return ""
+}
+
+func (x *Checkout) GetStatus() CheckoutStatus {
+	if x != nil {
+		return x.Status
+	}
+	return CheckoutStatus_CHECKOUT_STATUS_INITIATED
+}
+
+func (x *Checkout) GetItems() []*CheckoutItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Checkout) GetTotalAmount() string {
+	if x != nil {
+		return x.TotalAmount
+	}
+	return ""
+}
+
+func (x *Checkout) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Checkout) GetInventoryReservationId() string {
+	if x != nil {
+		return x.InventoryReservationId
+	}
+	return ""
+}
+
+func (x *Checkout) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *Checkout) GetFailureReason() string {
+	if x != nil {
+		return x.FailureReason
+	}
+	return ""
+}
+
+func (x *Checkout) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Checkout) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *Checkout) GetTotalAmountMinor() int64 {
+	if x != nil {
+		return x.TotalAmountMinor
+	}
+	return 0
+}
+
+type GetCheckoutRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CheckoutId    string                 `protobuf:"bytes,1,opt,name=checkout_id,json=checkoutId,proto3" json:"checkout_id,omitempty"`
+	UserId        int64                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // owner of the checkout, other users get NOT_FOUND
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCheckoutRequest) Reset() {
+	*x = GetCheckoutRequest{}
+	mi := &file_pkg_proto_checkout_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCheckoutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCheckoutRequest) ProtoMessage() {}
+
+func (x *GetCheckoutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_checkout_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCheckoutRequest.ProtoReflect.Descriptor instead.
+func (*GetCheckoutRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_checkout_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetCheckoutRequest) GetCheckoutId() string {
+	if x != nil {
+		return x.CheckoutId
+	}
+	return ""
+}
+
+func (x *GetCheckoutRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type GetCheckoutResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Checkout      *Checkout              `protobuf:"bytes,1,opt,name=checkout,proto3" json:"checkout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCheckoutResponse) Reset() {
+	*x = GetCheckoutResponse{}
+	mi := &file_pkg_proto_checkout_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCheckoutResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCheckoutResponse) ProtoMessage() {}
+
+func (x *GetCheckoutResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_checkout_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCheckoutResponse.ProtoReflect.Descriptor instead.
+func (*GetCheckoutResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_checkout_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetCheckoutResponse) GetCheckout() *Checkout {
+	if x != nil {
+		return x.Checkout
+	}
+	return nil
+}
+
+type ListCheckoutsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // defaults to 20, max 100
+	PageToken     string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // next_page_token from the previous response
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCheckoutsRequest) Reset() {
+	*x = ListCheckoutsRequest{}
+	mi := &file_pkg_proto_checkout_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCheckoutsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCheckoutsRequest) ProtoMessage() {}
+
+func (x *ListCheckoutsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_checkout_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCheckoutsRequest.ProtoReflect.Descriptor instead.
+func (*ListCheckoutsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_checkout_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListCheckoutsRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *ListCheckoutsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListCheckoutsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListCheckoutsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Checkouts     []*Checkout            `protobuf:"bytes,1,rep,name=checkouts,proto3" json:"checkouts,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // empty when there are no more pages
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCheckoutsResponse) Reset() {
+	*x = ListCheckoutsResponse{}
+	mi := &file_pkg_proto_checkout_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCheckoutsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCheckoutsResponse) ProtoMessage() {}
+
+func (x *ListCheckoutsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_checkout_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCheckoutsResponse.ProtoReflect.Descriptor instead.
+func (*ListCheckoutsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_checkout_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListCheckoutsResponse) GetCheckouts() []*Checkout {
+	if x != nil {
+		return x.Checkouts
+	}
+	return nil
+}
+
+func (x *ListCheckoutsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type WatchCheckoutRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CheckoutId    string                 `protobuf:"bytes,1,opt,name=checkout_id,json=checkoutId,proto3" json:"checkout_id,omitempty"`
+	UserId        int64                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // owner of the checkout, other users get NOT_FOUND
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchCheckoutRequest) Reset() {
+	*x = WatchCheckoutRequest{}
+	mi := &file_pkg_proto_checkout_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchCheckoutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchCheckoutRequest) ProtoMessage() {}
+
+func (x *WatchCheckoutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_checkout_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchCheckoutRequest.ProtoReflect.Descriptor instead.
+func (*WatchCheckoutRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_checkout_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *WatchCheckoutRequest) GetCheckoutId() string {
+	if x != nil {
+		return x.CheckoutId
+	}
+	return ""
+}
+
+func (x *WatchCheckoutRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+// State transition of a checkout session
+type CheckoutEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CheckoutId    string                 `protobuf:"bytes,1,opt,name=checkout_id,json=checkoutId,proto3" json:"checkout_id,omitempty"`
+	Status        CheckoutStatus         `protobuf:"varint,2,opt,name=status,proto3,enum=checkout.CheckoutStatus" json:"status,omitempty"`
+	FailureReason string                 `protobuf:"bytes,3,opt,name=failure_reason,json=failureReason,proto3" json:"failure_reason,omitempty"` // empty unless status is FAILED
+	OccurredAt    string                 `protobuf:"bytes,4,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`          // RFC3339 format
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckoutEvent) Reset() {
+	*x = CheckoutEvent{}
+	mi := &file_pkg_proto_checkout_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckoutEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckoutEvent) ProtoMessage() {}
+
+func (x *CheckoutEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_checkout_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckoutEvent.ProtoReflect.Descriptor instead.
+func (*CheckoutEvent) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_checkout_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CheckoutEvent) GetCheckoutId() string {
+	if x != nil {
+		return x.CheckoutId
+	}
+	return ""
+}
+
+func (x *CheckoutEvent) GetStatus() CheckoutStatus {
+	if x != nil {
+		return x.Status
+	}
+	return CheckoutStatus_CHECKOUT_STATUS_INITIATED
+}
+
+func (x *CheckoutEvent) GetFailureReason() string {
+	if x != nil {
+		return x.FailureReason
+	}
+	return ""
+}
+
+func (x *CheckoutEvent) GetOccurredAt() string {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return ""
+}
+
+type GetCheckoutHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CheckoutId    string                 `protobuf:"bytes,1,opt,name=checkout_id,json=checkoutId,proto3" json:"checkout_id,omitempty"`
+	UserId        int64                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // owner of the checkout, other users get NOT_FOUND
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCheckoutHistoryRequest) Reset() {
+	*x = GetCheckoutHistoryRequest{}
+	mi := &file_pkg_proto_checkout_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCheckoutHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCheckoutHistoryRequest) ProtoMessage() {}
+
+func (x *GetCheckoutHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_checkout_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCheckoutHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetCheckoutHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_checkout_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetCheckoutHistoryRequest) GetCheckoutId() string {
+	if x != nil {
+		return x.CheckoutId
+	}
+	return ""
+}
+
+func (x *GetCheckoutHistoryRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+// One attempt of a saga step (downstream call) made for a checkout
+type CheckoutStep struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Step          string                 `protobuf:"bytes,1,opt,name=step,proto3" json:"step,omitempty"`        // RESERVE_INVENTORY, PROCESS_PAYMENT, CONFIRM_INVENTORY, RELEASE_INVENTORY, COMPLETE, REFUND
+	Attempt       int32                  `protobuf:"varint,2,opt,name=attempt,proto3" json:"attempt,omitempty"` // 1 for the first call, higher for recovery retries
+	Outcome       string                 `protobuf:"bytes,3,opt,name=outcome,proto3" json:"outcome,omitempty"`  // SUCCEEDED or FAILED
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`      // empty unless outcome is FAILED
+	DurationMs    int64                  `protobuf:"varint,5,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	RecordedAt    string                 `protobuf:"bytes,6,opt,name=recorded_at,json=recordedAt,proto3" json:"recorded_at,omitempty"` // RFC3339 format
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckoutStep) Reset() {
+	*x = CheckoutStep{}
+	mi := &file_pkg_proto_checkout_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckoutStep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckoutStep) ProtoMessage() {}
+
+func (x *CheckoutStep) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_checkout_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckoutStep.ProtoReflect.Descriptor instead.
+func (*CheckoutStep) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_checkout_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CheckoutStep) GetStep() string {
+	if x != nil {
+		return x.Step
+	}
+	return ""
+}
+
+func (x *CheckoutStep) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *CheckoutStep) GetOutcome() string {
+	if x != nil {
+		return x.Outcome
+	}
+	return ""
+}
+
+func (x *CheckoutStep) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *CheckoutStep) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *CheckoutStep) GetRecordedAt() string {
+	if x != nil {
+		return x.RecordedAt
+	}
+	return ""
+}
+
+type GetCheckoutHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CheckoutId    string                 `protobuf:"bytes,1,opt,name=checkout_id,json=checkoutId,proto3" json:"checkout_id,omitempty"`
+	Steps         []*CheckoutStep        `protobuf:"bytes,2,rep,name=steps,proto3" json:"steps,omitempty"` // oldest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCheckoutHistoryResponse) Reset() {
+	*x = GetCheckoutHistoryResponse{}
+	mi := &file_pkg_proto_checkout_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCheckoutHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCheckoutHistoryResponse) ProtoMessage() {}
+
+func (x *GetCheckoutHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_checkout_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCheckoutHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetCheckoutHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_checkout_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetCheckoutHistoryResponse) GetCheckoutId() string {
+	if x != nil {
+		return x.CheckoutId
+	}
+	return ""
+}
+
+func (x *GetCheckoutHistoryResponse) GetSteps() []*CheckoutStep {
+	if x != nil {
+		return x.Steps
+	}
+	return nil
+}
+
+var File_pkg_proto_checkout_proto protoreflect.FileDescriptor
+
+const file_pkg_proto_checkout_proto_rawDesc = "" +
+	"\n" +
+	"\x18pkg/proto/checkout.proto\x12\bcheckout\"q\n" +
+	"\x17InitiateCheckoutRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12'\n" +
+	"\x0fidempotency_key\x18\x02 \x01(\tR\x0eidempotencyKey\x12\x14\n" +
+	"\x05async\x18\x03 \x01(\bR\x05async\"m\n" +
+	"\x18InitiateCheckoutResponse\x12\x1f\n" +
+	"\vcheckout_id\x18\x01 \x01(\tR\n" +
+	"checkoutId\x120\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x18.checkout.CheckoutStatusR\x06status\"\xb1\x02\n" +
+	"\fCheckoutItem\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x03R\tproductId\x12!\n" +
+	"\fproduct_name\x18\x02 \x01(\tR\vproductName\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\x12!\n" +
+	"\n" +
+	"unit_price\x18\x04 \x01(\x01B\x02\x18\x01R\tunitPrice\x12\x1e\n" +
+	"\bsubtotal\x18\x05 \x01(\x01B\x02\x18\x01R\bsubtotal\x12(\n" +
+	"\x10unit_price_minor\x18\x06 \x01(\x03R\x0eunitPriceMinor\x12%\n" +
+	"\x0esubtotal_minor\x18\a \x01(\x03R\rsubtotalMinor\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\b \x01(\x03R\tvariantId\x12\x10\n" +
+	"\x03sku\x18\t \x01(\tR\x03sku\"\xb6\x03\n" +
+	"\bCheckout\x12\x1f\n" +
+	"\vcheckout_id\x18\x01 \x01(\tR\n" +
+	"checkoutId\x120\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x18.checkout.CheckoutStatusR\x06status\x12,\n" +
+	"\x05items\x18\x03 \x03(\v2\x16.checkout.CheckoutItemR\x05items\x12!\n" +
+	"\ftotal_amount\x18\x04 \x01(\tR\vtotalAmount\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\x128\n" +
+	"\x18inventory_reservation_id\x18\x06 \x01(\tR\x16inventoryReservationId\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\a \x01(\tR\tpaymentId\x12%\n" +
+	"\x0efailure_reason\x18\b \x01(\tR\rfailureReason\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\t \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\tR\tupdatedAt\x12,\n" +
+	"\x12total_amount_minor\x18\v \x01(\x03R\x10totalAmountMinor\"N\n" +
+	"\x12GetCheckoutRequest\x12\x1f\n" +
+	"\vcheckout_id\x18\x01 \x01(\tR\n" +
+	"checkoutId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x03R\x06userId\"E\n" +
+	"\x13GetCheckoutResponse\x12.\n" +
+	"\bcheckout\x18\x01 \x01(\v2\x12.checkout.CheckoutR\bcheckout\"k\n" +
+	"\x14ListCheckoutsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"q\n" +
+	"\x15ListCheckoutsResponse\x120\n" +
+	"\tcheckouts\x18\x01 \x03(\v2\x12.checkout.CheckoutR\tcheckouts\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"P\n" +
+	"\x14WatchCheckoutRequest\x12\x1f\n" +
+	"\vcheckout_id\x18\x01 \x01(\tR\n" +
+	"checkoutId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x03R\x06userId\"\xaa\x01\n" +
+	"\rCheckoutEvent\x12\x1f\n" +
+	"\vcheckout_id\x18\x01 \x01(\tR\n" +
+	"checkoutId\x120\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x18.checkout.CheckoutStatusR\x06status\x12%\n" +
+	"\x0efailure_reason\x18\x03 \x01(\tR\rfailureReason\x12\x1f\n" +
+	"\voccurred_at\x18\x04 \x01(\tR\n" +
+	"occurredAt\"U\n" +
+	"\x19GetCheckoutHistoryRequest\x12\x1f\n" +
+	"\vcheckout_id\x18\x01 \x01(\tR\n" +
+	"checkoutId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x03R\x06userId\"\xae\x01\n" +
+	"\fCheckoutStep\x12\x12\n" +
+	"\x04step\x18\x01 \x01(\tR\x04step\x12\x18\n" +
+	"\aattempt\x18\x02 \x01(\x05R\aattempt\x12\x18\n" +
+	"\aoutcome\x18\x03 \x01(\tR\aoutcome\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x12\x1f\n" +
+	"\vduration_ms\x18\x05 \x01(\x03R\n" +
+	"durationMs\x12\x1f\n" +
+	"\vrecorded_at\x18\x06 \x01(\tR\n" +
+	"recordedAt\"k\n" +
+	"\x1aGetCheckoutHistoryResponse\x12\x1f\n" +
+	"\vcheckout_id\x18\x01 \x01(\tR\n" +
+	"checkoutId\x12,\n" +
+	"\x05steps\x18\x02 \x03(\v2\x16.checkout.CheckoutStepR\x05steps*\x87\x02\n" +
 	"\x0eCheckoutStatus\x12\x1d\n" +
 	"\x19CHECKOUT_STATUS_INITIATED\x10\x00\x12&\n" +
 	"\"CHECKOUT_STATUS_INVENTORY_RESERVED\x10\x01\x12#\n" +
 	"\x1fCHECKOUT_STATUS_PAYMENT_PENDING\x10\x02\x12%\n" +
 	"!CHECKOUT_STATUS_PAYMENT_COMPLETED\x10\x03\x12\x1d\n" +
 	"\x19CHECKOUT_STATUS_COMPLETED\x10\x04\x12\x1a\n" +
-	"\x16CHECKOUT_STATUS_FAILED\x10\x052l\n" +
+	"\x16CHECKOUT_STATUS_FAILED\x10\x05\x12'\n" +
+	"#CHECKOUT_STATUS_INVENTORY_CONFIRMED\x10\x062\xb7\x03\n" +
 	"\x0fCheckoutService\x12Y\n" +
-	"\x10InitiateCheckout\x12!.checkout.InitiateCheckoutRequest\x1a\".checkout.InitiateCheckoutResponseB4Z2github.com/fjod/go_cart/checkout-service/pkg/protob\x06proto3"
+	"\x10InitiateCheckout\x12!.checkout.InitiateCheckoutRequest\x1a\".checkout.InitiateCheckoutResponse\x12J\n" +
+	"\vGetCheckout\x12\x1c.checkout.GetCheckoutRequest\x1a\x1d.checkout.GetCheckoutResponse\x12P\n" +
+	"\rListCheckouts\x12\x1e.checkout.ListCheckoutsRequest\x1a\x1f.checkout.ListCheckoutsResponse\x12J\n" +
+	"\rWatchCheckout\x12\x1e.checkout.WatchCheckoutRequest\x1a\x17.checkout.CheckoutEvent0\x01\x12_\n" +
+	"\x12GetCheckoutHistory\x12#.checkout.GetCheckoutHistoryRequest\x1a$.checkout.GetCheckoutHistoryResponseB4Z2github.com/fjod/go_cart/checkout-service/pkg/protob\x06proto3"
 
 var (
 	file_pkg_proto_checkout_proto_rawDescOnce sync.Once
@@ -218,21 +1063,46 @@ func file_pkg_proto_checkout_proto_rawDescGZIP() []byte {
 }
 
 var file_pkg_proto_checkout_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_pkg_proto_checkout_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_pkg_proto_checkout_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_pkg_proto_checkout_proto_goTypes = []any{
-	(CheckoutStatus)(0),              // 0: checkout.CheckoutStatus
-	(*InitiateCheckoutRequest)(nil),  // 1: checkout.InitiateCheckoutRequest
-	(*InitiateCheckoutResponse)(nil), // 2: checkout.InitiateCheckoutResponse
+	(CheckoutStatus)(0),                // 0: checkout.CheckoutStatus
+	(*InitiateCheckoutRequest)(nil),    // 1: checkout.InitiateCheckoutRequest
+	(*InitiateCheckoutResponse)(nil),   // 2: checkout.InitiateCheckoutResponse
+	(*CheckoutItem)(nil),               // 3: checkout.CheckoutItem
+	(*Checkout)(nil),                   // 4: checkout.Checkout
+	(*GetCheckoutRequest)(nil),         // 5: checkout.GetCheckoutRequest
+	(*GetCheckoutResponse)(nil),        // 6: checkout.GetCheckoutResponse
+	(*ListCheckoutsRequest)(nil),       // 7: checkout.ListCheckoutsRequest
+	(*ListCheckoutsResponse)(nil),      // 8: checkout.ListCheckoutsResponse
+	(*WatchCheckoutRequest)(nil),       // 9: checkout.WatchCheckoutRequest
+	(*CheckoutEvent)(nil),              // 10: checkout.CheckoutEvent
+	(*GetCheckoutHistoryRequest)(nil),  // 11: checkout.GetCheckoutHistoryRequest
+	(*CheckoutStep)(nil),               // 12: checkout.CheckoutStep
+	(*GetCheckoutHistoryResponse)(nil), // 13: checkout.GetCheckoutHistoryResponse
 }
 var file_pkg_proto_checkout_proto_depIdxs = []int32{
-	0, // 0: checkout.InitiateCheckoutResponse.status:type_name -> checkout.CheckoutStatus
-	1, // 1: checkout.CheckoutService.InitiateCheckout:input_type -> checkout.InitiateCheckoutRequest
-	2, // 2: checkout.CheckoutService.InitiateCheckout:output_type -> checkout.InitiateCheckoutResponse
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	0,  // 0: checkout.InitiateCheckoutResponse.status:type_name -> checkout.CheckoutStatus
+	0,  // 1: checkout.Checkout.status:type_name -> checkout.CheckoutStatus
+	3,  // 2: checkout.Checkout.items:type_name -> checkout.CheckoutItem
+	4,  // 3: checkout.GetCheckoutResponse.checkout:type_name -> checkout.Checkout
+	4,  // 4: checkout.ListCheckoutsResponse.checkouts:type_name -> checkout.Checkout
+	0,  // 5: checkout.CheckoutEvent.status:type_name -> checkout.CheckoutStatus
+	12, // 6: checkout.GetCheckoutHistoryResponse.steps:type_name -> checkout.CheckoutStep
+	1,  // 7: checkout.CheckoutService.InitiateCheckout:input_type -> checkout.InitiateCheckoutRequest
+	5,  // 8: checkout.CheckoutService.GetCheckout:input_type -> checkout.GetCheckoutRequest
+	7,  // 9: checkout.CheckoutService.ListCheckouts:input_type -> checkout.ListCheckoutsRequest
+	9,  // 10: checkout.CheckoutService.WatchCheckout:input_type -> checkout.WatchCheckoutRequest
+	11, // 11: checkout.CheckoutService.GetCheckoutHistory:input_type -> checkout.GetCheckoutHistoryRequest
+	2,  // 12: checkout.CheckoutService.InitiateCheckout:output_type -> checkout.InitiateCheckoutResponse
+	6,  // 13: checkout.CheckoutService.GetCheckout:output_type -> checkout.GetCheckoutResponse
+	8,  // 14: checkout.CheckoutService.ListCheckouts:output_type -> checkout.ListCheckoutsResponse
+	10, // 15: checkout.CheckoutService.WatchCheckout:output_type -> checkout.CheckoutEvent
+	13, // 16: checkout.CheckoutService.GetCheckoutHistory:output_type -> checkout.GetCheckoutHistoryResponse
+	12, // [12:17] is the sub-list for method output_type
+	7,  // [7:12] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_pkg_proto_checkout_proto_init() }
@@ -246,7 +1116,7 @@ func file_pkg_proto_checkout_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_proto_checkout_proto_rawDesc), len(file_pkg_proto_checkout_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   2,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
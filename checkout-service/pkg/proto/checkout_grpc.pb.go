@@ -19,7 +19,11 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	CheckoutService_InitiateCheckout_FullMethodName = "/checkout.CheckoutService/InitiateCheckout"
+	CheckoutService_InitiateCheckout_FullMethodName   = "/checkout.CheckoutService/InitiateCheckout"
+	CheckoutService_GetCheckout_FullMethodName        = "/checkout.CheckoutService/GetCheckout"
+	CheckoutService_ListCheckouts_FullMethodName      = "/checkout.CheckoutService/ListCheckouts"
+	CheckoutService_WatchCheckout_FullMethodName      = "/checkout.CheckoutService/WatchCheckout"
+	CheckoutService_GetCheckoutHistory_FullMethodName = "/checkout.CheckoutService/GetCheckoutHistory"
 )
 
 // CheckoutServiceClient is the client API for CheckoutService service.
@@ -27,6 +31,12 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type CheckoutServiceClient interface {
 	InitiateCheckout(ctx context.Context, in *InitiateCheckoutRequest, opts ...grpc.CallOption) (*InitiateCheckoutResponse, error)
+	GetCheckout(ctx context.Context, in *GetCheckoutRequest, opts ...grpc.CallOption) (*GetCheckoutResponse, error)
+	ListCheckouts(ctx context.Context, in *ListCheckoutsRequest, opts ...grpc.CallOption) (*ListCheckoutsResponse, error)
+	// Streams the current status and every transition until the checkout is COMPLETED or FAILED
+	WatchCheckout(ctx context.Context, in *WatchCheckoutRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CheckoutEvent], error)
+	// Saga step log of a checkout, for support and debugging
+	GetCheckoutHistory(ctx context.Context, in *GetCheckoutHistoryRequest, opts ...grpc.CallOption) (*GetCheckoutHistoryResponse, error)
 }
 
 type checkoutServiceClient struct {
@@ -47,11 +57,66 @@ func (c *checkoutServiceClient) InitiateCheckout(ctx context.Context, in *Initia
 	return out, nil
 }
 
+func (c *checkoutServiceClient) GetCheckout(ctx context.Context, in *GetCheckoutRequest, opts ...grpc.CallOption) (*GetCheckoutResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCheckoutResponse)
+	err := c.cc.Invoke(ctx, CheckoutService_GetCheckout_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checkoutServiceClient) ListCheckouts(ctx context.Context, in *ListCheckoutsRequest, opts ...grpc.CallOption) (*ListCheckoutsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCheckoutsResponse)
+	err := c.cc.Invoke(ctx, CheckoutService_ListCheckouts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *checkoutServiceClient) WatchCheckout(ctx context.Context, in *WatchCheckoutRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CheckoutEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CheckoutService_ServiceDesc.Streams[0], CheckoutService_WatchCheckout_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchCheckoutRequest, CheckoutEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CheckoutService_WatchCheckoutClient = grpc.ServerStreamingClient[CheckoutEvent]
+
+func (c *checkoutServiceClient) GetCheckoutHistory(ctx context.Context, in *GetCheckoutHistoryRequest, opts ...grpc.CallOption) (*GetCheckoutHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCheckoutHistoryResponse)
+	err := c.cc.Invoke(ctx, CheckoutService_GetCheckoutHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CheckoutServiceServer is the server API for CheckoutService service.
 // All implementations must embed UnimplementedCheckoutServiceServer
 // for forward compatibility.
 type CheckoutServiceServer interface {
 	InitiateCheckout(context.Context, *InitiateCheckoutRequest) (*InitiateCheckoutResponse, error)
+	GetCheckout(context.Context, *GetCheckoutRequest) (*GetCheckoutResponse, error)
+	ListCheckouts(context.Context, *ListCheckoutsRequest) (*ListCheckoutsResponse, error)
+	// Streams the current status and every transition until the checkout is COMPLETED or FAILED
+	WatchCheckout(*WatchCheckoutRequest, grpc.ServerStreamingServer[CheckoutEvent]) error
+	// Saga step log of a checkout, for support and debugging
+	GetCheckoutHistory(context.Context, *GetCheckoutHistoryRequest) (*GetCheckoutHistoryResponse, error)
 	mustEmbedUnimplementedCheckoutServiceServer()
 }
 
@@ -65,6 +130,18 @@ type UnimplementedCheckoutServiceServer struct{}
 func (UnimplementedCheckoutServiceServer) InitiateCheckout(context.Context, *InitiateCheckoutRequest) (*InitiateCheckoutResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method InitiateCheckout not implemented")
 }
+func (UnimplementedCheckoutServiceServer) GetCheckout(context.Context, *GetCheckoutRequest) (*GetCheckoutResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetCheckout not implemented")
+}
+func (UnimplementedCheckoutServiceServer) ListCheckouts(context.Context, *ListCheckoutsRequest) (*ListCheckoutsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListCheckouts not implemented")
+}
+func (UnimplementedCheckoutServiceServer) WatchCheckout(*WatchCheckoutRequest, grpc.ServerStreamingServer[CheckoutEvent]) error {
+	return status.Error(codes.Unimplemented, "method WatchCheckout not implemented")
+}
+func (UnimplementedCheckoutServiceServer) GetCheckoutHistory(context.Context, *GetCheckoutHistoryRequest) (*GetCheckoutHistoryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetCheckoutHistory not implemented")
+}
 func (UnimplementedCheckoutServiceServer) mustEmbedUnimplementedCheckoutServiceServer() {}
 func (UnimplementedCheckoutServiceServer) testEmbeddedByValue()                         {}
 
@@ -104,6 +181,71 @@ func _CheckoutService_InitiateCheckout_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CheckoutService_GetCheckout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCheckoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CheckoutServiceServer).GetCheckout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CheckoutService_GetCheckout_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CheckoutServiceServer).GetCheckout(ctx, req.(*GetCheckoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CheckoutService_ListCheckouts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCheckoutsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CheckoutServiceServer).ListCheckouts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CheckoutService_ListCheckouts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CheckoutServiceServer).ListCheckouts(ctx, req.(*ListCheckoutsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CheckoutService_WatchCheckout_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchCheckoutRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CheckoutServiceServer).WatchCheckout(m, &grpc.GenericServerStream[WatchCheckoutRequest, CheckoutEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CheckoutService_WatchCheckoutServer = grpc.ServerStreamingServer[CheckoutEvent]
+
+func _CheckoutService_GetCheckoutHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCheckoutHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CheckoutServiceServer).GetCheckoutHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CheckoutService_GetCheckoutHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CheckoutServiceServer).GetCheckoutHistory(ctx, req.(*GetCheckoutHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CheckoutService_ServiceDesc is the grpc.ServiceDesc for CheckoutService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,7 +257,25 @@ var CheckoutService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "InitiateCheckout",
 			Handler:    _CheckoutService_InitiateCheckout_Handler,
 		},
+		{
+			MethodName: "GetCheckout",
+			Handler:    _CheckoutService_GetCheckout_Handler,
+		},
+		{
+			MethodName: "ListCheckouts",
+			Handler:    _CheckoutService_ListCheckouts_Handler,
+		},
+		{
+			MethodName: "GetCheckoutHistory",
+			Handler:    _CheckoutService_GetCheckoutHistory_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchCheckout",
+			Handler:       _CheckoutService_WatchCheckout_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/proto/checkout.proto",
 }
@@ -9,6 +9,7 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/fjod/go_cart/inventory-service/internal/domain"
 	inventorygrpc "github.com/fjod/go_cart/inventory-service/internal/grpc"
 	"github.com/fjod/go_cart/inventory-service/internal/store"
 	pb "github.com/fjod/go_cart/inventory-service/pkg/proto"
@@ -20,13 +21,18 @@ import (
 )
 
 var initialStock = map[int64]int32{
-	1: 100, // Laptop
 	2: 500, // Mouse
 	3: 300, // Keyboard
 	4: 150, // Monitor
 	5: 200, // Headphones
 }
 
+// the laptop is sold per configuration, see product-service migration 008
+var initialVariantStock = map[domain.StockKey]int32{
+	{ProductID: 1, VariantID: 1}: 60, // Laptop 16GB RAM, 512GB SSD
+	{ProductID: 1, VariantID: 2}: 40, // Laptop 32GB RAM, 1TB SSD
+}
+
 func main() {
 	log := logger.New("inventory-service", "info")
 	slog.SetDefault(log)
@@ -41,7 +47,13 @@ func main() {
 			os.Exit(1)
 		}
 	}
-	log.Info("initialized stock", "product_count", len(initialStock))
+	for key, quantity := range initialVariantStock {
+		if err := memStore.SetVariantStock(key.ProductID, key.VariantID, quantity); err != nil {
+			log.Error("failed to set initial stock", "product_id", key.ProductID, "variant_id", key.VariantID, "error", err)
+			os.Exit(1)
+		}
+	}
+	log.Info("initialized stock", "product_count", len(initialStock), "variant_count", len(initialVariantStock))
 
 	server := inventorygrpc.NewInventoryServiceServer(memStore)
 
@@ -12,12 +12,23 @@ const (
 	StatusExpired   ReservationStatus = "expired"
 )
 
+// StockKey identifies one stock line: a product variant (SKU), or VariantID 0 for a product sold by its id alone
+type StockKey struct {
+	ProductID int64
+	VariantID int64
+}
+
 // ReservationItem represents a single product reservation within a reservation
 type ReservationItem struct {
 	ProductID int64
+	VariantID int64 // 0 for a product without variants
 	Quantity  int32
 }
 
+func (i ReservationItem) Key() StockKey {
+	return StockKey{ProductID: i.ProductID, VariantID: i.VariantID}
+}
+
 // Reservation represents a stock reservation made during checkout
 type Reservation struct {
 	ID         string
@@ -36,6 +47,7 @@ func (r *Reservation) IsExpired() bool {
 // StockInfo contains stock information for a product
 type StockInfo struct {
 	ProductID int64
+	VariantID int64 // 0 for a product without variants
 	Total     int32 // Total stock in inventory
 	Reserved  int32 // Currently reserved (pending checkout)
 }
@@ -40,6 +40,7 @@ func (s *InventoryServiceServer) GetStock(_ context.Context, req *pb.GetStockReq
 	for i, stock := range stocks {
 		protoStocks[i] = &pb.StockInfo{
 			ProductId: stock.ProductID,
+			VariantId: stock.VariantID,
 			Available: stock.Available(),
 			Reserved:  stock.Reserved,
 		}
@@ -63,6 +64,9 @@ func (s *InventoryServiceServer) Reserve(_ context.Context, req *pb.ReserveReque
 		if item.ProductId <= 0 {
 			return nil, status.Error(codes.InvalidArgument, "product_id must be greater than 0")
 		}
+		if item.VariantId < 0 {
+			return nil, status.Error(codes.InvalidArgument, "variant_id must not be negative")
+		}
 		if item.Quantity <= 0 {
 			return nil, status.Error(codes.InvalidArgument, "quantity must be greater than 0")
 		}
@@ -73,6 +77,7 @@ func (s *InventoryServiceServer) Reserve(_ context.Context, req *pb.ReserveReque
 	for i, item := range req.Items {
 		domainItems[i] = domain.ReservationItem{
 			ProductID: item.ProductId,
+			VariantID: item.VariantId,
 			Quantity:  item.Quantity,
 		}
 	}
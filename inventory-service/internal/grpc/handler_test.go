@@ -79,6 +79,10 @@ func (m *mockStore) SetStock(productID int64, quantity int32) error {
 	return nil
 }
 
+func (m *mockStore) SetVariantStock(productID, variantID int64, quantity int32) error {
+	return nil
+}
+
 func (m *mockStore) Close() error {
 	return nil
 }
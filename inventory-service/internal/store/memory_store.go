@@ -1,6 +1,7 @@
 package store
 
 import (
+	"sort"
 	"sync"
 	"time"
 
@@ -19,8 +20,8 @@ const (
 // MemoryStore implements InventoryStore with in-memory storage
 type MemoryStore struct {
 	mu           sync.RWMutex
-	stocks       map[int64]*domain.StockInfo    // productID -> stock info
-	reservations map[string]*domain.Reservation // reservationID -> reservation
+	stocks       map[domain.StockKey]*domain.StockInfo // product variant -> stock info
+	reservations map[string]*domain.Reservation        // reservationID -> reservation
 
 	stopCleanup chan struct{}
 	wg          sync.WaitGroup
@@ -29,7 +30,7 @@ type MemoryStore struct {
 // NewMemoryStore creates a new in-memory inventory store
 func NewMemoryStore() *MemoryStore {
 	s := &MemoryStore{
-		stocks:       make(map[int64]*domain.StockInfo),
+		stocks:       make(map[domain.StockKey]*domain.StockInfo),
 		reservations: make(map[string]*domain.Reservation),
 		stopCleanup:  make(chan struct{}),
 	}
@@ -66,7 +67,7 @@ func (s *MemoryStore) expireReservations() {
 		if reservation.Status == domain.StatusReserved && reservation.IsExpired() {
 			reservation.Status = domain.StatusExpired
 			for _, item := range reservation.Items {
-				s.stocks[item.ProductID].Reserved -= item.Quantity
+				s.stocks[item.Key()].Reserved -= item.Quantity
 			}
 		}
 	}
@@ -79,9 +80,14 @@ func (s *MemoryStore) GetStock(productIDs []int64) ([]domain.StockInfo, error) {
 
 	result := make([]domain.StockInfo, 0, len(productIDs))
 	for _, id := range productIDs {
-		if stock, exists := s.stocks[id]; exists {
-			result = append(result, *stock)
+		var variants []domain.StockInfo
+		for key, stock := range s.stocks {
+			if key.ProductID == id {
+				variants = append(variants, *stock)
+			}
 		}
+		sort.Slice(variants, func(i, j int) bool { return variants[i].VariantID < variants[j].VariantID })
+		result = append(result, variants...)
 	}
 	return result, nil
 }
@@ -93,7 +99,7 @@ func (s *MemoryStore) Reserve(checkoutID string, items []domain.ReservationItem)
 
 	// First pass: validate all items have sufficient stock
 	for _, item := range items {
-		stock, exists := s.stocks[item.ProductID]
+		stock, exists := s.stocks[item.Key()]
 		if !exists {
 			return nil, ErrProductNotFound
 		}
@@ -104,7 +110,7 @@ func (s *MemoryStore) Reserve(checkoutID string, items []domain.ReservationItem)
 
 	// Second pass: reserve stock for all items
 	for _, item := range items {
-		s.stocks[item.ProductID].Reserved += item.Quantity
+		s.stocks[item.Key()].Reserved += item.Quantity
 	}
 
 	// Create the reservation
@@ -132,6 +138,11 @@ func (s *MemoryStore) Confirm(reservationID string) error {
 		return ErrReservationNotFound
 	}
 
+	// confirming twice is a no-op, so checkout recovery can safely retry
+	if reservation.Status == domain.StatusConfirmed {
+		return nil
+	}
+
 	if reservation.Status != domain.StatusReserved {
 		return ErrInvalidStatus
 	}
@@ -142,7 +153,7 @@ func (s *MemoryStore) Confirm(reservationID string) error {
 
 	// Deduct from total stock (reserved already holds the quantity)
 	for _, item := range reservation.Items {
-		stock := s.stocks[item.ProductID]
+		stock := s.stocks[item.Key()]
 		stock.Total -= item.Quantity
 		stock.Reserved -= item.Quantity
 	}
@@ -152,6 +163,7 @@ func (s *MemoryStore) Confirm(reservationID string) error {
 }
 
 // Release cancels a reservation on payment failure
+// or restocks a confirmed one when checkout is compensated after confirmation
 func (s *MemoryStore) Release(reservationID string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -161,26 +173,38 @@ func (s *MemoryStore) Release(reservationID string) error {
 		return ErrReservationNotFound
 	}
 
-	if reservation.Status != domain.StatusReserved {
+	switch reservation.Status {
+	case domain.StatusReserved:
+		// Return reserved stock to available pool
+		for _, item := range reservation.Items {
+			s.stocks[item.Key()].Reserved -= item.Quantity
+		}
+	case domain.StatusConfirmed:
+		// Stock was already deducted, put it back
+		for _, item := range reservation.Items {
+			s.stocks[item.Key()].Total += item.Quantity
+		}
+	default:
 		return ErrInvalidStatus
 	}
 
-	// Return reserved stock to available pool
-	for _, item := range reservation.Items {
-		s.stocks[item.ProductID].Reserved -= item.Quantity
-	}
-
 	reservation.Status = domain.StatusReleased
 	return nil
 }
 
 // SetStock sets the stock level for a product
 func (s *MemoryStore) SetStock(productID int64, quantity int32) error {
+	return s.SetVariantStock(productID, 0, quantity)
+}
+
+// SetVariantStock sets the stock level for one variant of a product
+func (s *MemoryStore) SetVariantStock(productID, variantID int64, quantity int32) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.stocks[productID] = &domain.StockInfo{
+	s.stocks[domain.StockKey{ProductID: productID, VariantID: variantID}] = &domain.StockInfo{
 		ProductID: productID,
+		VariantID: variantID,
 		Total:     quantity,
 		Reserved:  0,
 	}
@@ -140,6 +140,26 @@ func TestMemoryStore_Confirm_InvalidStatus(t *testing.T) {
 	assert.ErrorIs(t, err, ErrInvalidStatus)
 }
 
+func TestMemoryStore_Confirm_Twice(t *testing.T) {
+	store := setupStore(t)
+	require.NoError(t, store.SetStock(1, 100))
+
+	items := []domain.ReservationItem{
+		{ProductID: 1, Quantity: 10},
+	}
+
+	reservation, _ := store.Reserve("checkout-123", items)
+	require.NoError(t, store.Confirm(reservation.ID))
+
+	// Second confirm (checkout recovery retry) must not deduct stock again
+	err := store.Confirm(reservation.ID)
+	require.NoError(t, err)
+
+	stocks, _ := store.GetStock([]int64{1})
+	assert.Equal(t, int32(90), stocks[0].Total)
+	assert.Equal(t, int32(0), stocks[0].Reserved)
+}
+
 func TestMemoryStore_Release_Success(t *testing.T) {
 	store := setupStore(t)
 	require.NoError(t, store.SetStock(1, 100))
@@ -160,6 +180,29 @@ func TestMemoryStore_Release_Success(t *testing.T) {
 	assert.Equal(t, int32(100), stocks[0].Available())
 }
 
+func TestMemoryStore_Release_Confirmed(t *testing.T) {
+	store := setupStore(t)
+	require.NoError(t, store.SetStock(1, 100))
+
+	items := []domain.ReservationItem{
+		{ProductID: 1, Quantity: 10},
+	}
+
+	reservation, _ := store.Reserve("checkout-123", items)
+	require.NoError(t, store.Confirm(reservation.ID))
+
+	err := store.Release(reservation.ID)
+	require.NoError(t, err)
+
+	// Confirmed stock should be put back
+	stocks, _ := store.GetStock([]int64{1})
+	assert.Equal(t, int32(100), stocks[0].Total)
+	assert.Equal(t, int32(0), stocks[0].Reserved)
+
+	// Released reservation can't be released again
+	assert.ErrorIs(t, store.Release(reservation.ID), ErrInvalidStatus)
+}
+
 func TestMemoryStore_Release_NotFound(t *testing.T) {
 	store := setupStore(t)
 
@@ -231,3 +274,26 @@ func TestMemoryStore_ExpireReservations(t *testing.T) {
 	assert.Equal(t, int32(100), stocks[0].Available())
 	assert.Equal(t, int32(0), stocks[0].Reserved)
 }
+
+func TestMemoryStore_VariantsHaveSeparateStock(t *testing.T) {
+	store := setupStore(t)
+	require.NoError(t, store.SetVariantStock(1, 1, 10))
+	require.NoError(t, store.SetVariantStock(1, 2, 5))
+
+	_, err := store.Reserve("checkout-123", []domain.ReservationItem{{ProductID: 1, VariantID: 2, Quantity: 5}})
+	require.NoError(t, err)
+
+	stocks, err := store.GetStock([]int64{1})
+	require.NoError(t, err)
+	require.Len(t, stocks, 2)
+	assert.Equal(t, int64(1), stocks[0].VariantID)
+	assert.Equal(t, int32(10), stocks[0].Available())
+	assert.Equal(t, int64(2), stocks[1].VariantID)
+	assert.Equal(t, int32(0), stocks[1].Available())
+
+	// the product itself has no stock line, only its variants do
+	_, err = store.Reserve("checkout-456", []domain.ReservationItem{{ProductID: 1, Quantity: 1}})
+	assert.ErrorIs(t, err, ErrProductNotFound)
+	_, err = store.Reserve("checkout-789", []domain.ReservationItem{{ProductID: 1, VariantID: 2, Quantity: 1}})
+	assert.ErrorIs(t, err, ErrInsufficientStock)
+}
@@ -17,7 +17,7 @@ var (
 
 // InventoryStore defines the interface for inventory storage operations
 type InventoryStore interface {
-	// GetStock returns stock information for the given product IDs
+	// GetStock returns stock information for the given product IDs, one entry per variant of products sold by SKU
 	GetStock(productIDs []int64) ([]domain.StockInfo, error)
 
 	// Reserve creates a new reservation, reducing available stock
@@ -25,16 +25,19 @@ type InventoryStore interface {
 	Reserve(checkoutID string, items []domain.ReservationItem) (*domain.Reservation, error)
 
 	// Confirm finalizes a reservation, permanently deducting stock
-	// Can only be called on reservations with status "reserved"
+	// Can only be called on reservations with status "reserved", confirming a "confirmed" one is a no-op
 	Confirm(reservationID string) error
 
 	// Release cancels a reservation, returning stock to available pool
-	// Can only be called on reservations with status "reserved"
+	// Can only be called on reservations with status "reserved" or "confirmed"
 	Release(reservationID string) error
 
 	// SetStock sets the stock level for a product (used for initialization)
 	SetStock(productID int64, quantity int32) error
 
+	// SetVariantStock sets the stock level for one variant (SKU) of a product
+	SetVariantStock(productID, variantID int64, quantity int32) error
+
 	// Close shuts down the store and any background processes
 	Close() error
 }
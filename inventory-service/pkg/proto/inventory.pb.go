@@ -21,12 +21,13 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-// Stock information for a product
+// Stock information for a product, or one variant of it
 type StockInfo struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ProductId     int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	Available     int32                  `protobuf:"varint,2,opt,name=available,proto3" json:"available,omitempty"` // Available stock (total - reserved)
-	Reserved      int32                  `protobuf:"varint,3,opt,name=reserved,proto3" json:"reserved,omitempty"`   // Currently reserved quantity
+	Available     int32                  `protobuf:"varint,2,opt,name=available,proto3" json:"available,omitempty"`                  // Available stock (total - reserved)
+	Reserved      int32                  `protobuf:"varint,3,opt,name=reserved,proto3" json:"reserved,omitempty"`                    // Currently reserved quantity
+	VariantId     int64                  `protobuf:"varint,4,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"` // 0 for a product without variants
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -82,7 +83,14 @@ func (x *StockInfo) GetReserved() int32 {
 	return 0
 }
 
-// Request to get stock levels for products
+func (x *StockInfo) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+// Request to get stock levels for products, products sold by SKU get one entry per variant
 type GetStockRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ProductIds    []int64                `protobuf:"varint,1,rep,packed,name=product_ids,json=productIds,proto3" json:"product_ids,omitempty"`
@@ -177,6 +185,7 @@ type ReservationItem struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ProductId     int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
 	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	VariantId     int64                  `protobuf:"varint,3,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"` // 0 for a product without variants
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -225,6 +234,13 @@ func (x *ReservationItem) GetQuantity() int32 {
 	return 0
 }
 
+func (x *ReservationItem) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
 // Request to create a reservation
 type ReserveRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -515,21 +531,25 @@ var File_pkg_proto_inventory_proto protoreflect.FileDescriptor
 
 const file_pkg_proto_inventory_proto_rawDesc = "" +
 	"\n" +
-	"\x19pkg/proto/inventory.proto\x12\tinventory\"d\n" +
+	"\x19pkg/proto/inventory.proto\x12\tinventory\"\x83\x01\n" +
 	"\tStockInfo\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x01 \x01(\x03R\tproductId\x12\x1c\n" +
 	"\tavailable\x18\x02 \x01(\x05R\tavailable\x12\x1a\n" +
-	"\breserved\x18\x03 \x01(\x05R\breserved\"2\n" +
+	"\breserved\x18\x03 \x01(\x05R\breserved\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x04 \x01(\x03R\tvariantId\"2\n" +
 	"\x0fGetStockRequest\x12\x1f\n" +
 	"\vproduct_ids\x18\x01 \x03(\x03R\n" +
 	"productIds\"@\n" +
 	"\x10GetStockResponse\x12,\n" +
-	"\x06stocks\x18\x01 \x03(\v2\x14.inventory.StockInfoR\x06stocks\"L\n" +
+	"\x06stocks\x18\x01 \x03(\v2\x14.inventory.StockInfoR\x06stocks\"k\n" +
 	"\x0fReservationItem\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x01 \x01(\x03R\tproductId\x12\x1a\n" +
-	"\bquantity\x18\x02 \x01(\x05R\bquantity\"c\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x03 \x01(\x03R\tvariantId\"c\n" +
 	"\x0eReserveRequest\x12\x1f\n" +
 	"\vcheckout_id\x18\x01 \x01(\tR\n" +
 	"checkoutId\x120\n" +
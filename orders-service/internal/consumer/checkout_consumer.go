@@ -8,6 +8,7 @@ import (
 
 	"github.com/fjod/go_cart/orders-service/internal/domain"
 	"github.com/fjod/go_cart/orders-service/internal/repository"
+	"github.com/fjod/go_cart/pkg/money"
 	"github.com/google/uuid"
 	"github.com/segmentio/kafka-go"
 )
@@ -15,19 +16,27 @@ import (
 // eventItem mirrors the Kafka payload item shape from the checkout-service outbox.
 // The checkout-service publishes prices as "unit_price" (from CartSnapshotItem),
 // which differs from domain.OrderItem's "price" json tag.
+// Events written before minor units carry only the float fields.
 type eventItem struct {
 	ProductID   int64   `json:"product_id"`
+	VariantID   int64   `json:"variant_id"`
+	SKU         string  `json:"sku"`
 	ProductName string  `json:"product_name"`
 	Quantity    int     `json:"quantity"`
 	Price       float64 `json:"unit_price"`
+	PriceMinor  *int64  `json:"unit_price_minor"`
+	Discount    int64   `json:"discount_minor"`
 }
 
 type CheckoutCompletedEvent struct {
-	CheckoutID  string      `json:"checkout_id"`
-	UserID      string      `json:"user_id"`
-	Items       []eventItem `json:"items"`
-	TotalAmount float64     `json:"total_amount"`
-	Currency    string      `json:"currency"`
+	CheckoutID       string      `json:"checkout_id"`
+	UserID           string      `json:"user_id"`
+	Items            []eventItem `json:"items"`
+	TotalAmount      float64     `json:"total_amount"`
+	TotalAmountMinor *int64      `json:"total_amount_minor"`
+	Currency         string      `json:"currency"`
+	PromoCode        string      `json:"promo_code"`
+	DiscountMinor    int64       `json:"discount_amount_minor"`
 }
 
 type Consumer struct {
@@ -87,16 +96,19 @@ func (c *Consumer) processMessage(ctx context.Context) {
 
 	currency := event.Currency
 	if currency == "" {
-		currency = "USD"
+		currency = money.DefaultCurrency
 	}
 
 	items := make([]domain.OrderItem, len(event.Items))
 	for i, item := range event.Items {
 		items[i] = domain.OrderItem{
 			ProductID:   item.ProductID,
+			VariantID:   item.VariantID,
+			SKU:         item.SKU,
 			ProductName: item.ProductName,
 			Quantity:    item.Quantity,
-			Price:       item.Price,
+			Price:       exactAmount(item.PriceMinor, item.Price, currency),
+			Discount:    money.New(item.Discount, currency),
 		}
 	}
 
@@ -104,8 +116,10 @@ func (c *Consumer) processMessage(ctx context.Context) {
 		ID:          uuid.New(),
 		CheckoutID:  checkoutID,
 		UserID:      event.UserID,
-		TotalAmount: event.TotalAmount,
+		TotalAmount: exactAmount(event.TotalAmountMinor, event.TotalAmount, currency),
 		Currency:    currency,
+		PromoCode:   event.PromoCode,
+		Discount:    money.New(event.DiscountMinor, currency),
 		Status:      domain.OrderStatusConfirmed,
 		Items:       items,
 	}
@@ -121,3 +135,11 @@ func (c *Consumer) processMessage(ctx context.Context) {
 
 	c.logger.Info("order created", "order_id", order.ID, "checkout_id", order.CheckoutID)
 }
+
+// exactAmount prefers the minor units of the event and rounds the float of older events
+func exactAmount(minor *int64, legacy float64, currency string) money.Money {
+	if minor != nil {
+		return money.New(*minor, currency)
+	}
+	return money.FromFloat(legacy, currency)
+}
@@ -146,13 +146,15 @@ func TestProcessMessage_CreatesOrder(t *testing.T) {
 	createTopic(t, brokerAddr, topic)
 
 	checkoutID := uuid.New()
+	totalMinor, priceMinor := int64(12999), int64(12999)
 	event := CheckoutCompletedEvent{
-		CheckoutID:  checkoutID.String(),
-		UserID:      "user-test-1",
-		TotalAmount: 129.99,
-		Currency:    "USD",
+		CheckoutID:       checkoutID.String(),
+		UserID:           "user-test-1",
+		TotalAmount:      129.99,
+		TotalAmountMinor: &totalMinor,
+		Currency:         "USD",
 		Items: []eventItem{
-			{ProductID: 1, ProductName: "Laptop", Quantity: 1, Price: 129.99},
+			{ProductID: 1, VariantID: 2, SKU: "LAPTOP-32GB-1TB", ProductName: "Laptop", Quantity: 1, Price: 129.99, PriceMinor: &priceMinor},
 		},
 	}
 
@@ -166,7 +168,10 @@ func TestProcessMessage_CreatesOrder(t *testing.T) {
 		if err != nil || len(orders) == 0 {
 			return false
 		}
-		return orders[0].CheckoutID == checkoutID
+		return orders[0].CheckoutID == checkoutID &&
+			orders[0].TotalAmount.Amount == totalMinor &&
+			orders[0].Items[0].Price.Amount == priceMinor &&
+			orders[0].Items[0].SKU == "LAPTOP-32GB-1TB"
 	}, 15*time.Second, 500*time.Millisecond)
 }
 
@@ -1,8 +1,10 @@
 package domain
 
 import (
+	"encoding/json"
 	"time"
 
+	"github.com/fjod/go_cart/pkg/money"
 	"github.com/google/uuid"
 )
 
@@ -16,18 +18,76 @@ const (
 )
 
 type OrderItem struct {
+	ProductID   int64
+	VariantID   int64  // 0 for a product sold by its id alone
+	SKU         string // empty for a product sold by its id alone
+	ProductName string
+	Quantity    int
+	Price       money.Money
+	Discount    money.Money // share of the order promo code discount
+}
+
+// orderItemJSON is how an item is stored in orders.items: price_minor is exact,
+// price is the legacy float kept for rows written before minor units
+type orderItemJSON struct {
 	ProductID   int64   `json:"product_id"`
+	VariantID   int64   `json:"variant_id,omitempty"`
+	SKU         string  `json:"sku,omitempty"`
 	ProductName string  `json:"product_name"`
 	Quantity    int     `json:"quantity"`
 	Price       float64 `json:"price"`
+	PriceMinor  *int64  `json:"price_minor,omitempty"`
+	Currency    string  `json:"currency,omitempty"`
+	Discount    int64   `json:"discount_minor,omitempty"`
+}
+
+func (i OrderItem) MarshalJSON() ([]byte, error) {
+	return json.Marshal(orderItemJSON{
+		ProductID:   i.ProductID,
+		VariantID:   i.VariantID,
+		SKU:         i.SKU,
+		ProductName: i.ProductName,
+		Quantity:    i.Quantity,
+		Price:       i.Price.Float64(),
+		PriceMinor:  &i.Price.Amount,
+		Currency:    i.Price.Currency,
+		Discount:    i.Discount.Amount,
+	})
+}
+
+func (i *OrderItem) UnmarshalJSON(data []byte) error {
+	var raw orderItemJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	currency := raw.Currency
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+	price := money.FromFloat(raw.Price, currency)
+	if raw.PriceMinor != nil {
+		price = money.New(*raw.PriceMinor, currency)
+	}
+	*i = OrderItem{
+		ProductID:   raw.ProductID,
+		VariantID:   raw.VariantID,
+		SKU:         raw.SKU,
+		ProductName: raw.ProductName,
+		Quantity:    raw.Quantity,
+		Price:       price,
+		Discount:    money.New(raw.Discount, currency),
+	}
+	return nil
 }
 
 type Order struct {
 	ID          uuid.UUID
 	CheckoutID  uuid.UUID
 	UserID      string
-	TotalAmount float64
+	TotalAmount money.Money // what the customer paid, the discount is already taken off
 	Currency    string
+	PromoCode   string
+	Discount    money.Money
 	Status      OrderStatus
 	Items       []OrderItem
 	CreatedAt   time.Time
@@ -56,20 +56,27 @@ func convertOrderToProto(order *domain.Order) *pb.Order {
 	items := make([]*pb.OrderItem, 0, len(order.Items))
 	for _, item := range order.Items {
 		items = append(items, &pb.OrderItem{
-			ProductId:   item.ProductID,
-			ProductName: item.ProductName,
-			Quantity:    int32(item.Quantity),
-			Price:       item.Price,
+			ProductId:     item.ProductID,
+			VariantId:     item.VariantID,
+			Sku:           item.SKU,
+			ProductName:   item.ProductName,
+			Quantity:      int32(item.Quantity),
+			Price:         item.Price.Float64(),
+			PriceMinor:    item.Price.Amount,
+			DiscountMinor: item.Discount.Amount,
 		})
 	}
 	return &pb.Order{
-		Id:          order.ID.String(),
-		CheckoutId:  order.CheckoutID.String(),
-		UserId:      order.UserID,
-		TotalAmount: order.TotalAmount,
-		Currency:    order.Currency,
-		Status:      string(order.Status),
-		Items:       items,
-		CreatedAt:   order.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Id:                  order.ID.String(),
+		CheckoutId:          order.CheckoutID.String(),
+		UserId:              order.UserID,
+		TotalAmount:         order.TotalAmount.Float64(),
+		TotalAmountMinor:    order.TotalAmount.Amount,
+		Currency:            order.Currency,
+		PromoCode:           order.PromoCode,
+		DiscountAmountMinor: order.Discount.Amount,
+		Status:              string(order.Status),
+		Items:               items,
+		CreatedAt:           order.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
 }
@@ -0,0 +1,5 @@
+UPDATE orders o
+SET items = COALESCE((
+        SELECT jsonb_agg(e.item - 'price_minor' - 'currency' ORDER BY e.ord)
+        FROM jsonb_array_elements(o.items) WITH ORDINALITY AS e(item, ord)
+    ), '[]'::JSONB);
@@ -0,0 +1,11 @@
+-- Items written before minor units only carry a float price; every one of them is in USD (2 fraction digits)
+UPDATE orders o
+SET items = COALESCE((
+        SELECT jsonb_agg(e.item || jsonb_build_object(
+                   'price_minor', ROUND((e.item->>'price')::NUMERIC * 100)::BIGINT,
+                   'currency', o.currency) ORDER BY e.ord)
+        FROM jsonb_array_elements(o.items) WITH ORDINALITY AS e(item, ord)
+    ), '[]'::JSONB)
+WHERE EXISTS (
+    SELECT 1 FROM jsonb_array_elements(o.items) AS i(item) WHERE NOT i.item ? 'price_minor'
+);
@@ -0,0 +1,4 @@
+-- total_amount is what the customer paid, discount_amount is what the promo code took off before it
+ALTER TABLE orders
+    ADD COLUMN promo_code VARCHAR(32) NOT NULL DEFAULT '',
+    ADD COLUMN discount_amount DECIMAL(10, 2) NOT NULL DEFAULT 0;
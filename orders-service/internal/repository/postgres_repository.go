@@ -8,6 +8,7 @@ import (
 	"fmt"
 
 	"github.com/fjod/go_cart/orders-service/internal/domain"
+	"github.com/fjod/go_cart/pkg/money"
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
@@ -73,15 +74,17 @@ func (r *Repository) CreateOrder(ctx context.Context, order *domain.Order) error
 		return fmt.Errorf("failed to marshal order items: %w", err)
 	}
 
-	query := `INSERT INTO orders (id, checkout_id, user_id, total_amount, currency, status, items, created_at, updated_at)
-	          VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())`
+	query := `INSERT INTO orders (id, checkout_id, user_id, total_amount, currency, promo_code, discount_amount, status, items, created_at, updated_at)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW())`
 
 	_, insertErr := r.db.ExecContext(ctx, query,
 		order.ID,
 		order.CheckoutID,
 		order.UserID,
-		order.TotalAmount,
+		order.TotalAmount.String(),
 		order.Currency,
+		order.PromoCode,
+		order.Discount.String(),
 		order.Status,
 		itemsJSON)
 
@@ -96,17 +99,20 @@ func (r *Repository) CreateOrder(ctx context.Context, order *domain.Order) error
 }
 
 func (r *Repository) GetOrderByID(ctx context.Context, id uuid.UUID) (*domain.Order, error) {
-	query := `SELECT id, checkout_id, user_id, total_amount, currency, status, items, created_at, updated_at
+	query := `SELECT id, checkout_id, user_id, total_amount, currency, promo_code, discount_amount, status, items, created_at, updated_at
 	          FROM orders WHERE id = $1`
 
 	var order domain.Order
+	var totalAmount, discount string
 	var itemsJSON []byte
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&order.ID,
 		&order.CheckoutID,
 		&order.UserID,
-		&order.TotalAmount,
+		&totalAmount,
 		&order.Currency,
+		&order.PromoCode,
+		&discount,
 		&order.Status,
 		&itemsJSON,
 		&order.CreatedAt,
@@ -119,6 +125,12 @@ func (r *Repository) GetOrderByID(ctx context.Context, id uuid.UUID) (*domain.Or
 		return nil, fmt.Errorf("query order by id: %w", err)
 	}
 
+	if order.TotalAmount, err = money.Parse(totalAmount, order.Currency); err != nil {
+		return nil, fmt.Errorf("parse order total: %w", err)
+	}
+	if order.Discount, err = money.Parse(discount, order.Currency); err != nil {
+		return nil, fmt.Errorf("parse order discount: %w", err)
+	}
 	if err := json.Unmarshal(itemsJSON, &order.Items); err != nil {
 		return nil, fmt.Errorf("unmarshal order items: %w", err)
 	}
@@ -127,7 +139,7 @@ func (r *Repository) GetOrderByID(ctx context.Context, id uuid.UUID) (*domain.Or
 }
 
 func (r *Repository) ListOrdersByUserID(ctx context.Context, userID string) ([]*domain.Order, error) {
-	query := `SELECT id, checkout_id, user_id, total_amount, currency, status, items, created_at, updated_at
+	query := `SELECT id, checkout_id, user_id, total_amount, currency, promo_code, discount_amount, status, items, created_at, updated_at
 	          FROM orders WHERE user_id = $1 ORDER BY created_at DESC`
 
 	rows, err := r.db.QueryContext(ctx, query, userID)
@@ -139,13 +151,16 @@ func (r *Repository) ListOrdersByUserID(ctx context.Context, userID string) ([]*
 	var orders []*domain.Order
 	for rows.Next() {
 		var order domain.Order
+		var totalAmount, discount string
 		var itemsJSON []byte
 		if err := rows.Scan(
 			&order.ID,
 			&order.CheckoutID,
 			&order.UserID,
-			&order.TotalAmount,
+			&totalAmount,
 			&order.Currency,
+			&order.PromoCode,
+			&discount,
 			&order.Status,
 			&itemsJSON,
 			&order.CreatedAt,
@@ -153,6 +168,12 @@ func (r *Repository) ListOrdersByUserID(ctx context.Context, userID string) ([]*
 		); err != nil {
 			return nil, fmt.Errorf("scan order row: %w", err)
 		}
+		if order.TotalAmount, err = money.Parse(totalAmount, order.Currency); err != nil {
+			return nil, fmt.Errorf("parse order total: %w", err)
+		}
+		if order.Discount, err = money.Parse(discount, order.Currency); err != nil {
+			return nil, fmt.Errorf("parse order discount: %w", err)
+		}
 		if err := json.Unmarshal(itemsJSON, &order.Items); err != nil {
 			return nil, fmt.Errorf("unmarshal order items: %w", err)
 		}
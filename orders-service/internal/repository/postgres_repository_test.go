@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/fjod/go_cart/orders-service/internal/domain"
+	"github.com/fjod/go_cart/pkg/money"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -66,11 +67,11 @@ func newTestOrder(checkoutID uuid.UUID) *domain.Order {
 		ID:          uuid.New(),
 		CheckoutID:  checkoutID,
 		UserID:      "user-123",
-		TotalAmount: 99.99,
+		TotalAmount: money.New(9999, "USD"),
 		Currency:    "USD",
 		Status:      domain.OrderStatusConfirmed,
 		Items: []domain.OrderItem{
-			{ProductID: 1, ProductName: "Laptop", Quantity: 1, Price: 99.99},
+			{ProductID: 1, ProductName: "Laptop", Quantity: 1, Price: money.New(9999, "USD")},
 		},
 	}
 }
@@ -98,6 +99,27 @@ func TestCreateOrder_Success(t *testing.T) {
 	assert.Equal(t, order.Items[0].ProductID, fetched.Items[0].ProductID)
 }
 
+func TestCreateOrder_PromoCode(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	order := newTestOrder(uuid.New())
+	order.PromoCode = "TEN"
+	order.Discount = money.New(1000, "USD")
+	order.TotalAmount = money.New(8999, "USD")
+	order.Items[0].Discount = money.New(1000, "USD")
+
+	require.NoError(t, repo.CreateOrder(ctx, order))
+
+	fetched, err := repo.GetOrderByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "TEN", fetched.PromoCode)
+	assert.Equal(t, order.Discount, fetched.Discount)
+	assert.Equal(t, order.TotalAmount, fetched.TotalAmount)
+	assert.Equal(t, int64(1000), fetched.Items[0].Discount.Amount)
+}
+
 func TestCreateOrder_DuplicateCheckout(t *testing.T) {
 	repo, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -150,10 +172,10 @@ func TestListOrdersByUserID(t *testing.T) {
 		ID:          uuid.New(),
 		CheckoutID:  uuid.New(),
 		UserID:      userID,
-		TotalAmount: 10.00,
+		TotalAmount: money.New(1000, "USD"),
 		Currency:    "USD",
 		Status:      domain.OrderStatusConfirmed,
-		Items:       []domain.OrderItem{{ProductID: 1, ProductName: "Mouse", Quantity: 1, Price: 10.00}},
+		Items:       []domain.OrderItem{{ProductID: 1, ProductName: "Mouse", Quantity: 1, Price: money.New(1000, "USD")}},
 	}
 	require.NoError(t, repo.CreateOrder(ctx, order1))
 
@@ -164,10 +186,10 @@ func TestListOrdersByUserID(t *testing.T) {
 		ID:          uuid.New(),
 		CheckoutID:  uuid.New(),
 		UserID:      userID,
-		TotalAmount: 20.00,
+		TotalAmount: money.New(2000, "USD"),
 		Currency:    "USD",
 		Status:      domain.OrderStatusConfirmed,
-		Items:       []domain.OrderItem{{ProductID: 2, ProductName: "Keyboard", Quantity: 1, Price: 20.00}},
+		Items:       []domain.OrderItem{{ProductID: 2, ProductName: "Keyboard", Quantity: 1, Price: money.New(2000, "USD")}},
 	}
 	require.NoError(t, repo.CreateOrder(ctx, order2))
 
@@ -179,3 +201,21 @@ func TestListOrdersByUserID(t *testing.T) {
 	assert.Equal(t, order2.ID, orders[0].ID)
 	assert.Equal(t, order1.ID, orders[1].ID)
 }
+
+func TestGetOrder_ReadsLegacyFloatPrices(t *testing.T) {
+	repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	id := uuid.New()
+	_, err := repo.db.ExecContext(ctx,
+		`INSERT INTO orders (id, checkout_id, user_id, total_amount, currency, status, items)
+		 VALUES ($1, $2, 'user-legacy', 59.98, 'USD', 'CONFIRMED', $3)`,
+		id, uuid.New(), `[{"product_id":1,"product_name":"Widget","quantity":2,"price":29.99}]`)
+	require.NoError(t, err)
+
+	fetched, err := repo.GetOrderByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, money.New(5998, "USD"), fetched.TotalAmount)
+	assert.Equal(t, money.New(2999, "USD"), fetched.Items[0].Price)
+}
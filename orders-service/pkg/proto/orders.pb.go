@@ -22,11 +22,16 @@ const (
 )
 
 type OrderItem struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProductId     int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	ProductName   string                 `protobuf:"bytes,2,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"`
-	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
-	Price         float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	ProductId   int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	ProductName string                 `protobuf:"bytes,2,opt,name=product_name,json=productName,proto3" json:"product_name,omitempty"`
+	Quantity    int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// Deprecated: Marked as deprecated in pkg/proto/orders.proto.
+	Price         float64 `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`                            // use price_minor
+	PriceMinor    int64   `protobuf:"varint,5,opt,name=price_minor,json=priceMinor,proto3" json:"price_minor,omitempty"` // minor units of the order currency, e.g. cents
+	VariantId     int64   `protobuf:"varint,6,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`    // 0 for a product sold by its id alone
+	Sku           string  `protobuf:"bytes,7,opt,name=sku,proto3" json:"sku,omitempty"`
+	DiscountMinor int64   `protobuf:"varint,8,opt,name=discount_minor,json=discountMinor,proto3" json:"discount_minor,omitempty"` // share of the order promo code discount
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -82,6 +87,7 @@ func (x *OrderItem) GetQuantity() int32 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in pkg/proto/orders.proto.
 func (x *OrderItem) GetPrice() float64 {
 	if x != nil {
 		return x.Price
@@ -89,18 +95,50 @@ func (x *OrderItem) GetPrice() float64 {
 	return 0
 }
 
+func (x *OrderItem) GetPriceMinor() int64 {
+	if x != nil {
+		return x.PriceMinor
+	}
+	return 0
+}
+
+func (x *OrderItem) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *OrderItem) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *OrderItem) GetDiscountMinor() int64 {
+	if x != nil {
+		return x.DiscountMinor
+	}
+	return 0
+}
+
 type Order struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	CheckoutId    string                 `protobuf:"bytes,2,opt,name=checkout_id,json=checkoutId,proto3" json:"checkout_id,omitempty"`
-	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	TotalAmount   float64                `protobuf:"fixed64,4,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"`
-	Currency      string                 `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
-	Status        string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
-	Items         []*OrderItem           `protobuf:"bytes,7,rep,name=items,proto3" json:"items,omitempty"`
-	CreatedAt     string                 `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CheckoutId string                 `protobuf:"bytes,2,opt,name=checkout_id,json=checkoutId,proto3" json:"checkout_id,omitempty"`
+	UserId     string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Deprecated: Marked as deprecated in pkg/proto/orders.proto.
+	TotalAmount         float64      `protobuf:"fixed64,4,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"` // use total_amount_minor
+	Currency            string       `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	Status              string       `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	Items               []*OrderItem `protobuf:"bytes,7,rep,name=items,proto3" json:"items,omitempty"`
+	CreatedAt           string       `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	TotalAmountMinor    int64        `protobuf:"varint,9,opt,name=total_amount_minor,json=totalAmountMinor,proto3" json:"total_amount_minor,omitempty"` // what was paid, discount_amount_minor is already taken off
+	PromoCode           string       `protobuf:"bytes,10,opt,name=promo_code,json=promoCode,proto3" json:"promo_code,omitempty"`
+	DiscountAmountMinor int64        `protobuf:"varint,11,opt,name=discount_amount_minor,json=discountAmountMinor,proto3" json:"discount_amount_minor,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
 }
 
 func (x *Order) Reset() {
@@ -154,6 +192,7 @@ func (x *Order) GetUserId() string {
 	return ""
 }
 
+// Deprecated: Marked as deprecated in pkg/proto/orders.proto.
 func (x *Order) GetTotalAmount() float64 {
 	if x != nil {
 		return x.TotalAmount
@@ -189,6 +228,27 @@ func (x *Order) GetCreatedAt() string {
 	return ""
 }
 
+func (x *Order) GetTotalAmountMinor() int64 {
+	if x != nil {
+		return x.TotalAmountMinor
+	}
+	return 0
+}
+
+func (x *Order) GetPromoCode() string {
+	if x != nil {
+		return x.PromoCode
+	}
+	return ""
+}
+
+func (x *Order) GetDiscountAmountMinor() int64 {
+	if x != nil {
+		return x.DiscountAmountMinor
+	}
+	return 0
+}
+
 type GetOrderRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
@@ -369,24 +429,35 @@ var File_pkg_proto_orders_proto protoreflect.FileDescriptor
 
 const file_pkg_proto_orders_proto_rawDesc = "" +
 	"\n" +
-	"\x16pkg/proto/orders.proto\x12\x06orders\"\x7f\n" +
+	"\x16pkg/proto/orders.proto\x12\x06orders\"\xfc\x01\n" +
 	"\tOrderItem\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x01 \x01(\x03R\tproductId\x12!\n" +
 	"\fproduct_name\x18\x02 \x01(\tR\vproductName\x12\x1a\n" +
-	"\bquantity\x18\x03 \x01(\x05R\bquantity\x12\x14\n" +
-	"\x05price\x18\x04 \x01(\x01R\x05price\"\xf0\x01\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\x12\x18\n" +
+	"\x05price\x18\x04 \x01(\x01B\x02\x18\x01R\x05price\x12\x1f\n" +
+	"\vprice_minor\x18\x05 \x01(\x03R\n" +
+	"priceMinor\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x06 \x01(\x03R\tvariantId\x12\x10\n" +
+	"\x03sku\x18\a \x01(\tR\x03sku\x12%\n" +
+	"\x0ediscount_minor\x18\b \x01(\x03R\rdiscountMinor\"\xf5\x02\n" +
 	"\x05Order\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
 	"\vcheckout_id\x18\x02 \x01(\tR\n" +
 	"checkoutId\x12\x17\n" +
-	"\auser_id\x18\x03 \x01(\tR\x06userId\x12!\n" +
-	"\ftotal_amount\x18\x04 \x01(\x01R\vtotalAmount\x12\x1a\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12%\n" +
+	"\ftotal_amount\x18\x04 \x01(\x01B\x02\x18\x01R\vtotalAmount\x12\x1a\n" +
 	"\bcurrency\x18\x05 \x01(\tR\bcurrency\x12\x16\n" +
 	"\x06status\x18\x06 \x01(\tR\x06status\x12'\n" +
 	"\x05items\x18\a \x03(\v2\x11.orders.OrderItemR\x05items\x12\x1d\n" +
 	"\n" +
-	"created_at\x18\b \x01(\tR\tcreatedAt\",\n" +
+	"created_at\x18\b \x01(\tR\tcreatedAt\x12,\n" +
+	"\x12total_amount_minor\x18\t \x01(\x03R\x10totalAmountMinor\x12\x1d\n" +
+	"\n" +
+	"promo_code\x18\n" +
+	" \x01(\tR\tpromoCode\x122\n" +
+	"\x15discount_amount_minor\x18\v \x01(\x03R\x13discountAmountMinor\",\n" +
 	"\x0fGetOrderRequest\x12\x19\n" +
 	"\border_id\x18\x01 \x01(\tR\aorderId\"7\n" +
 	"\x10GetOrderResponse\x12#\n" +
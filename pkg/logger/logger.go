@@ -83,3 +83,28 @@ func UnaryServerInterceptor(log *slog.Logger) grpc.UnaryServerInterceptor {
 		return resp, err
 	}
 }
+
+func StreamServerInterceptor(log *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+
+		err := handler(srv, ss)
+
+		duration := time.Since(start)
+		st, _ := status.FromError(err)
+
+		attrs := []any{
+			slog.String("method", info.FullMethod),
+			slog.Duration("duration", duration),
+			slog.String("grpc_code", st.Code().String()),
+		}
+
+		if err != nil {
+			log.Error("stream failed", append(attrs, slog.String("error", err.Error()))...)
+		} else {
+			log.Info("stream completed", attrs...)
+		}
+
+		return err
+	}
+}
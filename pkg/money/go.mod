@@ -0,0 +1,3 @@
+module github.com/fjod/go_cart/pkg/money
+
+go 1.25
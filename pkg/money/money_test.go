@@ -0,0 +1,96 @@
+package money
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in       string
+		currency string
+		want     int64
+	}{
+		{"1299.99", "USD", 129999},
+		{"0.1", "USD", 10},
+		{"5", "USD", 500},
+		{"-3.05", "USD", -305},
+		{"1500", "JPY", 1500},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in, tt.currency)
+		if err != nil {
+			t.Fatalf("Parse(%q): unexpected error %v", tt.in, err)
+		}
+		if got.Amount != tt.want || got.Currency != tt.currency {
+			t.Errorf("Parse(%q) = %+v, want %d %s", tt.in, got, tt.want, tt.currency)
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, in := range []string{"", "abc", "1.234", ".5", "1.-5", "10.5 JPY"} {
+		if _, err := Parse(in, "USD"); !errors.Is(err, ErrInvalidAmount) {
+			t.Errorf("Parse(%q): expected ErrInvalidAmount, got %v", in, err)
+		}
+	}
+	if _, err := Parse("10.5", "JPY"); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("expected JPY fraction to be rejected, got %v", err)
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		m    Money
+		want string
+	}{
+		{New(129999, "USD"), "1299.99"},
+		{New(5, "USD"), "0.05"},
+		{New(-305, "USD"), "-3.05"},
+		{New(0, "USD"), "0.00"},
+		{New(1500, "JPY"), "1500"},
+	}
+	for _, tt := range tests {
+		if got := tt.m.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.m, got, tt.want)
+		}
+	}
+}
+
+func TestFromFloat_RoundsToMinorUnit(t *testing.T) {
+	// 0.1 + 0.2 is 0.30000000000000004 as float64
+	if got := FromFloat(0.1+0.2, "USD"); got.Amount != 30 {
+		t.Errorf("expected 30 cents, got %d", got.Amount)
+	}
+	if got := FromFloat(29.99, "USD"); got.Amount != 2999 {
+		t.Errorf("expected 2999 cents, got %d", got.Amount)
+	}
+}
+
+func TestSumHasNoDrift(t *testing.T) {
+	total := Zero("USD")
+	price := New(10, "USD") // 0.10
+	for i := 0; i < 1000; i++ {
+		var err error
+		total, err = total.Add(price)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if total.String() != "100.00" {
+		t.Errorf("expected 100.00, got %s", total)
+	}
+}
+
+func TestAdd_CurrencyMismatch(t *testing.T) {
+	_, err := New(100, "USD").Add(New(100, "EUR"))
+	if !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("expected ErrCurrencyMismatch, got %v", err)
+	}
+}
+
+func TestMultiply(t *testing.T) {
+	if got := New(2999, "USD").Multiply(3); got.Amount != 8997 {
+		t.Errorf("expected 8997, got %d", got.Amount)
+	}
+}
@@ -0,0 +1,198 @@
+// Command catalog imports and exports the product catalog as CSV or JSON Lines.
+//
+//	catalog import [-format csv|jsonl] [-dry-run] [-no-cache] <file>
+//	catalog export [-format csv|jsonl] [-o <file>]
+//
+// Rows are matched by sku. Every change goes through the repository, so it gets its price history
+// and outbox event like an admin edit. It uses the same DB_DRIVER, DB_PATH or DATABASE_URL and MIGRATIONS_PATH as the service;
+// the products an import changes are also dropped from the service's product cache in Redis at REDIS_ADDR
+// (localhost:6379 by default). An import fails when Redis can't be reached, unless -no-cache is given.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/fjod/go_cart/product-service/internal/cache"
+	"github.com/fjod/go_cart/product-service/internal/catalog"
+	"github.com/fjod/go_cart/product-service/internal/domain"
+	repository "github.com/fjod/go_cart/product-service/internal/repository"
+	"github.com/redis/go-redis/v9"
+)
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: catalog import [-format csv|jsonl] [-dry-run] [-no-cache] <file>")
+	fmt.Fprintln(os.Stderr, "       catalog export [-format csv|jsonl] [-o <file>]")
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "import":
+		err = runImport(os.Args[2:])
+	case "export":
+		err = runExport(os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func openRepository() (*repository.Repository, error) {
+	driver, err := repository.ParseDriver(os.Getenv("DB_DRIVER"))
+	if err != nil {
+		return nil, err
+	}
+	dsn, migrationsPath := getEnv("DB_PATH", "./internal/repository/products.db"), getEnv("MIGRATIONS_PATH", "./internal/repository/migrations")
+	if driver == repository.DriverPostgres {
+		dsn, migrationsPath = os.Getenv("DATABASE_URL"), getEnv("MIGRATIONS_PATH", "./internal/repository/migrations_postgres")
+	}
+
+	repo, err := repository.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := repo.RunMigrations(migrationsPath); err != nil {
+		repo.Close()
+		return nil, err
+	}
+	return repo, nil
+}
+
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	formatName := fs.String("format", "csv", "file format: csv or jsonl")
+	dryRun := fs.Bool("dry-run", false, "validate and report without writing")
+	noCache := fs.Bool("no-cache", false, "write without invalidating the product cache in Redis")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		usage()
+	}
+	format, err := catalog.ParseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	rows, err := catalog.Read(f, format)
+	if err != nil {
+		return err
+	}
+
+	repo, err := openRepository()
+	if err != nil {
+		return err
+	}
+	defer repo.Close()
+
+	// the service caches products in the same Redis by default, so an import has to reach it
+	// or be told explicitly that stale cached products are fine
+	var store catalog.Store = repo
+	if !*noCache && !*dryRun {
+		addr := getEnv("REDIS_ADDR", "localhost:6379")
+		client := redis.NewClient(&redis.Options{Addr: addr, Password: getEnv("REDIS_PASSWORD", "")})
+		defer client.Close()
+		if err := client.Ping(context.Background()).Err(); err != nil {
+			return fmt.Errorf("connect to redis at %s to invalidate cached products (-no-cache skips it): %w", addr, err)
+		}
+		logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+		store = cachedStore{cache.NewCachedRepository(repo, cache.NewRedisCache(client), logger), repo}
+	}
+
+	report, err := catalog.Import(context.Background(), store, rows, *dryRun)
+	printReport(os.Stdout, report)
+	if err != nil {
+		return err
+	}
+	if failed := report.Count(catalog.OutcomeFailed); failed > 0 {
+		return fmt.Errorf("%d rows failed", failed)
+	}
+	return nil
+}
+
+// cachedStore writes through the cache so updated and archived products are invalidated
+type cachedStore struct {
+	*cache.CachedRepository
+	repo *repository.Repository
+}
+
+func (s cachedStore) GetProductBySKU(ctx context.Context, sku string) (*domain.Product, error) {
+	return s.repo.GetProductBySKU(ctx, sku)
+}
+
+func printReport(w io.Writer, report *catalog.Report) {
+	for _, res := range report.Results {
+		if res.Err != nil {
+			fmt.Fprintf(w, "line %d %s: %s: %v\n", res.Line, res.SKU, res.Outcome, res.Err)
+			continue
+		}
+		fmt.Fprintf(w, "line %d %s: %s\n", res.Line, res.SKU, res.Outcome)
+	}
+	prefix := ""
+	if report.DryRun {
+		prefix = "dry run, nothing written: "
+	}
+	fmt.Fprintf(w, "%s%d created, %d updated, %d archived, %d unchanged, %d failed\n", prefix,
+		report.Count(catalog.OutcomeCreated),
+		report.Count(catalog.OutcomeUpdated),
+		report.Count(catalog.OutcomeArchived),
+		report.Count(catalog.OutcomeUnchanged),
+		report.Count(catalog.OutcomeFailed))
+}
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	formatName := fs.String("format", "csv", "file format: csv or jsonl")
+	output := fs.String("o", "", "output file, stdout when empty")
+	fs.Parse(args)
+	format, err := catalog.ParseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+
+	repo, err := openRepository()
+	if err != nil {
+		return err
+	}
+	defer repo.Close()
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	n, err := catalog.Export(context.Background(), repo, w, format)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "exported %d products\n", n)
+	return nil
+}
@@ -5,12 +5,20 @@ import (
 	"log/slog"
 	"net"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/fjod/go_cart/pkg/logger"
 	"github.com/fjod/go_cart/pkg/tracing"
+	"github.com/fjod/go_cart/product-service/internal/cache"
 	grpcHandler "github.com/fjod/go_cart/product-service/internal/grpc"
+	"github.com/fjod/go_cart/product-service/internal/pricing"
+	"github.com/fjod/go_cart/product-service/internal/publisher"
 	repository "github.com/fjod/go_cart/product-service/internal/repository"
 	pb "github.com/fjod/go_cart/product-service/pkg/proto"
+	"github.com/redis/go-redis/v9"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
@@ -27,10 +35,17 @@ func main() {
 	log := logger.New("product-service", "info")
 	slog.SetDefault(log)
 
-	dbPath := getEnv("DB_PATH", "./internal/repository/products.db")
-	migrationsPath := getEnv("MIGRATIONS_PATH", "./internal/repository/migrations")
+	driver, err := repository.ParseDriver(os.Getenv("DB_DRIVER"))
+	if err != nil {
+		log.Error("invalid DB_DRIVER", "error", err)
+		os.Exit(1)
+	}
+	dsn, migrationsPath := getEnv("DB_PATH", "./internal/repository/products.db"), getEnv("MIGRATIONS_PATH", "./internal/repository/migrations")
+	if driver == repository.DriverPostgres {
+		dsn, migrationsPath = os.Getenv("DATABASE_URL"), getEnv("MIGRATIONS_PATH", "./internal/repository/migrations_postgres")
+	}
 
-	repo, err := repository.NewRepository(dbPath)
+	repo, err := repository.Open(driver, dsn)
 	if err != nil {
 		log.Error("failed to open repository", "error", err)
 		os.Exit(1)
@@ -41,7 +56,7 @@ func main() {
 		log.Error("failed to run migrations", "error", err)
 		os.Exit(1)
 	}
-	log.Info("migrations completed successfully")
+	log.Info("migrations completed successfully", "driver", driver)
 
 	shutdown, err := tracing.InitTracer("product-service", "localhost:4317")
 	if err != nil {
@@ -49,6 +64,50 @@ func main() {
 		os.Exit(1)
 	}
 	defer shutdown(context.Background())
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:     getEnv("REDIS_ADDR", "localhost:6379"),
+		Password: getEnv("REDIS_PASSWORD", ""),
+		DB:       0,
+	})
+	defer redisClient.Close()
+	if err := redisClient.Ping(context.Background()).Err(); err != nil {
+		log.Error("redis connection failed", "error", err)
+		os.Exit(1)
+	}
+	log.Info("redis ping succeeded")
+	// reads and every write that changes a product go through the cache, the outbox poller reads the repository directly
+	cachedRepo := cache.NewCachedRepository(repo, cache.NewRedisCache(redisClient), log)
+
+	priceTick, err := time.ParseDuration(getEnv("PRICE_SCHEDULER_TICK", "30s"))
+	if err != nil || priceTick <= 0 {
+		log.Error("invalid PRICE_SCHEDULER_TICK", "value", os.Getenv("PRICE_SCHEDULER_TICK"))
+		os.Exit(1)
+	}
+	backgroundCtx, backgroundCancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		pricing.NewScheduler(cachedRepo, priceTick, log).Run(backgroundCtx)
+	}()
+	log.Info("price scheduler started", "tick", priceTick)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		cachedRepo.LogStats(backgroundCtx, time.Minute)
+	}()
+
+	kafkaPort := getEnv("KAFKA_PORT", "localhost:9092")
+	poller := publisher.NewOutboxPoller(repo, log, kafkaPort)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		poller.Run(backgroundCtx)
+	}()
+	log.Info("outbox poller started", "topic", publisher.Topic)
+
 	grpcServer := grpc.NewServer(
 		grpc.StatsHandler(otelgrpc.NewServerHandler()),
 		grpc.ChainUnaryInterceptor(
@@ -56,7 +115,7 @@ func main() {
 		),
 	)
 
-	productService := grpcHandler.NewProductServiceServer(repo)
+	productService := grpcHandler.NewProductServiceServer(cachedRepo)
 	pb.RegisterProductServiceServer(grpcServer, productService)
 	reflection.Register(grpcServer)
 
@@ -67,9 +126,24 @@ func main() {
 		os.Exit(1)
 	}
 
-	log.Info("product service listening", "port", port)
-	if err := grpcServer.Serve(listener); err != nil {
-		log.Error("failed to serve gRPC", "error", err)
-		os.Exit(1)
+	go func() {
+		log.Info("product service listening", "port", port)
+		if err := grpcServer.Serve(listener); err != nil {
+			log.Error("failed to serve gRPC", "error", err)
+			os.Exit(1)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Info("shutting down product service")
+	grpcServer.GracefulStop()
+	backgroundCancel()
+	wg.Wait()
+	if err := poller.Close(); err != nil {
+		log.Error("failed to close kafka writer", "error", err)
 	}
+	log.Info("product service stopped")
 }
@@ -0,0 +1,18 @@
+package cache
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fjod/go_cart/product-service/internal/domain"
+)
+
+type ProductCache interface {
+	Get(ctx context.Context, id int64) (*domain.Product, error)
+	// GetMany returns the cached products by id, ids that are not cached are left out
+	GetMany(ctx context.Context, ids []int64) (map[int64]*domain.Product, error)
+	Set(ctx context.Context, products ...*domain.Product) error
+	Delete(ctx context.Context, ids ...int64) error
+}
+
+var ErrCacheMiss = errors.New("cache miss")
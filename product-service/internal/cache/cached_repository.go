@@ -0,0 +1,194 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/fjod/go_cart/pkg/logger"
+	"github.com/fjod/go_cart/product-service/internal/domain"
+	"github.com/fjod/go_cart/product-service/internal/repository"
+	"golang.org/x/sync/singleflight"
+)
+
+// Stats counts how product reads were served since the service started
+type Stats struct {
+	Hits   int64
+	Misses int64
+	Errors int64 // cache reads and writes that failed, the read was served from the repository
+}
+
+// CachedRepository serves GetProduct and GetProductsByIds read-through from the cache
+// and drops a product from it whenever an admin write or a scheduled price changes it.
+// Every other method goes straight to the repository.
+type CachedRepository struct {
+	repository.RepoInterface
+	cache  ProductCache
+	sfg    singleflight.Group // Prevents cache stampede
+	logger *slog.Logger
+
+	hits   atomic.Int64
+	misses atomic.Int64
+	errors atomic.Int64
+}
+
+func NewCachedRepository(repo repository.RepoInterface, cache ProductCache, logger *slog.Logger) *CachedRepository {
+	return &CachedRepository{
+		RepoInterface: repo,
+		cache:         cache,
+		logger:        logger,
+	}
+}
+
+func (c *CachedRepository) GetProduct(ctx context.Context, id int64) (*domain.Product, error) {
+	v, err, _ := c.sfg.Do(strconv.FormatInt(id, 10), func() (interface{}, error) {
+		l := logger.WithContext(c.logger, ctx)
+		p, err := c.cache.Get(ctx, id)
+		if err == nil {
+			c.hits.Add(1)
+			return p, nil
+		}
+		c.misses.Add(1)
+		if !errors.Is(err, ErrCacheMiss) {
+			c.errors.Add(1)
+			l.Warn("cache get error", "product_id", id, "error", err) // log cache error but continue
+		}
+
+		p, err = c.RepoInterface.GetProduct(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		c.store(ctx, p)
+		return p, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*domain.Product), nil
+}
+
+// GetProductsByIds returns the products ordered by id like the repository, only the ids missing from the cache are loaded
+func (c *CachedRepository) GetProductsByIds(ctx context.Context, ids []int64) ([]*domain.Product, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	unique := slices.Clone(ids)
+	slices.Sort(unique)
+	unique = slices.Compact(unique)
+
+	cached, err := c.cache.GetMany(ctx, unique)
+	if err != nil {
+		c.errors.Add(1)
+		logger.WithContext(c.logger, ctx).Warn("cache get error", "error", err) // log cache error but continue
+		cached = nil
+	}
+	var missing []int64
+	for _, id := range unique {
+		if _, ok := cached[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	c.hits.Add(int64(len(unique) - len(missing)))
+	c.misses.Add(int64(len(missing)))
+
+	products := make([]*domain.Product, 0, len(unique))
+	loaded := map[int64]*domain.Product{}
+	if len(missing) > 0 {
+		v, err, _ := c.sfg.Do(fmt.Sprint(missing), func() (interface{}, error) {
+			found, err := c.RepoInterface.GetProductsByIds(ctx, missing)
+			if err != nil {
+				return nil, err
+			}
+			c.store(ctx, found...)
+			return found, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range v.([]*domain.Product) {
+			loaded[p.ID] = p
+		}
+	}
+	for _, id := range unique {
+		if p, ok := cached[id]; ok {
+			products = append(products, p)
+		} else if p, ok := loaded[id]; ok {
+			products = append(products, p)
+		}
+	}
+	return products, nil
+}
+
+func (c *CachedRepository) UpdateProduct(ctx context.Context, p *domain.Product) error {
+	if err := c.RepoInterface.UpdateProduct(ctx, p); err != nil {
+		return err
+	}
+	c.invalidate(p.ID)
+	return nil
+}
+
+func (c *CachedRepository) ArchiveProduct(ctx context.Context, id int64) (*domain.Product, error) {
+	p, err := c.RepoInterface.ArchiveProduct(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	c.invalidate(id)
+	return p, nil
+}
+
+func (c *CachedRepository) ApplyScheduledPrices(ctx context.Context, now time.Time) ([]int64, error) {
+	changed, err := c.RepoInterface.ApplyScheduledPrices(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	c.invalidate(changed...)
+	return changed, nil
+}
+
+func (c *CachedRepository) Stats() Stats {
+	return Stats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+		Errors: c.errors.Load(),
+	}
+}
+
+// LogStats logs the counters every interval until ctx is cancelled
+func (c *CachedRepository) LogStats(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s := c.Stats()
+			c.logger.Info("product cache stats", "hits", s.Hits, "misses", s.Misses, "errors", s.Errors)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *CachedRepository) store(ctx context.Context, products ...*domain.Product) {
+	if err := c.cache.Set(ctx, products...); err != nil {
+		c.errors.Add(1)
+		logger.WithContext(c.logger, ctx).Error("cache set error", "error", err) // log cache error but continue
+	}
+}
+
+// invalidate runs after the write committed, on its own context so a cancelled request cannot leave a stale entry
+func (c *CachedRepository) invalidate(ids ...int64) {
+	if len(ids) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := c.cache.Delete(ctx, ids...); err != nil {
+		c.errors.Add(1)
+		c.logger.Error("cache invalidate error", "product_ids", ids, "error", err)
+	}
+}
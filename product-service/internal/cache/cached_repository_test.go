@@ -0,0 +1,174 @@
+package cache
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fjod/go_cart/product-service/internal/domain"
+	"github.com/fjod/go_cart/product-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// countingRepo serves products from memory and counts the reads that reach it
+type countingRepo struct {
+	repository.RepoInterface
+	products map[int64]*domain.Product
+	reads    atomic.Int64
+	changed  []int64 // returned by ApplyScheduledPrices
+	delay    time.Duration
+}
+
+func (r *countingRepo) GetProduct(_ context.Context, id int64) (*domain.Product, error) {
+	r.reads.Add(1)
+	time.Sleep(r.delay)
+	p, ok := r.products[id]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "product not found")
+	}
+	copied := *p
+	return &copied, nil
+}
+
+func (r *countingRepo) GetProductsByIds(_ context.Context, ids []int64) ([]*domain.Product, error) {
+	r.reads.Add(1)
+	var found []*domain.Product
+	for _, id := range ids {
+		if p, ok := r.products[id]; ok {
+			copied := *p
+			found = append(found, &copied)
+		}
+	}
+	return found, nil
+}
+
+func (r *countingRepo) UpdateProduct(_ context.Context, p *domain.Product) error {
+	r.products[p.ID] = p
+	return nil
+}
+
+func (r *countingRepo) ArchiveProduct(_ context.Context, id int64) (*domain.Product, error) {
+	now := time.Now()
+	r.products[id].ArchivedAt = &now
+	return r.products[id], nil
+}
+
+func (r *countingRepo) ApplyScheduledPrices(context.Context, time.Time) ([]int64, error) {
+	return r.changed, nil
+}
+
+func setupCachedRepo(t *testing.T) (*CachedRepository, *countingRepo) {
+	redisCache, _ := setupTestRedis(t)
+	repo := &countingRepo{products: map[int64]*domain.Product{1: testProduct(1), 2: testProduct(2), 3: testProduct(3)}}
+	return NewCachedRepository(repo, redisCache, slog.New(slog.NewTextHandler(io.Discard, nil))), repo
+}
+
+func TestCachedGetProduct_ReadThrough(t *testing.T) {
+	cached, repo := setupCachedRepo(t)
+	ctx := context.Background()
+
+	first, err := cached.GetProduct(ctx, 1)
+	require.NoError(t, err)
+	second, err := cached.GetProduct(ctx, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, int64(1), repo.reads.Load())
+	assert.Equal(t, Stats{Hits: 1, Misses: 1}, cached.Stats())
+}
+
+func TestCachedGetProduct_NotFoundIsNotCached(t *testing.T) {
+	cached, repo := setupCachedRepo(t)
+	ctx := context.Background()
+
+	for range 2 {
+		_, err := cached.GetProduct(ctx, 42)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	}
+	assert.Equal(t, int64(2), repo.reads.Load())
+}
+
+func TestCachedGetProduct_SingleflightOnMiss(t *testing.T) {
+	cached, repo := setupCachedRepo(t)
+	repo.delay = 50 * time.Millisecond
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := cached.GetProduct(context.Background(), 1)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(1), repo.reads.Load())
+}
+
+func TestCachedGetProductsByIds_LoadsOnlyMissing(t *testing.T) {
+	cached, repo := setupCachedRepo(t)
+	ctx := context.Background()
+	_, err := cached.GetProduct(ctx, 2)
+	require.NoError(t, err)
+
+	products, err := cached.GetProductsByIds(ctx, []int64{3, 2, 1, 3, 99})
+
+	require.NoError(t, err)
+	require.Len(t, products, 3)
+	assert.Equal(t, []int64{1, 2, 3}, []int64{products[0].ID, products[1].ID, products[2].ID})
+	assert.Equal(t, int64(2), repo.reads.Load()) // GetProduct(2) and one batch for 1, 3, 99
+	assert.Equal(t, Stats{Hits: 1, Misses: 4}, cached.Stats())
+
+	_, err = cached.GetProductsByIds(ctx, []int64{1, 2, 3})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), repo.reads.Load())
+}
+
+func TestCachedRepository_WritesInvalidate(t *testing.T) {
+	cached, repo := setupCachedRepo(t)
+	ctx := context.Background()
+	for _, id := range []int64{1, 2, 3} {
+		_, err := cached.GetProduct(ctx, id)
+		require.NoError(t, err)
+	}
+
+	updated := testProduct(1)
+	updated.Name = "Laptop Pro"
+	require.NoError(t, cached.UpdateProduct(ctx, updated))
+	_, err := cached.ArchiveProduct(ctx, 2)
+	require.NoError(t, err)
+	repo.changed = []int64{3}
+	_, err = cached.ApplyScheduledPrices(ctx, time.Now())
+	require.NoError(t, err)
+
+	p, err := cached.GetProduct(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Laptop Pro", p.Name)
+	archived, err := cached.GetProduct(ctx, 2)
+	require.NoError(t, err)
+	assert.True(t, archived.IsArchived())
+	_, err = cached.GetProduct(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), repo.reads.Load())
+}
+
+func TestCachedGetProduct_RedisDownFallsBackToRepository(t *testing.T) {
+	redisCache, mr := setupTestRedis(t)
+	repo := &countingRepo{products: map[int64]*domain.Product{1: testProduct(1)}}
+	cached := NewCachedRepository(repo, redisCache, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	mr.Close()
+
+	p, err := cached.GetProduct(context.Background(), 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), p.ID)
+	assert.Equal(t, int64(2), cached.Stats().Errors) // the get and the set
+}
@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/fjod/go_cart/product-service/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+func NewRedisCache(client *redis.Client) *RedisCache {
+	return &RedisCache{
+		client:  client,
+		baseTTL: 15 * time.Minute,
+	}
+}
+
+type RedisCache struct {
+	client  *redis.Client
+	baseTTL time.Duration
+}
+
+func (r RedisCache) Get(ctx context.Context, id int64) (*domain.Product, error) {
+	data, err := r.client.Get(ctx, cacheKey(id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrCacheMiss
+	}
+	if err != nil {
+		return nil, fmt.Errorf("redis get failed: %w", err)
+	}
+
+	var p domain.Product
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("unmarshal product failed: %w", err)
+	}
+	return &p, nil
+}
+
+func (r RedisCache) GetMany(ctx context.Context, ids []int64) (map[int64]*domain.Product, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = cacheKey(id)
+	}
+
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis mget failed: %w", err)
+	}
+	products := make(map[int64]*domain.Product, len(ids))
+	for i, v := range values {
+		data, ok := v.(string)
+		if !ok {
+			continue // nil for a key that is not cached
+		}
+		var p domain.Product
+		if err := json.Unmarshal([]byte(data), &p); err != nil {
+			continue // a broken entry is a miss, the next Set overwrites it
+		}
+		products[ids[i]] = &p
+	}
+	return products, nil
+}
+
+func (r RedisCache) Set(ctx context.Context, products ...*domain.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+	pipe := r.client.Pipeline()
+	for _, p := range products {
+		data, err := json.Marshal(p)
+		if err != nil {
+			return fmt.Errorf("marshal product failed: %w", err)
+		}
+		// jitter spreads the expiry of products cached together, e.g. by one GetProductsByIds
+		jitter := time.Duration(rand.Intn(5)) * time.Minute
+		pipe.Set(ctx, cacheKey(p.ID), data, r.baseTTL+jitter)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("redis set failed: %w", err)
+	}
+	return nil
+}
+
+func (r RedisCache) Delete(ctx context.Context, ids ...int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = cacheKey(id)
+	}
+	if err := r.client.Del(ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("redis delete failed: %w", err)
+	}
+	return nil
+}
+
+func cacheKey(id int64) string {
+	return fmt.Sprintf("product:%d", id)
+}
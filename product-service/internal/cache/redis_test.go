@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/fjod/go_cart/pkg/money"
+	"github.com/fjod/go_cart/product-service/internal/domain"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTestRedis creates a miniredis server and returns a RedisCache instance
+func setupTestRedis(t *testing.T) (*RedisCache, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{
+		Addr:       mr.Addr(),
+		MaxRetries: -1, // fail fast once a test closes the server
+	})
+	t.Cleanup(func() { client.Close() })
+	return NewRedisCache(client), mr
+}
+
+func testProduct(id int64) *domain.Product {
+	price := int64(159999)
+	return &domain.Product{
+		ID:        id,
+		SKU:       "PRD-1",
+		Name:      "Laptop",
+		Price:     money.New(129999, "USD"),
+		CreatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Variants: []*domain.Variant{
+			{ID: 2, ProductID: id, SKU: "LAPTOP-32GB-1TB", PriceMinor: &price, Attributes: map[string]string{"memory": "32GB"}},
+		},
+	}
+}
+
+func TestSetGet_RoundTrip(t *testing.T) {
+	cache, _ := setupTestRedis(t)
+	ctx := context.Background()
+
+	require.NoError(t, cache.Set(ctx, testProduct(1)))
+
+	result, err := cache.Get(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, testProduct(1), result)
+}
+
+func TestGet_CacheMiss(t *testing.T) {
+	cache, _ := setupTestRedis(t)
+
+	result, err := cache.Get(context.Background(), 42)
+
+	assert.ErrorIs(t, err, ErrCacheMiss)
+	assert.Nil(t, result)
+}
+
+func TestGet_InvalidJSON(t *testing.T) {
+	cache, mr := setupTestRedis(t)
+	require.NoError(t, mr.Set(cacheKey(1), `{"ID":`))
+
+	_, err := cache.Get(context.Background(), 1)
+
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrCacheMiss)
+}
+
+func TestSet_TTLWithJitter(t *testing.T) {
+	cache, mr := setupTestRedis(t)
+
+	require.NoError(t, cache.Set(context.Background(), testProduct(1)))
+
+	ttl := mr.TTL(cacheKey(1))
+	assert.GreaterOrEqual(t, ttl, 15*time.Minute)
+	assert.Less(t, ttl, 20*time.Minute)
+}
+
+func TestGetMany_SkipsMissingAndBroken(t *testing.T) {
+	cache, mr := setupTestRedis(t)
+	ctx := context.Background()
+	require.NoError(t, cache.Set(ctx, testProduct(1), testProduct(3)))
+	require.NoError(t, mr.Set(cacheKey(2), "not json"))
+
+	products, err := cache.GetMany(ctx, []int64{1, 2, 3, 4})
+
+	require.NoError(t, err)
+	assert.Len(t, products, 2)
+	assert.Equal(t, int64(3), products[3].ID)
+}
+
+func TestDelete(t *testing.T) {
+	cache, mr := setupTestRedis(t)
+	ctx := context.Background()
+	require.NoError(t, cache.Set(ctx, testProduct(1), testProduct(2)))
+
+	require.NoError(t, cache.Delete(ctx, 1, 2))
+
+	assert.False(t, mr.Exists(cacheKey(1)))
+	assert.False(t, mr.Exists(cacheKey(2)))
+}
+
+func TestRedisDown(t *testing.T) {
+	cache, mr := setupTestRedis(t)
+	mr.Close()
+
+	_, err := cache.Get(context.Background(), 1)
+
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrCacheMiss)
+}
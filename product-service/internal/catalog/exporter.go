@@ -0,0 +1,26 @@
+package catalog
+
+import (
+	"context"
+	"io"
+
+	"github.com/fjod/go_cart/product-service/internal/domain"
+)
+
+// ExportStore is the part of the repository an export reads from
+type ExportStore interface {
+	GetProductsForExport(ctx context.Context) ([]*domain.Product, error)
+}
+
+// Export writes the whole catalog, archived products included, in the format Import reads. It returns the number of products written.
+func Export(ctx context.Context, store ExportStore, w io.Writer, f Format) (int, error) {
+	products, err := store.GetProductsForExport(ctx)
+	if err != nil {
+		return 0, err
+	}
+	records := make([]Record, len(products))
+	for i, p := range products {
+		records[i] = FromProduct(p)
+	}
+	return len(records), Write(w, f, records)
+}
@@ -0,0 +1,227 @@
+package catalog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/fjod/go_cart/product-service/internal/domain"
+)
+
+type Format string
+
+const (
+	FormatCSV   Format = "csv"
+	FormatJSONL Format = "jsonl"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatCSV, FormatJSONL:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown format %q, use csv or jsonl", s)
+	}
+}
+
+// Record is one product in an import or export file. Prices are in minor units so a round trip is exact.
+type Record struct {
+	SKU         string `json:"sku"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	PriceMinor  int64  `json:"price_minor"`
+	Currency    string `json:"currency"`
+	ImageURL    string `json:"image_url"`
+	Archived    bool   `json:"archived"`
+}
+
+// Row is a record read from a file, Err is set when the line could not be parsed
+type Row struct {
+	Line   int
+	Record Record
+	Err    error
+}
+
+// csvHeader is the column order export writes, import accepts the columns in any order and archived may be left out
+var csvHeader = []string{"sku", "name", "description", "price_minor", "currency", "image_url", "archived"}
+
+// maxLineSize bounds a JSON Lines record, descriptions are at most 5000 characters
+const maxLineSize = 1 << 20
+
+func FromProduct(p *domain.Product) Record {
+	return Record{
+		SKU:         p.SKU,
+		Name:        p.Name,
+		Description: p.Description,
+		PriceMinor:  p.Price.Amount,
+		Currency:    p.Price.Currency,
+		ImageURL:    p.ImageURL,
+		Archived:    p.IsArchived(),
+	}
+}
+
+// Read parses every row of the file. A malformed row is returned with Err set, an unreadable file is an error.
+func Read(r io.Reader, f Format) ([]Row, error) {
+	if f == FormatJSONL {
+		return readJSONL(r)
+	}
+	return readCSV(r)
+}
+
+func Write(w io.Writer, f Format, records []Record) error {
+	if f == FormatJSONL {
+		return writeJSONL(w, records)
+	}
+	return writeCSV(w, records)
+}
+
+func readCSV(r io.Reader) ([]Row, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	columns, err := columnIndex(header)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []Row
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) || !errors.Is(parseErr.Err, csv.ErrFieldCount) {
+				return nil, fmt.Errorf("read csv: %w", err)
+			}
+			rows = append(rows, Row{Line: parseErr.StartLine, Err: fmt.Errorf("expected %d columns, got %d", len(header), len(fields))})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		record, err := recordFromCSV(fields, columns)
+		rows = append(rows, Row{Line: line, Record: record, Err: err})
+	}
+}
+
+func columnIndex(header []string) (map[string]int, error) {
+	known := make(map[string]bool, len(csvHeader))
+	for _, c := range csvHeader {
+		known[c] = true
+	}
+	columns := make(map[string]int, len(header))
+	for i, c := range header {
+		c = strings.TrimSpace(strings.TrimPrefix(c, "\ufeff")) // spreadsheets like to start with a BOM
+		if !known[c] {
+			return nil, fmt.Errorf("unknown column %q", c)
+		}
+		if _, dup := columns[c]; dup {
+			return nil, fmt.Errorf("column %q appears twice", c)
+		}
+		columns[c] = i
+	}
+	for _, c := range csvHeader {
+		if _, ok := columns[c]; !ok && c != "archived" {
+			return nil, fmt.Errorf("missing column %q", c)
+		}
+	}
+	return columns, nil
+}
+
+func recordFromCSV(fields []string, columns map[string]int) (Record, error) {
+	get := func(c string) string {
+		if i, ok := columns[c]; ok {
+			return fields[i]
+		}
+		return ""
+	}
+
+	record := Record{
+		SKU:         strings.TrimSpace(get("sku")),
+		Name:        get("name"),
+		Description: get("description"),
+		Currency:    strings.TrimSpace(get("currency")),
+		ImageURL:    strings.TrimSpace(get("image_url")),
+	}
+	price, err := strconv.ParseInt(strings.TrimSpace(get("price_minor")), 10, 64)
+	if err != nil {
+		return record, errors.New("price_minor must be an integer amount in minor units")
+	}
+	record.PriceMinor = price
+	if archived := strings.TrimSpace(get("archived")); archived != "" {
+		if record.Archived, err = strconv.ParseBool(archived); err != nil {
+			return record, errors.New("archived must be true or false")
+		}
+	}
+	return record, nil
+}
+
+func writeCSV(w io.Writer, records []Record) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, r := range records {
+		err := writer.Write([]string{
+			r.SKU,
+			r.Name,
+			r.Description,
+			strconv.FormatInt(r.PriceMinor, 10),
+			r.Currency,
+			r.ImageURL,
+			strconv.FormatBool(r.Archived),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func readJSONL(r io.Reader) ([]Row, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	var rows []Row
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		var record Record
+		err := decoder.Decode(&record)
+		if err != nil {
+			err = fmt.Errorf("invalid JSON: %w", err)
+		}
+		record.SKU = strings.TrimSpace(record.SKU)
+		rows = append(rows, Row{Line: line, Record: record, Err: err})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read jsonl: %w", err)
+	}
+	return rows, nil
+}
+
+func writeJSONL(w io.Writer, records []Record) error {
+	encoder := json.NewEncoder(w)
+	for _, r := range records {
+		if err := encoder.Encode(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package catalog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var sample = []Record{
+	{SKU: "LAMP-1", Name: "Desk Lamp", Description: "LED, \"warm\" white\nwith dimmer", PriceMinor: 2599, Currency: "EUR", ImageURL: "https://example.com/lamp.jpg"},
+	{SKU: "OLD-1", Name: "Old Lamp", PriceMinor: 100, Currency: "USD", Archived: true},
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, f := range []Format{FormatCSV, FormatJSONL} {
+		t.Run(string(f), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, Write(&buf, f, sample))
+
+			rows, err := Read(&buf, f)
+
+			require.NoError(t, err)
+			require.Len(t, rows, 2)
+			for i, row := range rows {
+				assert.NoError(t, row.Err)
+				assert.Equal(t, sample[i], row.Record)
+			}
+		})
+	}
+}
+
+func TestReadCSV_ColumnsInAnyOrder(t *testing.T) {
+	input := "price_minor,sku,name,description,currency,image_url\n999,MUG-1,Mug,,USD,\n"
+
+	rows, err := Read(strings.NewReader(input), FormatCSV)
+
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, 2, rows[0].Line)
+	assert.Equal(t, Record{SKU: "MUG-1", Name: "Mug", PriceMinor: 999, Currency: "USD"}, rows[0].Record)
+}
+
+func TestReadCSV_RowErrors(t *testing.T) {
+	input := strings.Join([]string{
+		"sku,name,description,price_minor,currency,image_url,archived",
+		"A-1,Lamp,,12.50,USD,,false",
+		"A-2,Lamp,,100,USD",
+		"A-3,Lamp,,100,USD,,maybe",
+		"A-4,Lamp,,100,USD,,",
+	}, "\n")
+
+	rows, err := Read(strings.NewReader(input), FormatCSV)
+
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+	assert.ErrorContains(t, rows[0].Err, "price_minor")
+	assert.ErrorContains(t, rows[1].Err, "columns")
+	assert.Equal(t, 3, rows[1].Line)
+	assert.ErrorContains(t, rows[2].Err, "archived")
+	assert.NoError(t, rows[3].Err)
+}
+
+func TestReadCSV_BadHeader(t *testing.T) {
+	_, err := Read(strings.NewReader("sku,name,colour\n"), FormatCSV)
+	assert.ErrorContains(t, err, "colour")
+
+	_, err = Read(strings.NewReader("sku,name\n"), FormatCSV)
+	assert.ErrorContains(t, err, "missing column")
+}
+
+func TestReadJSONL_RowErrors(t *testing.T) {
+	input := `{"sku":"A-1","name":"Lamp","price_minor":100}
+
+{"sku":"A-2","name":"Lamp","price":1.00}
+not json
+`
+
+	rows, err := Read(strings.NewReader(input), FormatJSONL)
+
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.NoError(t, rows[0].Err)
+	assert.Equal(t, 3, rows[1].Line)
+	assert.ErrorContains(t, rows[1].Err, "price")
+	assert.Error(t, rows[2].Err)
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := ParseFormat("JSONL")
+	require.NoError(t, err)
+	assert.Equal(t, FormatJSONL, f)
+
+	_, err = ParseFormat("xml")
+	assert.Error(t, err)
+}
@@ -0,0 +1,185 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fjod/go_cart/pkg/money"
+	"github.com/fjod/go_cart/product-service/internal/domain"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const maxSKULength = 64
+
+// Store is the part of the repository an import writes through, so every change gets its outbox event and price history
+type Store interface {
+	GetProductBySKU(ctx context.Context, sku string) (*domain.Product, error)
+	CreateProduct(ctx context.Context, p *domain.Product) error
+	UpdateProduct(ctx context.Context, p *domain.Product) error
+	ArchiveProduct(ctx context.Context, id int64) (*domain.Product, error)
+}
+
+type Outcome string
+
+const (
+	OutcomeCreated   Outcome = "created"
+	OutcomeUpdated   Outcome = "updated"
+	OutcomeArchived  Outcome = "archived"
+	OutcomeUnchanged Outcome = "unchanged"
+	OutcomeFailed    Outcome = "failed"
+)
+
+// Result is what happened to one row, Err explains a failed row
+type Result struct {
+	Line    int
+	SKU     string
+	Outcome Outcome
+	Err     error
+}
+
+type Report struct {
+	DryRun  bool
+	Results []Result
+}
+
+func (r *Report) Count(o Outcome) int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Outcome == o {
+			n++
+		}
+	}
+	return n
+}
+
+// Import upserts every row by SKU. A row that is invalid or conflicts with the catalog fails on its own
+// and the rest of the file is still imported; a storage error stops the import.
+// A dry run reports what would happen without writing anything.
+func Import(ctx context.Context, store Store, rows []Row, dryRun bool) (*Report, error) {
+	report := &Report{DryRun: dryRun}
+	seen := make(map[string]int, len(rows))
+
+	for _, row := range rows {
+		outcome, err := importRow(ctx, store, row, seen, dryRun)
+		if err != nil {
+			if _, ok := status.FromError(err); !ok {
+				return report, fmt.Errorf("line %d: %w", row.Line, err)
+			}
+			outcome = OutcomeFailed
+			err = rowError(err)
+		}
+		report.Results = append(report.Results, Result{Line: row.Line, SKU: row.Record.SKU, Outcome: outcome, Err: err})
+	}
+	return report, nil
+}
+
+func importRow(ctx context.Context, store Store, row Row, seen map[string]int, dryRun bool) (Outcome, error) {
+	if row.Err != nil {
+		return "", status.Error(codes.InvalidArgument, row.Err.Error())
+	}
+	incoming, err := toProduct(row.Record)
+	if err != nil {
+		return "", status.Error(codes.InvalidArgument, err.Error())
+	}
+	if line, dup := seen[incoming.SKU]; dup {
+		return "", status.Errorf(codes.InvalidArgument, "sku %s already appears on line %d", incoming.SKU, line)
+	}
+	seen[incoming.SKU] = row.Line
+
+	current, err := store.GetProductBySKU(ctx, incoming.SKU)
+	if status.Code(err) == codes.NotFound {
+		return create(ctx, store, incoming, row.Record.Archived, dryRun)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	changed := !sameFields(current, incoming)
+	if current.IsArchived() {
+		if !row.Record.Archived {
+			return "", status.Error(codes.FailedPrecondition, "product is archived, an import cannot restore it")
+		}
+		if changed {
+			return "", status.Error(codes.FailedPrecondition, "product is archived")
+		}
+		return OutcomeUnchanged, nil
+	}
+
+	outcome := OutcomeUnchanged
+	if changed {
+		outcome = OutcomeUpdated
+		incoming.ID = current.ID
+		if !dryRun {
+			if err := store.UpdateProduct(ctx, incoming); err != nil {
+				return "", err
+			}
+		}
+	}
+	if row.Record.Archived {
+		outcome = OutcomeArchived
+		if !dryRun {
+			if _, err := store.ArchiveProduct(ctx, current.ID); err != nil {
+				return "", err
+			}
+		}
+	}
+	return outcome, nil
+}
+
+// create adds a product the catalog does not have yet, an archived row is created and archived right away
+func create(ctx context.Context, store Store, p *domain.Product, archived, dryRun bool) (Outcome, error) {
+	if dryRun {
+		return OutcomeCreated, nil
+	}
+	if err := store.CreateProduct(ctx, p); err != nil {
+		return "", err
+	}
+	if archived {
+		if _, err := store.ArchiveProduct(ctx, p.ID); err != nil {
+			return "", err
+		}
+	}
+	return OutcomeCreated, nil
+}
+
+func toProduct(r Record) (*domain.Product, error) {
+	if r.SKU == "" {
+		return nil, errors.New("sku is required")
+	}
+	if len(r.SKU) > maxSKULength || strings.ContainsAny(r.SKU, " \t\r\n") {
+		return nil, fmt.Errorf("sku must be at most %d characters without spaces", maxSKULength)
+	}
+	currency := r.Currency
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+	p := &domain.Product{
+		SKU:         r.SKU,
+		Name:        r.Name,
+		Description: r.Description,
+		Price:       money.New(r.PriceMinor, currency),
+		ImageURL:    r.ImageURL,
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func sameFields(a, b *domain.Product) bool {
+	return a.Name == b.Name &&
+		a.Description == b.Description &&
+		a.Price == b.Price &&
+		a.ImageURL == b.ImageURL
+}
+
+// rowError reduces a status error to its message for the report
+func rowError(err error) error {
+	if st, ok := status.FromError(err); ok {
+		return errors.New(st.Message())
+	}
+	return err
+}
@@ -0,0 +1,157 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/fjod/go_cart/pkg/money"
+	"github.com/fjod/go_cart/product-service/internal/domain"
+	"github.com/fjod/go_cart/product-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRepo(t *testing.T) *repository.Repository {
+	repo, err := repository.NewRepository(":memory:")
+	require.NoError(t, err)
+	require.NoError(t, repo.RunMigrations("../repository/migrations"))
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+func rowsOf(records ...Record) []Row {
+	rows := make([]Row, len(records))
+	for i, r := range records {
+		rows[i] = Row{Line: i + 2, Record: r}
+	}
+	return rows
+}
+
+func TestImport_UpsertsBySKU(t *testing.T) {
+	repo := setupRepo(t)
+	ctx := context.Background()
+
+	laptop, err := repo.GetProductBySKU(ctx, "PRD-1")
+	require.NoError(t, err)
+	edited := FromProduct(laptop)
+	edited.PriceMinor = 119999
+	unchanged, err := repo.GetProductBySKU(ctx, "PRD-2")
+	require.NoError(t, err)
+
+	report, err := Import(ctx, repo, rowsOf(
+		edited,
+		FromProduct(unchanged),
+		Record{SKU: "LAMP-1", Name: "Desk Lamp", PriceMinor: 2599, Currency: "EUR"},
+	), false)
+
+	require.NoError(t, err)
+	assert.Equal(t, []Outcome{OutcomeUpdated, OutcomeUnchanged, OutcomeCreated}, outcomes(report))
+
+	stored, err := repo.GetProduct(ctx, laptop.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(119999), stored.Price.Amount)
+	lamp, err := repo.GetProductBySKU(ctx, "LAMP-1")
+	require.NoError(t, err)
+	assert.Equal(t, money.New(2599, "EUR"), lamp.Price)
+}
+
+func TestImport_DryRunWritesNothing(t *testing.T) {
+	repo := setupRepo(t)
+	ctx := context.Background()
+
+	report, err := Import(ctx, repo, rowsOf(
+		Record{SKU: "PRD-1", Name: "Laptop Pro", PriceMinor: 1},
+		Record{SKU: "LAMP-1", Name: "Desk Lamp", PriceMinor: 2599},
+		Record{SKU: "PRD-2", Name: "Mouse", PriceMinor: 1, Archived: true},
+	), true)
+
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, []Outcome{OutcomeUpdated, OutcomeCreated, OutcomeArchived}, outcomes(report))
+
+	laptop, err := repo.GetProductBySKU(ctx, "PRD-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Laptop", laptop.Name)
+	_, err = repo.GetProductBySKU(ctx, "LAMP-1")
+	assert.Error(t, err)
+	mouse, err := repo.GetProductBySKU(ctx, "PRD-2")
+	require.NoError(t, err)
+	assert.False(t, mouse.IsArchived())
+}
+
+func TestImport_ReportsRowErrorsAndContinues(t *testing.T) {
+	repo := setupRepo(t)
+	ctx := context.Background()
+
+	rows := rowsOf(
+		Record{SKU: "", Name: "No SKU", PriceMinor: 1},
+		Record{SKU: "NEG-1", Name: "Negative", PriceMinor: -5},
+		Record{SKU: "LAMP-1", Name: "Desk Lamp", PriceMinor: 2599},
+		Record{SKU: "LAMP-1", Name: "Desk Lamp again", PriceMinor: 2599},
+	)
+	rows = append(rows, Row{Line: 6, Err: assert.AnError})
+
+	report, err := Import(ctx, repo, rows, false)
+
+	require.NoError(t, err)
+	assert.Equal(t, []Outcome{OutcomeFailed, OutcomeFailed, OutcomeCreated, OutcomeFailed, OutcomeFailed}, outcomes(report))
+	assert.ErrorContains(t, report.Results[0].Err, "sku is required")
+	assert.ErrorContains(t, report.Results[1].Err, "price_minor must not be negative")
+	assert.ErrorContains(t, report.Results[3].Err, "already appears on line 4")
+	assert.Equal(t, 1, report.Count(OutcomeCreated))
+}
+
+func TestImport_ArchivedProducts(t *testing.T) {
+	repo := setupRepo(t)
+	ctx := context.Background()
+
+	mouse, err := repo.GetProductBySKU(ctx, "PRD-2")
+	require.NoError(t, err)
+	archived := FromProduct(mouse)
+	archived.Archived = true
+
+	report, err := Import(ctx, repo, rowsOf(archived), false)
+	require.NoError(t, err)
+	assert.Equal(t, []Outcome{OutcomeArchived}, outcomes(report))
+
+	// the exported archived row imports as unchanged, it cannot be restored or edited
+	restored := archived
+	restored.Archived = false
+	edited := archived
+	edited.Name = "Renamed"
+	report, err = Import(ctx, repo, []Row{{Line: 2, Record: archived}}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []Outcome{OutcomeUnchanged}, outcomes(report))
+	report, err = Import(ctx, repo, []Row{{Line: 2, Record: restored}, {Line: 3, Record: edited}}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []Outcome{OutcomeFailed, OutcomeFailed}, outcomes(report))
+}
+
+func TestExportImport_RoundTrip(t *testing.T) {
+	source := setupRepo(t)
+	ctx := context.Background()
+	_, err := source.ArchiveProduct(ctx, 3)
+	require.NoError(t, err)
+	require.NoError(t, source.CreateProduct(ctx, &domain.Product{SKU: "LAMP-1", Name: "Desk Lamp", Price: money.New(2599, "EUR")}))
+
+	var buf bytes.Buffer
+	n, err := Export(ctx, source, &buf, FormatJSONL)
+	require.NoError(t, err)
+	assert.Equal(t, 6, n)
+
+	// re-importing an unedited export changes nothing
+	rows, err := Read(bytes.NewReader(buf.Bytes()), FormatJSONL)
+	require.NoError(t, err)
+	report, err := Import(ctx, source, rows, false)
+	require.NoError(t, err)
+	assert.Equal(t, len(rows), report.Count(OutcomeUnchanged))
+}
+
+func outcomes(r *Report) []Outcome {
+	out := make([]Outcome, len(r.Results))
+	for i, res := range r.Results {
+		out[i] = res.Outcome
+	}
+	return out
+}
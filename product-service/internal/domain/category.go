@@ -0,0 +1,12 @@
+package domain
+
+// Category is a node of the catalog taxonomy
+type Category struct {
+	ID       int64
+	ParentID *int64 // nil for a top level category
+	Name     string
+	Slug     string
+}
+
+// Breadcrumb is the path from a top level category down to one category a product is listed in
+type Breadcrumb []*Category
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/fjod/go_cart/pkg/money"
+)
+
+// PricePoint is one entry of a product's price history: the price it sold at from ValidFrom until ValidTo
+type PricePoint struct {
+	ProductID int64
+	Price     money.Money
+	ValidFrom time.Time
+	ValidTo   *time.Time // nil while this is the current price
+}
+
+// ScheduledPrice is a price the product switches to at StartsAt, e.g. a sale.
+// When EndsAt is set the price in effect before StartsAt comes back at EndsAt.
+type ScheduledPrice struct {
+	ID         int64
+	ProductID  int64
+	Price      money.Money
+	StartsAt   time.Time
+	EndsAt     *time.Time
+	AppliedAt  *time.Time
+	RevertedAt *time.Time
+	CreatedAt  time.Time
+}
@@ -1,12 +1,23 @@
 package domain
 
-import "time"
+import (
+	"time"
+
+	"github.com/fjod/go_cart/pkg/money"
+)
 
 type Product struct {
 	ID          int64
+	SKU         string // external id the catalog import and export match products by
 	Name        string
 	Description string
-	Price       float64
+	Price       money.Money
 	ImageURL    string
 	CreatedAt   time.Time
+	ArchivedAt  *time.Time // nil while the product is on sale
+	Variants    []*Variant // empty when the product is sold by its id alone
+}
+
+func (p *Product) IsArchived() bool {
+	return p.ArchivedAt != nil
 }
@@ -0,0 +1,73 @@
+package domain
+
+import "time"
+
+// ProductChangedEventType is the outbox event type written before each change got its own type,
+// such rows are published under the type of their payload's change
+const ProductChangedEventType = "ProductChanged"
+
+// Event types published to the product-events topic
+const (
+	ProductCreatedEventType      = "ProductCreated"
+	ProductUpdatedEventType      = "ProductUpdated"
+	ProductPriceChangedEventType = "ProductPriceChanged"
+	ProductArchivedEventType     = "ProductArchived"
+)
+
+type ProductChange string
+
+const (
+	ProductChangeCreated      ProductChange = "CREATED"
+	ProductChangeUpdated      ProductChange = "UPDATED" // fields other than the price
+	ProductChangePriceChanged ProductChange = "PRICE_CHANGED"
+	ProductChangeArchived     ProductChange = "ARCHIVED"
+)
+
+// EventType is the outbox and Kafka event type of the change
+func (c ProductChange) EventType() string {
+	switch c {
+	case ProductChangeCreated:
+		return ProductCreatedEventType
+	case ProductChangePriceChanged:
+		return ProductPriceChangedEventType
+	case ProductChangeArchived:
+		return ProductArchivedEventType
+	default:
+		return ProductUpdatedEventType
+	}
+}
+
+// ProductChangedEvent is the outbox payload written with every catalog change, it carries the product state after the change
+type ProductChangedEvent struct {
+	ProductID          int64         `json:"product_id"`
+	Change             ProductChange `json:"change"`
+	Name               string        `json:"name"`
+	Description        string        `json:"description"`
+	PriceMinor         int64         `json:"price_minor"`
+	PreviousPriceMinor *int64        `json:"previous_price_minor,omitempty"` // set on PRICE_CHANGED
+	Currency           string        `json:"currency"`
+	ImageURL           string        `json:"image_url"`
+	ArchivedAt         *time.Time    `json:"archived_at,omitempty"`
+	ChangedAt          time.Time     `json:"changed_at"`
+}
+
+func NewProductChangedEvent(p *Product, change ProductChange, changedAt time.Time) ProductChangedEvent {
+	return ProductChangedEvent{
+		ProductID:   p.ID,
+		Change:      change,
+		Name:        p.Name,
+		Description: p.Description,
+		PriceMinor:  p.Price.Amount,
+		Currency:    p.Price.Currency,
+		ImageURL:    p.ImageURL,
+		ArchivedAt:  p.ArchivedAt,
+		ChangedAt:   changedAt,
+	}
+}
+
+// NewPriceChangedEvent is a PRICE_CHANGED event for p, which already holds the new price
+func NewPriceChangedEvent(p *Product, previousMinor int64, changedAt time.Time) ProductChangedEvent {
+	e := NewProductChangedEvent(p, ProductChangePriceChanged, changedAt)
+	e.PreviousPriceMinor = &previousMinor
+	return e
+}
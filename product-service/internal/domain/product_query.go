@@ -0,0 +1,29 @@
+package domain
+
+type ProductSort int
+
+const (
+	ProductSortID ProductSort = iota // catalog order, the default
+	ProductSortPrice
+	ProductSortName
+	ProductSortCreatedAt
+)
+
+// ProductCursor points right after the last product of a page: its sort key as text and its id as tie breaker
+type ProductCursor struct {
+	Key string
+	ID  int64
+}
+
+// ProductQuery is one page of the catalog listing
+type ProductQuery struct {
+	PageSize      int
+	After         *ProductCursor // nil for the first page
+	SortBy        ProductSort
+	Descending    bool
+	MinPriceMinor *int64
+	MaxPriceMinor *int64
+	CategoryID    int64 // 0 lists every category
+	// IncludeDescendants also lists products of every category below CategoryID
+	IncludeDescendants bool
+}
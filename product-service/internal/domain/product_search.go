@@ -0,0 +1,9 @@
+package domain
+
+// ProductSearchHit is a product matched by full-text search
+type ProductSearchHit struct {
+	Product       *Product
+	NameHighlight string  // name with the matched terms wrapped in <mark></mark>
+	Snippet       string  // best matching fragment of the name or description, highlighted the same way
+	Score         float64 // relevance, higher is better
+}
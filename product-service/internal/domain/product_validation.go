@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"unicode/utf8"
+)
+
+const (
+	MaxNameLength        = 200
+	MaxDescriptionLength = 5000
+)
+
+// Validate checks the fields an admin or an import can set, the currency must already be defaulted
+func (p *Product) Validate() error {
+	if p.Name == "" {
+		return errors.New("name is required")
+	}
+	if utf8.RuneCountInString(p.Name) > MaxNameLength {
+		return fmt.Errorf("name must be at most %d characters", MaxNameLength)
+	}
+	if utf8.RuneCountInString(p.Description) > MaxDescriptionLength {
+		return fmt.Errorf("description must be at most %d characters", MaxDescriptionLength)
+	}
+	if p.Price.IsNegative() {
+		return errors.New("price_minor must not be negative")
+	}
+	if !isCurrencyCode(p.Price.Currency) {
+		return errors.New("currency must be a 3 letter ISO 4217 code")
+	}
+	if p.ImageURL != "" {
+		u, err := url.Parse(p.ImageURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("image_url must be an absolute http(s) URL")
+		}
+	}
+	return nil
+}
+
+func isCurrencyCode(c string) bool {
+	if len(c) != 3 {
+		return false
+	}
+	for _, r := range c {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
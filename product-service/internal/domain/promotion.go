@@ -0,0 +1,209 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fjod/go_cart/pkg/money"
+)
+
+// PromotionKind is how a promo code takes money off a cart
+type PromotionKind string
+
+const (
+	PromotionPercentage PromotionKind = "percentage" // Value percent off every eligible line
+	PromotionFixed      PromotionKind = "fixed"      // Value minor units off the eligible lines together
+)
+
+const (
+	MinPromoCodeLength = 3
+	MaxPromoCodeLength = 32
+)
+
+// ErrPromotionNotApplicable is wrapped by every reason a promo code can't be used on a cart
+var ErrPromotionNotApplicable = errors.New("promo code cannot be applied")
+
+// Promotion is a promo code shoppers enter on their cart. Without product and category rules
+// it covers the whole cart, otherwise only the lines of the listed products and of the products
+// listed in the categories or their subcategories.
+type Promotion struct {
+	ID            int64
+	Code          string // upper case, see NormalizePromoCode
+	Kind          PromotionKind
+	Value         int64  // percent (1-100) for percentage codes, minor units for fixed ones
+	Currency      string // of a fixed Value and of MinSpendMinor, empty when neither is set
+	MinSpendMinor int64  // the cart subtotal must reach it, 0 for no minimum
+	ProductIDs    []int64
+	CategoryIDs   []int64
+	UsageLimit    *int64 // nil for unlimited
+	TimesUsed     int64  // completed checkouts that used the code
+	StartsAt      *time.Time
+	ExpiresAt     *time.Time
+	CreatedAt     time.Time
+}
+
+// PromotionLine is one cart line the promotion is evaluated against
+type PromotionLine struct {
+	ProductID int64
+	VariantID int64
+	Quantity  int64
+	UnitPrice money.Money
+	Eligible  bool // covered by the product and category rules of the promotion
+}
+
+// PromotionResult is what a promotion takes off a cart
+type PromotionResult struct {
+	Subtotal      money.Money
+	Discount      money.Money
+	LineDiscounts []money.Money // one per line, in the order of the lines
+}
+
+// Total is what the cart costs after the discount
+func (r *PromotionResult) Total() money.Money {
+	return money.New(r.Subtotal.Amount-r.Discount.Amount, r.Subtotal.Currency)
+}
+
+// NormalizePromoCode is how codes are stored and looked up, they are case-insensitive
+func NormalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// HasRules reports whether the promotion is limited to some products or categories
+func (p *Promotion) HasRules() bool {
+	return len(p.ProductIDs) > 0 || len(p.CategoryIDs) > 0
+}
+
+// Validate checks the fields an admin sets, the code must already be normalized
+func (p *Promotion) Validate() error {
+	if len(p.Code) < MinPromoCodeLength || len(p.Code) > MaxPromoCodeLength {
+		return fmt.Errorf("code must be %d to %d characters", MinPromoCodeLength, MaxPromoCodeLength)
+	}
+	for _, r := range p.Code {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return errors.New("code may only contain letters, digits, - and _")
+		}
+	}
+	switch p.Kind {
+	case PromotionPercentage:
+		if p.Value < 1 || p.Value > 100 {
+			return errors.New("value of a percentage code must be between 1 and 100")
+		}
+	case PromotionFixed:
+		if p.Value <= 0 {
+			return errors.New("value of a fixed code must be positive")
+		}
+		if p.Currency == "" {
+			return errors.New("currency is required for a fixed code")
+		}
+	default:
+		return errors.New("kind must be percentage or fixed")
+	}
+	if p.MinSpendMinor < 0 {
+		return errors.New("min_spend_minor must not be negative")
+	}
+	if p.MinSpendMinor > 0 && p.Currency == "" {
+		return errors.New("currency is required with min_spend_minor")
+	}
+	if p.Currency != "" && !isCurrencyCode(p.Currency) {
+		return errors.New("currency must be a 3 letter ISO 4217 code")
+	}
+	if p.UsageLimit != nil && *p.UsageLimit <= 0 {
+		return errors.New("usage_limit must be positive")
+	}
+	if p.StartsAt != nil && p.ExpiresAt != nil && !p.ExpiresAt.After(*p.StartsAt) {
+		return errors.New("expires_at must be after starts_at")
+	}
+	return nil
+}
+
+// CheckActive reports why the code can't be used at the given time, nil when it can
+func (p *Promotion) CheckActive(now time.Time) error {
+	if p.StartsAt != nil && now.Before(*p.StartsAt) {
+		return fmt.Errorf("%w: it is not valid yet", ErrPromotionNotApplicable)
+	}
+	if p.ExpiresAt != nil && !now.Before(*p.ExpiresAt) {
+		return fmt.Errorf("%w: it has expired", ErrPromotionNotApplicable)
+	}
+	if p.UsageLimit != nil && p.TimesUsed >= *p.UsageLimit {
+		return fmt.Errorf("%w: it has reached its usage limit", ErrPromotionNotApplicable)
+	}
+	return nil
+}
+
+// Apply works out the discount of the promotion on the cart lines. Percentage discounts are
+// rounded down on every line, a fixed discount never exceeds the eligible lines and is spread
+// over them in proportion to their subtotals, the last eligible line takes the rounding remainder.
+func (p *Promotion) Apply(lines []PromotionLine, now time.Time) (*PromotionResult, error) {
+	if err := p.CheckActive(now); err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("%w: the cart is empty", ErrPromotionNotApplicable)
+	}
+
+	currency := lines[0].UnitPrice.Currency
+	subtotals := make([]int64, len(lines))
+	var subtotal, eligible int64
+	lastEligible := -1
+	for i, l := range lines {
+		if l.UnitPrice.Currency != currency {
+			return nil, fmt.Errorf("%w: %s and %s", money.ErrCurrencyMismatch, currency, l.UnitPrice.Currency)
+		}
+		subtotals[i] = l.UnitPrice.Multiply(l.Quantity).Amount
+		subtotal += subtotals[i]
+		if l.Eligible {
+			eligible += subtotals[i]
+			lastEligible = i
+		}
+	}
+
+	if p.Currency != "" && p.Currency != currency {
+		return nil, fmt.Errorf("%w: it is only valid for %s carts", ErrPromotionNotApplicable, p.Currency)
+	}
+	if subtotal < p.MinSpendMinor {
+		return nil, fmt.Errorf("%w: it requires a minimum spend of %s %s",
+			ErrPromotionNotApplicable, money.New(p.MinSpendMinor, currency), currency)
+	}
+	if lastEligible < 0 || eligible == 0 {
+		return nil, fmt.Errorf("%w: no item in the cart qualifies", ErrPromotionNotApplicable)
+	}
+
+	result := &PromotionResult{
+		Subtotal:      money.New(subtotal, currency),
+		LineDiscounts: make([]money.Money, len(lines)),
+	}
+	var total int64
+	switch p.Kind {
+	case PromotionPercentage:
+		for i, l := range lines {
+			if l.Eligible {
+				d := subtotals[i] * p.Value / 100
+				result.LineDiscounts[i] = money.New(d, currency)
+				total += d
+			}
+		}
+	case PromotionFixed:
+		total = min(p.Value, eligible)
+		allocated := int64(0)
+		for i, l := range lines {
+			if !l.Eligible {
+				continue
+			}
+			d := total * subtotals[i] / eligible
+			if i == lastEligible {
+				d = total - allocated
+			}
+			result.LineDiscounts[i] = money.New(d, currency)
+			allocated += d
+		}
+	}
+	for i := range result.LineDiscounts {
+		if result.LineDiscounts[i].Currency == "" {
+			result.LineDiscounts[i] = money.Zero(currency)
+		}
+	}
+	result.Discount = money.New(total, currency)
+	return result, nil
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/fjod/go_cart/pkg/money"
+)
+
+// Variant is one sellable configuration of a product (size, colour, ...), identified by its SKU.
+// A product without variants is sold by its id alone.
+type Variant struct {
+	ID         int64
+	ProductID  int64
+	SKU        string
+	Name       string
+	PriceMinor *int64            // overrides the product price when set, in the product currency
+	Attributes map[string]string // e.g. {"memory": "32GB"}
+	CreatedAt  time.Time
+	ArchivedAt *time.Time // nil while the variant is on sale
+}
+
+func (v *Variant) IsArchived() bool {
+	return v.ArchivedAt != nil
+}
+
+func (p *Product) HasVariants() bool {
+	return len(p.Variants) > 0
+}
+
+// Variant returns the variant of this product with the given id, nil when there is none
+func (p *Product) Variant(id int64) *Variant {
+	for _, v := range p.Variants {
+		if v.ID == id {
+			return v
+		}
+	}
+	return nil
+}
+
+// PriceOf is what the variant sells for: its override or the product price. A nil variant sells at the product price.
+func (p *Product) PriceOf(v *Variant) money.Money {
+	if v == nil || v.PriceMinor == nil {
+		return p.Price
+	}
+	return money.New(*v.PriceMinor, p.Price.Currency)
+}
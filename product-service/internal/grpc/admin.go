@@ -0,0 +1,86 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/fjod/go_cart/pkg/money"
+	"github.com/fjod/go_cart/product-service/internal/domain"
+	pb "github.com/fjod/go_cart/product-service/pkg/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *ProductServiceServer) CreateProduct(
+	ctx context.Context,
+	req *pb.CreateProductRequest,
+) (*pb.CreateProductResponse, error) {
+	p, err := newProduct(0, req.Name, req.Description, req.PriceMinor, req.Currency, req.ImageUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateProduct(ctx, p); err != nil {
+		return nil, repoError(err, "failed to create product")
+	}
+	return &pb.CreateProductResponse{Product: toProtoProduct(p)}, nil
+}
+
+func (s *ProductServiceServer) UpdateProduct(
+	ctx context.Context,
+	req *pb.UpdateProductRequest,
+) (*pb.UpdateProductResponse, error) {
+	if req.Id <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "id must be greater than 0")
+	}
+	p, err := newProduct(req.Id, req.Name, req.Description, req.PriceMinor, req.Currency, req.ImageUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateProduct(ctx, p); err != nil {
+		return nil, repoError(err, "failed to update product")
+	}
+	return &pb.UpdateProductResponse{Product: toProtoProduct(p)}, nil
+}
+
+func (s *ProductServiceServer) ArchiveProduct(
+	ctx context.Context,
+	req *pb.ArchiveProductRequest,
+) (*pb.ArchiveProductResponse, error) {
+	if req.Id <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "id must be greater than 0")
+	}
+
+	p, err := s.repo.ArchiveProduct(ctx, req.Id)
+	if err != nil {
+		return nil, repoError(err, "failed to archive product")
+	}
+	return &pb.ArchiveProductResponse{Product: toProtoProduct(p)}, nil
+}
+
+// newProduct validates the admin input and builds the product it describes
+func newProduct(id int64, name, description string, priceMinor int64, currency, imageURL string) (*domain.Product, error) {
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+	p := &domain.Product{
+		ID:          id,
+		Name:        name,
+		Description: description,
+		Price:       money.New(priceMinor, currency),
+		ImageURL:    imageURL,
+	}
+	if err := p.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return p, nil
+}
+
+// repoError passes through the gRPC statuses the repository returns (NotFound, FailedPrecondition)
+// and reports everything else as Internal
+func repoError(err error, msg string) error {
+	if st, ok := status.FromError(err); ok {
+		return st.Err()
+	}
+	return status.Errorf(codes.Internal, "%s: %v", msg, err)
+}
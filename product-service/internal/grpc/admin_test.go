@@ -0,0 +1,97 @@
+package grpc_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/fjod/go_cart/pkg/money"
+	"github.com/fjod/go_cart/product-service/internal/domain"
+	grpcHandler "github.com/fjod/go_cart/product-service/internal/grpc"
+	pb "github.com/fjod/go_cart/product-service/pkg/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCreateProduct_Success(t *testing.T) {
+	mockRepo := &mockRepository{}
+	server := grpcHandler.NewProductServiceServer(mockRepo)
+
+	resp, err := server.CreateProduct(context.Background(), &pb.CreateProductRequest{
+		Name:       "Desk Lamp",
+		PriceMinor: 2599,
+		ImageUrl:   "https://example.com/lamp.jpg",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), resp.Product.Id)
+	assert.Equal(t, money.New(2599, "USD"), mockRepo.saved.Price) // currency defaults to USD
+	assert.Equal(t, "", resp.Product.ArchivedAt)
+}
+
+func TestCreateProduct_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *pb.CreateProductRequest
+	}{
+		{"missing name", &pb.CreateProductRequest{PriceMinor: 100}},
+		{"name too long", &pb.CreateProductRequest{Name: strings.Repeat("a", 201), PriceMinor: 100}},
+		{"negative price", &pb.CreateProductRequest{Name: "Lamp", PriceMinor: -1}},
+		{"lowercase currency", &pb.CreateProductRequest{Name: "Lamp", PriceMinor: 100, Currency: "usd"}},
+		{"relative image url", &pb.CreateProductRequest{Name: "Lamp", PriceMinor: 100, ImageUrl: "/img/lamp.jpg"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockRepository{}
+			server := grpcHandler.NewProductServiceServer(mockRepo)
+
+			_, err := server.CreateProduct(context.Background(), tt.req)
+
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			assert.Nil(t, mockRepo.saved)
+		})
+	}
+}
+
+func TestCreateProduct_RepositoryError(t *testing.T) {
+	server := grpcHandler.NewProductServiceServer(&mockRepository{err: errors.New("disk full")})
+
+	_, err := server.CreateProduct(context.Background(), &pb.CreateProductRequest{Name: "Lamp", PriceMinor: 100})
+
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestUpdateProduct_ArchivedIsFailedPrecondition(t *testing.T) {
+	server := grpcHandler.NewProductServiceServer(&mockRepository{
+		err: status.Error(codes.FailedPrecondition, "product is archived"),
+	})
+
+	_, err := server.UpdateProduct(context.Background(), &pb.UpdateProductRequest{Id: 1, Name: "Lamp", PriceMinor: 100})
+
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestUpdateProduct_InvalidId(t *testing.T) {
+	server := grpcHandler.NewProductServiceServer(&mockRepository{})
+
+	_, err := server.UpdateProduct(context.Background(), &pb.UpdateProductRequest{Name: "Lamp", PriceMinor: 100})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestArchiveProduct(t *testing.T) {
+	mockRepo := &mockRepository{
+		products: []*domain.Product{{ID: 1, Name: "Lamp", Price: money.New(100, "USD")}},
+	}
+	server := grpcHandler.NewProductServiceServer(mockRepo)
+
+	resp, err := server.ArchiveProduct(context.Background(), &pb.ArchiveProductRequest{Id: 1})
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.Product.ArchivedAt)
+
+	_, err = server.ArchiveProduct(context.Background(), &pb.ArchiveProductRequest{Id: 42})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
@@ -0,0 +1,36 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/fjod/go_cart/product-service/internal/domain"
+	pb "github.com/fjod/go_cart/product-service/pkg/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *ProductServiceServer) ListCategories(
+	ctx context.Context,
+	_ *pb.ListCategoriesRequest,
+) (*pb.ListCategoriesResponse, error) {
+	categories, err := s.repo.ListCategories(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to fetch categories: %v", err)
+	}
+	return &pb.ListCategoriesResponse{Categories: toProtoCategories(categories)}, nil
+}
+
+func toProtoCategories(categories []*domain.Category) []*pb.Category {
+	pbCategories := make([]*pb.Category, len(categories))
+	for i, c := range categories {
+		pbCategories[i] = &pb.Category{
+			Id:   c.ID,
+			Name: c.Name,
+			Slug: c.Slug,
+		}
+		if c.ParentID != nil {
+			pbCategories[i].ParentId = *c.ParentID
+		}
+	}
+	return pbCategories
+}
@@ -0,0 +1,83 @@
+package grpc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fjod/go_cart/pkg/money"
+	"github.com/fjod/go_cart/product-service/internal/domain"
+	grpcHandler "github.com/fjod/go_cart/product-service/internal/grpc"
+	pb "github.com/fjod/go_cart/product-service/pkg/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	electronicsID = int64(1)
+	electronics   = &domain.Category{ID: 1, Name: "Electronics", Slug: "electronics"}
+	audio         = &domain.Category{ID: 6, ParentID: &electronicsID, Name: "Audio", Slug: "audio"}
+)
+
+func TestListCategories_MapsParents(t *testing.T) {
+	server := grpcHandler.NewProductServiceServer(&mockRepository{categories: []*domain.Category{electronics, audio}})
+
+	resp, err := server.ListCategories(context.Background(), &pb.ListCategoriesRequest{})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Categories, 2)
+	assert.Equal(t, int64(0), resp.Categories[0].ParentId)
+	assert.Equal(t, int64(1), resp.Categories[1].ParentId)
+	assert.Equal(t, "audio", resp.Categories[1].Slug)
+}
+
+func TestGetProduct_ReturnsBreadcrumbs(t *testing.T) {
+	server := grpcHandler.NewProductServiceServer(&mockRepository{
+		products:    []*domain.Product{{ID: 5, Name: "Headphones", Price: money.New(24999, "USD")}},
+		breadcrumbs: []domain.Breadcrumb{{electronics, audio}},
+	})
+
+	resp, err := server.GetProduct(context.Background(), &pb.GetProductRequest{Id: 5})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Breadcrumbs, 1)
+	require.Len(t, resp.Breadcrumbs[0].Categories, 2)
+	assert.Equal(t, "Electronics", resp.Breadcrumbs[0].Categories[0].Name)
+	assert.Equal(t, "Audio", resp.Breadcrumbs[0].Categories[1].Name)
+}
+
+func TestGetProducts_CategoryFilter(t *testing.T) {
+	mockRepo := &mockRepository{next: &domain.ProductCursor{Key: "2", ID: 2}}
+	server := grpcHandler.NewProductServiceServer(mockRepo)
+	req := &pb.GetProductsRequest{CategoryId: 2, IncludeDescendants: true}
+
+	first, err := server.GetProducts(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), mockRepo.listQuery.CategoryID)
+	assert.True(t, mockRepo.listQuery.IncludeDescendants)
+
+	// the token only continues the listing of the category it came from
+	req.PageToken = first.NextPageToken
+	req.IncludeDescendants = false
+	_, err = server.GetProducts(context.Background(), req)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGetProducts_InvalidCategory(t *testing.T) {
+	server := grpcHandler.NewProductServiceServer(&mockRepository{})
+
+	_, err := server.GetProducts(context.Background(), &pb.GetProductsRequest{CategoryId: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = server.GetProducts(context.Background(), &pb.GetProductsRequest{IncludeDescendants: true})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGetProducts_UnknownCategory(t *testing.T) {
+	server := grpcHandler.NewProductServiceServer(&mockRepository{err: status.Error(codes.NotFound, "category not found")})
+
+	_, err := server.GetProducts(context.Background(), &pb.GetProductsRequest{CategoryId: 999})
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
@@ -2,13 +2,23 @@ package grpc
 
 import (
 	"context"
+	"time"
 
+	"github.com/fjod/go_cart/product-service/internal/domain"
 	db "github.com/fjod/go_cart/product-service/internal/repository"
 	pb "github.com/fjod/go_cart/product-service/pkg/proto"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// maxIdsPerRequest bounds GetProductsByIds so a single call can't turn into an unbounded IN list
+const maxIdsPerRequest = 1000
+
 // ProductServiceServer implements the gRPC ProductService
 type ProductServiceServer struct {
 	pb.UnimplementedProductServiceServer
@@ -23,37 +33,79 @@ func NewProductServiceServer(repo db.RepoInterface) *ProductServiceServer {
 
 func (s *ProductServiceServer) GetProducts(
 	ctx context.Context,
-	_ *pb.GetProductsRequest,
+	req *pb.GetProductsRequest,
 ) (*pb.GetProductsResponse, error) {
-
-	// Fetch products from repository
-	products, err := s.repo.GetAllProducts(ctx)
+	q, err := toProductQuery(req)
 	if err != nil {
-		return nil, status.Errorf(
-			codes.Internal,
-			"failed to fetch products: %v",
-			err,
-		)
+		return nil, err
 	}
 
-	// Convert domain models to protobuf messages
-	pbProducts := make([]*pb.Product, len(products))
-	for i, p := range products {
-		pbProducts[i] = &pb.Product{
-			Id:          p.ID,
-			Name:        p.Name,
-			Description: p.Description,
-			Price:       p.Price,
-			ImageUrl:    p.ImageURL,
-			CreatedAt:   p.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		}
+	// Fetch products from repository
+	products, next, err := s.repo.ListProducts(ctx, q)
+	if err != nil {
+		return nil, repoError(err, "failed to fetch products")
 	}
 
 	return &pb.GetProductsResponse{
-		Products: pbProducts,
+		Products:      toProtoProducts(products),
+		NextPageToken: encodePageToken(next, q),
 	}, nil
 }
 
+// toProductQuery validates the paging, sort and filter fields of the request
+func toProductQuery(req *pb.GetProductsRequest) (domain.ProductQuery, error) {
+	q := domain.ProductQuery{
+		PageSize:      int(req.PageSize),
+		Descending:    req.Descending,
+		MinPriceMinor: req.MinPriceMinor,
+		MaxPriceMinor: req.MaxPriceMinor,
+		CategoryID:    req.CategoryId,
+	}
+
+	switch {
+	case req.PageSize < 0:
+		return q, status.Error(codes.InvalidArgument, "page_size must not be negative")
+	case req.PageSize == 0:
+		q.PageSize = defaultPageSize
+	case req.PageSize > maxPageSize:
+		q.PageSize = maxPageSize
+	}
+
+	switch req.SortBy {
+	case pb.ProductSort_PRODUCT_SORT_UNSPECIFIED:
+		q.SortBy = domain.ProductSortID
+	case pb.ProductSort_PRODUCT_SORT_PRICE:
+		q.SortBy = domain.ProductSortPrice
+	case pb.ProductSort_PRODUCT_SORT_NAME:
+		q.SortBy = domain.ProductSortName
+	case pb.ProductSort_PRODUCT_SORT_CREATED_AT:
+		q.SortBy = domain.ProductSortCreatedAt
+	default:
+		return q, status.Errorf(codes.InvalidArgument, "unknown sort_by %d", req.SortBy)
+	}
+
+	switch {
+	case q.CategoryID < 0:
+		return q, status.Error(codes.InvalidArgument, "category_id must not be negative")
+	case q.CategoryID == 0 && req.IncludeDescendants:
+		return q, status.Error(codes.InvalidArgument, "include_descendants needs a category_id")
+	}
+	q.IncludeDescendants = req.IncludeDescendants
+
+	if q.MinPriceMinor != nil && q.MaxPriceMinor != nil && *q.MinPriceMinor > *q.MaxPriceMinor {
+		return q, status.Error(codes.InvalidArgument, "min_price_minor must not exceed max_price_minor")
+	}
+
+	if req.PageToken != "" {
+		after, err := decodePageToken(req.PageToken, q)
+		if err != nil {
+			return q, status.Error(codes.InvalidArgument, err.Error())
+		}
+		q.After = after
+	}
+	return q, nil
+}
+
 func (s *ProductServiceServer) GetProduct(
 	ctx context.Context,
 	req *pb.GetProductRequest,
@@ -70,14 +122,97 @@ func (s *ProductServiceServer) GetProduct(
 		)
 	}
 
-	var ret = &pb.Product{
+	breadcrumbs, err := s.repo.GetBreadcrumbs(ctx, p.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to fetch breadcrumbs: %v", err)
+	}
+
+	resp := &pb.GetProductResponse{Product: toProtoProduct(p)}
+	for _, b := range breadcrumbs {
+		resp.Breadcrumbs = append(resp.Breadcrumbs, &pb.Breadcrumb{Categories: toProtoCategories(b)})
+	}
+	return resp, nil
+}
+
+func (s *ProductServiceServer) GetProductsByIds(
+	ctx context.Context,
+	req *pb.GetProductsByIdsRequest,
+) (*pb.GetProductsByIdsResponse, error) {
+	if len(req.Ids) > maxIdsPerRequest {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d ids per request", maxIdsPerRequest)
+	}
+
+	ids := make([]int64, 0, len(req.Ids))
+	seen := make(map[int64]struct{}, len(req.Ids))
+	for _, id := range req.Ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+
+	products, err := s.repo.GetProductsByIds(ctx, ids)
+	if err != nil {
+		return nil, status.Errorf(
+			codes.Internal,
+			"failed to fetch products: %v",
+			err,
+		)
+	}
+
+	return &pb.GetProductsByIdsResponse{
+		Products: toProtoProducts(products),
+	}, nil
+}
+
+func toProtoProducts(products []*domain.Product) []*pb.Product {
+	pbProducts := make([]*pb.Product, len(products))
+	for i, p := range products {
+		pbProducts[i] = toProtoProduct(p)
+	}
+	return pbProducts
+}
+
+func toProtoProduct(p *domain.Product) *pb.Product {
+	return &pb.Product{
 		Id:          p.ID,
+		Sku:         p.SKU,
 		Name:        p.Name,
 		Description: p.Description,
-		Price:       p.Price,
+		Price:       p.Price.Float64(),
+		PriceMinor:  p.Price.Amount,
+		Currency:    p.Price.Currency,
 		ImageUrl:    p.ImageURL,
 		CreatedAt:   p.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		ArchivedAt:  formatOptionalTime(p.ArchivedAt),
+		Variants:    toProtoVariants(p),
+	}
+}
+
+func toProtoVariants(p *domain.Product) []*pb.ProductVariant {
+	if !p.HasVariants() {
+		return nil
 	}
+	variants := make([]*pb.ProductVariant, len(p.Variants))
+	for i, v := range p.Variants {
+		price := p.PriceOf(v)
+		variants[i] = &pb.ProductVariant{
+			Id:         v.ID,
+			Sku:        v.SKU,
+			Name:       v.Name,
+			PriceMinor: price.Amount,
+			Currency:   price.Currency,
+			Attributes: v.Attributes,
+			ArchivedAt: formatOptionalTime(v.ArchivedAt),
+		}
+	}
+	return variants
+}
 
-	return &pb.GetProductResponse{Product: ret}, nil
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02T15:04:05Z07:00")
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/fjod/go_cart/pkg/money"
 	"github.com/fjod/go_cart/product-service/internal/domain"
 	grpcHandler "github.com/fjod/go_cart/product-service/internal/grpc"
 	pb "github.com/fjod/go_cart/product-service/pkg/proto"
@@ -15,8 +16,24 @@ import (
 
 // Mock repository for testing
 type mockRepository struct {
-	products []*domain.Product
-	err      error
+	products     []*domain.Product
+	err          error
+	requestedIds []int64
+	saved        *domain.Product // captures the product passed to CreateProduct/UpdateProduct
+	listQuery    *domain.ProductQuery
+	next         *domain.ProductCursor // returned by ListProducts
+	hits         []*domain.ProductSearchHit
+	searchLimit  int
+	searchOffset int
+	categories   []*domain.Category
+	breadcrumbs  []domain.Breadcrumb
+	pricePoint   *domain.PricePoint
+	priceAt      time.Time
+	scheduled    *domain.ScheduledPrice // captures the price passed to SchedulePrice
+	promotion    *domain.Promotion      // returned by GetPromotionByCode, captures CreatePromotion
+	inCategories []int64                // returned by FilterProductsByCategories
+	redeemed     string                 // checkout id passed to RedeemPromotion
+	released     string                 // checkout id passed to ReleasePromotion
 }
 
 func (m *mockRepository) GetAllProducts(context.Context) ([]*domain.Product, error) {
@@ -26,6 +43,14 @@ func (m *mockRepository) GetAllProducts(context.Context) ([]*domain.Product, err
 	return m.products, m.err
 }
 
+func (m *mockRepository) ListProducts(_ context.Context, q domain.ProductQuery) ([]*domain.Product, *domain.ProductCursor, error) {
+	if m.err != nil {
+		return nil, nil, m.err
+	}
+	m.listQuery = &q
+	return m.products, m.next, nil
+}
+
 func (m *mockRepository) GetProduct(_ context.Context, id int64) (*domain.Product, error) {
 	if m.err != nil {
 		return nil, m.err
@@ -38,6 +63,141 @@ func (m *mockRepository) GetProduct(_ context.Context, id int64) (*domain.Produc
 	return nil, m.err
 }
 
+func (m *mockRepository) GetProductsByIds(_ context.Context, ids []int64) ([]*domain.Product, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.requestedIds = ids
+	var found []*domain.Product
+	for _, p := range m.products {
+		for _, id := range ids {
+			if p.ID == id {
+				found = append(found, p)
+			}
+		}
+	}
+	return found, nil
+}
+
+func (m *mockRepository) CreateProduct(_ context.Context, p *domain.Product) error {
+	if m.err != nil {
+		return m.err
+	}
+	p.ID = int64(len(m.products) + 1)
+	m.saved = p
+	return nil
+}
+
+func (m *mockRepository) UpdateProduct(_ context.Context, p *domain.Product) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.saved = p
+	return nil
+}
+
+func (m *mockRepository) ArchiveProduct(_ context.Context, id int64) (*domain.Product, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	for _, p := range m.products {
+		if p.ID == id {
+			now := time.Now()
+			p.ArchivedAt = &now
+			return p, nil
+		}
+	}
+	return nil, status.Error(codes.NotFound, "product not found")
+}
+
+func (m *mockRepository) SearchProducts(_ context.Context, _ string, limit, offset int) ([]*domain.ProductSearchHit, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.searchLimit, m.searchOffset = limit, offset
+	if len(m.hits) > limit {
+		return m.hits[:limit], nil
+	}
+	return m.hits, nil
+}
+
+func (m *mockRepository) ListCategories(context.Context) ([]*domain.Category, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.categories, nil
+}
+
+func (m *mockRepository) GetBreadcrumbs(context.Context, int64) ([]domain.Breadcrumb, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.breadcrumbs, nil
+}
+
+func (m *mockRepository) GetPriceAt(_ context.Context, _ int64, at time.Time) (*domain.PricePoint, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.priceAt = at
+	return m.pricePoint, nil
+}
+
+func (m *mockRepository) SchedulePrice(_ context.Context, sp *domain.ScheduledPrice) error {
+	if m.err != nil {
+		return m.err
+	}
+	sp.ID = 1
+	sp.Price.Currency = "USD"
+	m.scheduled = sp
+	return nil
+}
+
+func (m *mockRepository) ApplyScheduledPrices(context.Context, time.Time) ([]int64, error) {
+	return nil, m.err
+}
+
+func (m *mockRepository) CreatePromotion(_ context.Context, p *domain.Promotion) error {
+	if m.err != nil {
+		return m.err
+	}
+	p.ID = 1
+	m.promotion = p
+	return nil
+}
+
+func (m *mockRepository) GetPromotionByCode(context.Context, string) (*domain.Promotion, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.promotion == nil {
+		return nil, status.Error(codes.NotFound, "promo code not found")
+	}
+	return m.promotion, nil
+}
+
+func (m *mockRepository) FilterProductsByCategories(context.Context, []int64, []int64) ([]int64, error) {
+	return m.inCategories, m.err
+}
+
+func (m *mockRepository) RedeemPromotion(_ context.Context, _ string, checkoutID string, _ time.Time) (*domain.Promotion, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.redeemed = checkoutID
+	m.promotion.TimesUsed++
+	return m.promotion, nil
+}
+
+func (m *mockRepository) ReleasePromotion(_ context.Context, _ string, checkoutID string) (*domain.Promotion, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.released = checkoutID
+	m.promotion.TimesUsed--
+	return m.promotion, nil
+}
+
 func (m *mockRepository) Close() error                 { return nil }
 func (m *mockRepository) RunMigrations(_ string) error { return nil }
 
@@ -48,7 +208,7 @@ func TestGetAllProducts_Success(t *testing.T) {
 				ID:          1,
 				Name:        "Test Product",
 				Description: "Test Description",
-				Price:       99.99,
+				Price:       money.New(9999, "USD"),
 				ImageURL:    "http://example.com/image.jpg",
 				CreatedAt:   time.Now(),
 			},
@@ -79,7 +239,7 @@ func TestGetProduct_Success(t *testing.T) {
 				ID:          1,
 				Name:        "Test Product",
 				Description: "Test Description",
-				Price:       99.99,
+				Price:       money.New(9999, "USD"),
 				ImageURL:    "http://example.com/image.jpg",
 				CreatedAt:   time.Now(),
 			},
@@ -99,6 +259,9 @@ func TestGetProduct_Success(t *testing.T) {
 	}
 
 	assert.Equal(t, "Test Product", resp.Product.Name)
+	assert.Equal(t, int64(9999), resp.Product.PriceMinor)
+	assert.Equal(t, "USD", resp.Product.Currency)
+	assert.Equal(t, 99.99, resp.Product.Price)
 }
 
 func TestGetProduct_NotFound(t *testing.T) {
@@ -108,7 +271,7 @@ func TestGetProduct_NotFound(t *testing.T) {
 				ID:          1,
 				Name:        "Test Product",
 				Description: "Test Description",
-				Price:       99.99,
+				Price:       money.New(9999, "USD"),
 				ImageURL:    "http://example.com/image.jpg",
 				CreatedAt:   time.Now(),
 			},
@@ -124,3 +287,87 @@ func TestGetProduct_NotFound(t *testing.T) {
 	assert.Nil(t, resp)
 	assert.Equal(t, status.Code(err), codes.NotFound)
 }
+
+func TestGetProductsByIds_DeduplicatesIds(t *testing.T) {
+	mockRepo := &mockRepository{
+		products: []*domain.Product{
+			{ID: 1, Name: "First", Price: money.New(100, "USD")},
+			{ID: 2, Name: "Second", Price: money.New(200, "USD")},
+		},
+	}
+	server := grpcHandler.NewProductServiceServer(mockRepo)
+
+	resp, err := server.GetProductsByIds(context.Background(), &pb.GetProductsByIdsRequest{Ids: []int64{2, 1, 2, 42}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 1, 42}, mockRepo.requestedIds)
+	assert.Len(t, resp.Products, 2)
+	assert.Equal(t, int64(200), resp.Products[1].PriceMinor)
+}
+
+func TestGetProductsByIds_TooManyIds(t *testing.T) {
+	server := grpcHandler.NewProductServiceServer(&mockRepository{})
+
+	_, err := server.GetProductsByIds(context.Background(), &pb.GetProductsByIdsRequest{Ids: make([]int64, 1001)})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGetProducts_PageSizeDefaultsAndCap(t *testing.T) {
+	mockRepo := &mockRepository{}
+	server := grpcHandler.NewProductServiceServer(mockRepo)
+
+	_, err := server.GetProducts(context.Background(), &pb.GetProductsRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, 20, mockRepo.listQuery.PageSize)
+
+	_, err = server.GetProducts(context.Background(), &pb.GetProductsRequest{PageSize: 1000})
+	assert.NoError(t, err)
+	assert.Equal(t, 100, mockRepo.listQuery.PageSize)
+
+	_, err = server.GetProducts(context.Background(), &pb.GetProductsRequest{PageSize: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGetProducts_PageTokenRoundTrip(t *testing.T) {
+	mockRepo := &mockRepository{next: &domain.ProductCursor{Key: "2999", ID: 2}}
+	server := grpcHandler.NewProductServiceServer(mockRepo)
+	req := &pb.GetProductsRequest{PageSize: 2, SortBy: pb.ProductSort_PRODUCT_SORT_PRICE}
+
+	first, err := server.GetProducts(context.Background(), req)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, first.NextPageToken)
+
+	mockRepo.next = nil
+	req.PageToken = first.NextPageToken
+	second, err := server.GetProducts(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.ProductCursor{Key: "2999", ID: 2}, mockRepo.listQuery.After)
+	assert.Empty(t, second.NextPageToken)
+
+	// the token only continues the listing it came from
+	req.SortBy = pb.ProductSort_PRODUCT_SORT_NAME
+	_, err = server.GetProducts(context.Background(), req)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGetProducts_InvalidArguments(t *testing.T) {
+	minPrice, maxPrice := int64(500), int64(100)
+	tests := []struct {
+		name string
+		req  *pb.GetProductsRequest
+	}{
+		{"garbage token", &pb.GetProductsRequest{PageToken: "not-a-token"}},
+		{"unknown sort", &pb.GetProductsRequest{SortBy: pb.ProductSort(42)}},
+		{"min above max", &pb.GetProductsRequest{MinPriceMinor: &minPrice, MaxPriceMinor: &maxPrice}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := grpcHandler.NewProductServiceServer(&mockRepository{})
+
+			_, err := server.GetProducts(context.Background(), tt.req)
+
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
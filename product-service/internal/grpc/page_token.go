@@ -0,0 +1,79 @@
+package grpc
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/fjod/go_cart/product-service/internal/domain"
+)
+
+var errInvalidPageToken = errors.New("invalid page_token")
+
+// pageToken is what next_page_token carries; Query ties it to the sort and filters it was issued for
+type pageToken struct {
+	Key   string `json:"k"`
+	ID    int64  `json:"id"`
+	Query string `json:"q"`
+}
+
+func encodePageToken(c *domain.ProductCursor, q domain.ProductQuery) string {
+	if c == nil {
+		return ""
+	}
+	raw, _ := json.Marshal(pageToken{Key: c.Key, ID: c.ID, Query: queryFingerprint(q)})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodePageToken(token string, q domain.ProductQuery) (*domain.ProductCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errInvalidPageToken
+	}
+	var t pageToken
+	if err := json.Unmarshal(raw, &t); err != nil {
+		return nil, errInvalidPageToken
+	}
+	if t.Query != queryFingerprint(q) {
+		return nil, fmt.Errorf("%w: it was issued for a different sort or filter", errInvalidPageToken)
+	}
+	return &domain.ProductCursor{Key: t.Key, ID: t.ID}, nil
+}
+
+func queryFingerprint(q domain.ProductQuery) string {
+	bound := func(v *int64) string {
+		if v == nil {
+			return "-"
+		}
+		return fmt.Sprint(*v)
+	}
+	return fmt.Sprintf("%d/%t/%s/%s/%d/%t", q.SortBy, q.Descending, bound(q.MinPriceMinor), bound(q.MaxPriceMinor),
+		q.CategoryID, q.IncludeDescendants)
+}
+
+// searchPageToken carries the offset of the next search page, Query ties it to the search text
+type searchPageToken struct {
+	Offset int    `json:"o"`
+	Query  string `json:"q"`
+}
+
+func encodeSearchPageToken(offset int, query string) string {
+	raw, _ := json.Marshal(searchPageToken{Offset: offset, Query: query})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeSearchPageToken(token string, query string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, errInvalidPageToken
+	}
+	var t searchPageToken
+	if err := json.Unmarshal(raw, &t); err != nil || t.Offset < 0 {
+		return 0, errInvalidPageToken
+	}
+	if t.Query != query {
+		return 0, fmt.Errorf("%w: it was issued for a different query", errInvalidPageToken)
+	}
+	return t.Offset, nil
+}
@@ -0,0 +1,83 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/fjod/go_cart/pkg/money"
+	"github.com/fjod/go_cart/product-service/internal/domain"
+	pb "github.com/fjod/go_cart/product-service/pkg/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *ProductServiceServer) GetPriceAt(
+	ctx context.Context,
+	req *pb.GetPriceAtRequest,
+) (*pb.GetPriceAtResponse, error) {
+	if req.ProductId <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "product_id must be greater than 0")
+	}
+	at := time.Now()
+	if req.At != "" {
+		var err error
+		if at, err = time.Parse(time.RFC3339, req.At); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "at must be an RFC3339 timestamp")
+		}
+	}
+
+	pp, err := s.repo.GetPriceAt(ctx, req.ProductId, at)
+	if err != nil {
+		return nil, repoError(err, "failed to fetch price history")
+	}
+	return &pb.GetPriceAtResponse{
+		ProductId:  pp.ProductID,
+		PriceMinor: pp.Price.Amount,
+		Currency:   pp.Price.Currency,
+		ValidFrom:  pp.ValidFrom.Format(time.RFC3339),
+		ValidTo:    formatOptionalTime(pp.ValidTo),
+	}, nil
+}
+
+func (s *ProductServiceServer) SchedulePriceChange(
+	ctx context.Context,
+	req *pb.SchedulePriceChangeRequest,
+) (*pb.SchedulePriceChangeResponse, error) {
+	if req.ProductId <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "product_id must be greater than 0")
+	}
+	if req.PriceMinor < 0 {
+		return nil, status.Error(codes.InvalidArgument, "price_minor must not be negative")
+	}
+	startsAt, err := time.Parse(time.RFC3339, req.StartsAt)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "starts_at must be an RFC3339 timestamp")
+	}
+	sp := &domain.ScheduledPrice{
+		ProductID: req.ProductId,
+		Price:     money.New(req.PriceMinor, ""), // the repository prices it in the product currency
+		StartsAt:  startsAt,
+	}
+	if req.EndsAt != "" {
+		endsAt, err := time.Parse(time.RFC3339, req.EndsAt)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "ends_at must be an RFC3339 timestamp")
+		}
+		if !endsAt.After(startsAt) {
+			return nil, status.Error(codes.InvalidArgument, "ends_at must be after starts_at")
+		}
+		sp.EndsAt = &endsAt
+	}
+
+	if err := s.repo.SchedulePrice(ctx, sp); err != nil {
+		return nil, repoError(err, "failed to schedule price")
+	}
+	return &pb.SchedulePriceChangeResponse{ScheduledPrice: &pb.ScheduledPrice{
+		Id:         sp.ID,
+		ProductId:  sp.ProductID,
+		PriceMinor: sp.Price.Amount,
+		Currency:   sp.Price.Currency,
+		StartsAt:   sp.StartsAt.Format(time.RFC3339),
+		EndsAt:     formatOptionalTime(sp.EndsAt),
+	}}, nil
+}
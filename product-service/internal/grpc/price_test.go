@@ -0,0 +1,115 @@
+package grpc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fjod/go_cart/pkg/money"
+	"github.com/fjod/go_cart/product-service/internal/domain"
+	grpcHandler "github.com/fjod/go_cart/product-service/internal/grpc"
+	pb "github.com/fjod/go_cart/product-service/pkg/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetPriceAt_Success(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	mockRepo := &mockRepository{pricePoint: &domain.PricePoint{
+		ProductID: 1, Price: money.New(1999, "USD"), ValidFrom: from, ValidTo: &to,
+	}}
+	server := grpcHandler.NewProductServiceServer(mockRepo)
+
+	resp, err := server.GetPriceAt(context.Background(), &pb.GetPriceAtRequest{ProductId: 1, At: "2026-01-15T12:00:00Z"})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1999), resp.PriceMinor)
+	assert.Equal(t, "USD", resp.Currency)
+	assert.Equal(t, "2026-01-01T00:00:00Z", resp.ValidFrom)
+	assert.Equal(t, "2026-02-01T00:00:00Z", resp.ValidTo)
+	assert.Equal(t, time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC), mockRepo.priceAt)
+}
+
+func TestGetPriceAt_DefaultsToNow(t *testing.T) {
+	mockRepo := &mockRepository{pricePoint: &domain.PricePoint{ProductID: 1, Price: money.New(1999, "USD")}}
+	server := grpcHandler.NewProductServiceServer(mockRepo)
+
+	resp, err := server.GetPriceAt(context.Background(), &pb.GetPriceAtRequest{ProductId: 1})
+
+	require.NoError(t, err)
+	assert.Empty(t, resp.ValidTo)
+	assert.WithinDuration(t, time.Now(), mockRepo.priceAt, time.Minute)
+}
+
+func TestGetPriceAt_InvalidArgument(t *testing.T) {
+	server := grpcHandler.NewProductServiceServer(&mockRepository{})
+
+	for name, req := range map[string]*pb.GetPriceAtRequest{
+		"missing product": {At: "2026-01-15T12:00:00Z"},
+		"bad timestamp":   {ProductId: 1, At: "yesterday"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := server.GetPriceAt(context.Background(), req)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
+
+func TestGetPriceAt_NotFound(t *testing.T) {
+	server := grpcHandler.NewProductServiceServer(&mockRepository{err: status.Error(codes.NotFound, "product not found")})
+
+	_, err := server.GetPriceAt(context.Background(), &pb.GetPriceAtRequest{ProductId: 42})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestSchedulePriceChange_Success(t *testing.T) {
+	mockRepo := &mockRepository{}
+	server := grpcHandler.NewProductServiceServer(mockRepo)
+
+	resp, err := server.SchedulePriceChange(context.Background(), &pb.SchedulePriceChangeRequest{
+		ProductId:  1,
+		PriceMinor: 999,
+		StartsAt:   "2026-11-27T00:00:00Z",
+		EndsAt:     "2026-11-30T00:00:00Z",
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, mockRepo.scheduled)
+	require.NotNil(t, mockRepo.scheduled.EndsAt)
+	assert.Equal(t, int64(999), mockRepo.scheduled.Price.Amount)
+	assert.Equal(t, int64(1), resp.ScheduledPrice.Id)
+	assert.Equal(t, "USD", resp.ScheduledPrice.Currency)
+	assert.Equal(t, "2026-11-27T00:00:00Z", resp.ScheduledPrice.StartsAt)
+	assert.Equal(t, "2026-11-30T00:00:00Z", resp.ScheduledPrice.EndsAt)
+}
+
+func TestSchedulePriceChange_InvalidArgument(t *testing.T) {
+	server := grpcHandler.NewProductServiceServer(&mockRepository{})
+
+	for name, req := range map[string]*pb.SchedulePriceChangeRequest{
+		"missing product": {PriceMinor: 1, StartsAt: "2026-11-27T00:00:00Z"},
+		"negative price":  {ProductId: 1, PriceMinor: -1, StartsAt: "2026-11-27T00:00:00Z"},
+		"missing start":   {ProductId: 1, PriceMinor: 1},
+		"bad end":         {ProductId: 1, PriceMinor: 1, StartsAt: "2026-11-27T00:00:00Z", EndsAt: "soon"},
+		"end before start": {
+			ProductId: 1, PriceMinor: 1, StartsAt: "2026-11-27T00:00:00Z", EndsAt: "2026-11-26T00:00:00Z",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := server.SchedulePriceChange(context.Background(), req)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
+
+func TestSchedulePriceChange_ArchivedProduct(t *testing.T) {
+	server := grpcHandler.NewProductServiceServer(&mockRepository{err: status.Error(codes.FailedPrecondition, "product is archived")})
+
+	_, err := server.SchedulePriceChange(context.Background(), &pb.SchedulePriceChangeRequest{
+		ProductId: 1, PriceMinor: 1, StartsAt: "2026-11-27T00:00:00Z",
+	})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
@@ -0,0 +1,243 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/fjod/go_cart/pkg/money"
+	"github.com/fjod/go_cart/product-service/internal/domain"
+	pb "github.com/fjod/go_cart/product-service/pkg/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxPromotionLines bounds EvaluatePromotion like maxIdsPerRequest bounds GetProductsByIds
+const maxPromotionLines = 1000
+
+func (s *ProductServiceServer) CreatePromotion(
+	ctx context.Context,
+	req *pb.CreatePromotionRequest,
+) (*pb.CreatePromotionResponse, error) {
+	p := &domain.Promotion{
+		Code:          domain.NormalizePromoCode(req.Code),
+		Kind:          promotionKind(req.Kind),
+		Value:         req.Value,
+		Currency:      req.Currency,
+		MinSpendMinor: req.MinSpendMinor,
+		ProductIDs:    req.ProductIds,
+		CategoryIDs:   req.CategoryIds,
+		UsageLimit:    req.UsageLimit,
+	}
+	var err error
+	if p.StartsAt, err = parseOptionalTime(req.StartsAt, "starts_at"); err != nil {
+		return nil, err
+	}
+	if p.ExpiresAt, err = parseOptionalTime(req.ExpiresAt, "expires_at"); err != nil {
+		return nil, err
+	}
+	if err := p.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.repo.CreatePromotion(ctx, p); err != nil {
+		return nil, repoError(err, "failed to create promotion")
+	}
+	return &pb.CreatePromotionResponse{Promotion: toProtoPromotion(p)}, nil
+}
+
+// EvaluatePromotion prices the lines from the catalog and applies the promo code to them.
+// Codes that exist but can't be used on these lines fail with FailedPrecondition and the reason.
+func (s *ProductServiceServer) EvaluatePromotion(
+	ctx context.Context,
+	req *pb.EvaluatePromotionRequest,
+) (*pb.EvaluatePromotionResponse, error) {
+	if domain.NormalizePromoCode(req.Code) == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+	if len(req.Lines) > maxPromotionLines {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d lines per request", maxPromotionLines)
+	}
+	ids := make([]int64, 0, len(req.Lines))
+	for _, l := range req.Lines {
+		if l.ProductId <= 0 || l.Quantity <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "every line needs a product_id and a positive quantity")
+		}
+		ids = append(ids, l.ProductId)
+	}
+
+	promo, err := s.repo.GetPromotionByCode(ctx, req.Code)
+	if err != nil {
+		return nil, repoError(err, "failed to fetch promotion")
+	}
+	lines, err := s.promotionLines(ctx, promo, req.Lines, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := promo.Apply(lines, time.Now())
+	if errors.Is(err, domain.ErrPromotionNotApplicable) || errors.Is(err, money.ErrCurrencyMismatch) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to apply promotion: %v", err)
+	}
+
+	resp := &pb.EvaluatePromotionResponse{
+		Promotion:     toProtoPromotion(promo),
+		Lines:         make([]*pb.PromotionLineDiscount, len(lines)),
+		SubtotalMinor: result.Subtotal.Amount,
+		DiscountMinor: result.Discount.Amount,
+		TotalMinor:    result.Total().Amount,
+		Currency:      result.Subtotal.Currency,
+	}
+	for i, l := range lines {
+		resp.Lines[i] = &pb.PromotionLineDiscount{
+			ProductId:      l.ProductID,
+			VariantId:      l.VariantID,
+			UnitPriceMinor: l.UnitPrice.Amount,
+			SubtotalMinor:  l.UnitPrice.Multiply(l.Quantity).Amount,
+			DiscountMinor:  result.LineDiscounts[i].Amount,
+		}
+	}
+	return resp, nil
+}
+
+// promotionLines prices the requested lines and marks the ones the rules of the promotion cover
+func (s *ProductServiceServer) promotionLines(
+	ctx context.Context,
+	promo *domain.Promotion,
+	reqLines []*pb.PromotionLine,
+	ids []int64,
+) ([]domain.PromotionLine, error) {
+	products, err := s.repo.GetProductsByIds(ctx, ids)
+	if err != nil {
+		return nil, repoError(err, "failed to fetch products")
+	}
+	byID := make(map[int64]*domain.Product, len(products))
+	for _, p := range products {
+		byID[p.ID] = p
+	}
+
+	eligible := make(map[int64]bool)
+	if !promo.HasRules() {
+		for _, id := range ids {
+			eligible[id] = true
+		}
+	} else {
+		for _, id := range promo.ProductIDs {
+			eligible[id] = true
+		}
+		inCategories, err := s.repo.FilterProductsByCategories(ctx, ids, promo.CategoryIDs)
+		if err != nil {
+			return nil, repoError(err, "failed to match promotion categories")
+		}
+		for _, id := range inCategories {
+			eligible[id] = true
+		}
+	}
+
+	lines := make([]domain.PromotionLine, len(reqLines))
+	for i, l := range reqLines {
+		p, ok := byID[l.ProductId]
+		if !ok {
+			return nil, status.Errorf(codes.FailedPrecondition, "product %d not found", l.ProductId)
+		}
+		var v *domain.Variant
+		if l.VariantId != 0 {
+			if v = p.Variant(l.VariantId); v == nil {
+				return nil, status.Errorf(codes.FailedPrecondition, "variant %d of product %d not found", l.VariantId, l.ProductId)
+			}
+		}
+		lines[i] = domain.PromotionLine{
+			ProductID: l.ProductId,
+			VariantID: l.VariantId,
+			Quantity:  int64(l.Quantity),
+			UnitPrice: p.PriceOf(v),
+			Eligible:  eligible[l.ProductId],
+		}
+	}
+	return lines, nil
+}
+
+func (s *ProductServiceServer) RedeemPromotion(
+	ctx context.Context,
+	req *pb.RedeemPromotionRequest,
+) (*pb.RedeemPromotionResponse, error) {
+	if domain.NormalizePromoCode(req.Code) == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+	if req.CheckoutId == "" {
+		return nil, status.Error(codes.InvalidArgument, "checkout_id is required")
+	}
+
+	p, err := s.repo.RedeemPromotion(ctx, req.Code, req.CheckoutId, time.Now())
+	if err != nil {
+		return nil, repoError(err, "failed to redeem promotion")
+	}
+	return &pb.RedeemPromotionResponse{Promotion: toProtoPromotion(p)}, nil
+}
+
+func (s *ProductServiceServer) ReleasePromotion(
+	ctx context.Context,
+	req *pb.ReleasePromotionRequest,
+) (*pb.ReleasePromotionResponse, error) {
+	if domain.NormalizePromoCode(req.Code) == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+	if req.CheckoutId == "" {
+		return nil, status.Error(codes.InvalidArgument, "checkout_id is required")
+	}
+
+	p, err := s.repo.ReleasePromotion(ctx, req.Code, req.CheckoutId)
+	if err != nil {
+		return nil, repoError(err, "failed to release promotion")
+	}
+	return &pb.ReleasePromotionResponse{Promotion: toProtoPromotion(p)}, nil
+}
+
+func promotionKind(k pb.PromotionKind) domain.PromotionKind {
+	switch k {
+	case pb.PromotionKind_PROMOTION_KIND_PERCENTAGE:
+		return domain.PromotionPercentage
+	case pb.PromotionKind_PROMOTION_KIND_FIXED:
+		return domain.PromotionFixed
+	default:
+		return ""
+	}
+}
+
+func toProtoPromotion(p *domain.Promotion) *pb.Promotion {
+	kind := pb.PromotionKind_PROMOTION_KIND_UNSPECIFIED
+	switch p.Kind {
+	case domain.PromotionPercentage:
+		kind = pb.PromotionKind_PROMOTION_KIND_PERCENTAGE
+	case domain.PromotionFixed:
+		kind = pb.PromotionKind_PROMOTION_KIND_FIXED
+	}
+	return &pb.Promotion{
+		Id:            p.ID,
+		Code:          p.Code,
+		Kind:          kind,
+		Value:         p.Value,
+		Currency:      p.Currency,
+		MinSpendMinor: p.MinSpendMinor,
+		ProductIds:    p.ProductIDs,
+		CategoryIds:   p.CategoryIDs,
+		UsageLimit:    p.UsageLimit,
+		TimesUsed:     p.TimesUsed,
+		StartsAt:      formatOptionalTime(p.StartsAt),
+		ExpiresAt:     formatOptionalTime(p.ExpiresAt),
+	}
+}
+
+func parseOptionalTime(s, field string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%s must be an RFC3339 timestamp", field)
+	}
+	return &t, nil
+}
@@ -0,0 +1,194 @@
+package grpc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fjod/go_cart/pkg/money"
+	"github.com/fjod/go_cart/product-service/internal/domain"
+	grpcHandler "github.com/fjod/go_cart/product-service/internal/grpc"
+	pb "github.com/fjod/go_cart/product-service/pkg/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func promotionCatalog() []*domain.Product {
+	big := int64(1500)
+	return []*domain.Product{
+		{ID: 1, Name: "Laptop", Price: money.New(1000, "USD"), Variants: []*domain.Variant{
+			{ID: 7, ProductID: 1, SKU: "LAPTOP-BIG", PriceMinor: &big},
+		}},
+		{ID: 2, Name: "Mouse", Price: money.New(333, "USD")},
+		{ID: 3, Name: "Cable", Price: money.New(667, "USD")},
+	}
+}
+
+func evaluate(t *testing.T, promo *domain.Promotion, inCategories []int64, lines ...*pb.PromotionLine) (*pb.EvaluatePromotionResponse, error) {
+	t.Helper()
+	server := grpcHandler.NewProductServiceServer(&mockRepository{
+		products: promotionCatalog(), promotion: promo, inCategories: inCategories,
+	})
+	return server.EvaluatePromotion(context.Background(), &pb.EvaluatePromotionRequest{Code: promo.Code, Lines: lines})
+}
+
+func lineDiscounts(resp *pb.EvaluatePromotionResponse) []int64 {
+	d := make([]int64, len(resp.Lines))
+	for i, l := range resp.Lines {
+		d[i] = l.DiscountMinor
+	}
+	return d
+}
+
+func TestEvaluatePromotion_PercentageRoundsDownPerLine(t *testing.T) {
+	promo := &domain.Promotion{Code: "TEN", Kind: domain.PromotionPercentage, Value: 10}
+
+	resp, err := evaluate(t, promo, nil,
+		&pb.PromotionLine{ProductId: 1, VariantId: 7, Quantity: 1},
+		&pb.PromotionLine{ProductId: 2, Quantity: 3})
+
+	require.NoError(t, err)
+	assert.Equal(t, []int64{150, 99}, lineDiscounts(resp))
+	assert.Equal(t, int64(1500), resp.Lines[0].UnitPriceMinor)
+	assert.Equal(t, int64(2499), resp.SubtotalMinor)
+	assert.Equal(t, int64(249), resp.DiscountMinor)
+	assert.Equal(t, int64(2250), resp.TotalMinor)
+	assert.Equal(t, "USD", resp.Currency)
+}
+
+func TestEvaluatePromotion_FixedSpreadOverEligibleLines(t *testing.T) {
+	promo := &domain.Promotion{Code: "FIVE", Kind: domain.PromotionFixed, Value: 500, Currency: "USD", ProductIDs: []int64{2}}
+
+	resp, err := evaluate(t, promo, []int64{3},
+		&pb.PromotionLine{ProductId: 1, Quantity: 1},
+		&pb.PromotionLine{ProductId: 2, Quantity: 1},
+		&pb.PromotionLine{ProductId: 3, Quantity: 1})
+
+	require.NoError(t, err)
+	// 500 split 333:667, the cable line takes the cent lost to rounding
+	assert.Equal(t, []int64{0, 166, 334}, lineDiscounts(resp))
+	assert.Equal(t, int64(500), resp.DiscountMinor)
+}
+
+func TestEvaluatePromotion_FixedCappedAtEligibleSubtotal(t *testing.T) {
+	promo := &domain.Promotion{Code: "BIG", Kind: domain.PromotionFixed, Value: 5000, Currency: "USD", ProductIDs: []int64{2}}
+
+	resp, err := evaluate(t, promo, nil,
+		&pb.PromotionLine{ProductId: 1, Quantity: 1},
+		&pb.PromotionLine{ProductId: 2, Quantity: 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, []int64{0, 666}, lineDiscounts(resp))
+	assert.Equal(t, int64(1000), resp.TotalMinor)
+}
+
+func TestEvaluatePromotion_NotApplicable(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	limit := int64(2)
+	mouse := &pb.PromotionLine{ProductId: 2, Quantity: 1}
+
+	for name, promo := range map[string]*domain.Promotion{
+		"expired":        {Code: "OLD", Kind: domain.PromotionPercentage, Value: 10, ExpiresAt: &past},
+		"not started":    {Code: "SOON", Kind: domain.PromotionPercentage, Value: 10, StartsAt: &future},
+		"used up":        {Code: "GONE", Kind: domain.PromotionPercentage, Value: 10, UsageLimit: &limit, TimesUsed: 2},
+		"min spend":      {Code: "MIN", Kind: domain.PromotionPercentage, Value: 10, Currency: "USD", MinSpendMinor: 5000},
+		"other currency": {Code: "EURO", Kind: domain.PromotionFixed, Value: 100, Currency: "EUR"},
+		"no line covers": {Code: "LAPTOPS", Kind: domain.PromotionPercentage, Value: 10, ProductIDs: []int64{1}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := evaluate(t, promo, nil, mouse)
+			assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+			assert.Contains(t, status.Convert(err).Message(), "promo code cannot be applied")
+		})
+	}
+}
+
+func TestEvaluatePromotion_UnknownCode(t *testing.T) {
+	server := grpcHandler.NewProductServiceServer(&mockRepository{products: promotionCatalog()})
+
+	_, err := server.EvaluatePromotion(context.Background(), &pb.EvaluatePromotionRequest{
+		Code: "NOPE", Lines: []*pb.PromotionLine{{ProductId: 2, Quantity: 1}},
+	})
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestEvaluatePromotion_UnknownProduct(t *testing.T) {
+	promo := &domain.Promotion{Code: "TEN", Kind: domain.PromotionPercentage, Value: 10}
+
+	_, err := evaluate(t, promo, nil, &pb.PromotionLine{ProductId: 99, Quantity: 1})
+
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestCreatePromotion_Success(t *testing.T) {
+	mockRepo := &mockRepository{}
+	server := grpcHandler.NewProductServiceServer(mockRepo)
+	limit := int64(100)
+
+	resp, err := server.CreatePromotion(context.Background(), &pb.CreatePromotionRequest{
+		Code:        " spring-10 ",
+		Kind:        pb.PromotionKind_PROMOTION_KIND_PERCENTAGE,
+		Value:       10,
+		CategoryIds: []int64{2},
+		UsageLimit:  &limit,
+		ExpiresAt:   "2026-06-01T00:00:00Z",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "SPRING-10", resp.Promotion.Code)
+	assert.Equal(t, domain.PromotionPercentage, mockRepo.promotion.Kind)
+	assert.Equal(t, "2026-06-01T00:00:00Z", resp.Promotion.ExpiresAt)
+	assert.Equal(t, int64(100), resp.Promotion.GetUsageLimit())
+}
+
+func TestCreatePromotion_InvalidArgument(t *testing.T) {
+	server := grpcHandler.NewProductServiceServer(&mockRepository{})
+	zero := int64(0)
+
+	for name, req := range map[string]*pb.CreatePromotionRequest{
+		"short code":           {Code: "AB", Kind: pb.PromotionKind_PROMOTION_KIND_PERCENTAGE, Value: 10},
+		"code with spaces":     {Code: "TEN OFF", Kind: pb.PromotionKind_PROMOTION_KIND_PERCENTAGE, Value: 10},
+		"missing kind":         {Code: "TEN", Value: 10},
+		"percent over 100":     {Code: "TEN", Kind: pb.PromotionKind_PROMOTION_KIND_PERCENTAGE, Value: 101},
+		"fixed needs currency": {Code: "TEN", Kind: pb.PromotionKind_PROMOTION_KIND_FIXED, Value: 1000},
+		"zero usage limit":     {Code: "TEN", Kind: pb.PromotionKind_PROMOTION_KIND_PERCENTAGE, Value: 10, UsageLimit: &zero},
+		"bad expiry":           {Code: "TEN", Kind: pb.PromotionKind_PROMOTION_KIND_PERCENTAGE, Value: 10, ExpiresAt: "soon"},
+		"expires before start": {Code: "TEN", Kind: pb.PromotionKind_PROMOTION_KIND_PERCENTAGE, Value: 10,
+			StartsAt: "2026-06-01T00:00:00Z", ExpiresAt: "2026-05-01T00:00:00Z"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := server.CreatePromotion(context.Background(), req)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
+
+func TestRedeemPromotion(t *testing.T) {
+	mockRepo := &mockRepository{promotion: &domain.Promotion{Code: "TEN", Kind: domain.PromotionPercentage, Value: 10}}
+	server := grpcHandler.NewProductServiceServer(mockRepo)
+
+	resp, err := server.RedeemPromotion(context.Background(), &pb.RedeemPromotionRequest{Code: "ten", CheckoutId: "chk-1"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), resp.Promotion.TimesUsed)
+	assert.Equal(t, "chk-1", mockRepo.redeemed)
+
+	_, err = server.RedeemPromotion(context.Background(), &pb.RedeemPromotionRequest{Code: "ten"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestReleasePromotion(t *testing.T) {
+	mockRepo := &mockRepository{promotion: &domain.Promotion{Code: "TEN", Kind: domain.PromotionPercentage, Value: 10, TimesUsed: 1}}
+	server := grpcHandler.NewProductServiceServer(mockRepo)
+
+	resp, err := server.ReleasePromotion(context.Background(), &pb.ReleasePromotionRequest{Code: "ten", CheckoutId: "chk-1"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), resp.Promotion.TimesUsed)
+	assert.Equal(t, "chk-1", mockRepo.released)
+
+	_, err = server.ReleasePromotion(context.Background(), &pb.ReleasePromotionRequest{CheckoutId: "chk-1"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,66 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	pb "github.com/fjod/go_cart/product-service/pkg/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const maxSearchQueryLength = 200
+
+func (s *ProductServiceServer) SearchProducts(
+	ctx context.Context,
+	req *pb.SearchProductsRequest,
+) (*pb.SearchProductsResponse, error) {
+	query := strings.TrimSpace(req.Query)
+	if query == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+	if utf8.RuneCountInString(query) > maxSearchQueryLength {
+		return nil, status.Errorf(codes.InvalidArgument, "query must be at most %d characters", maxSearchQueryLength)
+	}
+
+	pageSize := int(req.PageSize)
+	switch {
+	case pageSize < 0:
+		return nil, status.Error(codes.InvalidArgument, "page_size must not be negative")
+	case pageSize == 0:
+		pageSize = defaultPageSize
+	case pageSize > maxPageSize:
+		pageSize = maxPageSize
+	}
+
+	offset := 0
+	if req.PageToken != "" {
+		var err error
+		if offset, err = decodeSearchPageToken(req.PageToken, query); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	// one extra hit tells whether another page exists
+	hits, err := s.repo.SearchProducts(ctx, query, pageSize+1, offset)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to search products: %v", err)
+	}
+
+	resp := &pb.SearchProductsResponse{}
+	if len(hits) > pageSize {
+		hits = hits[:pageSize]
+		resp.NextPageToken = encodeSearchPageToken(offset+pageSize, query)
+	}
+	resp.Results = make([]*pb.ProductSearchResult, len(hits))
+	for i, h := range hits {
+		resp.Results[i] = &pb.ProductSearchResult{
+			Product:         toProtoProduct(h.Product),
+			NameHighlighted: h.NameHighlight,
+			Snippet:         h.Snippet,
+			Score:           h.Score,
+		}
+	}
+	return resp, nil
+}
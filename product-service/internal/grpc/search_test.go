@@ -0,0 +1,98 @@
+package grpc_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/fjod/go_cart/pkg/money"
+	"github.com/fjod/go_cart/product-service/internal/domain"
+	grpcHandler "github.com/fjod/go_cart/product-service/internal/grpc"
+	pb "github.com/fjod/go_cart/product-service/pkg/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func searchHits(n int) []*domain.ProductSearchHit {
+	hits := make([]*domain.ProductSearchHit, n)
+	for i := range hits {
+		hits[i] = &domain.ProductSearchHit{
+			Product:       &domain.Product{ID: int64(i + 1), Name: "Lamp", Price: money.New(1000, "USD")},
+			NameHighlight: "<mark>Lamp</mark>",
+			Snippet:       "<mark>Lamp</mark>",
+			Score:         float64(n - i),
+		}
+	}
+	return hits
+}
+
+func TestSearchProducts_MapsHits(t *testing.T) {
+	server := grpcHandler.NewProductServiceServer(&mockRepository{hits: searchHits(1)})
+
+	resp, err := server.SearchProducts(context.Background(), &pb.SearchProductsRequest{Query: "lamp"})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 1)
+	assert.Equal(t, int64(1), resp.Results[0].Product.Id)
+	assert.Equal(t, "<mark>Lamp</mark>", resp.Results[0].NameHighlighted)
+	assert.Equal(t, "<mark>Lamp</mark>", resp.Results[0].Snippet)
+	assert.Equal(t, 1.0, resp.Results[0].Score)
+	assert.Empty(t, resp.NextPageToken)
+}
+
+func TestSearchProducts_PageTokenRoundTrip(t *testing.T) {
+	mockRepo := &mockRepository{hits: searchHits(3)}
+	server := grpcHandler.NewProductServiceServer(mockRepo)
+	req := &pb.SearchProductsRequest{Query: "lamp", PageSize: 2}
+
+	first, err := server.SearchProducts(context.Background(), req)
+	require.NoError(t, err)
+	assert.Len(t, first.Results, 2)
+	assert.Equal(t, 3, mockRepo.searchLimit)
+	assert.NotEmpty(t, first.NextPageToken)
+
+	mockRepo.hits = searchHits(1)
+	req.PageToken = first.NextPageToken
+	second, err := server.SearchProducts(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, 2, mockRepo.searchOffset)
+	assert.Empty(t, second.NextPageToken)
+
+	// the token only continues the search it came from
+	req.Query = "desk"
+	_, err = server.SearchProducts(context.Background(), req)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestSearchProducts_InvalidArguments(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *pb.SearchProductsRequest
+	}{
+		{"empty query", &pb.SearchProductsRequest{}},
+		{"blank query", &pb.SearchProductsRequest{Query: "   "}},
+		{"query too long", &pb.SearchProductsRequest{Query: strings.Repeat("a", 201)}},
+		{"negative page size", &pb.SearchProductsRequest{Query: "lamp", PageSize: -1}},
+		{"garbage token", &pb.SearchProductsRequest{Query: "lamp", PageToken: "not-a-token"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := grpcHandler.NewProductServiceServer(&mockRepository{})
+
+			_, err := server.SearchProducts(context.Background(), tt.req)
+
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
+
+func TestSearchProducts_RepositoryError(t *testing.T) {
+	server := grpcHandler.NewProductServiceServer(&mockRepository{err: errors.New("disk I/O error")})
+
+	_, err := server.SearchProducts(context.Background(), &pb.SearchProductsRequest{Query: "lamp"})
+
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
@@ -0,0 +1,33 @@
+package grpc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fjod/go_cart/pkg/money"
+	"github.com/fjod/go_cart/product-service/internal/domain"
+	grpcHandler "github.com/fjod/go_cart/product-service/internal/grpc"
+	pb "github.com/fjod/go_cart/product-service/pkg/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetProduct_MapsVariantPrices(t *testing.T) {
+	override := int64(159999)
+	laptop := &domain.Product{ID: 1, Name: "Laptop", Price: money.New(129999, "USD")}
+	laptop.Variants = []*domain.Variant{
+		{ID: 1, ProductID: 1, SKU: "LAPTOP-16GB-512GB", Attributes: map[string]string{"memory": "16GB"}},
+		{ID: 2, ProductID: 1, SKU: "LAPTOP-32GB-1TB", PriceMinor: &override},
+	}
+	server := grpcHandler.NewProductServiceServer(&mockRepository{products: []*domain.Product{laptop}})
+
+	resp, err := server.GetProduct(context.Background(), &pb.GetProductRequest{Id: 1})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Product.Variants, 2)
+	assert.Equal(t, int64(129999), resp.Product.Variants[0].PriceMinor)
+	assert.Equal(t, "16GB", resp.Product.Variants[0].Attributes["memory"])
+	assert.Equal(t, int64(159999), resp.Product.Variants[1].PriceMinor)
+	assert.Equal(t, "USD", resp.Product.Variants[1].Currency)
+	assert.Equal(t, "LAPTOP-32GB-1TB", resp.Product.Variants[1].Sku)
+}
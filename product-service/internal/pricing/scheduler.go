@@ -0,0 +1,51 @@
+package pricing
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// PriceApplier applies the scheduled prices that are due at now and returns the products whose price changed
+type PriceApplier interface {
+	ApplyScheduledPrices(ctx context.Context, now time.Time) ([]int64, error)
+}
+
+// Scheduler starts and ends scheduled prices (sales) on a ticker
+type Scheduler struct {
+	tick   time.Duration
+	repo   PriceApplier
+	now    func() time.Time
+	logger *slog.Logger
+}
+
+func NewScheduler(repo PriceApplier, tick time.Duration, log *slog.Logger) *Scheduler {
+	return &Scheduler{tick, repo, time.Now, log}
+}
+
+// Run applies due prices right away and then on every tick until ctx is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.tick)
+	defer ticker.Stop()
+
+	s.applyDuePrices(ctx)
+	for {
+		select {
+		case <-ticker.C:
+			s.applyDuePrices(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Scheduler) applyDuePrices(ctx context.Context) {
+	changed, err := s.repo.ApplyScheduledPrices(ctx, s.now().UTC())
+	if err != nil {
+		s.logger.Error("failed to apply scheduled prices", "error", err)
+		return
+	}
+	if len(changed) > 0 {
+		s.logger.Info("applied scheduled prices", "product_ids", changed)
+	}
+}
@@ -0,0 +1,67 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeApplier struct {
+	mu    sync.Mutex
+	calls []time.Time
+	err   error
+}
+
+func (f *fakeApplier) ApplyScheduledPrices(_ context.Context, now time.Time) ([]int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, now)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return []int64{1}, nil
+}
+
+func (f *fakeApplier) callCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.calls)
+}
+
+func runScheduler(t *testing.T, applier *fakeApplier) {
+	s := NewScheduler(applier, 10*time.Millisecond, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return applier.callCount() >= 3 }, time.Second, 5*time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler did not stop after cancel")
+	}
+}
+
+func TestScheduler_AppliesOnEveryTickUntilCancelled(t *testing.T) {
+	applier := &fakeApplier{}
+	runScheduler(t, applier)
+
+	for _, at := range applier.calls {
+		assert.Equal(t, time.UTC, at.Location())
+	}
+}
+
+func TestScheduler_KeepsRunningAfterError(t *testing.T) {
+	applier := &fakeApplier{err: errors.New("database is locked")}
+	runScheduler(t, applier)
+}
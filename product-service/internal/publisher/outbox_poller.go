@@ -0,0 +1,140 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	pk "github.com/fjod/go_cart/pkg/tracing"
+	"github.com/fjod/go_cart/product-service/internal/domain"
+	r "github.com/fjod/go_cart/product-service/internal/repository"
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+)
+
+// Topic carries ProductCreated, ProductUpdated, ProductPriceChanged and ProductArchived events keyed by product id
+const Topic = "product-events"
+
+const batchSize = 100
+
+// errUnpublishable marks events that fail the same way on every attempt, retrying them would block the outbox for good
+var errUnpublishable = errors.New("event can't be published")
+
+// OutboxStore is the part of the repository the poller reads the outbox through
+type OutboxStore interface {
+	GetUnprocessedEvents(ctx context.Context, limit int) ([]*r.OutboxEvent, error)
+	MarkEventAsProcessed(ctx context.Context, id int64) error
+	MarkEventAsFailed(ctx context.Context, id int64, reason string) error
+}
+
+type messageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// OutboxPoller publishes the catalog events written to the outbox to the product-events topic
+type OutboxPoller struct {
+	eventTick time.Duration
+	repo      OutboxStore
+	writer    messageWriter
+	logger    *slog.Logger
+}
+
+func NewOutboxPoller(repo OutboxStore, log *slog.Logger, brokers ...string) *OutboxPoller {
+	w := &kafka.Writer{
+		Addr:                   kafka.TCP(brokers...),
+		Topic:                  Topic,
+		Balancer:               &kafka.Hash{}, // events of one product stay in order on one partition
+		AllowAutoTopicCreation: true,
+	}
+	return &OutboxPoller{time.Second, repo, w, log}
+}
+
+func (p *OutboxPoller) Run(ctx context.Context) {
+	eventTicker := time.NewTicker(p.eventTick)
+	defer eventTicker.Stop()
+	for {
+		select {
+		case <-eventTicker.C:
+			p.processUnpublishedEvents(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (p *OutboxPoller) Close() error {
+	return p.writer.Close()
+}
+
+// processUnpublishedEvents stops at the first event Kafka doesn't take, so a later price change
+// never overtakes an earlier one, and retries it on the next tick. An event that can never be
+// published is set aside as failed and the events after it go out.
+func (p *OutboxPoller) processUnpublishedEvents(ctx context.Context) {
+	events, err := p.repo.GetUnprocessedEvents(ctx, batchSize)
+	if err != nil {
+		p.logger.Error("failed to fetch unprocessed events", "error", err)
+		return
+	}
+
+	for _, event := range events {
+		if err := p.publishToKafka(ctx, event); err != nil {
+			if errors.Is(err, errUnpublishable) {
+				p.logger.Error("setting aside event that can't be published", "event_id", event.ID, "error", err)
+				if err := p.repo.MarkEventAsFailed(ctx, event.ID, err.Error()); err != nil {
+					p.logger.Error("failed to mark event as failed", "event_id", event.ID, "error", err)
+					return
+				}
+				continue
+			}
+			p.logger.Error("failed to publish event", "event_id", event.ID, "error", err)
+			return
+		}
+
+		if err := p.repo.MarkEventAsProcessed(ctx, event.ID); err != nil {
+			p.logger.Error("failed to mark event as processed", "event_id", event.ID, "error", err)
+			return
+		}
+	}
+}
+
+func (p *OutboxPoller) publishToKafka(ctx context.Context, event *r.OutboxEvent) error {
+	eventType, err := publishedType(event)
+	if err != nil {
+		return err
+	}
+
+	tr := otel.Tracer("kafka")
+	spanCtx, messageSpan := tr.Start(ctx, fmt.Sprintf("kafka - publish - %s", eventType))
+	defer messageSpan.End()
+
+	headers := []kafka.Header{
+		{Key: "event_type", Value: []byte(eventType)},
+	}
+	for k, v := range pk.Inject(spanCtx) {
+		headers = append(headers, kafka.Header{Key: k, Value: []byte(v)})
+	}
+
+	msg := kafka.Message{
+		Key:     []byte(strconv.FormatInt(event.AggregateID, 10)),
+		Value:   event.Payload,
+		Headers: headers,
+	}
+	return p.writer.WriteMessages(spanCtx, msg)
+}
+
+// publishedType is the event type of the row, rows written as ProductChanged are typed by their payload's change
+func publishedType(event *r.OutboxEvent) (string, error) {
+	if event.EventType != domain.ProductChangedEventType {
+		return event.EventType, nil
+	}
+	var payload domain.ProductChangedEvent
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return "", fmt.Errorf("%w: decode event %d: %v", errUnpublishable, event.ID, err)
+	}
+	return payload.Change.EventType(), nil
+}
@@ -0,0 +1,148 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/fjod/go_cart/product-service/internal/domain"
+	r "github.com/fjod/go_cart/product-service/internal/repository"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type fakeStore struct {
+	events    []*r.OutboxEvent
+	processed []int64
+	failed    []int64
+}
+
+func (f *fakeStore) GetUnprocessedEvents(context.Context, int) ([]*r.OutboxEvent, error) {
+	return f.events, nil
+}
+
+func (f *fakeStore) MarkEventAsProcessed(_ context.Context, id int64) error {
+	f.processed = append(f.processed, id)
+	return nil
+}
+
+func (f *fakeStore) MarkEventAsFailed(_ context.Context, id int64, _ string) error {
+	f.failed = append(f.failed, id)
+	return nil
+}
+
+type fakeWriter struct {
+	messages []kafka.Message
+	failOn   int // 1-based index of the write that fails, 0 never fails
+}
+
+func (f *fakeWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	if f.failOn == len(f.messages)+1 {
+		return errors.New("broker unavailable")
+	}
+	f.messages = append(f.messages, msgs...)
+	return nil
+}
+
+func (f *fakeWriter) Close() error { return nil }
+
+func newTestPoller(store *fakeStore, writer *fakeWriter) *OutboxPoller {
+	return &OutboxPoller{repo: store, writer: writer, logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+}
+
+func payload(t *testing.T, change domain.ProductChange) []byte {
+	b, err := json.Marshal(domain.ProductChangedEvent{ProductID: 7, Change: change})
+	require.NoError(t, err)
+	return b
+}
+
+func header(msg kafka.Message, key string) string {
+	for _, h := range msg.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func TestProcessUnpublishedEvents_PublishesAndMarks(t *testing.T) {
+	store := &fakeStore{events: []*r.OutboxEvent{
+		{ID: 1, AggregateID: 7, EventType: domain.ProductCreatedEventType, Payload: payload(t, domain.ProductChangeCreated)},
+		{ID: 2, AggregateID: 7, EventType: domain.ProductPriceChangedEventType, Payload: payload(t, domain.ProductChangePriceChanged)},
+	}}
+	writer := &fakeWriter{}
+
+	newTestPoller(store, writer).processUnpublishedEvents(context.Background())
+
+	require.Len(t, writer.messages, 2)
+	assert.Equal(t, "7", string(writer.messages[0].Key))
+	assert.Equal(t, domain.ProductCreatedEventType, header(writer.messages[0], "event_type"))
+	assert.Equal(t, domain.ProductPriceChangedEventType, header(writer.messages[1], "event_type"))
+	assert.Equal(t, []int64{1, 2}, store.processed)
+}
+
+func TestProcessUnpublishedEvents_StopsAtFirstFailure(t *testing.T) {
+	store := &fakeStore{events: []*r.OutboxEvent{
+		{ID: 1, AggregateID: 7, EventType: domain.ProductPriceChangedEventType, Payload: payload(t, domain.ProductChangePriceChanged)},
+		{ID: 2, AggregateID: 7, EventType: domain.ProductPriceChangedEventType, Payload: payload(t, domain.ProductChangePriceChanged)},
+	}}
+	writer := &fakeWriter{failOn: 1}
+
+	newTestPoller(store, writer).processUnpublishedEvents(context.Background())
+
+	assert.Empty(t, writer.messages)
+	assert.Empty(t, store.processed)
+}
+
+func TestProcessUnpublishedEvents_SetsAsideUndecodableEvents(t *testing.T) {
+	store := &fakeStore{events: []*r.OutboxEvent{
+		{ID: 1, AggregateID: 7, EventType: domain.ProductChangedEventType, Payload: []byte("{not json")},
+		{ID: 2, AggregateID: 7, EventType: domain.ProductPriceChangedEventType, Payload: payload(t, domain.ProductChangePriceChanged)},
+	}}
+	writer := &fakeWriter{}
+
+	newTestPoller(store, writer).processUnpublishedEvents(context.Background())
+
+	assert.Equal(t, []int64{1}, store.failed)
+	require.Len(t, writer.messages, 1)
+	assert.Equal(t, domain.ProductPriceChangedEventType, header(writer.messages[0], "event_type"))
+	assert.Equal(t, []int64{2}, store.processed)
+}
+
+func TestProcessUnpublishedEvents_TypesLegacyRowsByPayload(t *testing.T) {
+	store := &fakeStore{events: []*r.OutboxEvent{
+		{ID: 1, AggregateID: 7, EventType: domain.ProductChangedEventType, Payload: payload(t, domain.ProductChangeArchived)},
+	}}
+	writer := &fakeWriter{}
+
+	newTestPoller(store, writer).processUnpublishedEvents(context.Background())
+
+	require.Len(t, writer.messages, 1)
+	assert.Equal(t, domain.ProductArchivedEventType, header(writer.messages[0], "event_type"))
+}
+
+func TestPublishToKafka_CarriesTraceContext(t *testing.T) {
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(previous) })
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled,
+	}))
+	writer := &fakeWriter{}
+	event := &r.OutboxEvent{ID: 1, AggregateID: 7, EventType: domain.ProductCreatedEventType, Payload: payload(t, domain.ProductChangeCreated)}
+
+	require.NoError(t, newTestPoller(&fakeStore{}, writer).publishToKafka(ctx, event))
+
+	require.Len(t, writer.messages, 1)
+	assert.Contains(t, header(writer.messages[0], "traceparent"), traceID.String())
+}
@@ -0,0 +1,361 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fjod/go_cart/pkg/money"
+	"github.com/fjod/go_cart/product-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testRepositoryContract is what every storage backend has to provide. open returns a repository
+// on a freshly migrated database holding only the seed catalog.
+func testRepositoryContract(t *testing.T, open func(t *testing.T) *Repository) {
+	ctx := context.Background()
+
+	t.Run("seed catalog", func(t *testing.T) {
+		repo := open(t)
+
+		all, err := repo.GetAllProducts(ctx)
+		require.NoError(t, err)
+		assert.Len(t, all, 5)
+
+		laptop, err := repo.GetProduct(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "PRD-1", laptop.SKU)
+		assert.Equal(t, money.New(129999, "USD"), laptop.Price)
+		require.Len(t, laptop.Variants, 2)
+		assert.Equal(t, "32GB", laptop.Variants[1].Attributes["memory"])
+
+		_, err = repo.GetProduct(ctx, 999)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+
+		byIDs, err := repo.GetProductsByIds(ctx, []int64{3, 1, 999})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1, 3}, productIDs(byIDs))
+	})
+
+	t.Run("list products pages every sort", func(t *testing.T) {
+		repo := open(t)
+		lamp := &domain.Product{Name: "Desk Lamp", Price: money.New(2599, "USD")}
+		require.NoError(t, repo.CreateProduct(ctx, lamp))
+
+		cases := map[domain.ProductSort][]int64{
+			domain.ProductSortID:        {1, 2, 3, 4, 5, lamp.ID},
+			domain.ProductSortPrice:     {lamp.ID, 2, 3, 5, 4, 1},
+			domain.ProductSortName:      {lamp.ID, 5, 3, 1, 4, 2},
+			domain.ProductSortCreatedAt: {1, 2, 3, 4, 5, lamp.ID},
+		}
+		for sort, want := range cases {
+			assert.Equal(t, want, listPages(t, repo, domain.ProductQuery{SortBy: sort, PageSize: 4}), "sort %d", sort)
+		}
+
+		descending := listPages(t, repo, domain.ProductQuery{SortBy: domain.ProductSortPrice, Descending: true, PageSize: 2})
+		assert.Equal(t, []int64{1, 4, 5, 3, 2, lamp.ID}, descending)
+
+		minPrice := int64(8999)
+		assert.Equal(t, []int64{1, 3, 4, 5}, listPages(t, repo, domain.ProductQuery{MinPriceMinor: &minPrice, PageSize: 10}))
+	})
+
+	t.Run("categories", func(t *testing.T) {
+		repo := open(t)
+
+		categories, err := repo.ListCategories(ctx)
+		require.NoError(t, err)
+		require.Len(t, categories, 7)
+		assert.Equal(t, "electronics", categories[0].Slug)
+
+		assert.Equal(t, []int64{2, 3, 5}, listPages(t, repo, domain.ProductQuery{CategoryID: 4, PageSize: 10}))
+		assert.Equal(t, []int64{1, 2, 3, 4, 5}, listPages(t, repo, domain.ProductQuery{CategoryID: 2, IncludeDescendants: true, PageSize: 10}))
+
+		_, _, err = repo.ListProducts(ctx, domain.ProductQuery{CategoryID: 999, PageSize: 10})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+
+		breadcrumbs, err := repo.GetBreadcrumbs(ctx, 5)
+		require.NoError(t, err)
+		require.Len(t, breadcrumbs, 2)
+		assert.Equal(t, []string{"electronics", "computers", "computer-accessories"}, slugs(breadcrumbs[0]))
+		assert.Equal(t, []string{"electronics", "audio", "headphones"}, slugs(breadcrumbs[1]))
+	})
+
+	t.Run("search", func(t *testing.T) {
+		repo := open(t)
+		rest := &domain.Product{Name: "Wrist Rest", Description: "Soft rest for any keyboard", Price: money.New(1999, "USD")}
+		require.NoError(t, repo.CreateProduct(ctx, rest))
+
+		hits, err := repo.SearchProducts(ctx, "wireless", 10, 0)
+		require.NoError(t, err)
+		require.Len(t, hits, 1)
+		assert.Equal(t, int64(2), hits[0].Product.ID)
+		assert.Contains(t, hits[0].Snippet, "<mark>wireless</mark>")
+		assert.Positive(t, hits[0].Score)
+
+		hits, err = repo.SearchProducts(ctx, "keyboard", 10, 0)
+		require.NoError(t, err)
+		require.Len(t, hits, 2)
+		assert.Equal(t, []int64{3, rest.ID}, []int64{hits[0].Product.ID, hits[1].Product.ID})
+		assert.Equal(t, "<mark>Keyboard</mark>", hits[0].NameHighlight)
+
+		hits, err = repo.SearchProducts(ctx, "head", 10, 0)
+		require.NoError(t, err)
+		require.Len(t, hits, 1)
+		assert.Equal(t, int64(5), hits[0].Product.ID)
+
+		hits, err = repo.SearchProducts(ctx, "monitors", 10, 0)
+		require.NoError(t, err)
+		require.Len(t, hits, 1)
+		assert.Equal(t, int64(4), hits[0].Product.ID)
+
+		for _, q := range []string{`"`, `'`, `laptop OR`, `NEAR(laptop`, `-`, `*`, `4K"`, `a\b`, `a:b & !c`, "   "} {
+			_, err := repo.SearchProducts(ctx, q, 10, 0)
+			assert.NoError(t, err, "query %q", q)
+		}
+
+		_, err = repo.ArchiveProduct(ctx, 2)
+		require.NoError(t, err)
+		hits, err = repo.SearchProducts(ctx, "wireless", 10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, hits)
+	})
+
+	t.Run("admin writes and outbox", func(t *testing.T) {
+		repo := open(t)
+
+		p := &domain.Product{Name: "Desk Lamp", Description: "LED", Price: money.New(2599, "EUR")}
+		require.NoError(t, repo.CreateProduct(ctx, p))
+		assert.Equal(t, fmt.Sprintf("PRD-%d", p.ID), p.SKU)
+		assert.Greater(t, p.ID, int64(5))
+
+		err := repo.CreateProduct(ctx, &domain.Product{SKU: p.SKU, Name: "Copy", Price: money.New(1, "EUR")})
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+		p.Price = money.New(1999, "EUR")
+		require.NoError(t, repo.UpdateProduct(ctx, p))
+		_, err = repo.ArchiveProduct(ctx, p.ID)
+		require.NoError(t, err)
+		_, err = repo.ArchiveProduct(ctx, p.ID)
+		require.NoError(t, err)
+
+		err = repo.UpdateProduct(ctx, p)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+		err = repo.UpdateProduct(ctx, &domain.Product{ID: 999, Name: "x", Price: money.New(1, "USD")})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+
+		bySKU, err := repo.GetProductBySKU(ctx, p.SKU)
+		require.NoError(t, err)
+		assert.True(t, bySKU.IsArchived())
+		exported, err := repo.GetProductsForExport(ctx)
+		require.NoError(t, err)
+		assert.Len(t, exported, 6)
+
+		events := outboxEvents(t, repo, p.ID)
+		require.Len(t, events, 3)
+		assert.Equal(t, domain.ProductChangeCreated, events[0].Change)
+		assert.Equal(t, domain.ProductChangePriceChanged, events[1].Change)
+		require.NotNil(t, events[1].PreviousPriceMinor)
+		assert.Equal(t, int64(2599), *events[1].PreviousPriceMinor)
+		assert.Equal(t, domain.ProductChangeArchived, events[2].Change)
+
+		pending, err := repo.GetUnprocessedEvents(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, pending, 3)
+		assert.Equal(t, domain.ProductCreatedEventType, pending[0].EventType)
+		require.NoError(t, repo.MarkEventAsProcessed(ctx, pending[0].ID))
+		pending, err = repo.GetUnprocessedEvents(ctx, 10)
+		require.NoError(t, err)
+		assert.Len(t, pending, 2)
+		require.NoError(t, repo.MarkEventAsFailed(ctx, pending[0].ID, "decode event: bad payload"))
+		pending, err = repo.GetUnprocessedEvents(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, domain.ProductArchivedEventType, pending[0].EventType)
+	})
+
+	t.Run("price history and scheduled prices", func(t *testing.T) {
+		repo := open(t)
+
+		p := &domain.Product{Name: "Desk Lamp", Price: money.New(2599, "EUR")}
+		require.NoError(t, repo.CreateProduct(ctx, p))
+		beforeSale := time.Now()
+
+		start := time.Now().Add(time.Hour)
+		end := start.Add(24 * time.Hour)
+		sale := &domain.ScheduledPrice{ProductID: p.ID, Price: money.New(1999, ""), StartsAt: start, EndsAt: &end}
+		require.NoError(t, repo.SchedulePrice(ctx, sale))
+		assert.NotZero(t, sale.ID)
+
+		changed, err := repo.ApplyScheduledPrices(ctx, start.Add(time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, []int64{p.ID}, changed)
+		changed, err = repo.ApplyScheduledPrices(ctx, start.Add(2*time.Minute))
+		require.NoError(t, err)
+		assert.Empty(t, changed)
+		changed, err = repo.ApplyScheduledPrices(ctx, end.Add(time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, []int64{p.ID}, changed)
+
+		during, err := repo.GetPriceAt(ctx, p.ID, start.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, money.New(1999, "EUR"), during.Price)
+		before, err := repo.GetPriceAt(ctx, p.ID, beforeSale)
+		require.NoError(t, err)
+		assert.Equal(t, money.New(2599, "EUR"), before.Price)
+		assert.Equal(t, 3, priceHistoryLen(t, repo, p.ID))
+
+		_, err = repo.GetPriceAt(ctx, p.ID, p.CreatedAt.Add(-time.Hour))
+		assert.Equal(t, codes.NotFound, status.Code(err))
+
+		_, err = repo.ArchiveProduct(ctx, 2)
+		require.NoError(t, err)
+		err = repo.SchedulePrice(ctx, &domain.ScheduledPrice{ProductID: 2, Price: money.New(100, ""), StartsAt: time.Now()})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("promotions", func(t *testing.T) {
+		repo := open(t)
+		expires := time.Now().Add(24 * time.Hour)
+		limit := int64(1)
+		promo := &domain.Promotion{
+			Code: "ACCESSORIES-10", Kind: domain.PromotionPercentage, Value: 10,
+			ProductIDs: []int64{1}, CategoryIDs: []int64{4}, UsageLimit: &limit, ExpiresAt: &expires,
+		}
+		require.NoError(t, repo.CreatePromotion(ctx, promo))
+		assert.NotZero(t, promo.ID)
+
+		err := repo.CreatePromotion(ctx, &domain.Promotion{Code: "ACCESSORIES-10", Kind: domain.PromotionFixed, Value: 1, Currency: "USD"})
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+		err = repo.CreatePromotion(ctx, &domain.Promotion{Code: "NOPE", Kind: domain.PromotionFixed, Value: 1, Currency: "USD", CategoryIDs: []int64{999}})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+
+		got, err := repo.GetPromotionByCode(ctx, "accessories-10")
+		require.NoError(t, err)
+		assert.Equal(t, domain.PromotionPercentage, got.Kind)
+		assert.Equal(t, []int64{1}, got.ProductIDs)
+		assert.Equal(t, []int64{4}, got.CategoryIDs)
+		require.NotNil(t, got.ExpiresAt)
+		assert.WithinDuration(t, expires, *got.ExpiresAt, time.Second)
+		_, err = repo.GetPromotionByCode(ctx, "MISSING")
+		assert.Equal(t, codes.NotFound, status.Code(err))
+
+		covered, err := repo.FilterProductsByCategories(ctx, []int64{1, 2, 3, 4, 5}, []int64{4})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{2, 3, 5}, covered)
+		covered, err = repo.FilterProductsByCategories(ctx, []int64{1, 4}, []int64{2})
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1, 4}, covered, "subcategories count")
+
+		redeemed, err := repo.RedeemPromotion(ctx, "ACCESSORIES-10", "checkout-1", time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), redeemed.TimesUsed)
+		redeemed, err = repo.RedeemPromotion(ctx, "ACCESSORIES-10", "checkout-1", time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), redeemed.TimesUsed, "a checkout is counted once")
+		assert.Error(t, redeemed.CheckActive(time.Now()))
+		_, err = repo.RedeemPromotion(ctx, "ACCESSORIES-10", "checkout-2", time.Now())
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err), "the limit holds for checkouts already running")
+
+		released, err := repo.ReleasePromotion(ctx, "ACCESSORIES-10", "checkout-1")
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), released.TimesUsed)
+		released, err = repo.ReleasePromotion(ctx, "ACCESSORIES-10", "checkout-1")
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), released.TimesUsed, "a checkout is given back once")
+		redeemed, err = repo.RedeemPromotion(ctx, "ACCESSORIES-10", "checkout-2", time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), redeemed.TimesUsed)
+	})
+}
+
+func TestRepositoryContract_SQLite(t *testing.T) {
+	testRepositoryContract(t, setupAdminDB)
+}
+
+func TestRepositoryContract_Postgres(t *testing.T) {
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+	ctx := context.Background()
+
+	pgContainer, err := postgres.Run(ctx,
+		"postgres:16-alpine",
+		postgres.WithDatabase("catalog"),
+		postgres.WithUsername("testuser"),
+		postgres.WithPassword("testpass"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(30*time.Second),
+		),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if err := pgContainer.Terminate(ctx); err != nil {
+			t.Logf("failed to terminate container: %s", err)
+		}
+	})
+
+	dsn, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
+	require.NoError(t, err)
+
+	// the catalog database is migrated once and every test gets a copy of it
+	template, err := NewPostgresRepository(dsn)
+	require.NoError(t, err)
+	require.NoError(t, template.RunMigrations("./migrations_postgres"))
+	require.NoError(t, template.Close())
+
+	admin, err := sql.Open("postgres", strings.Replace(dsn, "/catalog?", "/postgres?", 1))
+	require.NoError(t, err)
+	t.Cleanup(func() { admin.Close() })
+
+	copies := 0
+	testRepositoryContract(t, func(t *testing.T) *Repository {
+		copies++
+		name := fmt.Sprintf("catalog_%d", copies)
+		_, err := admin.ExecContext(ctx, "CREATE DATABASE "+name+" TEMPLATE catalog")
+		require.NoError(t, err)
+
+		repo, err := NewPostgresRepository(strings.Replace(dsn, "/catalog?", "/"+name+"?", 1))
+		require.NoError(t, err)
+		t.Cleanup(func() { repo.Close() })
+		return repo
+	})
+}
+
+// listPages follows the cursor to the last page and returns the ids in the order they were listed
+func listPages(t *testing.T, repo *Repository, q domain.ProductQuery) []int64 {
+	var ids []int64
+	for {
+		page, next, err := repo.ListProducts(context.Background(), q)
+		require.NoError(t, err)
+		ids = append(ids, productIDs(page)...)
+		if next == nil {
+			return ids
+		}
+		q.After = next
+	}
+}
+
+func productIDs(products []*domain.Product) []int64 {
+	ids := make([]int64, len(products))
+	for i, p := range products {
+		ids[i] = p.ID
+	}
+	return ids
+}
+
+func slugs(b domain.Breadcrumb) []string {
+	s := make([]string, len(b))
+	for i, c := range b {
+		s[i] = c.Slug
+	}
+	return s
+}
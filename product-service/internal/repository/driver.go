@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Driver names the database a Repository stores the catalog in
+type Driver string
+
+const (
+	DriverSQLite   Driver = "sqlite"
+	DriverPostgres Driver = "postgres"
+)
+
+// ParseDriver reads the DB_DRIVER setting, empty means sqlite
+func ParseDriver(s string) (Driver, error) {
+	switch Driver(strings.ToLower(s)) {
+	case "", DriverSQLite:
+		return DriverSQLite, nil
+	case DriverPostgres:
+		return DriverPostgres, nil
+	default:
+		return "", fmt.Errorf("unknown database driver %q, expected sqlite or postgres", s)
+	}
+}
+
+// rebind rewrites the ? placeholders every query of the package is written with into the driver's syntax
+func (d Driver) rebind(query string) string {
+	if d != DriverPostgres || !strings.Contains(query, "?") {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	inLiteral := false
+	for _, c := range query {
+		switch {
+		case c == '\'':
+			inLiteral = !inLiteral
+		case c == '?' && !inLiteral:
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// sqlDB is the database handle of a Repository, queries pass through the driver's rebind
+type sqlDB struct {
+	raw    *sql.DB
+	driver Driver
+}
+
+func (d *sqlDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return d.raw.ExecContext(ctx, d.driver.rebind(query), args...)
+}
+
+func (d *sqlDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return d.raw.QueryContext(ctx, d.driver.rebind(query), args...)
+}
+
+func (d *sqlDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return d.raw.QueryRowContext(ctx, d.driver.rebind(query), args...)
+}
+
+func (d *sqlDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sqlTx, error) {
+	tx, err := d.raw.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlTx{raw: tx, driver: d.driver}, nil
+}
+
+// sqlTx is a transaction of a Repository, queries pass through the driver's rebind
+type sqlTx struct {
+	raw    *sql.Tx
+	driver Driver
+}
+
+func (t *sqlTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return t.raw.ExecContext(ctx, t.driver.rebind(query), args...)
+}
+
+func (t *sqlTx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return t.raw.QueryContext(ctx, t.driver.rebind(query), args...)
+}
+
+func (t *sqlTx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return t.raw.QueryRowContext(ctx, t.driver.rebind(query), args...)
+}
+
+func (t *sqlTx) Commit() error {
+	return t.raw.Commit()
+}
+
+func (t *sqlTx) Rollback() error {
+	return t.raw.Rollback()
+}
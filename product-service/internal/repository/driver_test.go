@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebind_NumbersPlaceholdersForPostgres(t *testing.T) {
+	query := `SELECT ts_headline('english', name, q, 'a?b') FROM products WHERE id IN (?, ?) AND name = 'it''s?' LIMIT ?`
+
+	assert.Equal(t, query, DriverSQLite.rebind(query))
+	assert.Equal(t,
+		`SELECT ts_headline('english', name, q, 'a?b') FROM products WHERE id IN ($1, $2) AND name = 'it''s?' LIMIT $3`,
+		DriverPostgres.rebind(query))
+}
+
+func TestParseDriver(t *testing.T) {
+	d, err := ParseDriver("")
+	require.NoError(t, err)
+	assert.Equal(t, DriverSQLite, d)
+
+	d, err = ParseDriver("Postgres")
+	require.NoError(t, err)
+	assert.Equal(t, DriverPostgres, d)
+
+	_, err = ParseDriver("mysql")
+	assert.Error(t, err)
+}
+
+func TestTSQueryExpression(t *testing.T) {
+	assert.Equal(t, `'wireless' & 'mou':*`, tsQueryExpression("wireless mou"))
+	assert.Equal(t, `'it''s' & 'a\\b':*`, tsQueryExpression(`it's - a\b`))
+	assert.Empty(t, tsQueryExpression(`" * '`))
+}
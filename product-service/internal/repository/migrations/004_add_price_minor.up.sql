@@ -0,0 +1,5 @@
+ALTER TABLE products ADD COLUMN price_minor INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE products ADD COLUMN currency TEXT NOT NULL DEFAULT 'USD';
+
+-- price (REAL) is kept for older readers, price_minor is the source of truth from now on
+UPDATE products SET price_minor = CAST(ROUND(price * 100) AS INTEGER);
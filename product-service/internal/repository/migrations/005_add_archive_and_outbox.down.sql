@@ -0,0 +1,2 @@
+DROP TABLE outbox_events;
+ALTER TABLE products DROP COLUMN archived_at;
@@ -0,0 +1,13 @@
+-- archived products stay in the table so carts and orders that reference them keep resolving
+ALTER TABLE products ADD COLUMN archived_at TIMESTAMP;
+
+CREATE TABLE outbox_events (
+                               id INTEGER PRIMARY KEY AUTOINCREMENT,
+                               aggregate_id INTEGER NOT NULL, -- product id
+                               event_type TEXT NOT NULL,
+                               payload TEXT NOT NULL,
+                               created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                               processed_at TIMESTAMP
+);
+
+CREATE INDEX idx_outbox_unprocessed ON outbox_events(processed_at) WHERE processed_at IS NULL;
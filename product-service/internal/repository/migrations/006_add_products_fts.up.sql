@@ -0,0 +1,23 @@
+-- external content FTS5 index over the catalog text, products stays the source of truth
+CREATE VIRTUAL TABLE products_fts USING fts5(
+    name,
+    description,
+    content='products',
+    content_rowid='id',
+    tokenize='porter unicode61 remove_diacritics 2'
+);
+
+INSERT INTO products_fts(products_fts) VALUES ('rebuild');
+
+CREATE TRIGGER products_fts_insert AFTER INSERT ON products BEGIN
+    INSERT INTO products_fts(rowid, name, description) VALUES (new.id, new.name, new.description);
+END;
+
+CREATE TRIGGER products_fts_delete AFTER DELETE ON products BEGIN
+    INSERT INTO products_fts(products_fts, rowid, name, description) VALUES ('delete', old.id, old.name, old.description);
+END;
+
+CREATE TRIGGER products_fts_update AFTER UPDATE OF name, description ON products BEGIN
+    INSERT INTO products_fts(products_fts, rowid, name, description) VALUES ('delete', old.id, old.name, old.description);
+    INSERT INTO products_fts(rowid, name, description) VALUES (new.id, new.name, new.description);
+END;
@@ -0,0 +1,35 @@
+-- categories form a tree: parent_id is NULL for top level categories
+CREATE TABLE categories (
+                            id INTEGER PRIMARY KEY AUTOINCREMENT,
+                            parent_id INTEGER REFERENCES categories(id),
+                            name TEXT NOT NULL,
+                            slug TEXT NOT NULL UNIQUE
+);
+
+CREATE INDEX idx_categories_parent ON categories(parent_id);
+
+-- a product can be listed in any number of categories
+CREATE TABLE product_categories (
+                                    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+                                    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+                                    PRIMARY KEY (product_id, category_id)
+);
+
+CREATE INDEX idx_product_categories_category ON product_categories(category_id);
+
+INSERT INTO categories (id, parent_id, name, slug) VALUES
+                                                       (1, NULL, 'Electronics', 'electronics'),
+                                                       (2, 1, 'Computers', 'computers'),
+                                                       (3, 2, 'Laptops', 'laptops'),
+                                                       (4, 2, 'Computer Accessories', 'computer-accessories'),
+                                                       (5, 2, 'Monitors', 'monitors'),
+                                                       (6, 1, 'Audio', 'audio'),
+                                                       (7, 6, 'Headphones', 'headphones');
+
+INSERT INTO product_categories (product_id, category_id) VALUES
+                                                             (1, 3),
+                                                             (2, 4),
+                                                             (3, 4),
+                                                             (4, 5),
+                                                             (5, 7),
+                                                             (5, 4);
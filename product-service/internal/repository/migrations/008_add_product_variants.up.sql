@@ -0,0 +1,17 @@
+-- a product with rows here is sold per variant (SKU), a product without any is sold by its id alone
+CREATE TABLE product_variants (
+                                  id INTEGER PRIMARY KEY AUTOINCREMENT,
+                                  product_id INTEGER NOT NULL REFERENCES products(id),
+                                  sku TEXT NOT NULL UNIQUE,
+                                  name TEXT NOT NULL,
+                                  price_minor INTEGER, -- NULL sells at the product price, in the product currency
+                                  attributes TEXT NOT NULL DEFAULT '{}', -- JSON object of string values, e.g. {"memory": "32GB"}
+                                  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                  archived_at TIMESTAMP
+);
+
+CREATE INDEX idx_product_variants_product ON product_variants(product_id);
+
+INSERT INTO product_variants (id, product_id, sku, name, price_minor, attributes) VALUES
+                                                                                       (1, 1, 'LAPTOP-16GB-512GB', '16GB RAM, 512GB SSD', NULL, '{"memory":"16GB","storage":"512GB"}'),
+                                                                                       (2, 1, 'LAPTOP-32GB-1TB', '32GB RAM, 1TB SSD', 159999, '{"memory":"32GB","storage":"1TB"}');
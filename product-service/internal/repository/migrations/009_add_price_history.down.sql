@@ -0,0 +1,2 @@
+DROP TABLE scheduled_prices;
+DROP TABLE product_prices;
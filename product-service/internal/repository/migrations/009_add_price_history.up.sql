@@ -0,0 +1,29 @@
+-- every price a product has had: valid_to is NULL on the row that is current
+CREATE TABLE product_prices (
+                                id INTEGER PRIMARY KEY AUTOINCREMENT,
+                                product_id INTEGER NOT NULL REFERENCES products(id),
+                                price_minor INTEGER NOT NULL,
+                                currency TEXT NOT NULL,
+                                valid_from TIMESTAMP NOT NULL,
+                                valid_to TIMESTAMP
+);
+
+CREATE INDEX idx_product_prices_product ON product_prices(product_id, valid_from);
+
+INSERT INTO product_prices (product_id, price_minor, currency, valid_from)
+SELECT id, price_minor, currency, created_at FROM products;
+
+-- future prices (sales) applied by the price scheduler: the previous price comes back at ends_at
+CREATE TABLE scheduled_prices (
+                                  id INTEGER PRIMARY KEY AUTOINCREMENT,
+                                  product_id INTEGER NOT NULL REFERENCES products(id),
+                                  price_minor INTEGER NOT NULL, -- in the product currency
+                                  starts_at TIMESTAMP NOT NULL,
+                                  ends_at TIMESTAMP, -- NULL keeps the price after it starts
+                                  previous_price_minor INTEGER, -- set when applied, restored at ends_at
+                                  applied_at TIMESTAMP,
+                                  reverted_at TIMESTAMP,
+                                  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_scheduled_prices_pending ON scheduled_prices(applied_at, starts_at);
@@ -0,0 +1,6 @@
+-- external SKU the catalog import upserts by, every product gets one
+ALTER TABLE products ADD COLUMN sku TEXT NOT NULL DEFAULT '';
+
+UPDATE products SET sku = 'PRD-' || id;
+
+CREATE UNIQUE INDEX idx_products_sku ON products(sku) WHERE sku <> '';
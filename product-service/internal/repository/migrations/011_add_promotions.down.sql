@@ -0,0 +1,4 @@
+DROP TABLE promotion_redemptions;
+DROP TABLE promotion_categories;
+DROP TABLE promotion_products;
+DROP TABLE promotions;
@@ -0,0 +1,37 @@
+-- promo codes: value is a percent for percentage codes and minor units for fixed ones
+CREATE TABLE promotions (
+                            id INTEGER PRIMARY KEY AUTOINCREMENT,
+                            code TEXT NOT NULL UNIQUE, -- upper case
+                            kind TEXT NOT NULL, -- percentage or fixed
+                            value INTEGER NOT NULL,
+                            currency TEXT NOT NULL DEFAULT '', -- of a fixed value and of min_spend_minor
+                            min_spend_minor INTEGER NOT NULL DEFAULT 0,
+                            usage_limit INTEGER, -- NULL for unlimited
+                            times_used INTEGER NOT NULL DEFAULT 0,
+                            starts_at TIMESTAMP,
+                            expires_at TIMESTAMP,
+                            created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- a promotion without products and categories covers the whole cart
+CREATE TABLE promotion_products (
+                                    promotion_id INTEGER NOT NULL REFERENCES promotions(id) ON DELETE CASCADE,
+                                    product_id INTEGER NOT NULL REFERENCES products(id),
+                                    PRIMARY KEY (promotion_id, product_id)
+);
+
+-- a category also covers its subcategories
+CREATE TABLE promotion_categories (
+                                      promotion_id INTEGER NOT NULL REFERENCES promotions(id) ON DELETE CASCADE,
+                                      category_id INTEGER NOT NULL REFERENCES categories(id),
+                                      PRIMARY KEY (promotion_id, category_id)
+);
+
+-- one row per checkout holding a use of a code, times_used counts them
+CREATE TABLE promotion_redemptions (
+                                       id INTEGER PRIMARY KEY AUTOINCREMENT,
+                                       promotion_id INTEGER NOT NULL REFERENCES promotions(id),
+                                       checkout_id TEXT NOT NULL,
+                                       redeemed_at TIMESTAMP NOT NULL,
+                                       UNIQUE (promotion_id, checkout_id)
+);
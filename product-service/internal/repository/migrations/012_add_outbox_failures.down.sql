@@ -0,0 +1,2 @@
+ALTER TABLE outbox_events DROP COLUMN failure;
+ALTER TABLE outbox_events DROP COLUMN failed_at;
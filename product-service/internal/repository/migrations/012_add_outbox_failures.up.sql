@@ -0,0 +1,3 @@
+-- events that can never be published (a payload that doesn't decode) are set aside instead of blocking the outbox
+ALTER TABLE outbox_events ADD COLUMN failed_at TIMESTAMP;
+ALTER TABLE outbox_events ADD COLUMN failure TEXT;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS scheduled_prices;
+DROP TABLE IF EXISTS product_prices;
+DROP TABLE IF EXISTS product_variants;
+DROP TABLE IF EXISTS product_categories;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS outbox_events;
+DROP TABLE IF EXISTS products;
@@ -0,0 +1,88 @@
+-- the catalog schema as the sqlite migrations leave it, timestamps are stored as UTC without a time zone like there
+CREATE TABLE products (
+                          id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+                          sku TEXT NOT NULL DEFAULT '',
+                          name TEXT NOT NULL,
+                          description TEXT,
+                          price DOUBLE PRECISION NOT NULL, -- kept next to price_minor like the sqlite schema
+                          price_minor BIGINT NOT NULL DEFAULT 0,
+                          currency TEXT NOT NULL DEFAULT 'USD',
+                          image_url TEXT,
+                          created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+                          archived_at TIMESTAMP,
+                          -- replaces the products_fts index: name matches are weighted A, description matches B
+                          search_vector TSVECTOR GENERATED ALWAYS AS (
+                              setweight(to_tsvector('english', name), 'A') ||
+                              setweight(to_tsvector('english', COALESCE(description, '')), 'B')
+                          ) STORED
+);
+
+CREATE UNIQUE INDEX idx_products_sku ON products(sku) WHERE sku <> '';
+CREATE INDEX idx_products_search ON products USING GIN (search_vector);
+
+CREATE TABLE outbox_events (
+                               id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+                               aggregate_id BIGINT NOT NULL, -- product id
+                               event_type TEXT NOT NULL,
+                               payload TEXT NOT NULL,
+                               created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+                               processed_at TIMESTAMP
+);
+
+CREATE INDEX idx_outbox_unprocessed ON outbox_events(processed_at) WHERE processed_at IS NULL;
+
+-- categories form a tree: parent_id is NULL for top level categories
+CREATE TABLE categories (
+                            id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+                            parent_id BIGINT REFERENCES categories(id),
+                            name TEXT NOT NULL,
+                            slug TEXT NOT NULL UNIQUE
+);
+
+CREATE INDEX idx_categories_parent ON categories(parent_id);
+
+CREATE TABLE product_categories (
+                                    product_id BIGINT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+                                    category_id BIGINT NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+                                    PRIMARY KEY (product_id, category_id)
+);
+
+CREATE INDEX idx_product_categories_category ON product_categories(category_id);
+
+CREATE TABLE product_variants (
+                                  id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+                                  product_id BIGINT NOT NULL REFERENCES products(id),
+                                  sku TEXT NOT NULL UNIQUE,
+                                  name TEXT NOT NULL,
+                                  price_minor BIGINT, -- NULL sells at the product price, in the product currency
+                                  attributes TEXT NOT NULL DEFAULT '{}', -- JSON object of string values
+                                  created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+                                  archived_at TIMESTAMP
+);
+
+CREATE INDEX idx_product_variants_product ON product_variants(product_id);
+
+CREATE TABLE product_prices (
+                                id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+                                product_id BIGINT NOT NULL REFERENCES products(id),
+                                price_minor BIGINT NOT NULL,
+                                currency TEXT NOT NULL,
+                                valid_from TIMESTAMP NOT NULL,
+                                valid_to TIMESTAMP
+);
+
+CREATE INDEX idx_product_prices_product ON product_prices(product_id, valid_from);
+
+CREATE TABLE scheduled_prices (
+                                  id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+                                  product_id BIGINT NOT NULL REFERENCES products(id),
+                                  price_minor BIGINT NOT NULL,
+                                  starts_at TIMESTAMP NOT NULL,
+                                  ends_at TIMESTAMP,
+                                  previous_price_minor BIGINT,
+                                  applied_at TIMESTAMP,
+                                  reverted_at TIMESTAMP,
+                                  created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc')
+);
+
+CREATE INDEX idx_scheduled_prices_pending ON scheduled_prices(applied_at, starts_at);
@@ -0,0 +1,5 @@
+DELETE FROM product_variants WHERE id IN (1, 2);
+DELETE FROM product_categories WHERE product_id IN (1, 2, 3, 4, 5);
+DELETE FROM categories WHERE id IN (1, 2, 3, 4, 5, 6, 7);
+DELETE FROM product_prices WHERE product_id IN (1, 2, 3, 4, 5);
+DELETE FROM products WHERE id IN (1, 2, 3, 4, 5);
@@ -0,0 +1,36 @@
+-- the same seed data as the sqlite migrations
+INSERT INTO products (id, sku, name, description, price, price_minor, currency, image_url) VALUES
+                                                                                                (1, 'PRD-1', 'Laptop', 'High-performance laptop for work and gaming', 1299.99, 129999, 'USD', 'https://example.com/laptop.jpg'),
+                                                                                                (2, 'PRD-2', 'Mouse', 'Ergonomic wireless mouse', 29.99, 2999, 'USD', 'https://example.com/mouse.jpg'),
+                                                                                                (3, 'PRD-3', 'Keyboard', 'Mechanical keyboard with RGB lighting', 89.99, 8999, 'USD', 'https://example.com/keyboard.jpg'),
+                                                                                                (4, 'PRD-4', 'Monitor', '27-inch 4K monitor', 399.99, 39999, 'USD', 'https://example.com/monitor.jpg'),
+                                                                                                (5, 'PRD-5', 'Headphones', 'Noise-cancelling over-ear headphones', 249.99, 24999, 'USD', 'https://example.com/headphones.jpg');
+
+INSERT INTO product_prices (product_id, price_minor, currency, valid_from)
+SELECT id, price_minor, currency, created_at FROM products;
+
+INSERT INTO categories (id, parent_id, name, slug) VALUES
+                                                       (1, NULL, 'Electronics', 'electronics'),
+                                                       (2, 1, 'Computers', 'computers'),
+                                                       (3, 2, 'Laptops', 'laptops'),
+                                                       (4, 2, 'Computer Accessories', 'computer-accessories'),
+                                                       (5, 2, 'Monitors', 'monitors'),
+                                                       (6, 1, 'Audio', 'audio'),
+                                                       (7, 6, 'Headphones', 'headphones');
+
+INSERT INTO product_categories (product_id, category_id) VALUES
+                                                             (1, 3),
+                                                             (2, 4),
+                                                             (3, 4),
+                                                             (4, 5),
+                                                             (5, 7),
+                                                             (5, 4);
+
+INSERT INTO product_variants (id, product_id, sku, name, price_minor, attributes) VALUES
+                                                                                       (1, 1, 'LAPTOP-16GB-512GB', '16GB RAM, 512GB SSD', NULL, '{"memory":"16GB","storage":"512GB"}'),
+                                                                                       (2, 1, 'LAPTOP-32GB-1TB', '32GB RAM, 1TB SSD', 159999, '{"memory":"32GB","storage":"1TB"}');
+
+-- the rows above were inserted with explicit ids, new rows continue after them
+SELECT setval(pg_get_serial_sequence('products', 'id'), (SELECT MAX(id) FROM products));
+SELECT setval(pg_get_serial_sequence('categories', 'id'), (SELECT MAX(id) FROM categories));
+SELECT setval(pg_get_serial_sequence('product_variants', 'id'), (SELECT MAX(id) FROM product_variants));
@@ -0,0 +1,34 @@
+-- promo codes, the same tables as the sqlite 011_add_promotions migration
+CREATE TABLE promotions (
+                            id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+                            code TEXT NOT NULL UNIQUE,
+                            kind TEXT NOT NULL,
+                            value BIGINT NOT NULL,
+                            currency TEXT NOT NULL DEFAULT '',
+                            min_spend_minor BIGINT NOT NULL DEFAULT 0,
+                            usage_limit BIGINT,
+                            times_used BIGINT NOT NULL DEFAULT 0,
+                            starts_at TIMESTAMP,
+                            expires_at TIMESTAMP,
+                            created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc')
+);
+
+CREATE TABLE promotion_products (
+                                    promotion_id BIGINT NOT NULL REFERENCES promotions(id) ON DELETE CASCADE,
+                                    product_id BIGINT NOT NULL REFERENCES products(id),
+                                    PRIMARY KEY (promotion_id, product_id)
+);
+
+CREATE TABLE promotion_categories (
+                                      promotion_id BIGINT NOT NULL REFERENCES promotions(id) ON DELETE CASCADE,
+                                      category_id BIGINT NOT NULL REFERENCES categories(id),
+                                      PRIMARY KEY (promotion_id, category_id)
+);
+
+CREATE TABLE promotion_redemptions (
+                                       id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+                                       promotion_id BIGINT NOT NULL REFERENCES promotions(id),
+                                       checkout_id TEXT NOT NULL,
+                                       redeemed_at TIMESTAMP NOT NULL,
+                                       UNIQUE (promotion_id, checkout_id)
+);
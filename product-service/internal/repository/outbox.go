@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// OutboxEvent is a catalog event waiting in outbox_events to be published
+type OutboxEvent struct {
+	ID          int64
+	AggregateID int64 // product id
+	EventType   string
+	Payload     []byte
+	CreatedAt   time.Time
+}
+
+// GetUnprocessedEvents returns the oldest events that were neither published nor set aside as failed yet,
+// in the order they were written
+func (r *Repository) GetUnprocessedEvents(ctx context.Context, limit int) ([]*OutboxEvent, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, aggregate_id, event_type, payload, created_at
+		FROM outbox_events
+		WHERE processed_at IS NULL AND failed_at IS NULL
+		ORDER BY id
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*OutboxEvent
+	for rows.Next() {
+		e := &OutboxEvent{}
+		var payload string
+		if err := rows.Scan(&e.ID, &e.AggregateID, &e.EventType, &payload, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		e.Payload = []byte(payload)
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return events, nil
+}
+
+// MarkEventAsProcessed records that the event was published
+func (r *Repository) MarkEventAsProcessed(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE outbox_events SET processed_at = ? WHERE id = ?`, time.Now().UTC(), id)
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox event %d as processed: %w", id, err)
+	}
+	return nil
+}
+
+// MarkEventAsFailed sets aside an event that can never be published, it keeps the reason for whoever looks into it
+func (r *Repository) MarkEventAsFailed(ctx context.Context, id int64, reason string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE outbox_events SET failed_at = ?, failure = ? WHERE id = ?`, time.Now().UTC(), reason, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox event %d as failed: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fjod/go_cart/product-service/internal/domain"
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CreateProduct inserts the product and its ProductCreated outbox event in one transaction, ID and CreatedAt are filled in.
+// A product created without a SKU gets PRD-<id>.
+func (r *Repository) CreateProduct(ctx context.Context, p *domain.Product) error {
+	return r.inTx(ctx, func(tx *sqlTx) error {
+		now := time.Now().UTC()
+		// price (REAL) is still NOT NULL for older readers, it mirrors price_minor
+		var id int64
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO products (sku, name, description, price, price_minor, currency, image_url, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			RETURNING id`,
+			p.SKU, p.Name, p.Description, p.Price.Float64(), p.Price.Amount, p.Price.Currency, p.ImageURL, now).Scan(&id)
+		if isUniqueViolation(err) {
+			return status.Errorf(codes.AlreadyExists, "sku %s is already taken", p.SKU)
+		}
+		if err != nil {
+			return fmt.Errorf("insert product: %w", err)
+		}
+		p.ID = id
+		p.CreatedAt = now
+		if p.SKU == "" {
+			p.SKU = defaultSKU(id)
+			if _, err := tx.ExecContext(ctx, `UPDATE products SET sku = ? WHERE id = ?`, p.SKU, id); err != nil {
+				return fmt.Errorf("set default sku: %w", err)
+			}
+		}
+
+		if err := recordPrice(ctx, tx, id, p.Price, now); err != nil {
+			return err
+		}
+		return insertProductEvent(ctx, tx, domain.NewProductChangedEvent(p, domain.ProductChangeCreated, now))
+	})
+}
+
+// UpdateProduct replaces the editable fields of a product that is still on sale, p is reloaded from the table.
+// A new price is recorded in the price history and published as ProductPriceChanged instead of ProductUpdated.
+func (r *Repository) UpdateProduct(ctx context.Context, p *domain.Product) error {
+	return r.inTx(ctx, func(tx *sqlTx) error {
+		current, err := getProduct(ctx, tx, p.ID)
+		if err != nil {
+			return err
+		}
+		if current.IsArchived() {
+			return status.Error(codes.FailedPrecondition, "product is archived")
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE products
+			SET name = ?, description = ?, price = ?, price_minor = ?, currency = ?, image_url = ?
+			WHERE id = ?`,
+			p.Name, p.Description, p.Price.Float64(), p.Price.Amount, p.Price.Currency, p.ImageURL, p.ID)
+		if err != nil {
+			return fmt.Errorf("update product: %w", err)
+		}
+
+		updated, err := getProduct(ctx, tx, p.ID)
+		if err != nil {
+			return err
+		}
+		*p = *updated
+
+		now := time.Now().UTC()
+		if updated.Price != current.Price {
+			if err := recordPrice(ctx, tx, p.ID, updated.Price, now); err != nil {
+				return err
+			}
+			return insertProductEvent(ctx, tx, domain.NewPriceChangedEvent(p, current.Price.Amount, now))
+		}
+		return insertProductEvent(ctx, tx, domain.NewProductChangedEvent(p, domain.ProductChangeUpdated, now))
+	})
+}
+
+// ArchiveProduct soft deletes a product. Archiving twice is a no-op and writes no second event.
+func (r *Repository) ArchiveProduct(ctx context.Context, id int64) (*domain.Product, error) {
+	var archived *domain.Product
+	err := r.inTx(ctx, func(tx *sqlTx) error {
+		current, err := getProduct(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if current.IsArchived() {
+			archived = current
+			return nil
+		}
+
+		now := time.Now().UTC()
+		if _, err := tx.ExecContext(ctx, `UPDATE products SET archived_at = ? WHERE id = ?`, now, id); err != nil {
+			return fmt.Errorf("archive product: %w", err)
+		}
+		current.ArchivedAt = &now
+		archived = current
+
+		return insertProductEvent(ctx, tx, domain.NewProductChangedEvent(current, domain.ProductChangeArchived, now))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return archived, nil
+}
+
+// insertProductEvent writes the event to the outbox, the OutboxPoller publishes it to Kafka
+func insertProductEvent(ctx context.Context, tx *sqlTx, e domain.ProductChangedEvent) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal product event: %w", err)
+	}
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO outbox_events (aggregate_id, event_type, payload) VALUES (?, ?, ?)`,
+		e.ProductID, e.Change.EventType(), string(payload))
+	if err != nil {
+		return fmt.Errorf("insert outbox event: %w", err)
+	}
+	return nil
+}
+
+func defaultSKU(id int64) string {
+	return "PRD-" + strconv.FormatInt(id, 10)
+}
+
+// isUniqueViolation recognizes the unique constraint error of both drivers
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "23505"
+	}
+	return err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
+
+// inTx runs fn in a transaction and commits it when fn succeeds
+func (r *Repository) inTx(ctx context.Context, fn func(tx *sqlTx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback() // no-op after commit
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/fjod/go_cart/pkg/money"
+	"github.com/fjod/go_cart/product-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func setupAdminDB(t *testing.T) *Repository {
+	repo, err := NewRepository(":memory:")
+	require.NoError(t, err)
+	require.NoError(t, repo.RunMigrations("./migrations"))
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+func outboxEvents(t *testing.T, r *Repository, productID int64) []domain.ProductChangedEvent {
+	rows, err := r.db.QueryContext(context.Background(), `SELECT event_type, payload FROM outbox_events WHERE aggregate_id = ? ORDER BY id`, productID)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var events []domain.ProductChangedEvent
+	for rows.Next() {
+		var eventType, payload string
+		require.NoError(t, rows.Scan(&eventType, &payload))
+		var e domain.ProductChangedEvent
+		require.NoError(t, json.Unmarshal([]byte(payload), &e))
+		assert.Equal(t, e.Change.EventType(), eventType)
+		events = append(events, e)
+	}
+	require.NoError(t, rows.Err())
+	return events
+}
+
+func TestCreateProduct_WritesProductAndEvent(t *testing.T) {
+	repo := setupAdminDB(t)
+	ctx := context.Background()
+
+	p := &domain.Product{Name: "Desk Lamp", Description: "LED", Price: money.New(2599, "EUR")}
+	require.NoError(t, repo.CreateProduct(ctx, p))
+	assert.NotZero(t, p.ID)
+
+	stored, err := repo.GetProduct(ctx, p.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Desk Lamp", stored.Name)
+	assert.Equal(t, money.New(2599, "EUR"), stored.Price)
+	assert.False(t, stored.IsArchived())
+
+	events := outboxEvents(t, repo, p.ID)
+	require.Len(t, events, 1)
+	assert.Equal(t, domain.ProductChangeCreated, events[0].Change)
+	assert.Equal(t, int64(2599), events[0].PriceMinor)
+}
+
+func TestUpdateProduct_ReplacesFields(t *testing.T) {
+	repo := setupAdminDB(t)
+	ctx := context.Background()
+
+	p := &domain.Product{ID: 1, Name: "Laptop Pro", Description: "updated", Price: money.New(119999, "USD")}
+	require.NoError(t, repo.UpdateProduct(ctx, p))
+	assert.False(t, p.CreatedAt.IsZero()) // reloaded from the table
+
+	stored, err := repo.GetProduct(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Laptop Pro", stored.Name)
+	assert.Equal(t, int64(119999), stored.Price.Amount)
+
+	events := outboxEvents(t, repo, 1)
+	require.Len(t, events, 1)
+	assert.Equal(t, domain.ProductChangePriceChanged, events[0].Change)
+	require.NotNil(t, events[0].PreviousPriceMinor)
+	assert.Equal(t, int64(129999), *events[0].PreviousPriceMinor)
+}
+
+func TestUpdateProduct_SamePriceIsProductUpdated(t *testing.T) {
+	repo := setupAdminDB(t)
+	ctx := context.Background()
+
+	current, err := repo.GetProduct(ctx, 1)
+	require.NoError(t, err)
+	current.Name = "Laptop Pro"
+	require.NoError(t, repo.UpdateProduct(ctx, current))
+
+	events := outboxEvents(t, repo, 1)
+	require.Len(t, events, 1)
+	assert.Equal(t, domain.ProductChangeUpdated, events[0].Change)
+	assert.Nil(t, events[0].PreviousPriceMinor)
+}
+
+func TestUpdateProduct_NotFound(t *testing.T) {
+	repo := setupAdminDB(t)
+
+	err := repo.UpdateProduct(context.Background(), &domain.Product{ID: 999, Name: "x", Price: money.New(1, "USD")})
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestArchiveProduct_HidesFromCatalog(t *testing.T) {
+	repo := setupAdminDB(t)
+	ctx := context.Background()
+
+	archived, err := repo.ArchiveProduct(ctx, 1)
+	require.NoError(t, err)
+	assert.True(t, archived.IsArchived())
+
+	all, err := repo.GetAllProducts(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 4)
+
+	// carts and orders still resolve it by id
+	byID, err := repo.GetProduct(ctx, 1)
+	require.NoError(t, err)
+	assert.True(t, byID.IsArchived())
+
+	err = repo.UpdateProduct(ctx, &domain.Product{ID: 1, Name: "x", Price: money.New(1, "USD")})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// archiving again changes nothing and writes no second event
+	_, err = repo.ArchiveProduct(ctx, 1)
+	require.NoError(t, err)
+	events := outboxEvents(t, repo, 1)
+	require.Len(t, events, 1)
+	assert.Equal(t, domain.ProductChangeArchived, events[0].Change)
+	assert.NotNil(t, events[0].ArchivedAt)
+}
+
+func TestArchiveProduct_NotFound(t *testing.T) {
+	repo := setupAdminDB(t)
+
+	_, err := repo.ArchiveProduct(context.Background(), 999)
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestOutbox_UnprocessedUntilMarked(t *testing.T) {
+	repo := setupAdminDB(t)
+	ctx := context.Background()
+
+	first := &domain.Product{Name: "Desk Lamp", Price: money.New(2599, "EUR")}
+	require.NoError(t, repo.CreateProduct(ctx, first))
+	_, err := repo.ArchiveProduct(ctx, first.ID)
+	require.NoError(t, err)
+
+	events, err := repo.GetUnprocessedEvents(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, domain.ProductCreatedEventType, events[0].EventType)
+	assert.Equal(t, domain.ProductArchivedEventType, events[1].EventType)
+	assert.Equal(t, first.ID, events[0].AggregateID)
+
+	require.NoError(t, repo.MarkEventAsProcessed(ctx, events[0].ID))
+
+	events, err = repo.GetUnprocessedEvents(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, domain.ProductArchivedEventType, events[0].EventType)
+}
+
+func TestCreateProduct_SKU(t *testing.T) {
+	repo := setupAdminDB(t)
+	ctx := context.Background()
+
+	p := &domain.Product{Name: "Desk Lamp", Price: money.New(2599, "EUR")}
+	require.NoError(t, repo.CreateProduct(ctx, p))
+	assert.Equal(t, fmt.Sprintf("PRD-%d", p.ID), p.SKU)
+
+	stored, err := repo.GetProductBySKU(ctx, p.SKU)
+	require.NoError(t, err)
+	assert.Equal(t, p.ID, stored.ID)
+
+	err = repo.CreateProduct(ctx, &domain.Product{SKU: p.SKU, Name: "Copy", Price: money.New(1, "EUR")})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+}
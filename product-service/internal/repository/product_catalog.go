@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/fjod/go_cart/product-service/internal/domain"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetProductBySKU returns the product with the given external SKU even when it is archived
+func (r *Repository) GetProductBySKU(ctx context.Context, sku string) (*domain.Product, error) {
+	query := `
+		SELECT ` + productColumns + `
+		FROM products
+		WHERE sku = ?
+	`
+
+	p, err := scanProduct(r.db.QueryRowContext(ctx, query, sku))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Error(codes.NotFound, "product not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query products: %w", err)
+	}
+	return p, nil
+}
+
+// GetProductsForExport lists every product by id, archived ones included so an export can be imported back
+func (r *Repository) GetProductsForExport(ctx context.Context) ([]*domain.Product, error) {
+	query := `
+		SELECT ` + productColumns + `
+		FROM products
+		ORDER BY id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query products: %w", err)
+	}
+	defer rows.Close()
+
+	return scanProducts(rows)
+}
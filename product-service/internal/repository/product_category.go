@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/fjod/go_cart/product-service/internal/domain"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListCategories returns the whole taxonomy, parents before their children
+func (r *Repository) ListCategories(ctx context.Context) ([]*domain.Category, error) {
+	// walking the tree from the roots keeps every parent ahead of its children
+	query := `
+		WITH RECURSIVE tree(id, depth) AS (
+			SELECT id, 0 FROM categories WHERE parent_id IS NULL
+			UNION ALL
+			SELECT c.id, tree.depth + 1 FROM categories c JOIN tree ON c.parent_id = tree.id
+		)
+		SELECT c.id, c.parent_id, c.name, c.slug
+		FROM categories c
+		JOIN tree ON tree.id = c.id
+		ORDER BY tree.depth, c.name, c.id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query categories: %w", err)
+	}
+	defer rows.Close()
+
+	var categories []*domain.Category
+	for rows.Next() {
+		c := &domain.Category{}
+		if err := rows.Scan(&c.ID, &c.ParentID, &c.Name, &c.Slug); err != nil {
+			return nil, fmt.Errorf("failed to scan category: %w", err)
+		}
+		categories = append(categories, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return categories, nil
+}
+
+// GetBreadcrumbs returns one root-to-leaf path per category the product is listed in, ordered by leaf id.
+// A product without categories has no breadcrumbs.
+func (r *Repository) GetBreadcrumbs(ctx context.Context, productID int64) ([]domain.Breadcrumb, error) {
+	// climbs from every category of the product up to its root, depth 0 is the leaf
+	query := `
+		WITH RECURSIVE path(leaf, id, parent_id, name, slug, depth) AS (
+			SELECT c.id, c.id, c.parent_id, c.name, c.slug, 0
+			FROM categories c
+			JOIN product_categories pc ON pc.category_id = c.id
+			WHERE pc.product_id = ?
+			UNION ALL
+			SELECT path.leaf, c.id, c.parent_id, c.name, c.slug, path.depth + 1
+			FROM categories c
+			JOIN path ON c.id = path.parent_id
+		)
+		SELECT leaf, id, parent_id, name, slug
+		FROM path
+		ORDER BY leaf, depth DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query breadcrumbs: %w", err)
+	}
+	defer rows.Close()
+
+	var breadcrumbs []domain.Breadcrumb
+	var currentLeaf int64
+	for rows.Next() {
+		var leaf int64
+		c := &domain.Category{}
+		if err := rows.Scan(&leaf, &c.ID, &c.ParentID, &c.Name, &c.Slug); err != nil {
+			return nil, fmt.Errorf("failed to scan category: %w", err)
+		}
+		if len(breadcrumbs) == 0 || leaf != currentLeaf {
+			breadcrumbs = append(breadcrumbs, nil)
+			currentLeaf = leaf
+		}
+		last := len(breadcrumbs) - 1
+		breadcrumbs[last] = append(breadcrumbs[last], c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return breadcrumbs, nil
+}
+
+// categoryFilter is the ListProducts condition that keeps products of one category, it takes the category id
+func categoryFilter(includeDescendants bool) string {
+	if !includeDescendants {
+		return "id IN (SELECT product_id FROM product_categories WHERE category_id = ?)"
+	}
+	return `id IN (
+			SELECT pc.product_id
+			FROM product_categories pc
+			WHERE pc.category_id IN (
+				WITH RECURSIVE subtree(id) AS (
+					SELECT CAST(? AS BIGINT)
+					UNION
+					SELECT c.id FROM categories c JOIN subtree ON c.parent_id = subtree.id
+				)
+				SELECT id FROM subtree
+			)
+		)`
+}
+
+func categoryExists(ctx context.Context, q querier, id int64) error {
+	var found int64
+	err := q.QueryRowContext(ctx, `SELECT id FROM categories WHERE id = ?`, id).Scan(&found)
+	if errors.Is(err, sql.ErrNoRows) {
+		return status.Error(codes.NotFound, "category not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to query category: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fjod/go_cart/product-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestListCategories_ParentsComeFirst(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	categories, err := repo.ListCategories(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, categories, 7)
+	assert.Equal(t, "electronics", categories[0].Slug)
+	assert.Nil(t, categories[0].ParentID)
+
+	seen := map[int64]bool{}
+	for _, c := range categories {
+		if c.ParentID != nil {
+			assert.True(t, seen[*c.ParentID], "category %s listed before its parent", c.Slug)
+		}
+		seen[c.ID] = true
+	}
+}
+
+func TestListProducts_ByCategory(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	// Computer Accessories holds the mouse, the keyboard and the headphones
+	assert.Equal(t, []int64{2, 3, 5}, listAll(t, repo, domain.ProductQuery{PageSize: 2, CategoryID: 4}))
+	// Computers itself has no products, its subcategories do
+	assert.Empty(t, listAll(t, repo, domain.ProductQuery{PageSize: 10, CategoryID: 2}))
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, listAll(t, repo, domain.ProductQuery{PageSize: 2, CategoryID: 2, IncludeDescendants: true}))
+	assert.Equal(t, []int64{5}, listAll(t, repo, domain.ProductQuery{PageSize: 10, CategoryID: 6, IncludeDescendants: true}))
+}
+
+func TestListProducts_UnknownCategory(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	_, _, err := repo.ListProducts(context.Background(), domain.ProductQuery{PageSize: 10, CategoryID: 999})
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestGetBreadcrumbs_OnePathPerCategory(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	breadcrumbs, err := repo.GetBreadcrumbs(context.Background(), 5)
+
+	require.NoError(t, err)
+	require.Len(t, breadcrumbs, 2)
+	assert.Equal(t, []string{"electronics", "computers", "computer-accessories"}, slugs(breadcrumbs[0]))
+	assert.Equal(t, []string{"electronics", "audio", "headphones"}, slugs(breadcrumbs[1]))
+}
+
+func TestGetBreadcrumbs_NoCategories(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	breadcrumbs, err := repo.GetBreadcrumbs(context.Background(), 999)
+
+	require.NoError(t, err)
+	assert.Empty(t, breadcrumbs)
+}
+
+func slugs(b domain.Breadcrumb) []string {
+	s := make([]string, len(b))
+	for i, c := range b {
+		s[i] = c.Slug
+	}
+	return s
+}
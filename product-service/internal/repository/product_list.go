@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fjod/go_cart/product-service/internal/domain"
+)
+
+// sortKeys maps a sort to the SQL expression it orders by; the cursor keeps that expression's value as text.
+// created_at is compared as text: every timestamp is stored in UTC starting with "YYYY-MM-DD HH:MM:SS".
+var sortKeys = map[domain.ProductSort]string{
+	domain.ProductSortID:        "id",
+	domain.ProductSortPrice:     "price_minor",
+	domain.ProductSortName:      "name",
+	domain.ProductSortCreatedAt: "CAST(created_at AS TEXT)",
+}
+
+// ListProducts returns one page of the catalog using keyset pagination on (sort key, id).
+// The cursor is nil on the last page.
+func (r *Repository) ListProducts(ctx context.Context, q domain.ProductQuery) ([]*domain.Product, *domain.ProductCursor, error) {
+	key, ok := sortKeys[q.SortBy]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown sort %d", q.SortBy)
+	}
+	dir, cmp := "ASC", ">"
+	if q.Descending {
+		dir, cmp = "DESC", "<"
+	}
+
+	where := []string{"archived_at IS NULL"}
+	var args []any
+	if q.MinPriceMinor != nil {
+		where = append(where, "price_minor >= ?")
+		args = append(args, *q.MinPriceMinor)
+	}
+	if q.MaxPriceMinor != nil {
+		where = append(where, "price_minor <= ?")
+		args = append(args, *q.MaxPriceMinor)
+	}
+	if q.CategoryID != 0 {
+		if err := categoryExists(ctx, r.db, q.CategoryID); err != nil {
+			return nil, nil, err
+		}
+		where = append(where, categoryFilter(q.IncludeDescendants))
+		args = append(args, q.CategoryID)
+	}
+	if q.After != nil {
+		after, err := cursorValue(q.SortBy, q.After.Key)
+		if err != nil {
+			return nil, nil, err
+		}
+		where = append(where, fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))", key, cmp))
+		args = append(args, after, after, q.After.ID)
+	}
+
+	// one extra row tells whether another page exists
+	query := `
+		SELECT ` + productColumns + `, ` + key + `
+		FROM products
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY ` + key + ` ` + dir + `, id ` + dir + `
+		LIMIT ?
+	`
+	args = append(args, q.PageSize+1)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query products: %w", err)
+	}
+	defer rows.Close()
+
+	var products []*domain.Product
+	var keys []string
+	for rows.Next() {
+		p := &domain.Product{}
+		var sortKey any
+		if err := rows.Scan(append(productFields(p), &sortKey)...); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan product: %w", err)
+		}
+		products = append(products, p)
+		keys = append(keys, fmt.Sprint(sortKey))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	var next *domain.ProductCursor
+	if len(products) > q.PageSize {
+		products = products[:q.PageSize]
+		last := q.PageSize - 1
+		next = &domain.ProductCursor{Key: keys[last], ID: products[last].ID}
+	}
+	if err := r.attachVariants(ctx, products...); err != nil {
+		return nil, nil, err
+	}
+	return products, next, nil
+}
+
+// cursorValue turns the text sort key back into the type the column compares with
+func cursorValue(sort domain.ProductSort, key string) (any, error) {
+	switch sort {
+	case domain.ProductSortID, domain.ProductSortPrice:
+		v, err := strconv.ParseInt(key, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor key %q: %w", key, err)
+		}
+		return v, nil
+	default:
+		return key, nil
+	}
+}
@@ -0,0 +1,98 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fjod/go_cart/pkg/money"
+	"github.com/fjod/go_cart/product-service/internal/domain"
+	db "github.com/fjod/go_cart/product-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listAll walks every page of the query and returns the ids in the order they were served
+func listAll(t *testing.T, repo *db.Repository, q domain.ProductQuery) []int64 {
+	t.Helper()
+	var ids []int64
+	for pages := 0; pages < 10; pages++ {
+		products, next, err := repo.ListProducts(context.Background(), q)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(products), q.PageSize)
+		for _, p := range products {
+			ids = append(ids, p.ID)
+		}
+		if next == nil {
+			return ids
+		}
+		q.After = next
+	}
+	t.Fatal("pagination did not finish")
+	return nil
+}
+
+func TestListProducts_Sorts(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	tests := []struct {
+		name string
+		q    domain.ProductQuery
+		want []int64
+	}{
+		{"by id", domain.ProductQuery{PageSize: 2}, []int64{1, 2, 3, 4, 5}},
+		{"by price", domain.ProductQuery{PageSize: 2, SortBy: domain.ProductSortPrice}, []int64{2, 3, 5, 4, 1}},
+		{"by price descending", domain.ProductQuery{PageSize: 2, SortBy: domain.ProductSortPrice, Descending: true}, []int64{1, 4, 5, 3, 2}},
+		{"by name", domain.ProductQuery{PageSize: 3, SortBy: domain.ProductSortName}, []int64{5, 3, 1, 4, 2}},
+		// seeded rows share created_at, id breaks the tie
+		{"by created_at", domain.ProductQuery{PageSize: 2, SortBy: domain.ProductSortCreatedAt}, []int64{1, 2, 3, 4, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, listAll(t, repo, tt.q))
+		})
+	}
+}
+
+func TestListProducts_PriceFilter(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	minPrice, maxPrice := int64(8999), int64(39999)
+	q := domain.ProductQuery{PageSize: 1, SortBy: domain.ProductSortPrice, MinPriceMinor: &minPrice, MaxPriceMinor: &maxPrice}
+
+	assert.Equal(t, []int64{3, 5, 4}, listAll(t, repo, q))
+}
+
+func TestListProducts_LastPageHasNoCursor(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	products, next, err := repo.ListProducts(context.Background(), domain.ProductQuery{PageSize: 5})
+
+	require.NoError(t, err)
+	assert.Len(t, products, 5)
+	assert.Nil(t, next)
+}
+
+func TestListProducts_SkipsArchived(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	_, err := repo.ArchiveProduct(context.Background(), 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, []int64{1, 3, 4, 5}, listAll(t, repo, domain.ProductQuery{PageSize: 2}))
+}
+
+func TestListProducts_CreatedAtAfterSeed(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	p := &domain.Product{Name: "Desk Lamp", Price: money.New(2599, "USD")}
+	require.NoError(t, repo.CreateProduct(context.Background(), p))
+
+	ids := listAll(t, repo, domain.ProductQuery{PageSize: 4, SortBy: domain.ProductSortCreatedAt, Descending: true})
+
+	assert.Equal(t, []int64{p.ID, 5, 4, 3, 2, 1}, ids)
+}
@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fjod/go_cart/pkg/money"
+	"github.com/fjod/go_cart/product-service/internal/domain"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetPriceAt returns the history entry that was in effect for the product at the given time
+func (r *Repository) GetPriceAt(ctx context.Context, productID int64, at time.Time) (*domain.PricePoint, error) {
+	if _, err := getProduct(ctx, r.db, productID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT product_id, price_minor, currency, valid_from, valid_to
+		FROM product_prices
+		WHERE product_id = ? AND valid_from <= ? AND (valid_to IS NULL OR valid_to > ?)
+		ORDER BY valid_from DESC, id DESC
+		LIMIT 1
+	`
+	at = at.UTC()
+	pp := &domain.PricePoint{}
+	err := r.db.QueryRowContext(ctx, query, productID, at, at).
+		Scan(&pp.ProductID, &pp.Price.Amount, &pp.Price.Currency, &pp.ValidFrom, &pp.ValidTo)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Error(codes.NotFound, "product had no price at that time")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query price history: %w", err)
+	}
+	return pp, nil
+}
+
+// SchedulePrice stores a future price for a product that is still on sale, ID and CreatedAt are filled in
+func (r *Repository) SchedulePrice(ctx context.Context, sp *domain.ScheduledPrice) error {
+	return r.inTx(ctx, func(tx *sqlTx) error {
+		current, err := getProduct(ctx, tx, sp.ProductID)
+		if err != nil {
+			return err
+		}
+		if current.IsArchived() {
+			return status.Error(codes.FailedPrecondition, "product is archived")
+		}
+		if sp.Price.Currency != "" && sp.Price.Currency != current.Price.Currency {
+			return status.Errorf(codes.FailedPrecondition, "product is priced in %s", current.Price.Currency)
+		}
+		sp.Price.Currency = current.Price.Currency
+
+		now := time.Now().UTC()
+		sp.StartsAt = sp.StartsAt.UTC()
+		if sp.EndsAt != nil {
+			endsAt := sp.EndsAt.UTC()
+			sp.EndsAt = &endsAt
+		}
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO scheduled_prices (product_id, price_minor, starts_at, ends_at, created_at)
+			VALUES (?, ?, ?, ?, ?)
+			RETURNING id`,
+			sp.ProductID, sp.Price.Amount, sp.StartsAt, sp.EndsAt, now).Scan(&sp.ID)
+		if err != nil {
+			return fmt.Errorf("insert scheduled price: %w", err)
+		}
+		sp.CreatedAt = now
+		return nil
+	})
+}
+
+// ApplyScheduledPrices ends the scheduled prices whose window is over and starts the ones that are due,
+// in one transaction. Every price it changes is recorded in the history and gets a ProductPriceChanged event.
+// It returns the ids of the products whose price changed.
+func (r *Repository) ApplyScheduledPrices(ctx context.Context, now time.Time) ([]int64, error) {
+	now = now.UTC()
+	var changed []int64
+	err := r.inTx(ctx, func(tx *sqlTx) error {
+		changed = nil
+
+		// ends run first so a sale that starts when the previous one ends sees the regular price as previous
+		ending, err := dueScheduledPrices(ctx, tx, `
+			applied_at IS NOT NULL AND reverted_at IS NULL AND ends_at IS NOT NULL AND ends_at <= ?
+			ORDER BY ends_at, id`, now)
+		if err != nil {
+			return err
+		}
+		for _, sp := range ending {
+			ok, err := revertScheduledPrice(ctx, tx, sp, now)
+			if err != nil {
+				return err
+			}
+			if ok {
+				changed = append(changed, sp.ProductID)
+			}
+		}
+
+		starting, err := dueScheduledPrices(ctx, tx, `
+			applied_at IS NULL AND starts_at <= ?
+			ORDER BY starts_at, id`, now)
+		if err != nil {
+			return err
+		}
+		for _, sp := range starting {
+			ok, err := applyScheduledPrice(ctx, tx, sp, now)
+			if err != nil {
+				return err
+			}
+			if ok {
+				changed = append(changed, sp.ProductID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
+
+type dueScheduledPrice struct {
+	domain.ScheduledPrice
+	previousMinor *int64
+}
+
+func dueScheduledPrices(ctx context.Context, tx *sqlTx, where string, now time.Time) ([]*dueScheduledPrice, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, product_id, price_minor, starts_at, ends_at, previous_price_minor
+		FROM scheduled_prices
+		WHERE `+where, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query scheduled prices: %w", err)
+	}
+	defer rows.Close()
+
+	var due []*dueScheduledPrice
+	for rows.Next() {
+		sp := &dueScheduledPrice{}
+		if err := rows.Scan(&sp.ID, &sp.ProductID, &sp.Price.Amount, &sp.StartsAt, &sp.EndsAt, &sp.previousMinor); err != nil {
+			return nil, fmt.Errorf("failed to scan scheduled price: %w", err)
+		}
+		due = append(due, sp)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return due, nil
+}
+
+// applyScheduledPrice switches the product to the scheduled price. A window that is already over
+// or a product that was archived meanwhile is marked done without touching the price.
+func applyScheduledPrice(ctx context.Context, tx *sqlTx, sp *dueScheduledPrice, now time.Time) (bool, error) {
+	p, err := getProduct(ctx, tx, sp.ProductID)
+	if err != nil {
+		return false, err
+	}
+	if p.IsArchived() || (sp.EndsAt != nil && !sp.EndsAt.After(now)) {
+		_, err := tx.ExecContext(ctx,
+			`UPDATE scheduled_prices SET applied_at = ?, reverted_at = ? WHERE id = ?`, now, now, sp.ID)
+		if err != nil {
+			return false, fmt.Errorf("skip scheduled price: %w", err)
+		}
+		return false, nil
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE scheduled_prices SET applied_at = ?, previous_price_minor = ? WHERE id = ?`, now, p.Price.Amount, sp.ID)
+	if err != nil {
+		return false, fmt.Errorf("apply scheduled price: %w", err)
+	}
+	return setPrice(ctx, tx, p, money.New(sp.Price.Amount, p.Price.Currency), now)
+}
+
+// revertScheduledPrice restores the price the product had before the schedule started,
+// unless an admin changed the price while the schedule was in effect
+func revertScheduledPrice(ctx context.Context, tx *sqlTx, sp *dueScheduledPrice, now time.Time) (bool, error) {
+	if _, err := tx.ExecContext(ctx, `UPDATE scheduled_prices SET reverted_at = ? WHERE id = ?`, now, sp.ID); err != nil {
+		return false, fmt.Errorf("revert scheduled price: %w", err)
+	}
+
+	p, err := getProduct(ctx, tx, sp.ProductID)
+	if err != nil {
+		return false, err
+	}
+	if p.IsArchived() || sp.previousMinor == nil || p.Price.Amount != sp.Price.Amount {
+		return false, nil
+	}
+	return setPrice(ctx, tx, p, money.New(*sp.previousMinor, p.Price.Currency), now)
+}
+
+// setPrice changes the product price, records it in the history and writes the ProductPriceChanged event
+func setPrice(ctx context.Context, tx *sqlTx, p *domain.Product, price money.Money, now time.Time) (bool, error) {
+	if p.Price == price {
+		return false, nil
+	}
+	_, err := tx.ExecContext(ctx, `UPDATE products SET price = ?, price_minor = ? WHERE id = ?`,
+		price.Float64(), price.Amount, p.ID)
+	if err != nil {
+		return false, fmt.Errorf("update product price: %w", err)
+	}
+	previous := p.Price
+	p.Price = price
+	if err := recordPrice(ctx, tx, p.ID, price, now); err != nil {
+		return false, err
+	}
+	if err := insertProductEvent(ctx, tx, domain.NewPriceChangedEvent(p, previous.Amount, now)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// recordPrice closes the current history entry of the product and opens one for the new price
+func recordPrice(ctx context.Context, tx *sqlTx, productID int64, price money.Money, from time.Time) error {
+	_, err := tx.ExecContext(ctx,
+		`UPDATE product_prices SET valid_to = ? WHERE product_id = ? AND valid_to IS NULL`, from, productID)
+	if err != nil {
+		return fmt.Errorf("close price history: %w", err)
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO product_prices (product_id, price_minor, currency, valid_from)
+		VALUES (?, ?, ?, ?)`,
+		productID, price.Amount, price.Currency, from)
+	if err != nil {
+		return fmt.Errorf("insert price history: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fjod/go_cart/pkg/money"
+	"github.com/fjod/go_cart/product-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetPriceAt_SeededProductsHaveHistory(t *testing.T) {
+	repo := setupAdminDB(t)
+	ctx := context.Background()
+
+	current, err := repo.GetProduct(ctx, 1)
+	require.NoError(t, err)
+
+	pp, err := repo.GetPriceAt(ctx, 1, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, current.Price, pp.Price)
+	assert.Nil(t, pp.ValidTo)
+}
+
+func TestGetPriceAt_FollowsUpdates(t *testing.T) {
+	repo := setupAdminDB(t)
+	ctx := context.Background()
+
+	p := &domain.Product{Name: "Desk Lamp", Price: money.New(2599, "EUR")}
+	require.NoError(t, repo.CreateProduct(ctx, p))
+	beforeUpdate := time.Now()
+
+	p.Price = money.New(1999, "EUR")
+	require.NoError(t, repo.UpdateProduct(ctx, p))
+
+	old, err := repo.GetPriceAt(ctx, p.ID, beforeUpdate)
+	require.NoError(t, err)
+	assert.Equal(t, money.New(2599, "EUR"), old.Price)
+	require.NotNil(t, old.ValidTo)
+
+	latest, err := repo.GetPriceAt(ctx, p.ID, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, money.New(1999, "EUR"), latest.Price)
+	assert.Equal(t, *old.ValidTo, latest.ValidFrom)
+
+	_, err = repo.GetPriceAt(ctx, p.ID, p.CreatedAt.Add(-time.Hour))
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestUpdateProduct_SamePriceAddsNoHistory(t *testing.T) {
+	repo := setupAdminDB(t)
+	ctx := context.Background()
+
+	p := &domain.Product{Name: "Desk Lamp", Price: money.New(2599, "EUR")}
+	require.NoError(t, repo.CreateProduct(ctx, p))
+	p.Name = "Desk Lamp XL"
+	require.NoError(t, repo.UpdateProduct(ctx, p))
+
+	assert.Equal(t, 1, priceHistoryLen(t, repo, p.ID))
+}
+
+func TestGetPriceAt_UnknownProduct(t *testing.T) {
+	repo := setupAdminDB(t)
+
+	_, err := repo.GetPriceAt(context.Background(), 999, time.Now())
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestSchedulePrice_ArchivedProduct(t *testing.T) {
+	repo := setupAdminDB(t)
+	ctx := context.Background()
+
+	_, err := repo.ArchiveProduct(ctx, 2)
+	require.NoError(t, err)
+
+	err = repo.SchedulePrice(ctx, &domain.ScheduledPrice{ProductID: 2, Price: money.New(100, ""), StartsAt: time.Now()})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestApplyScheduledPrices_SaleStartsAndEnds(t *testing.T) {
+	repo := setupAdminDB(t)
+	ctx := context.Background()
+
+	p := &domain.Product{Name: "Desk Lamp", Price: money.New(2599, "EUR")}
+	require.NoError(t, repo.CreateProduct(ctx, p))
+
+	start := time.Now().Add(time.Hour)
+	end := start.Add(24 * time.Hour)
+	sale := &domain.ScheduledPrice{ProductID: p.ID, Price: money.New(1999, ""), StartsAt: start, EndsAt: &end}
+	require.NoError(t, repo.SchedulePrice(ctx, sale))
+	assert.NotZero(t, sale.ID)
+	assert.Equal(t, "EUR", sale.Price.Currency)
+
+	// not due yet
+	changed, err := repo.ApplyScheduledPrices(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+
+	changed, err = repo.ApplyScheduledPrices(ctx, start.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, []int64{p.ID}, changed)
+	onSale, err := repo.GetProduct(ctx, p.ID)
+	require.NoError(t, err)
+	assert.Equal(t, money.New(1999, "EUR"), onSale.Price)
+
+	// applying again is a no-op
+	changed, err = repo.ApplyScheduledPrices(ctx, start.Add(2*time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+
+	changed, err = repo.ApplyScheduledPrices(ctx, end.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, []int64{p.ID}, changed)
+	after, err := repo.GetProduct(ctx, p.ID)
+	require.NoError(t, err)
+	assert.Equal(t, money.New(2599, "EUR"), after.Price)
+
+	during, err := repo.GetPriceAt(ctx, p.ID, start.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1999), during.Price.Amount)
+
+	events := outboxEvents(t, repo, p.ID)
+	require.Len(t, events, 3)
+	assert.Equal(t, int64(1999), events[1].PriceMinor)
+	assert.Equal(t, int64(2599), events[2].PriceMinor)
+}
+
+func TestApplyScheduledPrices_KeepsAdminChangeMadeDuringSale(t *testing.T) {
+	repo := setupAdminDB(t)
+	ctx := context.Background()
+
+	p := &domain.Product{Name: "Desk Lamp", Price: money.New(2599, "EUR")}
+	require.NoError(t, repo.CreateProduct(ctx, p))
+
+	start := time.Now()
+	end := start.Add(time.Hour)
+	require.NoError(t, repo.SchedulePrice(ctx, &domain.ScheduledPrice{ProductID: p.ID, Price: money.New(1999, ""), StartsAt: start, EndsAt: &end}))
+	_, err := repo.ApplyScheduledPrices(ctx, start.Add(time.Second))
+	require.NoError(t, err)
+
+	p.Price = money.New(1499, "EUR")
+	require.NoError(t, repo.UpdateProduct(ctx, p))
+
+	changed, err := repo.ApplyScheduledPrices(ctx, end.Add(time.Second))
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+	stored, err := repo.GetProduct(ctx, p.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1499), stored.Price.Amount)
+}
+
+func TestApplyScheduledPrices_SkipsMissedWindow(t *testing.T) {
+	repo := setupAdminDB(t)
+	ctx := context.Background()
+
+	start := time.Now()
+	end := start.Add(time.Minute)
+	require.NoError(t, repo.SchedulePrice(ctx, &domain.ScheduledPrice{ProductID: 2, Price: money.New(1, ""), StartsAt: start, EndsAt: &end}))
+
+	changed, err := repo.ApplyScheduledPrices(ctx, end.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+	assert.Equal(t, 1, priceHistoryLen(t, repo, 2))
+}
+
+func priceHistoryLen(t *testing.T, r *Repository, productID int64) int {
+	var n int
+	require.NoError(t, r.db.QueryRowContext(context.Background(), `SELECT COUNT(*) FROM product_prices WHERE product_id = ?`, productID).Scan(&n))
+	return n
+}
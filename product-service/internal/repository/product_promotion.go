@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fjod/go_cart/product-service/internal/domain"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const promotionColumns = `id, code, kind, value, currency, min_spend_minor, usage_limit, times_used,
+		starts_at, expires_at, created_at`
+
+// CreatePromotion stores a new promo code with its product and category rules, ID and CreatedAt are filled in
+func (r *Repository) CreatePromotion(ctx context.Context, p *domain.Promotion) error {
+	return r.inTx(ctx, func(tx *sqlTx) error {
+		for _, id := range p.ProductIDs {
+			if _, err := getProduct(ctx, tx, id); err != nil {
+				return err
+			}
+		}
+		for _, id := range p.CategoryIDs {
+			if err := categoryExists(ctx, tx, id); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now().UTC()
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO promotions (code, kind, value, currency, min_spend_minor, usage_limit, starts_at, expires_at, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			RETURNING id`,
+			p.Code, string(p.Kind), p.Value, p.Currency, p.MinSpendMinor, p.UsageLimit,
+			utcOrNil(p.StartsAt), utcOrNil(p.ExpiresAt), now).Scan(&p.ID)
+		if isUniqueViolation(err) {
+			return status.Errorf(codes.AlreadyExists, "promo code %s already exists", p.Code)
+		}
+		if err != nil {
+			return fmt.Errorf("insert promotion: %w", err)
+		}
+		p.CreatedAt = now
+
+		for _, id := range p.ProductIDs {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO promotion_products (promotion_id, product_id) VALUES (?, ?) ON CONFLICT DO NOTHING`, p.ID, id); err != nil {
+				return fmt.Errorf("insert promotion product: %w", err)
+			}
+		}
+		for _, id := range p.CategoryIDs {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO promotion_categories (promotion_id, category_id) VALUES (?, ?) ON CONFLICT DO NOTHING`, p.ID, id); err != nil {
+				return fmt.Errorf("insert promotion category: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetPromotionByCode returns the promotion with its rules, the code is matched case-insensitively
+func (r *Repository) GetPromotionByCode(ctx context.Context, code string) (*domain.Promotion, error) {
+	p := &domain.Promotion{}
+	var kind string
+	err := r.db.QueryRowContext(ctx, `SELECT `+promotionColumns+` FROM promotions WHERE code = ?`,
+		domain.NormalizePromoCode(code)).
+		Scan(&p.ID, &p.Code, &kind, &p.Value, &p.Currency, &p.MinSpendMinor, &p.UsageLimit, &p.TimesUsed,
+			&p.StartsAt, &p.ExpiresAt, &p.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Error(codes.NotFound, "promo code not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query promotion: %w", err)
+	}
+	p.Kind = domain.PromotionKind(kind)
+
+	if p.ProductIDs, err = r.promotionRule(ctx, `SELECT product_id FROM promotion_products WHERE promotion_id = ? ORDER BY product_id`, p.ID); err != nil {
+		return nil, err
+	}
+	if p.CategoryIDs, err = r.promotionRule(ctx, `SELECT category_id FROM promotion_categories WHERE promotion_id = ? ORDER BY category_id`, p.ID); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *Repository) promotionRule(ctx context.Context, query string, promotionID int64) ([]int64, error) {
+	rows, err := r.db.QueryContext(ctx, query, promotionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query promotion rules: %w", err)
+	}
+	defer rows.Close()
+	return scanIDs(rows)
+}
+
+// FilterProductsByCategories returns the products of productIDs listed in one of the categories
+// or in one of their subcategories, ordered by id
+func (r *Repository) FilterProductsByCategories(ctx context.Context, productIDs, categoryIDs []int64) ([]int64, error) {
+	if len(productIDs) == 0 || len(categoryIDs) == 0 {
+		return nil, nil
+	}
+
+	args := make([]any, 0, len(categoryIDs)+len(productIDs))
+	for _, id := range categoryIDs {
+		args = append(args, id)
+	}
+	for _, id := range productIDs {
+		args = append(args, id)
+	}
+	roots := make([]string, len(categoryIDs))
+	for i := range roots {
+		roots[i] = "SELECT CAST(? AS BIGINT)"
+	}
+	query := `
+		WITH RECURSIVE subtree(id) AS (
+			` + strings.Join(roots, " UNION ") + `
+			UNION
+			SELECT c.id FROM categories c JOIN subtree ON c.parent_id = subtree.id
+		)
+		SELECT DISTINCT product_id
+		FROM product_categories
+		WHERE category_id IN (SELECT id FROM subtree)
+		  AND product_id IN (` + strings.TrimSuffix(strings.Repeat("?,", len(productIDs)), ",") + `)
+		ORDER BY product_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query product categories: %w", err)
+	}
+	defer rows.Close()
+	return scanIDs(rows)
+}
+
+// RedeemPromotion takes a use of the code for a checkout when its discount is frozen into the snapshot.
+// The count only goes up while it is under the usage limit, so concurrent checkouts can't take the code
+// past it; a code that is used up fails with FailedPrecondition. Redeeming the same checkout again changes nothing.
+func (r *Repository) RedeemPromotion(ctx context.Context, code, checkoutID string, now time.Time) (*domain.Promotion, error) {
+	p, err := r.GetPromotionByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	err = r.inTx(ctx, func(tx *sqlTx) error {
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO promotion_redemptions (promotion_id, checkout_id, redeemed_at)
+			VALUES (?, ?, ?)
+			ON CONFLICT (promotion_id, checkout_id) DO NOTHING`,
+			p.ID, checkoutID, now.UTC())
+		if err != nil {
+			return fmt.Errorf("insert promotion redemption: %w", err)
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return err // already redeemed for this checkout
+		}
+		res, err = tx.ExecContext(ctx, `
+			UPDATE promotions SET times_used = times_used + 1
+			WHERE id = ? AND (usage_limit IS NULL OR times_used < usage_limit)`, p.ID)
+		if err != nil {
+			return fmt.Errorf("count promotion use: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("count promotion use: %w", err)
+		}
+		if n == 0 {
+			// rolling back drops the redemption row as well
+			return status.Errorf(codes.FailedPrecondition, "%v: it has reached its usage limit", domain.ErrPromotionNotApplicable)
+		}
+		return tx.QueryRowContext(ctx, `SELECT times_used FROM promotions WHERE id = ?`, p.ID).Scan(&p.TimesUsed)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ReleasePromotion gives back the use a failed checkout took. Releasing a checkout that holds no use
+// of the code changes nothing, so the saga may repeat it.
+func (r *Repository) ReleasePromotion(ctx context.Context, code, checkoutID string) (*domain.Promotion, error) {
+	p, err := r.GetPromotionByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	err = r.inTx(ctx, func(tx *sqlTx) error {
+		res, err := tx.ExecContext(ctx, `DELETE FROM promotion_redemptions WHERE promotion_id = ? AND checkout_id = ?`, p.ID, checkoutID)
+		if err != nil {
+			return fmt.Errorf("delete promotion redemption: %w", err)
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return err // nothing was taken for this checkout
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE promotions SET times_used = times_used - 1 WHERE id = ? AND times_used > 0`, p.ID); err != nil {
+			return fmt.Errorf("give back promotion use: %w", err)
+		}
+		return tx.QueryRowContext(ctx, `SELECT times_used FROM promotions WHERE id = ?`, p.ID).Scan(&p.TimesUsed)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func scanIDs(rows *sql.Rows) ([]int64, error) {
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return ids, nil
+}
+
+func utcOrNil(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
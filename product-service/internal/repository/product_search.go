@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/fjod/go_cart/product-service/internal/domain"
+)
+
+// name matches weigh more than description matches in the ranking
+const searchNameWeight, searchDescriptionWeight = 10.0, 1.0
+
+// sqliteSearchQuery ranks with bm25 over the products_fts index, bm25 is lower for better matches,
+// it is negated so the score grows with relevance
+var sqliteSearchQuery = `
+		SELECT ` + prefixed("p.", productColumns) + `,
+		       highlight(products_fts, 0, '<mark>', '</mark>'),
+		       snippet(products_fts, -1, '<mark>', '</mark>', '…', 12),
+		       -bm25(products_fts, ?, ?) AS score
+		FROM products_fts
+		JOIN products p ON p.id = products_fts.rowid
+		WHERE products_fts MATCH ? AND p.archived_at IS NULL
+		ORDER BY score DESC, p.id
+		LIMIT ? OFFSET ?
+	`
+
+// postgresSearchQuery ranks with ts_rank over the weighted search_vector column,
+// the weight array is ordered D, C, B (description), A (name)
+var postgresSearchQuery = `
+		SELECT ` + productColumns + `,
+		       ts_headline('english', name, q, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true'),
+		       ts_headline('english', COALESCE(description, ''), q, 'StartSel=<mark>, StopSel=</mark>, MaxWords=12, MinWords=3, FragmentDelimiter=…, MaxFragments=1'),
+		       ts_rank(ARRAY[0, 0, CAST(? AS REAL), CAST(? AS REAL)], search_vector, q) AS score
+		FROM products, to_tsquery('english', ?) q
+		WHERE search_vector @@ q AND archived_at IS NULL
+		ORDER BY score DESC, id
+		LIMIT ? OFFSET ?
+	`
+
+// SearchProducts ranks the catalog against the user query, best match first. Archived products are left out.
+// An empty query or one without searchable terms matches nothing.
+func (r *Repository) SearchProducts(ctx context.Context, query string, limit, offset int) ([]*domain.ProductSearchHit, error) {
+	var sqlQuery string
+	var args []any
+	switch r.db.driver {
+	case DriverPostgres:
+		match := tsQueryExpression(query)
+		if match == "" {
+			return nil, nil
+		}
+		sqlQuery = postgresSearchQuery
+		args = []any{searchDescriptionWeight, searchNameWeight, match, limit, offset}
+	default:
+		match := ftsMatchExpression(query)
+		if match == "" {
+			return nil, nil
+		}
+		sqlQuery = sqliteSearchQuery
+		args = []any{searchNameWeight, searchDescriptionWeight, match, limit, offset}
+	}
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search products: %w", err)
+	}
+	defer rows.Close()
+
+	var hits []*domain.ProductSearchHit
+	for rows.Next() {
+		hit := &domain.ProductSearchHit{Product: &domain.Product{}}
+		dest := append(productFields(hit.Product), &hit.NameHighlight, &hit.Snippet, &hit.Score)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan search hit: %w", err)
+		}
+		hits = append(hits, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	products := make([]*domain.Product, len(hits))
+	for i, h := range hits {
+		products[i] = h.Product
+	}
+	return hits, r.attachVariants(ctx, products...)
+}
+
+// ftsMatchExpression turns free text into an FTS5 query: every word becomes a quoted string so operators
+// and punctuation in user input can't break the syntax, and the last word matches as a prefix for
+// search-as-you-type. Words are ANDed.
+func ftsMatchExpression(query string) string {
+	words := strings.Fields(query)
+	terms := make([]string, 0, len(words))
+	for _, w := range words {
+		// a word of only quotes would become an empty phrase
+		if strings.Trim(w, `"`) == "" {
+			continue
+		}
+		terms = append(terms, `"`+strings.ReplaceAll(w, `"`, `""`)+`"`)
+	}
+	if len(terms) == 0 {
+		return ""
+	}
+	terms[len(terms)-1] += "*"
+	return strings.Join(terms, " ")
+}
+
+// tsQueryExpression is ftsMatchExpression for Postgres to_tsquery: every word becomes a quoted lexeme,
+// the last one a prefix, ANDed. Words without letters or digits carry no lexeme and are dropped.
+func tsQueryExpression(query string) string {
+	var terms []string
+	for _, w := range strings.Fields(query) {
+		if !strings.ContainsFunc(w, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) {
+			continue
+		}
+		w = strings.ReplaceAll(w, `\`, `\\`)
+		terms = append(terms, "'"+strings.ReplaceAll(w, "'", "''")+"'")
+	}
+	if len(terms) == 0 {
+		return ""
+	}
+	terms[len(terms)-1] += ":*"
+	return strings.Join(terms, " & ")
+}
+
+// prefixed qualifies every column of a comma separated list with a table alias
+func prefixed(alias, columns string) string {
+	cols := strings.Split(columns, ", ")
+	for i, c := range cols {
+		cols[i] = alias + c
+	}
+	return strings.Join(cols, ", ")
+}
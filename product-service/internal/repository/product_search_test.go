@@ -0,0 +1,116 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fjod/go_cart/pkg/money"
+	"github.com/fjod/go_cart/product-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func hitIDs(hits []*domain.ProductSearchHit) []int64 {
+	ids := make([]int64, len(hits))
+	for i, h := range hits {
+		ids[i] = h.Product.ID
+	}
+	return ids
+}
+
+func TestSearchProducts_MatchesNameAndDescription(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	hits, err := repo.SearchProducts(context.Background(), "wireless", 10, 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2}, hitIDs(hits))
+	assert.Equal(t, "Ergonomic <mark>wireless</mark> mouse", hits[0].Snippet)
+	assert.Equal(t, "Mouse", hits[0].NameHighlight)
+	assert.Positive(t, hits[0].Score)
+}
+
+func TestSearchProducts_NameRanksAboveDescription(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+	ctx := context.Background()
+
+	// "keyboard" is the name of product 3 and only appears in the description of the new one
+	p := &domain.Product{Name: "Wrist Rest", Description: "Soft rest for any keyboard", Price: money.New(1999, "USD")}
+	require.NoError(t, repo.CreateProduct(ctx, p))
+
+	hits, err := repo.SearchProducts(ctx, "keyboard", 10, 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3, p.ID}, hitIDs(hits))
+	assert.Equal(t, "<mark>Keyboard</mark>", hits[0].NameHighlight)
+}
+
+func TestSearchProducts_PrefixAndStemming(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	prefix, err := repo.SearchProducts(context.Background(), "head", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{5}, hitIDs(prefix))
+
+	// porter stemmer: "monitors" finds "monitor"
+	stemmed, err := repo.SearchProducts(context.Background(), "monitors", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{4}, hitIDs(stemmed))
+}
+
+func TestSearchProducts_FollowsCatalogChanges(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+	ctx := context.Background()
+
+	require.NoError(t, repo.UpdateProduct(ctx, &domain.Product{ID: 2, Name: "Trackball", Description: "Thumb trackball", Price: money.New(4999, "USD")}))
+
+	old, err := repo.SearchProducts(ctx, "ergonomic", 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, old)
+
+	renamed, err := repo.SearchProducts(ctx, "trackball", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2}, hitIDs(renamed))
+
+	_, err = repo.ArchiveProduct(ctx, 2)
+	require.NoError(t, err)
+	archived, err := repo.SearchProducts(ctx, "trackball", 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, archived)
+}
+
+func TestSearchProducts_UserInputIsNotSyntax(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	for _, q := range []string{`"`, `laptop OR`, `NEAR(laptop`, `-`, `*`, `4K"`, "   "} {
+		_, err := repo.SearchProducts(context.Background(), q, 10, 0)
+		assert.NoError(t, err, "query %q", q)
+	}
+}
+
+func TestSearchProducts_Paging(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+	ctx := context.Background()
+
+	for _, name := range []string{"Desk Lamp", "Floor Lamp", "Lamp Shade"} {
+		require.NoError(t, repo.CreateProduct(ctx, &domain.Product{Name: name, Price: money.New(999, "USD")}))
+	}
+
+	all, err := repo.SearchProducts(ctx, "lamp", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+
+	first, err := repo.SearchProducts(ctx, "lamp", 2, 0)
+	require.NoError(t, err)
+	second, err := repo.SearchProducts(ctx, "lamp", 2, 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, hitIDs(all)[:2], hitIDs(first))
+	assert.Equal(t, hitIDs(all)[2:], hitIDs(second))
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/fjod/go_cart/product-service/internal/domain"
+)
+
+// attachVariants loads the variants of every product with one IN query, archived variants included
+// so carts that hold them keep resolving
+func (r *Repository) attachVariants(ctx context.Context, products ...*domain.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*domain.Product, len(products))
+	args := make([]any, 0, len(products))
+	for _, p := range products {
+		if _, ok := byID[p.ID]; ok {
+			continue
+		}
+		byID[p.ID] = p
+		p.Variants = nil
+		args = append(args, p.ID)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(args)), ",")
+	query := `
+		SELECT id, product_id, sku, name, price_minor, attributes, created_at, archived_at
+		FROM product_variants
+		WHERE product_id IN (` + placeholders + `)
+		ORDER BY product_id, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query variants: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		v := &domain.Variant{}
+		var attributes string
+		if err := rows.Scan(&v.ID, &v.ProductID, &v.SKU, &v.Name, &v.PriceMinor, &attributes, &v.CreatedAt, &v.ArchivedAt); err != nil {
+			return fmt.Errorf("failed to scan variant: %w", err)
+		}
+		if err := json.Unmarshal([]byte(attributes), &v.Attributes); err != nil {
+			return fmt.Errorf("variant %d has invalid attributes: %w", v.ID, err)
+		}
+		p := byID[v.ProductID]
+		p.Variants = append(p.Variants, v)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("row iteration error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fjod/go_cart/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetProduct_LoadsVariants(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	laptop, err := repo.GetProduct(context.Background(), 1)
+
+	require.NoError(t, err)
+	require.Len(t, laptop.Variants, 2)
+	base, upgraded := laptop.Variants[0], laptop.Variants[1]
+	assert.Equal(t, "LAPTOP-16GB-512GB", base.SKU)
+	assert.Equal(t, map[string]string{"memory": "16GB", "storage": "512GB"}, base.Attributes)
+	assert.Equal(t, money.New(129999, "USD"), laptop.PriceOf(base))
+	assert.Equal(t, money.New(159999, "USD"), laptop.PriceOf(upgraded))
+	assert.Same(t, upgraded, laptop.Variant(2))
+}
+
+func TestGetProductsByIds_LoadsVariants(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	products, err := repo.GetProductsByIds(context.Background(), []int64{1, 2})
+
+	require.NoError(t, err)
+	require.Len(t, products, 2)
+	assert.True(t, products[0].HasVariants())
+	assert.False(t, products[1].HasVariants())
+	assert.Nil(t, products[1].Variant(1))
+}
@@ -84,11 +84,26 @@ func TestGetProduct_ReturnsProduct(t *testing.T) {
 	}
 
 	if product == nil {
-		t.Errorf("Received nil product by valid id")
+		t.Fatalf("Received nil product by valid id")
 	}
 	t.Logf("Received product: %+v", *product)
 }
 
+func TestGetProduct_PriceMigratedToMinorUnits(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	product, err := repo.GetProduct(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// seeded as REAL 1299.99
+	if product.Price.Amount != 129999 || product.Price.Currency != "USD" {
+		t.Errorf("Expected 129999 USD, got %+v", product.Price)
+	}
+}
+
 func TestGetProduct_IncorrectId_ReturnsNil(t *testing.T) {
 	repo := setupTestDB(t)
 	defer repo.Close()
@@ -103,3 +118,25 @@ func TestGetProduct_IncorrectId_ReturnsNil(t *testing.T) {
 	t.Logf("Is returned product nil?  %t", isNil)
 	assert.Equal(t, status.Code(err), codes.NotFound)
 }
+
+func TestGetProductsByIds_ReturnsOnlyExisting(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	products, err := repo.GetProductsByIds(context.Background(), []int64{3, 1, 999})
+
+	assert.NoError(t, err)
+	assert.Len(t, products, 2)
+	assert.Equal(t, int64(1), products[0].ID)
+	assert.Equal(t, int64(3), products[1].ID)
+}
+
+func TestGetProductsByIds_Empty(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	products, err := repo.GetProductsByIds(context.Background(), nil)
+
+	assert.NoError(t, err)
+	assert.Empty(t, products)
+}
@@ -11,7 +11,8 @@ import (
 )
 
 type Claims struct {
-	UserID int64 `json:"user_id"`
+	UserID int64  `json:"user_id"`
+	Role   string `json:"role,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -22,6 +23,11 @@ func main() {
 			userID = id
 		}
 	}
+	// optional role claim, "admin" unlocks /api/v1/admin routes
+	role := ""
+	if len(os.Args) > 2 {
+		role = os.Args[2]
+	}
 
 	secret := os.Getenv("JWT_SECRET")
 	if secret == "" {
@@ -30,6 +36,7 @@ func main() {
 
 	claims := Claims{
 		UserID: userID,
+		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),